@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"llm-trading-bot/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:]))
+}
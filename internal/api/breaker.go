@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit open")
+
+// breaker opens after threshold consecutive failures. Once cooldown has
+// passed it lets a single probe through (half-open): success closes it,
+// failure reopens it for another cooldown. A nil breaker always allows.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *breaker) allow(host string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return fmt.Errorf("%w for %s until %s", ErrCircuitOpen, host, b.openUntil.Format(time.TimeOnly))
+	}
+	b.probing = true
+	return nil
+}
+
+// release gives back a half-open probe slot that was never used.
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.failures = 0
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
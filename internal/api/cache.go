@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/metrics"
+)
+
+type bypassKey struct{}
+
+// WithoutCache marks requests made with ctx to skip the response cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// cacheTransport is a disk-backed cache for GET responses. Entries younger
+// than ttl are served without a request; older ones are revalidated with
+// If-None-Match / If-Modified-Since, and a 304 refreshes them. Requests
+// carrying credentials are never cached.
+type cacheTransport struct {
+	next     http.RoundTripper
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	mu sync.Mutex
+}
+
+type cacheEntry struct {
+	URL      string
+	Status   int
+	Header   http.Header
+	Body     []byte
+	StoredAt time.Time
+}
+
+func newCacheTransport(next http.RoundTripper, dir string, ttl time.Duration, maxBytes int64) *cacheTransport {
+	return &cacheTransport{next: next, dir: dir, ttl: ttl, maxBytes: maxBytes}
+}
+
+func (ct *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		return ct.next.RoundTrip(req)
+	}
+
+	path := ct.path(req.URL.String())
+	entry := ct.load(path)
+	if entry != nil && ct.ttl > 0 && time.Since(entry.StoredAt) < ct.ttl {
+		metrics.APICache.Inc("hit")
+		return entry.response(req), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := ct.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		metrics.APICache.Inc("revalidated")
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		ct.store(path, entry)
+		return entry.response(req), nil
+	}
+
+	metrics.APICache.Inc("miss")
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if ct.maxBytes <= 0 || int64(len(body)) <= ct.maxBytes/4 {
+		header := resp.Header.Clone()
+		header.Del("Set-Cookie") // Sessions must not be replayed from disk
+		ct.store(path, &cacheEntry{
+			URL:      req.URL.String(),
+			Status:   resp.StatusCode,
+			Header:   header,
+			Body:     body,
+			StoredAt: time.Now(),
+		})
+	}
+	return resp, nil
+}
+
+func cacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Authorization") != "" {
+		return false
+	}
+	bypass, _ := req.Context().Value(bypassKey{}).(bool)
+	return !bypass
+}
+
+func (ct *cacheTransport) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(ct.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (ct *cacheTransport) load(path string) *cacheEntry {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil
+	}
+	return &e
+}
+
+func (ct *cacheTransport) store(path string, e *cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if err := os.MkdirAll(ct.dir, 0o755); err != nil {
+		return
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return
+	}
+	ct.evict()
+}
+
+// evict removes the least recently stored entries until the cache fits in
+// maxBytes.
+func (ct *cacheTransport) evict() {
+	if ct.maxBytes <= 0 {
+		return
+	}
+	files, err := os.ReadDir(ct.dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path string
+		size int64
+		mod  time.Time
+	}
+	var all []file
+	var total int64
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || f.IsDir() {
+			continue
+		}
+		all = append(all, file{filepath.Join(ct.dir, f.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].mod.Before(all[j].mod) })
+	for _, f := range all {
+		if total <= ct.maxBytes {
+			return
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("X-Cache", "HIT")
+	return &http.Response{
+		Status:        http.StatusText(e.Status),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,290 @@
+// Package api is the shared HTTP client for outbound calls to third-party
+// APIs (LLM providers, data sources). It throttles each host and stops
+// calling hosts that keep failing, so one misbehaving endpoint cannot
+// stall the tick loop.
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/metrics"
+)
+
+type Options struct {
+	Timeout time.Duration
+
+	// Token bucket per host; RatePerSecond <= 0 disables throttling.
+	RatePerSecond float64
+	Burst         int
+	HostRates     map[string]float64 // Per-host overrides of RatePerSecond
+
+	// Circuit breaker per host; FailureThreshold <= 0 disables it.
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	// Cookies keeps a cookie jar across requests. WarmUp maps a host to a
+	// page that must be visited first to obtain session cookies (NSE's
+	// JSON endpoints reject cookie-less calls); it is revisited every
+	// WarmUpTTL and whenever the host answers 401/403.
+	Cookies   bool
+	WarmUp    map[string]string
+	WarmUpTTL time.Duration
+
+	// CacheDir enables an on-disk cache of GET responses, revalidated with
+	// ETag/Last-Modified once older than CacheTTL and capped at
+	// CacheMaxBytes. CacheBypass turns it off without losing the entries;
+	// WithoutCache does the same for a single request.
+	CacheDir      string
+	CacheTTL      time.Duration
+	CacheMaxBytes int64
+	CacheBypass   bool
+
+	Retry RetryPolicy // Used by DoWithRetry
+
+	// Proxy is an http, https or socks5 URL used for every host not in
+	// HostProxies. UserAgents, when set, are rotated on requests that do
+	// not set their own User-Agent.
+	Proxy       string
+	HostProxies map[string]string
+	UserAgents  []string
+
+	// FixtureMode "record" saves every exchange under FixtureDir and
+	// "replay" serves them back without touching the network, for
+	// hermetic runs against recorded NSE, LLM and broker responses.
+	FixtureMode string
+	FixtureDir  string
+
+	// WrapTransport, when set, wraps the network transport below the
+	// cache, e.g. to inject faults.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
+}
+
+type Client struct {
+	http *http.Client
+	opts Options
+	ua   *userAgents
+	base http.RoundTripper // Proxied, fixture-aware network transport, below WrapTransport and the cache
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	limiter *tokenBucket
+	breaker *breaker
+
+	mu       sync.Mutex
+	warmedAt time.Time
+	health   HostHealth
+}
+
+// New builds a client; it only fails on an invalid proxy URL.
+func New(opts Options) (*Client, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 1
+	}
+	if opts.Retry.BaseDelay <= 0 {
+		opts.Retry.BaseDelay = 500 * time.Millisecond
+	}
+	if opts.Retry.MaxDelay <= 0 {
+		opts.Retry.MaxDelay = 10 * time.Second
+	}
+	if opts.WarmUpTTL <= 0 {
+		opts.WarmUpTTL = 15 * time.Minute
+	}
+
+	proxy, err := proxyFunc(opts.Proxy, opts.HostProxies)
+	if err != nil {
+		return nil, err
+	}
+	proxied := http.DefaultTransport.(*http.Transport).Clone()
+	proxied.Proxy = proxy
+	fixtures := NewFixtureTransport(proxied, opts.FixtureMode, opts.FixtureDir)
+	base := fixtures
+	if opts.WrapTransport != nil {
+		base = opts.WrapTransport(base)
+	}
+
+	hc := &http.Client{Timeout: opts.Timeout, Transport: base}
+	if opts.CacheDir != "" && !opts.CacheBypass {
+		hc.Transport = newCacheTransport(base, opts.CacheDir, opts.CacheTTL, opts.CacheMaxBytes)
+	}
+	if opts.Cookies || len(opts.WarmUp) > 0 {
+		jar, _ := cookiejar.New(nil) // Only errors on invalid options
+		hc.Jar = jar
+	}
+	return &Client{
+		http:  hc,
+		opts:  opts,
+		ua:    &userAgents{list: opts.UserAgents},
+		base:  fixtures,
+		hosts: make(map[string]*host),
+	}, nil
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultClient *Client
+)
+
+// Configure replaces the process-wide client returned by Default.
+func Configure(opts Options) error {
+	c, err := New(opts)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultClient = c
+
+	fixtureMu.Lock()
+	rawTransport = nil
+	if opts.FixtureMode != "" {
+		rawTransport = c.base
+	}
+	fixtureMu.Unlock()
+	return nil
+}
+
+// Default returns the process-wide client, with no throttling or breaker
+// unless Configure was called.
+func Default() *Client {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultClient == nil {
+		defaultClient, _ = New(Options{}) // No proxy, cannot fail
+	}
+	return defaultClient
+}
+
+// Do sends req after waiting for the host's rate limit. It fails fast with
+// ErrCircuitOpen while the host's breaker is open. Transport errors, 429
+// and 5xx responses count as failures.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	h := c.host(req.URL.Host)
+
+	if err := h.breaker.allow(req.URL.Host); err != nil {
+		return nil, err
+	}
+	if err := h.limiter.wait(req.Context()); err != nil {
+		h.breaker.release()
+		return nil, err
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		if ua := c.ua.pick(); ua != "" {
+			req.Header.Set("User-Agent", ua)
+		}
+	}
+
+	c.warmUp(req, h, false)
+	resp, err := c.http.Do(req)
+	if err == nil && rejected(resp) && c.warmUp(req, h, true) && rewind(req) {
+		resp.Body.Close()
+		resp, err = c.http.Do(req)
+	}
+	if err == nil {
+		h.record(req.Context(), req.URL.Host, resp)
+	}
+	metrics.APIRequests.Inc(req.URL.Host, result(resp, err))
+	if err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		h.breaker.failure()
+	} else {
+		h.breaker.success()
+	}
+	return resp, err
+}
+
+// warmUp visits the host's warm-up page when the session is stale (or
+// force is set) so the jar holds fresh cookies. It reports whether a
+// warm-up page is configured and was fetched.
+func (c *Client) warmUp(req *http.Request, h *host, force bool) bool {
+	page, ok := c.opts.WarmUp[req.URL.Host]
+	if !ok {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !force && time.Since(h.warmedAt) < c.opts.WarmUpTTL {
+		return false
+	}
+
+	wreq, err := http.NewRequestWithContext(WithoutCache(req.Context()), http.MethodGet, page, nil)
+	if err != nil {
+		return false
+	}
+	for _, k := range []string{"User-Agent", "Accept-Language"} {
+		if v := req.Header.Get(k); v != "" {
+			wreq.Header.Set(k, v)
+		}
+	}
+	resp, err := c.http.Do(wreq)
+	if err != nil {
+		return false
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	h.warmedAt = time.Now()
+	return true
+}
+
+func result(resp *http.Response, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case blocked(resp):
+		return "blocked"
+	case resp.StatusCode >= 500:
+		return "server_error"
+	}
+	return "ok"
+}
+
+func rejected(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden
+}
+
+// rewind resets the request body for a resend; bodiless requests always
+// can be resent.
+func rewind(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+func (c *Client) host(name string) *host {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h, ok := c.hosts[name]
+	if !ok {
+		rate := c.opts.RatePerSecond
+		if r, ok := c.opts.HostRates[name]; ok {
+			rate = r
+		}
+		h = &host{
+			limiter: newTokenBucket(rate, c.opts.Burst),
+			breaker: newBreaker(c.opts.FailureThreshold, c.opts.Cooldown),
+		}
+		c.hosts[name] = h
+	}
+	return h
+}
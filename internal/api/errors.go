@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Error kinds shared by every fetcher, so callers can tell why a call
+// failed with errors.Is instead of matching message text.
+var (
+	ErrRateLimited = errors.New("rate limited")     // 429: back off, try later
+	ErrUnavailable = errors.New("unavailable")      // 5xx: try later or elsewhere
+	ErrNotFound    = errors.New("not found")        // 404: the data does not exist
+	ErrAuth        = errors.New("not authorized")   // 401/403: credentials or session need the operator
+	ErrParse       = errors.New("unexpected reply") // Body could not be decoded
+)
+
+// StatusError is a non-2xx response. It matches the error kind of its
+// status with errors.Is.
+type StatusError struct {
+	Status int
+	URL    string
+	Body   string // Start of the response body, for the log
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("http %d from %s", e.Status, e.URL)
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.Status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden:
+		return ErrAuth
+	case e.Status == http.StatusNotFound || e.Status == http.StatusGone:
+		return ErrNotFound
+	case e.Status >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// CheckStatus returns nil for a 2xx response and a *StatusError
+// otherwise, reading up to 512 bytes of the body into it.
+func CheckStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	u := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		u = resp.Request.URL.Redacted()
+		if i := strings.IndexByte(u, '?'); i >= 0 {
+			u = u[:i] // Query strings may carry keys
+		}
+	}
+	return &StatusError{Status: resp.StatusCode, URL: u, Body: strings.TrimSpace(string(b))}
+}
+
+// Temporary reports whether err is worth retrying later: rate limiting,
+// server errors, an open circuit or a network failure. Auth, not-found and
+// parse errors will fail the same way again.
+func Temporary(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
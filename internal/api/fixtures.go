@@ -0,0 +1,289 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Fixture modes for Options.FixtureMode and NewFixtureTransport.
+const (
+	FixtureRecord = "record" // Call the network and save every exchange
+	FixtureReplay = "replay" // Serve saved exchanges; never call the network
+)
+
+// ErrNoFixture is returned in replay mode for a request nothing was
+// recorded for.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+type noFixtureKey struct{}
+
+// WithoutFixtures marks requests made with ctx to go straight to the
+// network in both modes. Notifications use it: they are side effects, not
+// data, and their URLs carry secrets (bot tokens, webhook paths).
+func WithoutFixtures(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noFixtureKey{}, true)
+}
+
+// fixtureTransport records HTTP exchanges to files and plays them back,
+// so code calling NSE, LLM or broker APIs can be exercised offline with
+// the same responses every time. A request is identified by method, URL
+// and body. Credentials in the query string and secret fields of form and
+// JSON bodies (passwords, TOTP codes, client secrets, access tokens) are
+// left out of the key and the file, and request headers are never saved.
+type fixtureTransport struct {
+	next http.RoundTripper
+	dir  string
+	mode string
+
+	mu sync.Mutex
+}
+
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        string      `json:"body"`
+}
+
+// NewFixtureTransport wraps next (http.DefaultTransport when nil) to
+// record to or replay from dir. Any other mode returns next unchanged.
+func NewFixtureTransport(next http.RoundTripper, mode, dir string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if mode != FixtureRecord && mode != FixtureReplay {
+		return next
+	}
+	return &fixtureTransport{next: next, dir: dir, mode: mode}
+}
+
+func (ft *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if skip, _ := req.Context().Value(noFixtureKey{}).(bool); skip {
+		return ft.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	u := redactURL(req.URL)
+	redacted := redactBody(req.Header.Get("Content-Type"), reqBody)
+	path := ft.path(req.Method, req.URL.Host, u, []byte(redacted))
+
+	if ft.mode == FixtureReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s %s (%s)", ErrNoFixture, req.Method, u, filepath.Base(path))
+		}
+		var f fixture
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", path, err)
+		}
+		return f.response(req), nil
+	}
+
+	resp, err := ft.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	ft.store(path, fixture{
+		Method:      req.Method,
+		URL:         u,
+		RequestBody: redacted,
+		Status:      resp.StatusCode,
+		Header:      header,
+		Body:        redactBody(resp.Header.Get("Content-Type"), body),
+	})
+	return resp, nil
+}
+
+// path is <dir>/<host>/<hash>.json, so fixtures for one site can be
+// refreshed or deleted together.
+func (ft *fixtureTransport) path(method, host, u string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, u)
+	h.Write(body)
+	return filepath.Join(ft.dir, strings.ReplaceAll(host, ":", "_"), hex.EncodeToString(h.Sum(nil))[:32]+".json")
+}
+
+func (ft *fixtureTransport) store(path string, f fixture) {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return
+	}
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0o600)
+}
+
+func (f *fixture) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
+
+// redactURL drops query parameters that look like credentials.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	q := c.Query()
+	for k := range q {
+		lk := strings.ToLower(k)
+		for _, s := range []string{"key", "token", "secret", "signature", "password"} {
+			if strings.Contains(lk, s) {
+				q.Del(k)
+				break
+			}
+		}
+	}
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// secretFields are form and JSON body fields whose values are replaced
+// before a fixture is saved: login credentials (Kite password and TOTP,
+// Upstox client secret) and the tokens auth endpoints return. Names are
+// matched exactly, since data fields such as instrument_token and
+// instrument_key must survive.
+var secretFields = map[string]bool{
+	"password":       true,
+	"twofa_value":    true,
+	"totp":           true,
+	"pin":            true,
+	"secret":         true,
+	"api_secret":     true,
+	"client_secret":  true,
+	"api_key":        true,
+	"checksum":       true,
+	"access_token":   true,
+	"refresh_token":  true,
+	"public_token":   true,
+	"request_token":  true,
+	"extended_token": true,
+	"enctoken":       true,
+	"id_token":       true,
+}
+
+// formSecretFields are also redacted in form bodies, where "code" is an
+// OAuth authorization code.
+var formSecretFields = map[string]bool{"code": true}
+
+const redactedValue = "REDACTED"
+
+// redactBody replaces the values of secretFields in a form or JSON body.
+// Other bodies are returned unchanged.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		for k := range form {
+			if secretFields[strings.ToLower(k)] || formSecretFields[strings.ToLower(k)] {
+				form.Set(k, redactedValue)
+			}
+		}
+		return form.Encode()
+	}
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // Keep large IDs exact when re-encoding
+	if err := dec.Decode(&v); err != nil {
+		return string(body)
+	}
+	if !redactJSON(v) {
+		return string(body)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+// redactJSON redacts secretFields anywhere in v and reports whether it
+// changed anything, so bodies without secrets are saved as they came.
+func redactJSON(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if secretFields[strings.ToLower(k)] {
+				if _, ok := val.(string); ok {
+					v[k] = redactedValue
+					changed = true
+					continue
+				}
+			}
+			if redactJSON(val) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, val := range v {
+			if redactJSON(val) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+var (
+	fixtureMu    sync.Mutex
+	rawTransport http.RoundTripper
+)
+
+// Transport returns the transport raw http.Clients (brokers, login flows)
+// should use so they take part in fixture recording and replay like the
+// shared client, through the same proxies. It is nil, meaning
+// http.DefaultTransport, unless Configure set a fixture mode.
+func Transport() http.RoundTripper {
+	fixtureMu.Lock()
+	defer fixtureMu.Unlock()
+	return rawTransport
+}
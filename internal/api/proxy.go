@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+)
+
+// proxyFunc routes each host through its own proxy when one is set,
+// otherwise through the global proxy, otherwise the environment's
+// HTTP(S)_PROXY. http, https and socks5 proxy URLs are supported.
+func proxyFunc(global string, perHost map[string]string) (func(*http.Request) (*url.URL, error), error) {
+	parse := func(raw string) (*url.URL, error) {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", raw, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+			return u, nil
+		}
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+
+	var def *url.URL
+	if global != "" {
+		u, err := parse(global)
+		if err != nil {
+			return nil, err
+		}
+		def = u
+	}
+	hosts := make(map[string]*url.URL, len(perHost))
+	for h, raw := range perHost {
+		u, err := parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		hosts[h] = u
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if u, ok := hosts[req.URL.Host]; ok {
+			return u, nil
+		}
+		if def != nil {
+			return def, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// userAgents hands out the configured User-Agent strings round-robin.
+type userAgents struct {
+	mu   sync.Mutex
+	list []string
+	next int
+}
+
+func (ua *userAgents) pick() string {
+	ua.mu.Lock()
+	defer ua.mu.Unlock()
+	if len(ua.list) == 0 {
+		return ""
+	}
+	v := ua.list[ua.next%len(ua.list)]
+	ua.next++
+	return v
+}
+
+// HostHealth summarizes how a host has been answering. Blocked counts
+// 403 and 429 responses, the usual signs of scraper blocking.
+type HostHealth struct {
+	Requests    int
+	Blocked     int
+	LastStatus  int
+	LastBlocked time.Time
+}
+
+func blocked(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (h *host) record(ctx context.Context, name string, resp *http.Response) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.health.Requests++
+	h.health.LastStatus = resp.StatusCode
+	if !blocked(resp) {
+		return
+	}
+	h.health.Blocked++
+	h.health.LastBlocked = time.Now()
+	logger.Warn(ctx, "Host appears to be blocking requests",
+		"host", name,
+		"status", resp.StatusCode,
+		"blocked", h.health.Blocked,
+		"requests", h.health.Requests,
+	)
+}
+
+// Health reports per-host request and block counts.
+func (c *Client) Health() map[string]HostHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]HostHealth, len(c.hosts))
+	for name, h := range c.hosts {
+		h.mu.Lock()
+		out[name] = h.health
+		h.mu.Unlock()
+	}
+	return out
+}
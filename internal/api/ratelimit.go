@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket refills rate tokens per second up to burst. A nil bucket
+// (rate <= 0) never waits.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (tb *tokenBucket) wait(ctx context.Context) error {
+	if tb == nil {
+		return nil
+	}
+	for {
+		delay := tb.take()
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take consumes a token if one is available, otherwise returns how long
+// until the next one.
+func (tb *tokenBucket) take() time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return 0
+	}
+	return time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+}
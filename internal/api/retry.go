@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls DoWithRetry. Delays use full jitter: a random wait
+// in [0, min(MaxDelay, BaseDelay*2^attempt)]. Budget caps the total time
+// spent waiting between attempts of one request, so a failing host cannot
+// hold up the caller for longer than that.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Budget      time.Duration
+}
+
+// DoWithRetry is Do with retries on transport errors, 429 and 5xx. Other
+// 4xx responses are returned at once, and a 429 Retry-After is honored
+// when it fits in the budget. Requests with a body are only retried when
+// the body can be rewound.
+func (c *Client) DoWithRetry(req *http.Request) (*http.Response, error) {
+	p := c.opts.Retry
+	if p.MaxAttempts <= 1 {
+		return c.Do(req)
+	}
+
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		resp, err := c.Do(req)
+		if !retryable(resp, err) || attempt+1 >= p.MaxAttempts {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		if ra, ok := retryAfter(resp); ok {
+			delay = ra
+		}
+		if waited+delay > p.Budget || !rewind(req) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		waited += delay
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << attempt
+	if ceiling > p.MaxDelay || ceiling <= 0 {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryAfter parses a 429 Retry-After given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
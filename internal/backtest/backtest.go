@@ -0,0 +1,194 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"llm-trading-bot/internal/clock"
+	"llm-trading-bot/internal/engine"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/types"
+)
+
+type Options struct {
+	InitialCash float64 // Starting cash of the simulated account
+	SlippageBps float64 // Adverse fill adjustment in basis points
+	Warmup      int     // Bars replayed before the engine is stepped
+	BarsPerYear float64 // Annualization factor for Sharpe (252 for daily bars)
+	From        int64   // Bars before this timestamp are history only (0 = replay everything)
+	To          int64   // Bars after this timestamp are ignored (0 = no limit)
+}
+
+type EquityPoint struct {
+	Ts     int64
+	Equity float64
+}
+
+type Trade struct {
+	Symbol     string
+	EntryTs    int64
+	ExitTs     int64
+	Qty        int
+	EntryPrice float64
+	ExitPrice  float64
+	PnL        float64
+}
+
+type SymbolStats struct {
+	Symbol string
+	Trades int
+	Wins   int
+	PnL    float64
+}
+
+func (s SymbolStats) WinRate() float64 {
+	if s.Trades == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Trades) * 100.0
+}
+
+type Result struct {
+	Equity  []EquityPoint
+	Trades  []Trade
+	Symbols []SymbolStats
+
+	StartEquity    float64
+	EndEquity      float64
+	TotalReturnPct float64
+	MaxDrawdownPct float64
+	Sharpe         float64
+	WinRatePct     float64
+	StepErrors     int
+}
+
+// Run replays candles bar by bar through the regular engine, stepping each
+// symbol when one of its bars closes, and marks the account to market after
+// every timestamp.
+func Run(ctx context.Context, cfg *store.Config, decider interfaces.Decider, candles map[string][]types.Candle, opts Options) (*Result, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candle data to replay")
+	}
+	if opts.Warmup < 50 {
+		opts.Warmup = 50
+	}
+	if opts.BarsPerYear <= 0 {
+		opts.BarsPerYear = 252
+	}
+
+	// The engine reads the time of the bar being replayed, not the wall clock
+	timeline := Timeline(candles)
+	if len(timeline) == 0 {
+		return nil, fmt.Errorf("no candle data to replay")
+	}
+	sim := clock.NewSim(time.Unix(timeline[0], 0))
+
+	broker := NewSimBroker(candles, opts.InitialCash, opts.SlippageBps)
+	eng := engine.New(cfg, broker, decider, sim)
+
+	symbols := make([]string, 0, len(candles))
+	for sym := range candles {
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+
+	res := &Result{StartEquity: opts.InitialCash}
+	var due []string
+
+	for _, ts := range timeline {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sim.Set(time.Unix(ts, 0))
+		if opts.To > 0 && ts > opts.To {
+			break
+		}
+		if ts < opts.From {
+			for _, sym := range symbols {
+				broker.advance(sym, ts)
+			}
+			continue
+		}
+
+		due = due[:0]
+		for _, sym := range symbols {
+			if broker.advance(sym, ts) && broker.barsSeen(sym) >= opts.Warmup {
+				due = append(due, sym)
+			}
+		}
+		eng.PlanTick(ctx, due)
+		for _, sym := range due {
+			if _, err := eng.Step(ctx, sym); err != nil {
+				res.StepErrors++
+			}
+		}
+
+		res.Equity = append(res.Equity, EquityPoint{Ts: ts, Equity: broker.equity()})
+	}
+
+	res.Trades = broker.trades
+	res.summarize(opts.BarsPerYear)
+	return res, nil
+}
+
+// Timeline returns the sorted union of candle timestamps across symbols.
+func Timeline(candles map[string][]types.Candle) []int64 {
+	seen := make(map[int64]struct{})
+	for _, cs := range candles {
+		for _, c := range cs {
+			seen[c.Ts] = struct{}{}
+		}
+	}
+	out := make([]int64, 0, len(seen))
+	for ts := range seen {
+		out = append(out, ts)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func (r *Result) summarize(barsPerYear float64) {
+	if n := len(r.Equity); n > 0 {
+		r.EndEquity = r.Equity[n-1].Equity
+	} else {
+		r.EndEquity = r.StartEquity
+	}
+	if r.StartEquity > 0 {
+		r.TotalReturnPct = (r.EndEquity/r.StartEquity - 1) * 100.0
+	}
+
+	curve := make([]float64, len(r.Equity))
+	for i, p := range r.Equity {
+		curve[i] = p.Equity
+	}
+	r.MaxDrawdownPct = MaxDrawdownPct(curve)
+	r.Sharpe = Sharpe(Returns(curve), barsPerYear)
+
+	bySymbol := make(map[string]*SymbolStats)
+	wins := 0
+	for _, t := range r.Trades {
+		s := bySymbol[t.Symbol]
+		if s == nil {
+			s = &SymbolStats{Symbol: t.Symbol}
+			bySymbol[t.Symbol] = s
+		}
+		s.Trades++
+		s.PnL += t.PnL
+		if t.PnL > 0 {
+			s.Wins++
+			wins++
+		}
+	}
+	if len(r.Trades) > 0 {
+		r.WinRatePct = float64(wins) / float64(len(r.Trades)) * 100.0
+	}
+
+	r.Symbols = make([]SymbolStats, 0, len(bySymbol))
+	for _, s := range bySymbol {
+		r.Symbols = append(r.Symbols, *s)
+	}
+	sort.Slice(r.Symbols, func(i, j int) bool { return r.Symbols[i].Symbol < r.Symbols[j].Symbol })
+}
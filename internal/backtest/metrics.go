@@ -0,0 +1,59 @@
+package backtest
+
+import "math"
+
+// Returns converts an equity curve into simple per-bar returns.
+func Returns(curve []float64) []float64 {
+	if len(curve) < 2 {
+		return nil
+	}
+	out := make([]float64, 0, len(curve)-1)
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1] == 0 {
+			out = append(out, 0)
+			continue
+		}
+		out = append(out, curve[i]/curve[i-1]-1)
+	}
+	return out
+}
+
+// MaxDrawdownPct is the largest peak-to-trough decline of the curve.
+func MaxDrawdownPct(curve []float64) float64 {
+	peak, maxDD := 0.0, 0.0
+	for _, v := range curve {
+		if v > peak {
+			peak = v
+		}
+		if peak > 0 {
+			if dd := (peak - v) / peak * 100.0; dd > maxDD {
+				maxDD = dd
+			}
+		}
+	}
+	return maxDD
+}
+
+// Sharpe is the annualized mean/stddev ratio of per-bar returns with a
+// zero risk-free rate.
+func Sharpe(returns []float64, periodsPerYear float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		d := r - mean
+		variance += d * d
+	}
+	sd := math.Sqrt(variance / float64(len(returns)-1))
+	if sd == 0 {
+		return 0
+	}
+	return mean / sd * math.Sqrt(periodsPerYear)
+}
@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+)
+
+func (r *Result) WriteSummary(w io.Writer) {
+	fmt.Fprintf(w, "Start equity:   %.2f\n", r.StartEquity)
+	fmt.Fprintf(w, "End equity:     %.2f\n", r.EndEquity)
+	fmt.Fprintf(w, "Total return:   %.2f%%\n", r.TotalReturnPct)
+	fmt.Fprintf(w, "Max drawdown:   %.2f%%\n", r.MaxDrawdownPct)
+	fmt.Fprintf(w, "Sharpe:         %.2f\n", r.Sharpe)
+	fmt.Fprintf(w, "Trades:         %d\n", len(r.Trades))
+	fmt.Fprintf(w, "Win rate:       %.1f%%\n", r.WinRatePct)
+	fmt.Fprintf(w, "Step errors:    %d\n\n", r.StepErrors)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SYMBOL\tTRADES\tWIN RATE\tPNL")
+	for _, s := range r.Symbols {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%+.2f\n", s.Symbol, s.Trades, s.WinRate(), s.PnL)
+	}
+	tw.Flush()
+}
+
+// WriteCSV writes equity.csv and trades.csv into dir.
+func (r *Result) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	equity := [][]string{{"ts", "equity"}}
+	for _, p := range r.Equity {
+		equity = append(equity, []string{strconv.FormatInt(p.Ts, 10), fmt.Sprintf("%.2f", p.Equity)})
+	}
+	if err := writeCSVFile(filepath.Join(dir, "equity.csv"), equity); err != nil {
+		return err
+	}
+
+	trades := [][]string{{"symbol", "entry_ts", "exit_ts", "qty", "entry_price", "exit_price", "pnl"}}
+	for _, t := range r.Trades {
+		trades = append(trades, []string{
+			t.Symbol,
+			strconv.FormatInt(t.EntryTs, 10),
+			strconv.FormatInt(t.ExitTs, 10),
+			strconv.Itoa(t.Qty),
+			fmt.Sprintf("%.4f", t.EntryPrice),
+			fmt.Sprintf("%.4f", t.ExitPrice),
+			fmt.Sprintf("%.2f", t.PnL),
+		})
+	}
+	return writeCSVFile(filepath.Join(dir, "trades.csv"), trades)
+}
+
+func writeCSVFile(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
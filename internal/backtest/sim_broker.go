@@ -0,0 +1,234 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+)
+
+type lot struct {
+	qty   int
+	price float64
+	ts    int64
+}
+
+// SimBroker replays historical candles and fills market orders at the
+// current bar's close (adjusted for slippage). The engine only ever sees
+// candles up to the bar being replayed.
+type SimBroker struct {
+	candles     map[string][]types.Candle
+	cursor      map[string]int
+	cash        float64
+	lots        map[string][]lot
+	slippageBps float64
+	nextID      int
+	filled      map[string]types.OrderStatus
+
+	trades []Trade
+}
+
+var _ interfaces.Broker = (*SimBroker)(nil)
+
+func NewSimBroker(candles map[string][]types.Candle, initialCash, slippageBps float64) *SimBroker {
+	cursor := make(map[string]int, len(candles))
+	for sym := range candles {
+		cursor[sym] = -1
+	}
+	return &SimBroker{
+		candles:     candles,
+		cursor:      cursor,
+		cash:        initialCash,
+		lots:        make(map[string][]lot),
+		filled:      make(map[string]types.OrderStatus),
+		slippageBps: slippageBps,
+	}
+}
+
+// advance moves symbol's cursor to the last candle at or before ts and
+// reports whether a bar closed exactly at ts.
+func (sb *SimBroker) advance(symbol string, ts int64) bool {
+	cs := sb.candles[symbol]
+	i := sb.cursor[symbol]
+	for i+1 < len(cs) && cs[i+1].Ts <= ts {
+		i++
+	}
+	sb.cursor[symbol] = i
+	return i >= 0 && cs[i].Ts == ts
+}
+
+func (sb *SimBroker) barsSeen(symbol string) int {
+	return sb.cursor[symbol] + 1
+}
+
+func (sb *SimBroker) current(symbol string) (types.Candle, bool) {
+	i, ok := sb.cursor[symbol]
+	if !ok || i < 0 {
+		return types.Candle{}, false
+	}
+	return sb.candles[symbol][i], true
+}
+
+func (sb *SimBroker) LTP(ctx context.Context, symbol string) (float64, error) {
+	c, ok := sb.current(symbol)
+	if !ok {
+		return 0, fmt.Errorf("no replayed data for %s", symbol)
+	}
+	return c.Close, nil
+}
+
+// GetQuote synthesizes a quote around the replayed close, using the
+// slippage as the half-spread.
+func (sb *SimBroker) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	c, ok := sb.current(symbol)
+	if !ok {
+		return types.Quote{}, fmt.Errorf("no replayed data for %s", symbol)
+	}
+	half := c.Close * sb.slippageBps / 10000.0
+	return types.Quote{
+		Symbol: symbol,
+		LTP:    c.Close,
+		Bid:    c.Close - half,
+		Ask:    c.Close + half,
+		Ts:     c.Ts,
+	}, nil
+}
+
+func (sb *SimBroker) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	i, ok := sb.cursor[symbol]
+	if !ok || i < 0 {
+		return nil, fmt.Errorf("no replayed data for %s", symbol)
+	}
+	start := i + 1 - n
+	if start < 0 {
+		start = 0
+	}
+	out := make([]types.Candle, i+1-start)
+	copy(out, sb.candles[symbol][start:i+1])
+	return out, nil
+}
+
+func (sb *SimBroker) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	c, ok := sb.current(req.Symbol)
+	if !ok {
+		return types.OrderResp{}, fmt.Errorf("no replayed data for %s", req.Symbol)
+	}
+	if req.Qty <= 0 {
+		return types.OrderResp{}, errors.New("quantity must be positive")
+	}
+
+	slip := c.Close * sb.slippageBps / 10000.0
+	sb.nextID++
+	id := fmt.Sprintf("BT-%d", sb.nextID)
+
+	var fill float64
+	switch req.Side {
+	case "BUY":
+		fill = c.Close + slip
+		cost := fill * float64(req.Qty)
+		if cost > sb.cash {
+			return types.OrderResp{}, fmt.Errorf("insufficient cash: need %.2f, have %.2f", cost, sb.cash)
+		}
+		sb.cash -= cost
+		sb.lots[req.Symbol] = append(sb.lots[req.Symbol], lot{qty: req.Qty, price: fill, ts: c.Ts})
+
+	case "SELL":
+		if sb.holding(req.Symbol) < req.Qty {
+			return types.OrderResp{}, fmt.Errorf("insufficient holdings: have %d, selling %d", sb.holding(req.Symbol), req.Qty)
+		}
+		fill = c.Close - slip
+		sb.cash += fill * float64(req.Qty)
+		sb.closeLots(req.Symbol, req.Qty, fill, c.Ts)
+
+	default:
+		return types.OrderResp{}, fmt.Errorf("unsupported side %q", req.Side)
+	}
+
+	sb.filled[id] = types.OrderStatus{OrderID: id, Status: types.OrderComplete, FilledQty: req.Qty, AvgPrice: fill}
+	return types.OrderResp{OrderID: id, Status: "FILLED", Message: "backtest"}, nil
+}
+
+// Orders fill at placement, so there is never anything left to amend.
+func (sb *SimBroker) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	return types.OrderResp{}, fmt.Errorf("order %s already filled", orderID)
+}
+
+func (sb *SimBroker) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	return types.OrderResp{}, fmt.Errorf("order %s already filled", orderID)
+}
+
+func (sb *SimBroker) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	st, ok := sb.filled[orderID]
+	if !ok {
+		return types.OrderStatus{}, fmt.Errorf("unknown order %s", orderID)
+	}
+	return st, nil
+}
+
+func (sb *SimBroker) closeLots(symbol string, qty int, price float64, ts int64) {
+	queue := sb.lots[symbol]
+	for qty > 0 && len(queue) > 0 {
+		l := &queue[0]
+		fill := l.qty
+		if fill > qty {
+			fill = qty
+		}
+		sb.trades = append(sb.trades, Trade{
+			Symbol:     symbol,
+			EntryTs:    l.ts,
+			ExitTs:     ts,
+			Qty:        fill,
+			EntryPrice: l.price,
+			ExitPrice:  price,
+			PnL:        float64(fill) * (price - l.price),
+		})
+		l.qty -= fill
+		qty -= fill
+		if l.qty == 0 {
+			queue = queue[1:]
+		}
+	}
+	sb.lots[symbol] = queue
+}
+
+func (sb *SimBroker) holding(symbol string) int {
+	total := 0
+	for _, l := range sb.lots[symbol] {
+		total += l.qty
+	}
+	return total
+}
+
+func (sb *SimBroker) marketValue() float64 {
+	value := 0.0
+	for sym := range sb.lots {
+		if c, ok := sb.current(sym); ok {
+			value += float64(sb.holding(sym)) * c.Close
+		}
+	}
+	return value
+}
+
+func (sb *SimBroker) equity() float64 {
+	return sb.cash + sb.marketValue()
+}
+
+func (sb *SimBroker) Funds(ctx context.Context) (types.Funds, error) {
+	return types.Funds{
+		Available: sb.cash,
+		Utilised:  sb.marketValue(),
+		Net:       sb.equity(),
+	}, nil
+}
+
+func (sb *SimBroker) Ticks() <-chan types.Tick {
+	return nil
+}
+
+func (sb *SimBroker) Start(ctx context.Context, symbols []string) error {
+	return nil
+}
+
+func (sb *SimBroker) Stop(ctx context.Context) {}
@@ -0,0 +1,228 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Param is one swept config key, addressed by its YAML path
+// (e.g. "stop.atr_mult" or "indicators.sma_windows").
+type Param struct {
+	Key    string
+	Values []string
+}
+
+// ParseParam parses "key=v1,v2,..." where values are YAML scalars or
+// flow sequences; commas inside brackets do not split values.
+func ParseParam(spec string) (Param, error) {
+	key, raw, ok := strings.Cut(spec, "=")
+	if !ok || strings.TrimSpace(key) == "" || strings.TrimSpace(raw) == "" {
+		return Param{}, fmt.Errorf("invalid param %q: expected key=v1,v2", spec)
+	}
+
+	var values []string
+	depth, start := 0, 0
+	for i, r := range raw {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				values = append(values, strings.TrimSpace(raw[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	values = append(values, strings.TrimSpace(raw[start:]))
+
+	return Param{Key: strings.TrimSpace(key), Values: values}, nil
+}
+
+// Combinations expands the grid into every assignment of values to keys.
+func Combinations(params []Param) []map[string]string {
+	combos := []map[string]string{{}}
+	for _, p := range params {
+		next := make([]map[string]string, 0, len(combos)*len(p.Values))
+		for _, c := range combos {
+			for _, v := range p.Values {
+				m := make(map[string]string, len(c)+1)
+				for k, cv := range c {
+					m[k] = cv
+				}
+				m[p.Key] = v
+				next = append(next, m)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// ApplyParams returns a copy of base with the given YAML paths overridden.
+func ApplyParams(base *store.Config, params map[string]string) (*store.Config, error) {
+	b, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+
+	for key, raw := range params {
+		var val any
+		if err := yaml.Unmarshal([]byte(raw), &val); err != nil {
+			return nil, fmt.Errorf("param %s: invalid value %q: %w", key, raw, err)
+		}
+		if err := setPath(tree, strings.Split(key, "."), val); err != nil {
+			return nil, fmt.Errorf("param %s: %w", key, err)
+		}
+	}
+
+	b, err = yaml.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	var out store.Config
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	if err := out.Validate(); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func setPath(tree map[string]any, path []string, val any) error {
+	node := tree
+	for _, part := range path[:len(path)-1] {
+		child, ok := node[part].(map[string]any)
+		if !ok {
+			return fmt.Errorf("unknown config section %q", part)
+		}
+		node = child
+	}
+	last := path[len(path)-1]
+	if _, ok := node[last]; !ok {
+		return fmt.Errorf("unknown config key %q", last)
+	}
+	node[last] = val
+	return nil
+}
+
+type SweepOptions struct {
+	Options
+	Folds    int     // Walk-forward folds; 0 runs each combination over the full range
+	TrainPct float64 // Share of each fold used for in-sample selection
+	Metric   string  // Selection metric: sharpe or return
+}
+
+type SweepRow struct {
+	Fold   int
+	Phase  string // FULL, TRAIN or TEST
+	Params map[string]string
+	Result *Result
+}
+
+// Sweep runs every parameter combination. With folds, the timeline is cut
+// into consecutive windows; each window's train part picks the best
+// combination by metric and its test part evaluates that choice out of
+// sample.
+func Sweep(ctx context.Context, base *store.Config, decider interfaces.Decider, candles map[string][]types.Candle, params []Param, opts SweepOptions) ([]SweepRow, error) {
+	combos := Combinations(params)
+	configs := make([]*store.Config, len(combos))
+	for i, c := range combos {
+		cfg, err := ApplyParams(base, c)
+		if err != nil {
+			return nil, err
+		}
+		configs[i] = cfg
+	}
+
+	var rows []SweepRow
+
+	if opts.Folds <= 0 {
+		for i, cfg := range configs {
+			res, err := Run(ctx, cfg, decider, candles, opts.Options)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, SweepRow{Phase: "FULL", Params: combos[i], Result: res})
+		}
+		return rows, nil
+	}
+
+	if opts.TrainPct <= 0 || opts.TrainPct >= 1 {
+		opts.TrainPct = 0.7
+	}
+
+	ts := Timeline(candles)
+	if len(ts) <= opts.Warmup {
+		return nil, fmt.Errorf("not enough bars for walk-forward: %d bars, warmup %d", len(ts), opts.Warmup)
+	}
+	usable := ts[opts.Warmup:]
+	foldLen := len(usable) / opts.Folds
+	if foldLen < 2 {
+		return nil, fmt.Errorf("not enough bars for %d folds", opts.Folds)
+	}
+
+	for f := 0; f < opts.Folds; f++ {
+		window := usable[f*foldLen : (f+1)*foldLen]
+		split := int(float64(len(window)) * opts.TrainPct)
+		if split < 1 || split >= len(window) {
+			return nil, fmt.Errorf("fold %d too small to split", f+1)
+		}
+
+		train := opts.Options
+		train.From, train.To = window[0], window[split-1]
+
+		best, bestScore := -1, 0.0
+		for i, cfg := range configs {
+			res, err := Run(ctx, cfg, decider, candles, train)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, SweepRow{Fold: f + 1, Phase: "TRAIN", Params: combos[i], Result: res})
+			if score := metric(res, opts.Metric); best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		test := opts.Options
+		test.From, test.To = window[split], window[len(window)-1]
+		res, err := Run(ctx, configs[best], decider, candles, test)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, SweepRow{Fold: f + 1, Phase: "TEST", Params: combos[best], Result: res})
+	}
+
+	return rows, nil
+}
+
+func metric(r *Result, name string) float64 {
+	if name == "return" {
+		return r.TotalReturnPct
+	}
+	return r.Sharpe
+}
+
+// ParamKeys returns the swept keys in a stable order for reporting.
+func ParamKeys(params []Param) []string {
+	keys := make([]string, 0, len(params))
+	for _, p := range params {
+		keys = append(keys, p.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
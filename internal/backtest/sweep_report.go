@@ -0,0 +1,63 @@
+package backtest
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strconv"
+)
+
+func sweepTable(rows []SweepRow, keys []string) [][]string {
+	header := append([]string{"fold", "phase"}, keys...)
+	header = append(header, "return_pct", "max_drawdown_pct", "sharpe", "trades", "win_rate_pct")
+	table := [][]string{header}
+
+	for _, r := range rows {
+		rec := []string{strconv.Itoa(r.Fold), r.Phase}
+		for _, k := range keys {
+			rec = append(rec, r.Params[k])
+		}
+		rec = append(rec,
+			fmt.Sprintf("%.2f", r.Result.TotalReturnPct),
+			fmt.Sprintf("%.2f", r.Result.MaxDrawdownPct),
+			fmt.Sprintf("%.2f", r.Result.Sharpe),
+			strconv.Itoa(len(r.Result.Trades)),
+			fmt.Sprintf("%.1f", r.Result.WinRatePct),
+		)
+		table = append(table, rec)
+	}
+	return table
+}
+
+func WriteSweepCSV(path string, rows []SweepRow, keys []string) error {
+	return writeCSVFile(path, sweepTable(rows, keys))
+}
+
+var sweepHTML = template.Must(template.New("sweep").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Parameter sweep</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #f0f0f0; }
+tr.TEST td { background: #eef7ee; font-weight: bold; }
+</style></head><body>
+<h1>Parameter sweep</h1>
+<table>
+<tr>{{range index .Rows 0}}<th>{{.}}</th>{{end}}</tr>
+{{range $i, $r := .Rows}}{{if $i}}<tr class="{{index $r 1}}">{{range $r}}<td>{{.}}</td>{{end}}</tr>
+{{end}}{{end}}</table>
+</body></html>
+`))
+
+func WriteSweepHTML(path string, rows []SweepRow, keys []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := sweepHTML.Execute(f, struct{ Rows [][]string }{sweepTable(rows, keys)}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,283 @@
+// Package brief compiles the pre-market briefing: for each universe
+// symbol, the prior session's range and floor pivots, the results dates
+// and corporate actions coming up, and any exchange surveillance measure,
+// rendered as Markdown, HTML or a short chat message.
+package brief
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/corpactions"
+	"llm-trading-bot/internal/earnings"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/surveillance"
+	"llm-trading-bot/internal/symbols"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// Levels are the prior session's range and the classic floor pivots
+// derived from it.
+type Levels struct {
+	Day   string  `json:"day"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+	Pivot float64 `json:"pivot"`
+	R1    float64 `json:"r1"`
+	R2    float64 `json:"r2"`
+	S1    float64 `json:"s1"`
+	S2    float64 `json:"s2"`
+}
+
+// Event is something scheduled for a symbol within the lookahead.
+type Event struct {
+	Date time.Time `json:"date"`
+	What string    `json:"what"`
+}
+
+type Entry struct {
+	Symbol       string   `json:"symbol"`
+	Name         string   `json:"name,omitempty"`
+	Industry     string   `json:"industry,omitempty"`
+	Levels       *Levels  `json:"levels,omitempty"`
+	Events       []Event  `json:"events,omitempty"`
+	Surveillance []string `json:"surveillance,omitempty"`
+}
+
+// Briefing is the compiled briefing for one trading day.
+type Briefing struct {
+	Date    string  `json:"date"`
+	Entries []Entry `json:"entries"`
+}
+
+// Params are the data the briefing draws on; nil fields are skipped.
+type Params struct {
+	Store         *warehouse.Store // Prior session bars
+	Earnings      *earnings.Calendar
+	Actions       *corpactions.Adjuster
+	Surveillance  *surveillance.Monitor
+	LookaheadDays int // Events up to this many days after the briefing date
+}
+
+// lookback is how far back the prior session is searched for, covering
+// long weekends and holiday runs.
+const lookback = 10
+
+// Build compiles the briefing for the IST day of now.
+func Build(ctx context.Context, p Params, syms []string, now time.Time) *Briefing {
+	day := timeutil.Midnight(now)
+	b := &Briefing{Date: timeutil.DateKey(day)}
+	for _, sym := range syms {
+		e := Entry{Symbol: sym}
+		if info, ok := symbols.Get(sym); ok {
+			e.Name, e.Industry = info.Name, info.Industry
+		}
+		if p.Store != nil {
+			if bars, err := p.Store.Load(sym, day.AddDate(0, 0, -lookback), day.AddDate(0, 0, -1)); err == nil {
+				e.Levels = levels(bars)
+			}
+		}
+		e.Events = p.events(ctx, sym, day)
+		if p.Surveillance != nil {
+			for _, l := range p.Surveillance.Get(ctx, sym) {
+				e.Surveillance = append(e.Surveillance, l.String())
+			}
+		}
+		b.Entries = append(b.Entries, e)
+	}
+	return b
+}
+
+func (p Params) events(ctx context.Context, symbol string, day time.Time) []Event {
+	until := day.AddDate(0, 0, p.LookaheadDays)
+	var out []Event
+	if p.Earnings != nil {
+		if d, ok := p.Earnings.Next(ctx, symbol, day); ok && !d.After(until) {
+			out = append(out, Event{Date: d, What: "results"})
+		}
+	}
+	if p.Actions != nil {
+		for _, a := range p.Actions.Actions(ctx, symbol) {
+			if a.ExDate.Before(day) || a.ExDate.After(until) {
+				continue
+			}
+			what := "ex-date: " + strings.ToLower(a.Kind)
+			if a.Subject != "" {
+				what = "ex-date: " + a.Subject
+			}
+			out = append(out, Event{Date: a.ExDate, What: what})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
+
+// levels takes the last IST day in bars as the prior session.
+func levels(bars []types.Candle) *Levels {
+	if len(bars) == 0 {
+		return nil
+	}
+	last := timeutil.Midnight(time.Unix(bars[len(bars)-1].Ts, 0))
+	l := &Levels{Day: timeutil.DateKey(last)}
+	first := true
+	for _, c := range bars {
+		if !timeutil.Midnight(time.Unix(c.Ts, 0)).Equal(last) {
+			continue
+		}
+		if first || c.High > l.High {
+			l.High = c.High
+		}
+		if first || c.Low < l.Low {
+			l.Low = c.Low
+		}
+		l.Close = c.Close
+		first = false
+	}
+	l.Pivot = (l.High + l.Low + l.Close) / 3
+	l.R1 = 2*l.Pivot - l.Low
+	l.S1 = 2*l.Pivot - l.High
+	l.R2 = l.Pivot + (l.High - l.Low)
+	l.S2 = l.Pivot - (l.High - l.Low)
+	return l
+}
+
+// Flagged reports whether the entry has events or surveillance measures.
+func (e Entry) Flagged() bool {
+	return len(e.Events) > 0 || len(e.Surveillance) > 0
+}
+
+// Markdown renders the briefing with one section per symbol.
+func (b *Briefing) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Pre-market briefing %s\n", b.Date)
+	for _, e := range b.Entries {
+		fmt.Fprintf(&sb, "\n## %s", e.Symbol)
+		if e.Name != "" {
+			fmt.Fprintf(&sb, " - %s", e.Name)
+		}
+		sb.WriteString("\n\n")
+		if e.Industry != "" {
+			fmt.Fprintf(&sb, "- Industry: %s\n", e.Industry)
+		}
+		if l := e.Levels; l != nil {
+			fmt.Fprintf(&sb, "- Prior session %s: high %s, low %s, close %s\n", l.Day, money.Plain(l.High), money.Plain(l.Low), money.Plain(l.Close))
+			fmt.Fprintf(&sb, "- Pivots: S2 %s, S1 %s, P %s, R1 %s, R2 %s\n", money.Plain(l.S2), money.Plain(l.S1), money.Plain(l.Pivot), money.Plain(l.R1), money.Plain(l.R2))
+		} else {
+			sb.WriteString("- Prior session: no stored bars\n")
+		}
+		for _, ev := range e.Events {
+			fmt.Fprintf(&sb, "- %s: %s\n", ev.Date.Format("Mon 2006-01-02"), ev.What)
+		}
+		if len(e.Surveillance) > 0 {
+			fmt.Fprintf(&sb, "- Surveillance: %s\n", strings.Join(e.Surveillance, ", "))
+		}
+	}
+	return sb.String()
+}
+
+var htmlTmpl = template.Must(template.New("brief").Funcs(template.FuncMap{
+	"amount": money.Plain,
+	"date":   func(t time.Time) string { return t.Format("Mon 2006-01-02") },
+	"join":   strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Pre-market briefing {{.Date}}</title>
+<style>
+body{font-family:sans-serif;margin:2em}
+table{border-collapse:collapse}
+th,td{border:1px solid #ccc;padding:4px 8px;text-align:right}
+th:first-child,td:first-child,td.text{text-align:left}
+tr.flagged{background:#fff4e0}
+</style></head><body>
+<h1>Pre-market briefing {{.Date}}</h1>
+<table>
+<tr><th>Symbol</th><th>Prior close</th><th>High</th><th>Low</th><th>S2</th><th>S1</th><th>P</th><th>R1</th><th>R2</th><th>Events</th><th>Surveillance</th></tr>
+{{range .Entries}}<tr{{if .Flagged}} class="flagged"{{end}}>
+<td>{{.Symbol}}{{if .Name}}<br><small>{{.Name}}{{if .Industry}} · {{.Industry}}{{end}}</small>{{end}}</td>
+{{with .Levels}}<td>{{amount .Close}}</td><td>{{amount .High}}</td><td>{{amount .Low}}</td><td>{{amount .S2}}</td><td>{{amount .S1}}</td><td>{{amount .Pivot}}</td><td>{{amount .R1}}</td><td>{{amount .R2}}</td>{{else}}<td colspan="8" class="text">no stored bars</td>{{end}}
+<td class="text">{{range .Events}}{{date .Date}}: {{.What}}<br>{{end}}</td>
+<td class="text">{{join .Surveillance ", "}}</td>
+</tr>
+{{end}}</table>
+</body></html>
+`))
+
+// HTML renders the briefing as a standalone page with one table row per
+// symbol; rows with events or surveillance measures are highlighted.
+func (b *Briefing) HTML() (string, error) {
+	var sb strings.Builder
+	if err := htmlTmpl.Execute(&sb, b); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Write saves the Markdown and HTML briefing as <dir>/<date>.md and
+// <dir>/<date>.html and returns their paths.
+func (b *Briefing) Write(dir string) ([]string, error) {
+	page, err := b.HTML()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for ext, body := range map[string]string{".md": b.Markdown(), ".html": page} {
+		path := filepath.Join(dir, b.Date+ext)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// maxText keeps chat messages under Telegram's 4096 character limit.
+const maxText = 3500
+
+// Text renders a short chat message: symbols with events or surveillance
+// measures, then the prior close and pivot of the rest.
+func (b *Briefing) Text() string {
+	var flagged, plain []string
+	for _, e := range b.Entries {
+		var parts []string
+		for _, ev := range e.Events {
+			parts = append(parts, ev.Date.Format("02 Jan")+" "+ev.What)
+		}
+		parts = append(parts, e.Surveillance...)
+		if l := e.Levels; l != nil {
+			parts = append(parts, fmt.Sprintf("close %s P %s", money.Plain(l.Close), money.Plain(l.Pivot)))
+		}
+		line := e.Symbol
+		if len(parts) > 0 {
+			line += ": " + strings.Join(parts, " | ")
+		}
+		if e.Flagged() {
+			flagged = append(flagged, line)
+		} else {
+			plain = append(plain, line)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Pre-market briefing %s: %d symbols, %d flagged", b.Date, len(b.Entries), len(flagged))
+	lines := append(flagged, plain...)
+	for i, line := range lines {
+		if sb.Len()+len(line) > maxText {
+			fmt.Fprintf(&sb, "\n... %d more in the full briefing", len(lines)-i)
+			break
+		}
+		sb.WriteString("\n" + line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,298 @@
+package alpaca
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+)
+
+const (
+	defaultTradingURL = "https://paper-api.alpaca.markets"
+	defaultDataURL    = "https://data.alpaca.markets"
+	defaultStreamURL  = "wss://stream.data.alpaca.markets/v2"
+)
+
+type Params struct {
+	Mode         string
+	KeyID        string
+	SecretKey    string
+	TradingURL   string // paper-api (default) or api.alpaca.markets for live money
+	DataURL      string
+	StreamURL    string
+	Feed         string // iex (free) | sip
+	CandleSource string
+	DryRunFunds  float64
+
+	CandleInterval int // Bar size in minutes
+}
+
+// Alpaca trades US equities through Alpaca's trading API, reads bars and
+// quotes from the market data API and streams trades over websocket.
+type Alpaca struct {
+	p      Params
+	client *http.Client
+	stream *stream
+}
+
+var _ interfaces.Broker = (*Alpaca)(nil)
+
+func NewAlpaca(p Params) *Alpaca {
+	if p.TradingURL == "" {
+		p.TradingURL = defaultTradingURL
+	}
+	if p.DataURL == "" {
+		p.DataURL = defaultDataURL
+	}
+	if p.StreamURL == "" {
+		p.StreamURL = defaultStreamURL
+	}
+	if p.Feed == "" {
+		p.Feed = "iex"
+	}
+	if p.CandleInterval <= 0 {
+		p.CandleInterval = 1
+	}
+
+	a := &Alpaca{
+		p:      p,
+		client: &http.Client{Timeout: 15 * time.Second, Transport: api.Transport()},
+	}
+	if p.CandleSource == "LIVE" {
+		a.stream = newStream(p.StreamURL+"/"+p.Feed, p.KeyID, p.SecretKey, p.CandleInterval)
+	}
+	return a
+}
+
+func (a *Alpaca) LTP(ctx context.Context, symbol string) (float64, error) {
+	var out struct {
+		Trade struct {
+			Price float64 `json:"p"`
+		} `json:"trade"`
+	}
+	q := url.Values{"feed": {a.p.Feed}}
+	if err := a.do(ctx, http.MethodGet, a.p.DataURL, "/v2/stocks/"+url.PathEscape(symbol)+"/trades/latest", q, nil, &out); err != nil {
+		return 0, fmt.Errorf("failed to fetch latest trade for %s: %w", symbol, err)
+	}
+	return out.Trade.Price, nil
+}
+
+func (a *Alpaca) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	var out struct {
+		Quote struct {
+			Ask     float64   `json:"ap"`
+			AskSize int       `json:"as"`
+			Bid     float64   `json:"bp"`
+			BidSize int       `json:"bs"`
+			Ts      time.Time `json:"t"`
+		} `json:"quote"`
+	}
+	q := url.Values{"feed": {a.p.Feed}}
+	if err := a.do(ctx, http.MethodGet, a.p.DataURL, "/v2/stocks/"+url.PathEscape(symbol)+"/quotes/latest", q, nil, &out); err != nil {
+		return types.Quote{}, fmt.Errorf("failed to fetch quote for %s: %w", symbol, err)
+	}
+
+	ltp, err := a.LTP(ctx, symbol)
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	// Alpaca only publishes the top of book
+	qt := out.Quote
+	return types.Quote{
+		Symbol: symbol,
+		LTP:    ltp,
+		Bid:    qt.Bid,
+		Ask:    qt.Ask,
+		Bids:   []types.DepthLevel{{Price: qt.Bid, Qty: qt.BidSize}},
+		Asks:   []types.DepthLevel{{Price: qt.Ask, Qty: qt.AskSize}},
+		Ts:     qt.Ts.Unix(),
+	}, nil
+}
+
+func (a *Alpaca) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	var out struct {
+		Bars []struct {
+			Ts    time.Time `json:"t"`
+			Open  float64   `json:"o"`
+			High  float64   `json:"h"`
+			Low   float64   `json:"l"`
+			Close float64   `json:"c"`
+			Vol   float64   `json:"v"`
+		} `json:"bars"`
+	}
+
+	// US regular session is 390 minutes; cover weekends and holidays
+	barsPerDay := 390 / a.p.CandleInterval
+	days := n/barsPerDay + 5
+	q := url.Values{
+		"timeframe": {fmt.Sprintf("%dMin", a.p.CandleInterval)},
+		"start":     {time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)},
+		"limit":     {strconv.Itoa(n)},
+		"sort":      {"desc"},
+		"feed":      {a.p.Feed},
+	}
+	if err := a.do(ctx, http.MethodGet, a.p.DataURL, "/v2/stocks/"+url.PathEscape(symbol)+"/bars", q, nil, &out); err != nil {
+		return nil, fmt.Errorf("failed to fetch bars for %s: %w", symbol, err)
+	}
+
+	// Newest-first from the API; the engine expects oldest-first
+	candles := make([]types.Candle, len(out.Bars))
+	for i, b := range out.Bars {
+		candles[len(out.Bars)-1-i] = types.Candle{
+			Ts:    b.Ts.Unix(),
+			Open:  b.Open,
+			High:  b.High,
+			Low:   b.Low,
+			Close: b.Close,
+			Vol:   b.Vol,
+		}
+	}
+	return candles, nil
+}
+
+func (a *Alpaca) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	if a.p.Mode == "DRY_RUN" {
+		return types.OrderResp{
+			OrderID: fmt.Sprintf("SIM-%d", time.Now().UnixNano()),
+			Status:  "SIMULATED",
+			Message: "dry-run",
+		}, nil
+	}
+
+	if a.p.KeyID == "" || a.p.SecretKey == "" {
+		return types.OrderResp{}, errors.New("missing Alpaca API key/secret")
+	}
+
+	body := map[string]any{
+		"symbol":        req.Symbol,
+		"qty":           strconv.Itoa(req.Qty),
+		"side":          strings.ToLower(req.Side),
+		"type":          "market",
+		"time_in_force": "day",
+	}
+	if req.Type == "LIMIT" {
+		body["type"] = "limit"
+		body["limit_price"] = strconv.FormatFloat(req.Price, 'f', 2, 64)
+	}
+	if req.Tag != "" {
+		body["client_order_id"] = fmt.Sprintf("%s-%d", req.Tag, time.Now().UnixNano())
+	}
+
+	var out struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := a.do(ctx, http.MethodPost, a.p.TradingURL, "/v2/orders", nil, body, &out); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return types.OrderResp{
+		OrderID: out.ID,
+		Status:  strings.ToUpper(out.Status),
+		Message: "ok",
+	}, nil
+}
+
+func (a *Alpaca) Funds(ctx context.Context) (types.Funds, error) {
+	if a.p.Mode == "DRY_RUN" {
+		return types.Funds{
+			Available: a.p.DryRunFunds,
+			Net:       a.p.DryRunFunds,
+		}, nil
+	}
+
+	var acct struct {
+		BuyingPower     string `json:"buying_power"`
+		Equity          string `json:"equity"`
+		LongMarketValue string `json:"long_market_value"`
+	}
+	if err := a.do(ctx, http.MethodGet, a.p.TradingURL, "/v2/account", nil, nil, &acct); err != nil {
+		return types.Funds{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	// Alpaca reports money as decimal strings
+	available, _ := strconv.ParseFloat(acct.BuyingPower, 64)
+	net, _ := strconv.ParseFloat(acct.Equity, 64)
+	utilised, _ := strconv.ParseFloat(acct.LongMarketValue, 64)
+	return types.Funds{
+		Available: available,
+		Utilised:  utilised,
+		Net:       net,
+	}, nil
+}
+
+func (a *Alpaca) Ticks() <-chan types.Tick {
+	if a.stream == nil {
+		return nil
+	}
+	return a.stream.ticks
+}
+
+func (a *Alpaca) Start(ctx context.Context, symbols []string) error {
+	if a.stream == nil {
+		return nil
+	}
+	return a.stream.start(ctx, symbols)
+}
+
+func (a *Alpaca) Stop(ctx context.Context) {
+	if a.stream != nil {
+		a.stream.stop()
+	}
+}
+
+// do sends an authenticated request and decodes the JSON response into out.
+func (a *Alpaca) do(ctx context.Context, method, base, path string, q url.Values, body, out any) error {
+	u := base + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("APCA-API-KEY-ID", a.p.KeyID)
+	req.Header.Set("APCA-API-SECRET-KEY", a.p.SecretKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := api.CheckStatus(resp); err != nil {
+		return fmt.Errorf("alpaca %s %s: %w", method, path, err)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("alpaca %s %s: %w: %w", method, path, api.ErrParse, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package alpaca
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"llm-trading-bot/internal/types"
+)
+
+type alpacaOrder struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	Qty            string `json:"qty"`
+	FilledQty      string `json:"filled_qty"`
+	FilledAvgPrice string `json:"filled_avg_price"`
+}
+
+func (a *Alpaca) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	if a.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run modify"}, nil
+	}
+
+	body := map[string]any{}
+	if mod.Qty > 0 {
+		body["qty"] = strconv.Itoa(mod.Qty)
+	}
+	if mod.Price > 0 {
+		body["limit_price"] = strconv.FormatFloat(mod.Price, 'f', 2, 64)
+	}
+	if mod.TriggerPrice > 0 {
+		body["stop_price"] = strconv.FormatFloat(mod.TriggerPrice, 'f', 2, 64)
+	}
+
+	// Alpaca replaces the order and returns the replacement with a new id
+	var out alpacaOrder
+	if err := a.do(ctx, http.MethodPatch, a.p.TradingURL, "/v2/orders/"+url.PathEscape(orderID), nil, body, &out); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to modify order %s: %w", orderID, err)
+	}
+	return types.OrderResp{OrderID: out.ID, Status: "MODIFIED", Message: "replaces " + orderID}, nil
+}
+
+func (a *Alpaca) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	if a.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run cancel"}, nil
+	}
+
+	if err := a.do(ctx, http.MethodDelete, a.p.TradingURL, "/v2/orders/"+url.PathEscape(orderID), nil, nil, nil); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return types.OrderResp{OrderID: orderID, Status: types.OrderCancelled, Message: "ok"}, nil
+}
+
+func (a *Alpaca) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	if a.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderStatus{OrderID: orderID, Status: types.OrderComplete, Message: "dry-run"}, nil
+	}
+
+	var out alpacaOrder
+	if err := a.do(ctx, http.MethodGet, a.p.TradingURL, "/v2/orders/"+url.PathEscape(orderID), nil, nil, &out); err != nil {
+		return types.OrderStatus{}, fmt.Errorf("failed to fetch order %s: %w", orderID, err)
+	}
+
+	qty, _ := strconv.Atoi(out.Qty)
+	filled, _ := strconv.Atoi(out.FilledQty)
+	avg, _ := strconv.ParseFloat(out.FilledAvgPrice, 64)
+
+	status := alpacaStatus(out.Status)
+	pending := qty - filled
+	if status != types.OrderOpen && status != types.OrderTriggerPending {
+		pending = 0
+	}
+	return types.OrderStatus{
+		OrderID:    out.ID,
+		Status:     status,
+		FilledQty:  filled,
+		PendingQty: pending,
+		AvgPrice:   avg,
+		Message:    out.Status,
+	}, nil
+}
+
+func alpacaStatus(status string) string {
+	switch status {
+	case "filled":
+		return types.OrderComplete
+	case "canceled", "expired", "replaced", "done_for_day":
+		return types.OrderCancelled
+	case "rejected":
+		return types.OrderRejected
+	case "held":
+		return types.OrderTriggerPending
+	default:
+		return types.OrderOpen
+	}
+}
+
+func isSimulated(orderID string) bool {
+	return strings.HasPrefix(orderID, "SIM-")
+}
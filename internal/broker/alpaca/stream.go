@@ -0,0 +1,176 @@
+package alpaca
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/crash"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	tickBufferSize    = 1024
+	maxReconnectDelay = time.Minute
+)
+
+// streamMsg is one element of the JSON array Alpaca sends per frame.
+type streamMsg struct {
+	Type   string    `json:"T"`
+	Symbol string    `json:"S"`
+	Price  float64   `json:"p"` // Trades
+	Close  float64   `json:"c"` // Bars
+	Ts     time.Time `json:"t"`
+	Msg    string    `json:"msg"`
+}
+
+// stream subscribes to trades and minute bars on Alpaca's market data
+// websocket and republishes them as ticks, reconnecting with backoff.
+type stream struct {
+	url             string
+	keyID, secret   string
+	intervalMinutes int
+
+	ticks chan types.Tick
+
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	cancel context.CancelFunc
+}
+
+func newStream(url, keyID, secret string, intervalMinutes int) *stream {
+	return &stream{
+		url:             url,
+		keyID:           keyID,
+		secret:          secret,
+		intervalMinutes: intervalMinutes,
+		ticks:           make(chan types.Tick, tickBufferSize),
+	}
+}
+
+func (s *stream) start(ctx context.Context, symbols []string) error {
+	conn, err := s.connect(ctx, symbols)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.mu.Lock()
+	s.conn, s.cancel = conn, cancel
+	s.mu.Unlock()
+
+	go s.run(ctx, conn, symbols)
+	return nil
+}
+
+func (s *stream) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
+
+func (s *stream) connect(ctx context.Context, symbols []string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to alpaca stream: %w", err)
+	}
+
+	if err := conn.WriteJSON(map[string]any{"action": "auth", "key": s.keyID, "secret": s.secret}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Server replies "connected" on open and "authenticated" after auth
+	for i := 0; i < 2; i++ {
+		var msgs []streamMsg
+		if err := conn.ReadJSON(&msgs); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.Type == "error" {
+				conn.Close()
+				return nil, errors.New("alpaca stream auth failed: " + m.Msg)
+			}
+		}
+	}
+
+	if err := conn.WriteJSON(map[string]any{"action": "subscribe", "trades": symbols, "bars": symbols}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// run reads frames until the connection drops, then reconnects and
+// resubscribes until stopped.
+func (s *stream) run(ctx context.Context, conn *websocket.Conn, symbols []string) {
+	delay := time.Second
+	for {
+		if crash.Guard(ctx, "alpaca.stream", func() { s.read(conn) }) {
+			conn.Close() // Reconnect as if it had dropped
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Warn(ctx, "Alpaca stream disconnected - reconnecting", "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		next, err := s.connect(ctx, symbols)
+		if err != nil {
+			logger.Warn(ctx, "Alpaca stream reconnect failed", "error", err)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+		delay = time.Second
+		conn = next
+		s.mu.Lock()
+		s.conn = conn
+		s.mu.Unlock()
+	}
+}
+
+func (s *stream) read(conn *websocket.Conn) {
+	defer conn.Close()
+	for {
+		var msgs []streamMsg
+		if err := conn.ReadJSON(&msgs); err != nil {
+			return
+		}
+		for _, m := range msgs {
+			switch m.Type {
+			case "t":
+				s.publish(types.Tick{Symbol: m.Symbol, Price: m.Price, Ts: m.Ts.Unix()})
+			case "b":
+				// Minute bars are stamped with their start; only report the
+				// close of a bar at the configured interval
+				end := m.Ts.Unix() + 60
+				if end%int64(s.intervalMinutes*60) == 0 {
+					s.publish(types.Tick{Symbol: m.Symbol, Price: m.Close, Ts: m.Ts.Unix(), BarClose: true})
+				}
+			}
+		}
+	}
+}
+
+func (s *stream) publish(tick types.Tick) {
+	select {
+	case s.ticks <- tick:
+	default:
+		// Consumer is behind; drop rather than block the reader
+	}
+}
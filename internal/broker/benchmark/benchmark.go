@@ -0,0 +1,79 @@
+// Package benchmark serves index candles (NIFTY 50, sector indices) used
+// to measure how a stock performs relative to its market.
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// cacheTTL bounds how often an index is refetched while stepping through
+// the universe; every symbol in a poll shares one fetch.
+const cacheTTL = 30 * time.Second
+
+// Feed fetches benchmark candles through the broker's candle API, so any
+// broker that can quote the index symbol (e.g. "NSE:NIFTY 50") works.
+type Feed struct {
+	broker  interfaces.Broker
+	index   string
+	sectors map[string]string // Symbol -> sector index
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	candles   []types.Candle
+	fetchedAt time.Time
+}
+
+func New(broker interfaces.Broker, index string, sectors map[string]string) *Feed {
+	normalized := make(map[string]string, len(sectors))
+	for sym, idx := range sectors {
+		normalized[types.NormalizeSymbol(sym)] = types.NormalizeSymbol(idx)
+	}
+	return &Feed{
+		broker:  broker,
+		index:   types.NormalizeSymbol(index),
+		sectors: normalized,
+		cache:   make(map[string]entry),
+	}
+}
+
+// IndexFor returns the benchmark for a symbol: its sector index when one
+// is configured, otherwise the broad market index.
+func (f *Feed) IndexFor(symbol string) string {
+	if idx, ok := f.sectors[symbol]; ok {
+		return idx
+	}
+	return f.index
+}
+
+// Candles returns up to n recent candles of the symbol's benchmark.
+func (f *Feed) Candles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	idx := f.IndexFor(symbol)
+
+	f.mu.Lock()
+	e, ok := f.cache[idx]
+	f.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < cacheTTL && len(e.candles) >= n {
+		return e.candles[len(e.candles)-n:], nil
+	}
+
+	candles, err := f.broker.RecentCandles(ctx, idx, n)
+	if err != nil {
+		return nil, err
+	}
+	// Stored so the EOD summary can compare the equity curve with the index
+	_ = warehouse.Put(idx, candles)
+
+	f.mu.Lock()
+	f.cache[idx] = entry{candles: candles, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	return candles, nil
+}
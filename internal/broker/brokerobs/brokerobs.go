@@ -3,9 +3,11 @@ package brokerobs
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"llm-trading-bot/internal/interfaces"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
 	"llm-trading-bot/internal/trace"
 	"llm-trading-bot/internal/types"
 )
@@ -14,7 +16,10 @@ type observableBroker struct {
 	broker interfaces.Broker
 }
 
-var _ interfaces.Broker = (*observableBroker)(nil)
+var (
+	_ interfaces.Broker     = (*observableBroker)(nil)
+	_ interfaces.StopBroker = (*observableBroker)(nil)
+)
 
 func Wrap(broker interfaces.Broker) interfaces.Broker {
 	return &observableBroker{
@@ -38,13 +43,36 @@ func (ob *observableBroker) LTP(ctx context.Context, symbol string) (float64, er
 	return price, nil
 }
 
+func (ob *observableBroker) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "broker.GetQuote")
+	defer span.End()
+
+	logger.DebugSkip(ctx, 1, "Fetching quote", "symbol", symbol)
+
+	quote, err := ob.broker.GetQuote(ctx, symbol)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to fetch quote", err, "symbol", symbol)
+		return types.Quote{}, err
+	}
+
+	logger.DebugSkip(ctx, 1, "Quote fetched successfully",
+		"symbol", symbol,
+		"bid", quote.Bid,
+		"ask", quote.Ask,
+		"spread_bps", quote.SpreadBps(),
+	)
+	return quote, nil
+}
+
 func (ob *observableBroker) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
 	ctx, span := trace.StartSpan(ctx, "broker.RecentCandles")
 	defer span.End()
 
 	logger.DebugSkip(ctx, 1, "Fetching recent candles", "symbol", symbol, "count", n)
 
+	start := time.Now()
 	candles, err := ob.broker.RecentCandles(ctx, symbol, n)
+	metrics.BrokerSeconds.Observe(time.Since(start).Seconds(), "RecentCandles")
 	if err != nil {
 		logger.ErrorWithErrSkip(ctx, 1, "Failed to fetch candles", err, "symbol", symbol, "count", n)
 		return nil, err
@@ -65,8 +93,11 @@ func (ob *observableBroker) PlaceOrder(ctx context.Context, req types.OrderReq)
 		"tag", req.Tag,
 	)
 
+	start := time.Now()
 	resp, err := ob.broker.PlaceOrder(ctx, req)
+	metrics.BrokerSeconds.Observe(time.Since(start).Seconds(), "PlaceOrder")
 	if err != nil {
+		metrics.BrokerOrders.Inc(req.Side, "error")
 		logger.ErrorWithErrSkip(ctx, 1, "Failed to place order", err,
 			"symbol", req.Symbol,
 			"side", req.Side,
@@ -75,6 +106,7 @@ func (ob *observableBroker) PlaceOrder(ctx context.Context, req types.OrderReq)
 		return types.OrderResp{}, err
 	}
 
+	metrics.BrokerOrders.Inc(req.Side, "ok")
 	logger.InfoSkip(ctx, 1, "Order placed successfully",
 		"symbol", req.Symbol,
 		"order_id", resp.OrderID,
@@ -83,6 +115,154 @@ func (ob *observableBroker) PlaceOrder(ctx context.Context, req types.OrderReq)
 	return resp, nil
 }
 
+func (ob *observableBroker) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	ctx, span := trace.StartSpan(ctx, "broker.ModifyOrder")
+	defer span.End()
+
+	logger.InfoSkip(ctx, 1, "Modifying order",
+		"order_id", orderID,
+		"qty", mod.Qty,
+		"price", mod.Price,
+		"trigger_price", mod.TriggerPrice,
+	)
+
+	resp, err := ob.broker.ModifyOrder(ctx, orderID, mod)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to modify order", err, "order_id", orderID)
+		return types.OrderResp{}, err
+	}
+
+	logger.InfoSkip(ctx, 1, "Order modified successfully", "order_id", resp.OrderID, "status", resp.Status)
+	return resp, nil
+}
+
+func (ob *observableBroker) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	ctx, span := trace.StartSpan(ctx, "broker.CancelOrder")
+	defer span.End()
+
+	logger.InfoSkip(ctx, 1, "Cancelling order", "order_id", orderID)
+
+	resp, err := ob.broker.CancelOrder(ctx, orderID)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to cancel order", err, "order_id", orderID)
+		return types.OrderResp{}, err
+	}
+
+	logger.InfoSkip(ctx, 1, "Order cancelled successfully", "order_id", resp.OrderID, "status", resp.Status)
+	return resp, nil
+}
+
+func (ob *observableBroker) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	ctx, span := trace.StartSpan(ctx, "broker.GetOrderStatus")
+	defer span.End()
+
+	logger.DebugSkip(ctx, 1, "Fetching order status", "order_id", orderID)
+
+	status, err := ob.broker.GetOrderStatus(ctx, orderID)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to fetch order status", err, "order_id", orderID)
+		return types.OrderStatus{}, err
+	}
+
+	logger.DebugSkip(ctx, 1, "Order status fetched successfully",
+		"order_id", orderID,
+		"status", status.Status,
+		"filled_qty", status.FilledQty,
+	)
+	return status, nil
+}
+
+func (ob *observableBroker) PlaceStop(ctx context.Context, req types.StopReq) (string, error) {
+	sb, ok := ob.broker.(interfaces.StopBroker)
+	if !ok {
+		return "", interfaces.ErrStopsUnsupported
+	}
+
+	ctx, span := trace.StartSpan(ctx, "broker.PlaceStop")
+	defer span.End()
+
+	logger.InfoSkip(ctx, 1, "Placing server-side stop",
+		"symbol", req.Symbol,
+		"qty", req.Qty,
+		"trigger_price", req.TriggerPrice,
+		"limit_price", req.LimitPrice,
+	)
+
+	id, err := sb.PlaceStop(ctx, req)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to place server-side stop", err, "symbol", req.Symbol)
+		return "", err
+	}
+
+	logger.InfoSkip(ctx, 1, "Server-side stop placed", "symbol", req.Symbol, "stop_id", id)
+	return id, nil
+}
+
+func (ob *observableBroker) ModifyStop(ctx context.Context, stopID string, req types.StopReq) error {
+	sb, ok := ob.broker.(interfaces.StopBroker)
+	if !ok {
+		return interfaces.ErrStopsUnsupported
+	}
+
+	ctx, span := trace.StartSpan(ctx, "broker.ModifyStop")
+	defer span.End()
+
+	logger.DebugSkip(ctx, 1, "Modifying server-side stop",
+		"symbol", req.Symbol,
+		"stop_id", stopID,
+		"qty", req.Qty,
+		"trigger_price", req.TriggerPrice,
+	)
+
+	if err := sb.ModifyStop(ctx, stopID, req); err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to modify server-side stop", err, "symbol", req.Symbol, "stop_id", stopID)
+		return err
+	}
+	return nil
+}
+
+func (ob *observableBroker) CancelStop(ctx context.Context, stopID string) error {
+	sb, ok := ob.broker.(interfaces.StopBroker)
+	if !ok {
+		return interfaces.ErrStopsUnsupported
+	}
+
+	ctx, span := trace.StartSpan(ctx, "broker.CancelStop")
+	defer span.End()
+
+	logger.InfoSkip(ctx, 1, "Cancelling server-side stop", "stop_id", stopID)
+
+	if err := sb.CancelStop(ctx, stopID); err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to cancel server-side stop", err, "stop_id", stopID)
+		return err
+	}
+	return nil
+}
+
+func (ob *observableBroker) Funds(ctx context.Context) (types.Funds, error) {
+	ctx, span := trace.StartSpan(ctx, "broker.Funds")
+	defer span.End()
+
+	logger.DebugSkip(ctx, 1, "Fetching available funds")
+
+	funds, err := ob.broker.Funds(ctx)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Failed to fetch funds", err)
+		return types.Funds{}, err
+	}
+
+	logger.DebugSkip(ctx, 1, "Funds fetched successfully",
+		"available", funds.Available,
+		"utilised", funds.Utilised,
+		"net", funds.Net,
+	)
+	return funds, nil
+}
+
+func (ob *observableBroker) Ticks() <-chan types.Tick {
+	return ob.broker.Ticks()
+}
+
 func (ob *observableBroker) Start(ctx context.Context, symbols []string) error {
 	ctx, span := trace.StartSpan(ctx, "broker.Start")
 	defer span.End()
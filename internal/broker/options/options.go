@@ -0,0 +1,249 @@
+// Package options fetches NSE option chains for stocks and summarizes
+// them (ATM implied volatility, put/call open-interest ratio) for the
+// decider and the hedging rules.
+package options
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+const chainURL = "https://www.nseindia.com/api/option-chain-equities?symbol="
+
+// Leg is one side (call or put) of a strike.
+type Leg struct {
+	LTP float64 `json:"lastPrice"`
+	IV  float64 `json:"impliedVolatility"` // Annualized, in %
+	OI  float64 `json:"openInterest"`
+}
+
+type Strike struct {
+	Expiry time.Time
+	Strike float64
+	Call   *Leg
+	Put    *Leg
+}
+
+// Chain is a snapshot of a stock's listed options.
+type Chain struct {
+	Underlying string
+	Spot       float64
+	Expiries   []time.Time // Ascending
+	Strikes    []Strike    // By expiry, then strike
+}
+
+// Summary condenses the nearest expiry of a chain.
+type Summary struct {
+	Expiry    string  `json:"expiry"`
+	ATMStrike float64 `json:"atm_strike"`
+	ATMIV     float64 `json:"atm_iv"`  // Mean of the ATM call and put IVs, in %
+	PCR       float64 `json:"pcr"`     // Put OI / call OI over the expiry
+	PutIV     float64 `json:"put_iv"`  // ATM put IV
+	CallIV    float64 `json:"call_iv"` // ATM call IV
+}
+
+// Feed fetches chains from NSE, caching each for ttl. It needs the
+// nseindia.com session cookies set up by api.warm_up.
+type Feed struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	chain     *Chain
+	fetchedAt time.Time
+}
+
+func NewFeed(ttl time.Duration) *Feed {
+	return &Feed{ttl: ttl, cache: make(map[string]entry)}
+}
+
+// Chain returns the option chain of an NSE stock.
+func (f *Feed) Chain(ctx context.Context, symbol string) (*Chain, error) {
+	exchange, sym := types.SplitSymbol(symbol, types.ExchangeNSE)
+	if exchange != types.ExchangeNSE {
+		return nil, fmt.Errorf("no option chain for %s: only NSE stocks are supported", symbol)
+	}
+
+	f.mu.Lock()
+	e, ok := f.cache[sym]
+	f.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < f.ttl {
+		return e.chain, nil
+	}
+
+	chain, err := fetchChain(ctx, sym)
+	if err != nil {
+		if ok && api.Temporary(err) {
+			logger.Warn(ctx, "Option chain fetch failed - using stale chain", "symbol", sym, "age", time.Since(e.fetchedAt).Round(time.Second), "error", err)
+			return e.chain, nil
+		}
+		return nil, err
+	}
+	f.mu.Lock()
+	f.cache[sym] = entry{chain: chain, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	return chain, nil
+}
+
+func fetchChain(ctx context.Context, sym string) (chain *Chain, err error) {
+	var usable, total int
+	defer func(start time.Time) { quality.Record("options.nse", start, err, usable, total) }(time.Now())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, chainURL+url.QueryEscape(sym), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("nse: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return nil, fmt.Errorf("nse: option chain for %s: %w", sym, err)
+	}
+
+	var raw struct {
+		Records struct {
+			Underlying float64 `json:"underlyingValue"`
+			Data       []struct {
+				Strike float64 `json:"strikePrice"`
+				Expiry string  `json:"expiryDate"`
+				CE     *Leg    `json:"CE"`
+				PE     *Leg    `json:"PE"`
+			} `json:"data"`
+		} `json:"records"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("nse: decode option chain: %w: %w", api.ErrParse, err)
+	}
+	if len(raw.Records.Data) == 0 {
+		return nil, fmt.Errorf("nse: %s has no listed options", sym)
+	}
+
+	chain = &Chain{Underlying: sym, Spot: raw.Records.Underlying}
+	seen := make(map[time.Time]bool)
+	total = len(raw.Records.Data)
+	for _, d := range raw.Records.Data {
+		exp, perr := time.ParseInLocation("02-Jan-2006", d.Expiry, timeutil.IST)
+		if perr != nil {
+			continue
+		}
+		if d.Strike > 0 && (d.CE != nil || d.PE != nil) {
+			usable++
+		}
+		if !seen[exp] {
+			seen[exp] = true
+			chain.Expiries = append(chain.Expiries, exp)
+		}
+		chain.Strikes = append(chain.Strikes, Strike{Expiry: exp, Strike: d.Strike, Call: d.CE, Put: d.PE})
+	}
+	sort.Slice(chain.Expiries, func(i, j int) bool { return chain.Expiries[i].Before(chain.Expiries[j]) })
+	sort.Slice(chain.Strikes, func(i, j int) bool {
+		a, b := chain.Strikes[i], chain.Strikes[j]
+		if !a.Expiry.Equal(b.Expiry) {
+			return a.Expiry.Before(b.Expiry)
+		}
+		return a.Strike < b.Strike
+	})
+	return chain, nil
+}
+
+// Expiry returns the first expiry at least minDays after now.
+func (c *Chain) Expiry(now time.Time, minDays int) (time.Time, bool) {
+	cutoff := now.AddDate(0, 0, minDays)
+	for _, exp := range c.Expiries {
+		if !exp.Before(cutoff) {
+			return exp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Summarize describes the first expiry at least minDays away.
+func (c *Chain) Summarize(now time.Time, minDays int) (Summary, bool) {
+	exp, ok := c.Expiry(now, minDays)
+	if !ok {
+		return Summary{}, false
+	}
+
+	var (
+		atm           *Strike
+		callOI, putOI float64
+		bestDist      = math.Inf(1)
+	)
+	for i := range c.Strikes {
+		s := &c.Strikes[i]
+		if !s.Expiry.Equal(exp) {
+			continue
+		}
+		if s.Call != nil {
+			callOI += s.Call.OI
+		}
+		if s.Put != nil {
+			putOI += s.Put.OI
+		}
+		if d := math.Abs(s.Strike - c.Spot); d < bestDist && s.Call != nil && s.Put != nil {
+			atm, bestDist = s, d
+		}
+	}
+	if atm == nil {
+		return Summary{}, false
+	}
+
+	sum := Summary{
+		Expiry:    exp.Format("2006-01-02"),
+		ATMStrike: atm.Strike,
+		CallIV:    atm.Call.IV,
+		PutIV:     atm.Put.IV,
+		ATMIV:     (atm.Call.IV + atm.Put.IV) / 2,
+	}
+	if callOI > 0 {
+		sum.PCR = math.Round(putOI/callOI*100) / 100
+	}
+	return sum, true
+}
+
+// Put returns the put at the highest strike at or below maxStrike for the
+// expiry, skipping strikes without a put quote.
+func (c *Chain) Put(exp time.Time, maxStrike float64) (Strike, bool) {
+	var best Strike
+	found := false
+	for _, s := range c.Strikes {
+		if s.Expiry.Equal(exp) && s.Put != nil && s.Put.LTP > 0 && s.Strike <= maxStrike {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// NFOSymbol returns the NSE F&O trading symbol of a monthly stock option
+// in the form brokers list it, e.g. "NFO:RELIANCE25OCT1400PE".
+func NFOSymbol(underlying string, exp time.Time, strike float64, kind string) string {
+	return fmt.Sprintf("NFO:%s%s%s%s%s",
+		underlying,
+		exp.Format("06"),
+		strings.ToUpper(exp.Format("Jan")),
+		strconv.FormatFloat(strike, 'f', -1, 64),
+		kind,
+	)
+}
@@ -0,0 +1,153 @@
+package paper
+
+import (
+	"context"
+	"sync"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+)
+
+// Feed shares one data feed between several paper books, so books
+// trading side by side see the same prices, candles and ticks over one
+// connection. The feed runs on the union of the books' symbols and stops
+// when the last book stops.
+type Feed struct {
+	feed interfaces.Broker
+
+	mu      sync.Mutex
+	running map[*feedView][]string // Started books and their symbols
+	live    bool                   // Feed started and not stopped since
+	symbols map[string]bool        // Symbols the feed is running on
+	src     <-chan types.Tick      // Feed stream currently fanned out
+	stopFan chan struct{}
+	views   []*feedView
+}
+
+// feedView is one book's handle on the shared feed. Market data calls go
+// straight to the feed; orders are never sent to it.
+type feedView struct {
+	interfaces.Broker
+	f     *Feed
+	ticks chan types.Tick
+}
+
+// Share wraps feed for use by several books; pass View() to each New.
+func Share(feed interfaces.Broker) *Feed {
+	return &Feed{
+		feed:    feed,
+		running: make(map[*feedView][]string),
+		symbols: make(map[string]bool),
+	}
+}
+
+// View returns a new book's handle on the feed, with its own copy of
+// every tick.
+func (f *Feed) View() interfaces.Broker {
+	v := &feedView{Broker: f.feed, f: f, ticks: make(chan types.Tick, 1024)}
+	f.mu.Lock()
+	f.views = append(f.views, v)
+	f.mu.Unlock()
+	return v
+}
+
+func (v *feedView) Ticks() <-chan types.Tick {
+	v.f.mu.Lock()
+	defer v.f.mu.Unlock()
+	v.f.fanOut()
+	return v.ticks
+}
+
+// Start restarts the feed only when the book trades a symbol the feed is
+// not yet running on.
+func (v *feedView) Start(ctx context.Context, symbols []string) error {
+	f := v.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.running[v] = symbols
+	missing := !f.live
+	for _, sym := range symbols {
+		if !f.symbols[sym] {
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	union := make(map[string]bool)
+	var all []string
+	for _, syms := range f.running {
+		for _, sym := range syms {
+			if !union[sym] {
+				union[sym] = true
+				all = append(all, sym)
+			}
+		}
+	}
+	if f.live {
+		f.feed.Stop(ctx)
+	}
+	f.symbols = union
+	if err := f.feed.Start(ctx, all); err != nil {
+		f.live = false
+		return err
+	}
+	f.live = true
+	f.fanOut()
+	return nil
+}
+
+// Stop stops the feed with the last running book.
+func (v *feedView) Stop(ctx context.Context) {
+	f := v.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.running[v]; !ok {
+		return
+	}
+	delete(f.running, v)
+	if len(f.running) == 0 {
+		f.feed.Stop(ctx)
+		f.live = false
+		f.symbols = make(map[string]bool)
+	}
+}
+
+// fanOut copies the feed's current stream into every view, replacing the
+// copier of a previous stream. Called with f.mu held.
+func (f *Feed) fanOut() {
+	src := f.feed.Ticks()
+	if src == nil || src == f.src {
+		return
+	}
+	if f.stopFan != nil {
+		close(f.stopFan)
+	}
+	f.src = src
+	f.stopFan = make(chan struct{})
+	go func(stop <-chan struct{}) {
+		for {
+			select {
+			case <-stop:
+				return
+			case tk, ok := <-src:
+				if !ok {
+					return
+				}
+				f.mu.Lock()
+				views := f.views
+				f.mu.Unlock()
+				for _, v := range views {
+					select {
+					case v.ticks <- tk:
+					default:
+						// This book is behind; drop rather than hold up the others
+					}
+				}
+			}
+		}
+	}(f.stopFan)
+}
@@ -0,0 +1,212 @@
+package paper
+
+import (
+	"context"
+	"math"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+type order struct {
+	id        string
+	symbol    string
+	side      string
+	orderType string
+	limit     float64
+	ref       float64 // Price used to reserve cash: the limit, or LTP at placement
+	qty       int
+	tag       string
+
+	filled    int
+	notional  float64 // Sum of fill price * qty
+	barsAlive int
+	cancelled bool
+}
+
+func (o *order) remaining() int {
+	return o.qty - o.filled
+}
+
+func (o *order) done() bool {
+	return o.cancelled || o.remaining() == 0
+}
+
+func (o *order) avgPrice() float64 {
+	if o.filled == 0 {
+		return 0
+	}
+	return o.notional / float64(o.filled)
+}
+
+func (o *order) status() string {
+	switch {
+	case o.remaining() == 0:
+		return "FILLED"
+	case o.cancelled && o.filled > 0:
+		return "PARTIAL_CANCELLED"
+	case o.cancelled:
+		return "CANCELLED"
+	case o.filled > 0:
+		return "PARTIAL"
+	default:
+		return "OPEN"
+	}
+}
+
+// matchBars runs resting orders for symbol against every candle newer than
+// the last one matched. Caller holds b.mu.
+func (b *Broker) matchBars(ctx context.Context, symbol string, candles []types.Candle) {
+	last := b.lastBar[symbol]
+	for _, c := range candles {
+		if c.Ts <= last {
+			continue
+		}
+		for _, o := range b.orders {
+			if o.symbol != symbol || o.done() {
+				continue
+			}
+			o.barsAlive++
+			if price, ok := b.barPrice(o, c); ok {
+				b.fillAt(ctx, o, price, c.Vol)
+			}
+			if !o.done() && o.orderType == "MARKET" && o.barsAlive >= b.p.MarketOrderTTL {
+				o.cancelled = true
+				logger.Warn(ctx, "Paper market order expired unfilled",
+					"order_id", o.id,
+					"symbol", o.symbol,
+					"filled", o.filled,
+					"qty", o.qty,
+				)
+			}
+		}
+		last = c.Ts
+	}
+	b.lastBar[symbol] = last
+	b.pruneOrders()
+}
+
+// barPrice returns the price an order trades at within bar c, if it trades.
+// Market orders take the open; limits fill at the open when it gaps through
+// the limit, otherwise at the limit once the range touches it.
+func (b *Broker) barPrice(o *order, c types.Candle) (float64, bool) {
+	if o.orderType == "MARKET" {
+		return c.Open, true
+	}
+	switch o.side {
+	case "BUY":
+		if c.Low > o.limit {
+			return 0, false
+		}
+		return math.Min(c.Open, o.limit), true
+	default:
+		if c.High < o.limit {
+			return 0, false
+		}
+		return math.Max(c.Open, o.limit), true
+	}
+}
+
+// fillAt fills as much of o as the bar volume allows at price, applying
+// slippage to market orders and settling cash and holdings.
+func (b *Broker) fillAt(ctx context.Context, o *order, price, barVol float64) {
+	if o.done() {
+		return
+	}
+
+	qty := o.remaining()
+	if b.p.MaxVolumePct > 0 && barVol > 0 {
+		if limit := int(barVol * b.p.MaxVolumePct / 100.0); limit < qty {
+			qty = limit
+		}
+	}
+	if qty <= 0 {
+		return
+	}
+
+	if o.orderType == "MARKET" {
+		slip := price * b.p.SlippageBps / 10000.0
+		if o.side == "BUY" {
+			price += slip
+		} else {
+			price -= slip
+		}
+	}
+
+	switch o.side {
+	case "BUY":
+		if cost := price * float64(qty); cost > b.cash {
+			qty = int(b.cash / price)
+			if qty <= 0 {
+				return
+			}
+		}
+		b.cash -= price * float64(qty)
+		h := b.holdings[o.symbol]
+		if h == nil {
+			h = &holding{}
+			b.holdings[o.symbol] = h
+		}
+		h.avg = (h.avg*float64(h.qty) + price*float64(qty)) / float64(h.qty+qty)
+		h.qty += qty
+	case "SELL":
+		h := b.holdings[o.symbol]
+		if h == nil || h.qty == 0 {
+			return
+		}
+		if qty > h.qty {
+			qty = h.qty
+		}
+		b.cash += price * float64(qty)
+		h.qty -= qty
+		if h.qty == 0 {
+			delete(b.holdings, o.symbol)
+		}
+	}
+
+	o.filled += qty
+	o.notional += price * float64(qty)
+
+	logger.Debug(ctx, "Paper fill",
+		"order_id", o.id,
+		"symbol", o.symbol,
+		"side", o.side,
+		"qty", qty,
+		"price", price,
+		"filled", o.filled,
+		"remaining", o.remaining(),
+	)
+}
+
+func (b *Broker) pruneOrders() {
+	open := b.orders[:0]
+	for _, o := range b.orders {
+		if !o.done() {
+			open = append(open, o)
+		}
+	}
+	b.orders = open
+}
+
+// committedCash is the cash set aside for unfilled BUY quantity.
+func (b *Broker) committedCash() float64 {
+	total := 0.0
+	for _, o := range b.orders {
+		if o.side != "BUY" || o.done() {
+			continue
+		}
+		total += o.ref * float64(o.remaining())
+	}
+	return total
+}
+
+// committedQty is the holding quantity set aside for unfilled SELL orders.
+func (b *Broker) committedQty(symbol string) int {
+	total := 0
+	for _, o := range b.orders {
+		if o.side == "SELL" && o.symbol == symbol && !o.done() {
+			total += o.remaining()
+		}
+	}
+	return total
+}
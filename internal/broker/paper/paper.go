@@ -0,0 +1,311 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+type Params struct {
+	InitialCash    float64 // Starting cash of the paper account
+	SlippageBps    float64 // Adverse adjustment applied to market fills
+	LatencyMs      int     // Simulated order round-trip before the order reaches the book
+	MaxVolumePct   float64 // Max share of a bar's volume one order may take (0 = unlimited)
+	MarketOrderTTL int     // Bars an unfilled market remainder rests before it is cancelled
+}
+
+// Broker executes orders against a simulated book while taking prices and
+// candles from a real data feed (live websocket or historical). Orders fill
+// bar by bar: market orders at the bar open plus slippage, limit orders once
+// the bar trades through the limit, each capped by the bar's volume.
+type Broker struct {
+	feed interfaces.Broker
+	p    Params
+
+	mu       sync.Mutex
+	cash     float64
+	holdings map[string]*holding
+	orders   []*order          // Resting (unfilled) orders
+	byID     map[string]*order // Every order placed, for status lookups
+	lastBar  map[string]int64  // Newest bar already matched per symbol
+	nextID   int
+}
+
+type holding struct {
+	qty int
+	avg float64
+}
+
+var _ interfaces.Broker = (*Broker)(nil)
+
+func New(feed interfaces.Broker, p Params) *Broker {
+	if p.MarketOrderTTL <= 0 {
+		p.MarketOrderTTL = 3
+	}
+	return &Broker{
+		feed:     feed,
+		p:        p,
+		cash:     p.InitialCash,
+		holdings: make(map[string]*holding),
+		byID:     make(map[string]*order),
+		lastBar:  make(map[string]int64),
+	}
+}
+
+func (b *Broker) LTP(ctx context.Context, symbol string) (float64, error) {
+	return b.feed.LTP(ctx, symbol)
+}
+
+func (b *Broker) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	return b.feed.GetQuote(ctx, symbol)
+}
+
+// RecentCandles returns candles from the feed and matches resting orders
+// against any bars that closed since the last call.
+func (b *Broker) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	candles, err := b.feed.RecentCandles(ctx, symbol, n)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.matchBars(ctx, symbol, candles)
+	b.mu.Unlock()
+
+	return candles, nil
+}
+
+func (b *Broker) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	if req.Qty <= 0 {
+		return types.OrderResp{}, errors.New("quantity must be positive")
+	}
+	if req.Side != "BUY" && req.Side != "SELL" {
+		return types.OrderResp{}, fmt.Errorf("unsupported side %q", req.Side)
+	}
+	orderType := req.Type
+	if orderType == "" {
+		orderType = "MARKET"
+	}
+	if orderType == "LIMIT" && req.Price <= 0 {
+		return types.OrderResp{}, errors.New("limit order requires a price")
+	}
+	if orderType != "MARKET" && orderType != "LIMIT" {
+		return types.OrderResp{}, fmt.Errorf("unsupported order type %q", req.Type)
+	}
+
+	if b.p.LatencyMs > 0 {
+		select {
+		case <-time.After(time.Duration(b.p.LatencyMs) * time.Millisecond):
+		case <-ctx.Done():
+			return types.OrderResp{}, ctx.Err()
+		}
+	}
+
+	ltp, err := b.feed.LTP(ctx, req.Symbol)
+	if err != nil {
+		return types.OrderResp{}, fmt.Errorf("no price for %s: %w", req.Symbol, err)
+	}
+	candles, err := b.feed.RecentCandles(ctx, req.Symbol, 1)
+	if err != nil || len(candles) == 0 {
+		return types.OrderResp{}, fmt.Errorf("no candles for %s", req.Symbol)
+	}
+	bar := candles[len(candles)-1]
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o := &order{
+		symbol:    req.Symbol,
+		side:      req.Side,
+		orderType: orderType,
+		limit:     req.Price,
+		ref:       ltp,
+		qty:       req.Qty,
+		tag:       req.Tag,
+	}
+	if orderType == "LIMIT" {
+		o.ref = req.Price
+	}
+	if err := b.reserve(o); err != nil {
+		return types.OrderResp{}, err
+	}
+
+	b.nextID++
+	o.id = fmt.Sprintf("PAPER-%d", b.nextID)
+	b.byID[o.id] = o
+
+	// The order arrives while the current bar is forming: it can trade
+	// against the last price now and against later bars as they close.
+	b.fillAt(ctx, o, ltp, bar.Vol)
+	if b.lastBar[req.Symbol] < bar.Ts {
+		b.lastBar[req.Symbol] = bar.Ts
+	}
+	if !o.done() {
+		b.orders = append(b.orders, o)
+	}
+
+	return types.OrderResp{
+		OrderID: o.id,
+		Status:  o.status(),
+		Message: fmt.Sprintf("paper: filled %d/%d @ %.2f", o.filled, o.qty, o.avgPrice()),
+	}, nil
+}
+
+// reserve checks that the order can be funded (BUY) or covered (SELL)
+// including quantity already committed to resting orders.
+func (b *Broker) reserve(o *order) error {
+	switch o.side {
+	case "BUY":
+		cost := o.ref * (1 + b.p.SlippageBps/10000.0) * float64(o.qty)
+		if avail := b.cash - b.committedCash(); cost > avail {
+			return fmt.Errorf("insufficient funds: need %.2f, have %.2f", cost, avail)
+		}
+	case "SELL":
+		held := 0
+		if h := b.holdings[o.symbol]; h != nil {
+			held = h.qty
+		}
+		if avail := held - b.committedQty(o.symbol); o.qty > avail {
+			return fmt.Errorf("insufficient holdings: have %d, selling %d", avail, o.qty)
+		}
+	}
+	return nil
+}
+
+func (b *Broker) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, ok := b.byID[orderID]
+	if !ok {
+		return types.OrderResp{}, fmt.Errorf("unknown order %s", orderID)
+	}
+	if o.done() {
+		return types.OrderResp{}, fmt.Errorf("order %s is %s and cannot be modified", orderID, o.status())
+	}
+
+	prev := *o
+	if mod.Qty > 0 {
+		if mod.Qty < o.filled {
+			return types.OrderResp{}, fmt.Errorf("quantity %d below filled %d", mod.Qty, o.filled)
+		}
+		o.qty = mod.Qty
+	}
+	if mod.Type != "" {
+		o.orderType = mod.Type
+	}
+	if mod.Price > 0 {
+		o.limit = mod.Price
+		o.ref = mod.Price
+	}
+
+	// Re-check funding for the amended order without counting itself
+	o.cancelled = true
+	err := b.reserve(&order{side: o.side, symbol: o.symbol, ref: o.ref, qty: o.remaining()})
+	o.cancelled = false
+	if err != nil {
+		*o = prev
+		return types.OrderResp{}, err
+	}
+
+	return types.OrderResp{OrderID: o.id, Status: o.status(), Message: "paper: modified"}, nil
+}
+
+func (b *Broker) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, ok := b.byID[orderID]
+	if !ok {
+		return types.OrderResp{}, fmt.Errorf("unknown order %s", orderID)
+	}
+	if o.done() {
+		return types.OrderResp{}, fmt.Errorf("order %s is %s and cannot be cancelled", orderID, o.status())
+	}
+
+	o.cancelled = true
+	b.pruneOrders()
+	return types.OrderResp{OrderID: o.id, Status: o.status(), Message: "paper: cancelled"}, nil
+}
+
+func (b *Broker) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, ok := b.byID[orderID]
+	if !ok {
+		return types.OrderStatus{}, fmt.Errorf("unknown order %s", orderID)
+	}
+
+	status := types.OrderOpen
+	switch {
+	case o.remaining() == 0:
+		status = types.OrderComplete
+	case o.cancelled:
+		status = types.OrderCancelled
+	}
+
+	pending := o.remaining()
+	if o.cancelled {
+		pending = 0
+	}
+	return types.OrderStatus{
+		OrderID:    o.id,
+		Status:     status,
+		FilledQty:  o.filled,
+		PendingQty: pending,
+		AvgPrice:   o.avgPrice(),
+		Message:    o.status(),
+	}, nil
+}
+
+func (b *Broker) Funds(ctx context.Context) (types.Funds, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	value := 0.0
+	for sym, h := range b.holdings {
+		price := h.avg
+		if ltp, err := b.feed.LTP(ctx, sym); err == nil {
+			price = ltp
+		}
+		value += float64(h.qty) * price
+	}
+
+	committed := b.committedCash()
+	return types.Funds{
+		Available: b.cash - committed,
+		Utilised:  value + committed,
+		Net:       b.cash + value,
+	}, nil
+}
+
+func (b *Broker) Ticks() <-chan types.Tick {
+	return b.feed.Ticks()
+}
+
+func (b *Broker) Start(ctx context.Context, symbols []string) error {
+	logger.Info(ctx, "Paper broker started",
+		"initial_cash", b.p.InitialCash,
+		"slippage_bps", b.p.SlippageBps,
+		"latency_ms", b.p.LatencyMs,
+		"max_volume_pct", b.p.MaxVolumePct,
+	)
+	return b.feed.Start(ctx, symbols)
+}
+
+func (b *Broker) Stop(ctx context.Context) {
+	b.mu.Lock()
+	open := len(b.orders)
+	b.mu.Unlock()
+	if open > 0 {
+		logger.Warn(ctx, "Paper broker stopping with open orders", "count", open)
+	}
+	b.feed.Stop(ctx)
+}
@@ -0,0 +1,190 @@
+package upstox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// ErrReloginRequired matches api.ErrAuth.
+var ErrReloginRequired = fmt.Errorf("upstox access token expired: manual re-login required (%w)", api.ErrAuth)
+
+type AuthParams struct {
+	APIKey      string // Upstox app client id
+	APISecret   string
+	RedirectURI string
+	AuthCode    string // One-off code from the login redirect
+	AccessToken string // Token obtained elsewhere (optional)
+	TokenFile   string // Where the current access token is persisted
+}
+
+type persistedToken struct {
+	AccessToken string    `json:"access_token"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+// auth keeps a valid Upstox access token. Tokens expire at 03:30 IST every
+// day; a new one comes from the persisted file or an authorization code.
+type auth struct {
+	client *http.Client
+	p      AuthParams
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func newAuth(client *http.Client, p AuthParams) *auth {
+	a := &auth{client: client, p: p}
+	if p.AccessToken != "" {
+		a.token, a.issuedAt = p.AccessToken, time.Now()
+	}
+	return a
+}
+
+// lastExpiry returns the most recent 03:30 IST before now.
+func lastExpiry(now time.Time) time.Time {
+	expiry := timeutil.At(now, 3, 30)
+	if now.Before(expiry) {
+		expiry = expiry.AddDate(0, 0, -1)
+	}
+	return expiry
+}
+
+func (a *auth) loginURL() string {
+	q := url.Values{
+		"response_type": {"code"},
+		"client_id":     {a.p.APIKey},
+		"redirect_uri":  {a.p.RedirectURI},
+	}
+	return apiBase + "/v2/login/authorization/dialog?" + q.Encode()
+}
+
+// ensure returns a valid access token, exchanging an auth code if needed.
+func (a *auth) ensure(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && a.issuedAt.After(lastExpiry(time.Now())) {
+		return a.token, nil
+	}
+
+	if pt, err := a.load(); err == nil && pt.IssuedAt.After(lastExpiry(time.Now())) {
+		a.token, a.issuedAt = pt.AccessToken, pt.IssuedAt
+		return a.token, nil
+	}
+
+	if a.p.AuthCode == "" || a.p.APISecret == "" {
+		logger.Error(ctx, "Upstox access token expired - manual re-login required",
+			"event", "UPSTOX_RELOGIN_REQUIRED",
+			"login_url", a.loginURL(),
+		)
+		return "", ErrReloginRequired
+	}
+
+	token, err := a.exchange(ctx)
+	// An auth code can only be exchanged once
+	a.p.AuthCode = ""
+	if err != nil {
+		logger.Error(ctx, "Upstox auth code exchange failed - manual re-login required",
+			"event", "UPSTOX_RELOGIN_REQUIRED",
+			"error", err,
+			"login_url", a.loginURL(),
+		)
+		return "", fmt.Errorf("%w: %v", ErrReloginRequired, err)
+	}
+
+	a.token, a.issuedAt = token, time.Now()
+	if err := a.save(); err != nil {
+		logger.Warn(ctx, "Failed to persist Upstox access token", "error", err, "path", a.p.TokenFile)
+	}
+
+	logger.Info(ctx, "Upstox access token refreshed")
+	return a.token, nil
+}
+
+func (a *auth) exchange(ctx context.Context) (string, error) {
+	form := url.Values{
+		"code":          {a.p.AuthCode},
+		"client_id":     {a.p.APIKey},
+		"client_secret": {a.p.APISecret},
+		"redirect_uri":  {a.p.RedirectURI},
+		"grant_type":    {"authorization_code"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+"/v2/login/authorization/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("http %d", resp.StatusCode)
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if out.AccessToken == "" {
+		return "", errors.New("empty access token in response")
+	}
+	return out.AccessToken, nil
+}
+
+// invalidate drops the current token after the API rejected it.
+func (a *auth) invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+	a.issuedAt = time.Time{}
+	if a.p.TokenFile != "" {
+		_ = os.Remove(a.p.TokenFile)
+	}
+}
+
+func (a *auth) load() (persistedToken, error) {
+	var pt persistedToken
+	if a.p.TokenFile == "" {
+		return pt, os.ErrNotExist
+	}
+	b, err := os.ReadFile(a.p.TokenFile)
+	if err != nil {
+		return pt, err
+	}
+	err = json.Unmarshal(b, &pt)
+	return pt, err
+}
+
+func (a *auth) save() error {
+	if a.p.TokenFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(a.p.TokenFile), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(persistedToken{AccessToken: a.token, IssuedAt: a.issuedAt})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.p.TokenFile, b, 0o600)
+}
@@ -0,0 +1,97 @@
+package upstox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/crash"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+const tickBufferSize = 1024
+
+// quoteFeed publishes ticks by polling batched LTP quotes. Upstox's
+// websocket market feed is protobuf-encoded; polling keeps the adapter
+// dependency-free at the cost of sub-second latency.
+type quoteFeed struct {
+	u        *Upstox
+	interval time.Duration
+	ticks    chan types.Tick
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	last   map[string]float64
+}
+
+func newQuoteFeed(u *Upstox, interval time.Duration) *quoteFeed {
+	return &quoteFeed{
+		u:        u,
+		interval: interval,
+		ticks:    make(chan types.Tick, tickBufferSize),
+		last:     make(map[string]float64),
+	}
+}
+
+func (f *quoteFeed) start(ctx context.Context, symbols []string) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	f.mu.Lock()
+	f.cancel = cancel
+	f.mu.Unlock()
+
+	go crash.Supervise(ctx, "upstox.feed", func(ctx context.Context) { f.run(ctx, symbols) })
+}
+
+func (f *quoteFeed) stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+func (f *quoteFeed) run(ctx context.Context, symbols []string) {
+	t := time.NewTicker(f.interval)
+	defer t.Stop()
+
+	bar := int64(f.u.p.CandleInterval * 60)
+	lastBucket := timeutil.BarStart(time.Now().Unix(), bar)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			prices, err := f.u.ltps(ctx, symbols)
+			if err != nil {
+				logger.Warn(ctx, "Upstox quote poll failed", "error", err)
+				continue
+			}
+
+			// Report a bar close on the first poll of each new bucket
+			bucket := timeutil.BarStart(now.Unix(), bar)
+			closed := bucket > lastBucket
+			lastBucket = bucket
+
+			for sym, price := range prices {
+				if closed {
+					f.publish(types.Tick{Symbol: sym, Price: price, Ts: bucket, BarClose: true})
+				}
+				if price != f.last[sym] {
+					f.last[sym] = price
+					f.publish(types.Tick{Symbol: sym, Price: price, Ts: now.Unix()})
+				}
+			}
+		}
+	}
+}
+
+func (f *quoteFeed) publish(tick types.Tick) {
+	select {
+	case f.ticks <- tick:
+	default:
+		// Consumer is behind; drop rather than block the poller
+	}
+}
@@ -0,0 +1,101 @@
+package upstox
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+const instrumentsURL = "https://assets.upstox.com/market-quote/instruments/exchange/%s.json.gz"
+
+// instrumentResolver maps symbols, optionally exchange-qualified
+// ("BSE:500325"), to Upstox instrument keys (e.g. NSE_EQ|INE002A01018)
+// using the public instrument master of each exchange.
+type instrumentResolver struct {
+	client   *http.Client
+	exchange string // Default for unqualified symbols
+
+	mu   sync.Mutex
+	keys map[string]map[string]string // exchange -> trading symbol or scrip code -> key
+}
+
+func newInstrumentResolver(client *http.Client, exchange string) *instrumentResolver {
+	return &instrumentResolver{
+		client:   client,
+		exchange: exchange,
+		keys:     make(map[string]map[string]string),
+	}
+}
+
+func (ir *instrumentResolver) key(ctx context.Context, symbol string) (string, error) {
+	exchange, ts := types.SplitSymbol(symbol, ir.exchange)
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	keys, ok := ir.keys[exchange]
+	if !ok {
+		loaded, err := ir.load(ctx, exchange)
+		if err != nil {
+			logger.Warn(ctx, "Failed to load Upstox instrument master", "exchange", exchange, "error", err)
+			return "", fmt.Errorf("unknown instrument %s:%s", exchange, ts)
+		}
+		keys = loaded
+		ir.keys[exchange] = keys
+	}
+
+	if key, ok := keys[ts]; ok {
+		return key, nil
+	}
+	return "", fmt.Errorf("unknown instrument %s:%s", exchange, ts)
+}
+
+func (ir *instrumentResolver) load(ctx context.Context, exchange string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(instrumentsURL, exchange), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ir.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http %d", resp.StatusCode)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var instruments []struct {
+		Segment       string `json:"segment"`
+		TradingSymbol string `json:"trading_symbol"`
+		InstrumentKey string `json:"instrument_key"`
+		ExchangeToken string `json:"exchange_token"`
+	}
+	if err := json.NewDecoder(gz).Decode(&instruments); err != nil {
+		return nil, err
+	}
+
+	segment := exchange + "_EQ"
+	keys := make(map[string]string)
+	for _, inst := range instruments {
+		if inst.Segment != segment {
+			continue
+		}
+		keys[inst.TradingSymbol] = inst.InstrumentKey
+		if inst.ExchangeToken != "" {
+			keys[inst.ExchangeToken] = inst.InstrumentKey
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,124 @@
+package upstox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"llm-trading-bot/internal/types"
+)
+
+func (u *Upstox) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	if u.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run modify"}, nil
+	}
+
+	// Upstox requires the full order shape on modify; fill gaps from the
+	// current state
+	cur, err := u.orderDetails(ctx, orderID)
+	if err != nil {
+		return types.OrderResp{}, err
+	}
+	body := map[string]any{
+		"order_id":           orderID,
+		"quantity":           cur.Quantity,
+		"order_type":         cur.OrderType,
+		"validity":           "DAY",
+		"price":              cur.Price,
+		"trigger_price":      cur.TriggerPrice,
+		"disclosed_quantity": 0,
+	}
+	if mod.Qty > 0 {
+		body["quantity"] = mod.Qty
+	}
+	if mod.Type != "" {
+		body["order_type"] = mod.Type
+	}
+	if mod.Price > 0 {
+		body["price"] = mod.Price
+	}
+	if mod.TriggerPrice > 0 {
+		body["trigger_price"] = mod.TriggerPrice
+	}
+
+	if err := u.do(ctx, http.MethodPut, "/v2/order/modify", nil, body, nil); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to modify order %s: %w", orderID, err)
+	}
+	return types.OrderResp{OrderID: orderID, Status: "MODIFIED", Message: "ok"}, nil
+}
+
+func (u *Upstox) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	if u.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run cancel"}, nil
+	}
+
+	q := url.Values{"order_id": {orderID}}
+	if err := u.do(ctx, http.MethodDelete, "/v2/order/cancel", q, nil, nil); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return types.OrderResp{OrderID: orderID, Status: types.OrderCancelled, Message: "ok"}, nil
+}
+
+func (u *Upstox) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	if u.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderStatus{OrderID: orderID, Status: types.OrderComplete, Message: "dry-run"}, nil
+	}
+
+	o, err := u.orderDetails(ctx, orderID)
+	if err != nil {
+		return types.OrderStatus{}, err
+	}
+	return types.OrderStatus{
+		OrderID:    orderID,
+		Status:     upstoxStatus(o.Status),
+		FilledQty:  o.FilledQuantity,
+		PendingQty: o.PendingQuantity,
+		AvgPrice:   o.AveragePrice,
+		Message:    o.StatusMessage,
+	}, nil
+}
+
+type upstoxOrder struct {
+	Status          string  `json:"status"`
+	StatusMessage   string  `json:"status_message"`
+	OrderType       string  `json:"order_type"`
+	Quantity        int     `json:"quantity"`
+	Price           float64 `json:"price"`
+	TriggerPrice    float64 `json:"trigger_price"`
+	FilledQuantity  int     `json:"filled_quantity"`
+	PendingQuantity int     `json:"pending_quantity"`
+	AveragePrice    float64 `json:"average_price"`
+}
+
+func (u *Upstox) orderDetails(ctx context.Context, orderID string) (upstoxOrder, error) {
+	var out struct {
+		Data upstoxOrder `json:"data"`
+	}
+	q := url.Values{"order_id": {orderID}}
+	if err := u.do(ctx, http.MethodGet, "/v2/order/details", q, nil, &out); err != nil {
+		return upstoxOrder{}, fmt.Errorf("failed to fetch order %s: %w", orderID, err)
+	}
+	return out.Data, nil
+}
+
+// upstoxStatus maps Upstox's lowercase order states onto the normalized set.
+func upstoxStatus(status string) string {
+	switch status {
+	case "complete":
+		return types.OrderComplete
+	case "cancelled":
+		return types.OrderCancelled
+	case "rejected":
+		return types.OrderRejected
+	case "trigger pending":
+		return types.OrderTriggerPending
+	default:
+		return types.OrderOpen
+	}
+}
+
+func isSimulated(orderID string) bool {
+	return strings.HasPrefix(orderID, "SIM-")
+}
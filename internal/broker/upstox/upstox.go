@@ -0,0 +1,415 @@
+package upstox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+const apiBase = "https://api.upstox.com"
+
+type Params struct {
+	Mode         string
+	Exchange     string
+	CandleSource string
+	DryRunFunds  float64
+
+	CandleInterval int // Bar size in minutes
+	PollSeconds    int // Quote polling interval for the tick feed
+
+	Auth AuthParams
+}
+
+// Upstox trades Indian equities through the Upstox v2 API. It needs no
+// Kite Connect subscription: candles come from the historical/intraday
+// candle API and ticks from polled LTP quotes.
+type Upstox struct {
+	p           Params
+	client      *http.Client
+	auth        *auth
+	instruments *instrumentResolver
+	feed        *quoteFeed
+}
+
+var _ interfaces.Broker = (*Upstox)(nil)
+
+func NewUpstox(p Params) *Upstox {
+	if p.Exchange == "" {
+		p.Exchange = "NSE"
+	}
+	if p.CandleInterval <= 0 {
+		p.CandleInterval = 1
+	}
+	if p.PollSeconds <= 0 {
+		p.PollSeconds = 2
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second, Transport: api.Transport()}
+	u := &Upstox{
+		p:      p,
+		client: client,
+		auth:   newAuth(client, p.Auth),
+	}
+	u.instruments = newInstrumentResolver(client, p.Exchange)
+	if p.CandleSource == "LIVE" {
+		u.feed = newQuoteFeed(u, time.Duration(p.PollSeconds)*time.Second)
+	}
+	return u
+}
+
+func (u *Upstox) LTP(ctx context.Context, symbol string) (float64, error) {
+	prices, err := u.ltps(ctx, []string{symbol})
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no quote for %s", symbol)
+	}
+	return price, nil
+}
+
+// ltps fetches last traded prices for several symbols in one request.
+func (u *Upstox) ltps(ctx context.Context, symbols []string) (map[string]float64, error) {
+	keys := make([]string, 0, len(symbols))
+	byKey := make(map[string]string, len(symbols))
+	for _, sym := range symbols {
+		key, err := u.instruments.key(ctx, sym)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		byKey[key] = sym
+	}
+
+	var out struct {
+		Data map[string]struct {
+			LastPrice       float64 `json:"last_price"`
+			InstrumentToken string  `json:"instrument_token"`
+		} `json:"data"`
+	}
+	q := url.Values{"instrument_key": {strings.Join(keys, ",")}}
+	if err := u.do(ctx, http.MethodGet, "/v2/market-quote/ltp", q, nil, &out); err != nil {
+		return nil, fmt.Errorf("failed to fetch quotes: %w", err)
+	}
+
+	// Response is keyed by "NSE_EQ:SYMBOL"; instrument_token carries the key we sent
+	prices := make(map[string]float64, len(out.Data))
+	for _, q := range out.Data {
+		if sym, ok := byKey[q.InstrumentToken]; ok {
+			prices[sym] = q.LastPrice
+		}
+	}
+	return prices, nil
+}
+
+func (u *Upstox) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	key, err := u.instruments.key(ctx, symbol)
+	if err != nil {
+		return types.Quote{}, err
+	}
+
+	type level struct {
+		Quantity int     `json:"quantity"`
+		Price    float64 `json:"price"`
+		Orders   int     `json:"orders"`
+	}
+	var out struct {
+		Data map[string]struct {
+			LastPrice       float64 `json:"last_price"`
+			InstrumentToken string  `json:"instrument_token"`
+			Depth           struct {
+				Buy  []level `json:"buy"`
+				Sell []level `json:"sell"`
+			} `json:"depth"`
+		} `json:"data"`
+	}
+	q := url.Values{"instrument_key": {key}}
+	if err := u.do(ctx, http.MethodGet, "/v2/market-quote/quotes", q, nil, &out); err != nil {
+		return types.Quote{}, fmt.Errorf("failed to fetch quote for %s: %w", symbol, err)
+	}
+
+	for _, d := range out.Data {
+		if d.InstrumentToken != key {
+			continue
+		}
+		qt := types.Quote{Symbol: symbol, LTP: d.LastPrice, Ts: time.Now().Unix()}
+		for _, l := range d.Depth.Buy {
+			if l.Price > 0 {
+				qt.Bids = append(qt.Bids, types.DepthLevel{Price: l.Price, Qty: l.Quantity, Orders: l.Orders})
+			}
+		}
+		for _, l := range d.Depth.Sell {
+			if l.Price > 0 {
+				qt.Asks = append(qt.Asks, types.DepthLevel{Price: l.Price, Qty: l.Quantity, Orders: l.Orders})
+			}
+		}
+		if len(qt.Bids) > 0 {
+			qt.Bid = qt.Bids[0].Price
+		}
+		if len(qt.Asks) > 0 {
+			qt.Ask = qt.Asks[0].Price
+		}
+		return qt, nil
+	}
+	return types.Quote{}, fmt.Errorf("no quote for %s", symbol)
+}
+
+func (u *Upstox) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	if u.p.CandleSource != "LIVE" {
+		return nil, errors.New("upstox broker requires data_source: LIVE")
+	}
+
+	key, err := u.instruments.key(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := upstoxInterval(u.p.CandleInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	// Today's bars come from the intraday endpoint, earlier sessions from
+	// the historical one
+	today, err := u.candles(ctx, "/v2/historical-candle/intraday/"+url.PathEscape(key)+"/"+interval)
+	if err != nil {
+		return nil, err
+	}
+	candles := today
+	if len(candles) < n {
+		barsPerDay := 375 / u.p.CandleInterval
+		days := (n-len(candles))/barsPerDay + 5
+		// Upstox takes IST dates; the host's own date can be a day off
+		now := timeutil.Now()
+		to := timeutil.DateKey(now.AddDate(0, 0, -1))
+		from := timeutil.DateKey(now.AddDate(0, 0, -days))
+		past, err := u.candles(ctx, "/v2/historical-candle/"+url.PathEscape(key)+"/"+interval+"/"+to+"/"+from)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(past, today...)
+	}
+
+	if len(candles) > n {
+		candles = candles[len(candles)-n:]
+	}
+	return candles, nil
+}
+
+// candles fetches a candle endpoint and returns bars oldest-first.
+func (u *Upstox) candles(ctx context.Context, path string) ([]types.Candle, error) {
+	var out struct {
+		Data struct {
+			Candles [][]any `json:"candles"`
+		} `json:"data"`
+	}
+	if err := u.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, fmt.Errorf("failed to fetch candles: %w", err)
+	}
+
+	// Rows are [timestamp, open, high, low, close, volume, oi], newest first
+	rows := out.Data.Candles
+	candles := make([]types.Candle, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		r := rows[i]
+		if len(r) < 6 {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, fmt.Sprint(r[0]))
+		if err != nil {
+			continue
+		}
+		candles = append(candles, types.Candle{
+			Ts:    ts.Unix(),
+			Open:  num(r[1]),
+			High:  num(r[2]),
+			Low:   num(r[3]),
+			Close: num(r[4]),
+			Vol:   num(r[5]),
+		})
+	}
+	return candles, nil
+}
+
+func num(v any) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+func upstoxInterval(minutes int) (string, error) {
+	switch minutes {
+	case 1:
+		return "1minute", nil
+	case 30:
+		return "30minute", nil
+	default:
+		return "", fmt.Errorf("unsupported candle interval %d minutes (upstox supports 1 and 30)", minutes)
+	}
+}
+
+func (u *Upstox) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	if u.p.Mode == "DRY_RUN" {
+		return types.OrderResp{
+			OrderID: fmt.Sprintf("SIM-%d", time.Now().UnixNano()),
+			Status:  "SIMULATED",
+			Message: "dry-run",
+		}, nil
+	}
+
+	key, err := u.instruments.key(ctx, req.Symbol)
+	if err != nil {
+		return types.OrderResp{}, err
+	}
+
+	body := map[string]any{
+		"instrument_token":   key,
+		"quantity":           req.Qty,
+		"transaction_type":   req.Side,
+		"order_type":         "MARKET",
+		"product":            "D",
+		"validity":           "DAY",
+		"price":              0,
+		"trigger_price":      0,
+		"disclosed_quantity": 0,
+		"is_amo":             false,
+		"tag":                req.Tag,
+	}
+	if req.Type == "LIMIT" {
+		body["order_type"] = "LIMIT"
+		body["price"] = req.Price
+	}
+
+	var out struct {
+		Data struct {
+			OrderID string `json:"order_id"`
+		} `json:"data"`
+	}
+	if err := u.do(ctx, http.MethodPost, "/v2/order/place", nil, body, &out); err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return types.OrderResp{
+		OrderID: out.Data.OrderID,
+		Status:  "PLACED",
+		Message: "ok",
+	}, nil
+}
+
+func (u *Upstox) Funds(ctx context.Context) (types.Funds, error) {
+	if u.p.Mode == "DRY_RUN" {
+		return types.Funds{
+			Available: u.p.DryRunFunds,
+			Net:       u.p.DryRunFunds,
+		}, nil
+	}
+
+	var out struct {
+		Data struct {
+			Equity struct {
+				Available float64 `json:"available_margin"`
+				Used      float64 `json:"used_margin"`
+			} `json:"equity"`
+		} `json:"data"`
+	}
+	q := url.Values{"segment": {"SEC"}}
+	if err := u.do(ctx, http.MethodGet, "/v2/user/get-funds-and-margin", q, nil, &out); err != nil {
+		return types.Funds{}, fmt.Errorf("failed to fetch equity margins: %w", err)
+	}
+
+	eq := out.Data.Equity
+	return types.Funds{
+		Available: eq.Available,
+		Utilised:  eq.Used,
+		Net:       eq.Available + eq.Used,
+	}, nil
+}
+
+func (u *Upstox) Ticks() <-chan types.Tick {
+	if u.feed == nil {
+		return nil
+	}
+	return u.feed.ticks
+}
+
+func (u *Upstox) Start(ctx context.Context, symbols []string) error {
+	if _, err := u.auth.ensure(ctx); err != nil {
+		return fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	if u.feed == nil {
+		return nil
+	}
+	u.feed.start(ctx, symbols)
+	return nil
+}
+
+func (u *Upstox) Stop(ctx context.Context) {
+	if u.feed != nil {
+		u.feed.stop()
+	}
+}
+
+// do sends an authenticated request and decodes the JSON response into out.
+// A 401 drops the token so the next call re-authenticates.
+func (u *Upstox) do(ctx context.Context, method, path string, q url.Values, body, out any) error {
+	token, err := u.auth.ensure(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := apiBase + path
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		u.auth.invalidate()
+		return ErrReloginRequired
+	}
+	if err := api.CheckStatus(resp); err != nil {
+		return fmt.Errorf("upstox %s %s: %w", method, path, err)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("upstox %s %s: %w: %w", method, path, api.ErrParse, err)
+	}
+	return nil
+}
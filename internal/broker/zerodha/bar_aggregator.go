@@ -0,0 +1,108 @@
+package zerodha
+
+import (
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+type barBuilder struct {
+	current    *types.Candle // Bar currently forming, nil before the first tick
+	lastCumVol float64       // Cumulative day volume seen on the previous tick
+}
+
+// barAggregator turns ticks into time-bucketed OHLCV bars. Buckets are
+// anchored to the 09:15 IST session open so N-minute bars line up with
+// the exchange's own bars.
+type barAggregator struct {
+	interval int64 // Bar size in seconds
+	builders map[string]*barBuilder
+}
+
+func newBarAggregator(intervalMinutes int) *barAggregator {
+	if intervalMinutes <= 0 {
+		intervalMinutes = 1
+	}
+	return &barAggregator{
+		interval: int64(intervalMinutes) * 60,
+		builders: make(map[string]*barBuilder),
+	}
+}
+
+func (ba *barAggregator) bucket(ts int64) int64 {
+	return timeutil.BarStart(ts, ba.interval)
+}
+
+// add folds a tick into its symbol's bar and returns any bars closed by it,
+// including flat zero-volume bars for buckets skipped within the same day.
+// cumVol is the exchange's cumulative traded volume for the day.
+func (ba *barAggregator) add(symbol string, ts int64, price, cumVol float64) []types.Candle {
+	b := ba.builders[symbol]
+	if b == nil {
+		b = &barBuilder{}
+		ba.builders[symbol] = b
+	}
+
+	delta := 0.0
+	switch {
+	case b.lastCumVol == 0:
+		// First tick seen: no baseline for a delta yet
+	case cumVol >= b.lastCumVol:
+		delta = cumVol - b.lastCumVol
+	default:
+		// Cumulative volume reset (new trading day)
+		delta = cumVol
+	}
+	b.lastCumVol = cumVol
+
+	bucket := ba.bucket(ts)
+	var closed []types.Candle
+
+	switch {
+	case b.current == nil:
+		b.current = newBar(bucket, price, delta)
+	case bucket == b.current.Ts:
+		b.current.High = max(b.current.High, price)
+		b.current.Low = min(b.current.Low, price)
+		b.current.Close = price
+		b.current.Vol += delta
+	case bucket > b.current.Ts:
+		prev := *b.current
+		closed = append(closed, prev)
+		if timeutil.SameDay(prev.Ts, bucket) {
+			for missing := prev.Ts + ba.interval; missing < bucket; missing += ba.interval {
+				closed = append(closed, types.Candle{
+					Ts:    missing,
+					Open:  prev.Close,
+					High:  prev.Close,
+					Low:   prev.Close,
+					Close: prev.Close,
+				})
+			}
+		}
+		b.current = newBar(bucket, price, delta)
+	default:
+		// Late tick for an already closed bucket; ignore it
+	}
+
+	return closed
+}
+
+// forming returns a copy of the symbol's in-progress bar.
+func (ba *barAggregator) forming(symbol string) (types.Candle, bool) {
+	b := ba.builders[symbol]
+	if b == nil || b.current == nil {
+		return types.Candle{}, false
+	}
+	return *b.current, true
+}
+
+func newBar(ts int64, price, vol float64) *types.Candle {
+	return &types.Candle{
+		Ts:    ts,
+		Open:  price,
+		High:  price,
+		Low:   price,
+		Close: price,
+		Vol:   vol,
+	}
+}
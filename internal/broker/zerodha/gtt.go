@@ -0,0 +1,117 @@
+package zerodha
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+var _ interfaces.StopBroker = (*Zerodha)(nil)
+
+// PlaceStop creates a single-leg SELL GTT that fires a limit order when the
+// price falls to the trigger.
+func (z *Zerodha) PlaceStop(ctx context.Context, req types.StopReq) (string, error) {
+	if z.p.Mode == "DRY_RUN" {
+		return fmt.Sprintf("SIM-GTT-%d", time.Now().UnixNano()), nil
+	}
+
+	var resp kiteconnect.GTTResponse
+	err := z.withToken(ctx, func() (err error) {
+		resp, err = z.kc.PlaceGTT(z.gttParams(req))
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to place GTT for %s: %w", req.Symbol, err)
+	}
+	return strconv.Itoa(resp.TriggerID), nil
+}
+
+func (z *Zerodha) ModifyStop(ctx context.Context, stopID string, req types.StopReq) error {
+	if z.p.Mode == "DRY_RUN" || isSimulated(stopID) {
+		return nil
+	}
+
+	id, err := strconv.Atoi(stopID)
+	if err != nil {
+		return fmt.Errorf("invalid GTT id %q", stopID)
+	}
+	if err := z.checkGTTActive(ctx, id); err != nil {
+		return err
+	}
+
+	err = z.withToken(ctx, func() error {
+		_, err := z.kc.ModifyGTT(id, z.gttParams(req))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to modify GTT %s: %w", stopID, err)
+	}
+	return nil
+}
+
+func (z *Zerodha) CancelStop(ctx context.Context, stopID string) error {
+	if z.p.Mode == "DRY_RUN" || isSimulated(stopID) {
+		return nil
+	}
+
+	id, err := strconv.Atoi(stopID)
+	if err != nil {
+		return fmt.Errorf("invalid GTT id %q", stopID)
+	}
+	if err := z.checkGTTActive(ctx, id); err != nil {
+		return err
+	}
+
+	err = z.withToken(ctx, func() error {
+		_, err := z.kc.DeleteGTT(id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete GTT %s: %w", stopID, err)
+	}
+	return nil
+}
+
+// checkGTTActive returns ErrStopTriggered if the GTT has already fired.
+func (z *Zerodha) checkGTTActive(ctx context.Context, id int) error {
+	var gtt kiteconnect.GTT
+	err := z.withToken(ctx, func() (err error) {
+		gtt, err = z.kc.GetGTT(id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch GTT %d: %w", id, err)
+	}
+	switch gtt.Status {
+	case "active":
+		return nil
+	case "triggered":
+		return interfaces.ErrStopTriggered
+	default:
+		return fmt.Errorf("GTT %d is %s", id, gtt.Status)
+	}
+}
+
+func (z *Zerodha) gttParams(req types.StopReq) kiteconnect.GTTParams {
+	exchange, ts := z.listing(req.Symbol)
+	return kiteconnect.GTTParams{
+		Tradingsymbol:   ts,
+		Exchange:        exchange,
+		LastPrice:       req.LastPrice,
+		TransactionType: kiteconnect.TransactionTypeSell,
+		Product:         z.p.Product,
+		Trigger: &kiteconnect.GTTSingleLegTrigger{
+			TriggerParams: kiteconnect.TriggerParams{
+				TriggerValue: req.TriggerPrice,
+				LimitPrice:   req.LimitPrice,
+				Quantity:     float64(req.Qty),
+			},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package zerodha
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+const (
+	// NSE cash session length, used to size the historical lookback
+	sessionMinutes = 375
+
+	// Minimum spacing between backfills of the same symbol
+	backfillCooldown = time.Minute
+)
+
+// kiteInterval maps a bar size in minutes to a Kite historical interval.
+func kiteInterval(minutes int) (string, error) {
+	switch minutes {
+	case 1:
+		return "minute", nil
+	case 3, 5, 10, 15, 30, 60:
+		return fmt.Sprintf("%dminute", minutes), nil
+	default:
+		return "", fmt.Errorf("unsupported candle interval %d minutes", minutes)
+	}
+}
+
+func (z *Zerodha) fetchHistoricalCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	interval, err := kiteInterval(z.p.CandleInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := z.instruments.token(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cover n bars plus weekends/holidays between sessions
+	barsPerDay := sessionMinutes / z.p.CandleInterval
+	days := n/barsPerDay + 5
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+
+	var data []kiteconnect.HistoricalData
+	err = z.withToken(ctx, func() (err error) {
+		data, err = z.kc.GetHistoricalData(int(token), interval, from, to, false, false)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch historical candles for %s: %w", symbol, err)
+	}
+
+	candles := make([]types.Candle, 0, len(data))
+	for _, d := range data {
+		candles = append(candles, types.Candle{
+			Ts:    d.Date.Time.Unix(),
+			Open:  d.Open,
+			High:  d.High,
+			Low:   d.Low,
+			Close: d.Close,
+			Vol:   float64(d.Volume),
+		})
+	}
+
+	if len(candles) > n {
+		candles = candles[len(candles)-n:]
+	}
+	return candles, nil
+}
+
+// backfill loads historical candles into the ticker cache, at most once
+// per backfillCooldown per symbol.
+func (z *Zerodha) backfill(ctx context.Context, symbol string) error {
+	z.mu.Lock()
+	if last, ok := z.lastBackfill[symbol]; ok && time.Since(last) < backfillCooldown {
+		z.mu.Unlock()
+		return nil
+	}
+	z.mu.Unlock()
+
+	return z.forceBackfill(ctx, symbol)
+}
+
+// resume backfills every symbol after the websocket reconnects, replacing
+// the flat placeholder bars the aggregator filled the outage with.
+func (z *Zerodha) resume(symbols []string) {
+	ctx := context.Background()
+	for _, symbol := range symbols {
+		if err := z.forceBackfill(ctx, symbol); err != nil {
+			logger.Warn(ctx, "Post-reconnect backfill failed", "symbol", symbol, "error", err)
+		}
+	}
+}
+
+func (z *Zerodha) forceBackfill(ctx context.Context, symbol string) error {
+	z.mu.Lock()
+	z.lastBackfill[symbol] = time.Now()
+	z.mu.Unlock()
+
+	candles, err := z.fetchHistoricalCandles(ctx, symbol, z.p.BackfillCandles)
+	if err != nil {
+		return err
+	}
+
+	z.tickerMgr.Backfill(symbol, candles)
+	logger.Debug(ctx, "Backfilled historical candles",
+		"symbol", symbol,
+		"count", len(candles),
+	)
+	return nil
+}
+
+// hasGap reports whether the cache is short of n bars or has a hole larger
+// than two bar intervals within a trading session.
+func hasGap(candles []types.Candle, n, intervalMinutes int) bool {
+	if len(candles) < n {
+		return true
+	}
+	maxStep := int64(2 * intervalMinutes * 60)
+	for i := 1; i < len(candles); i++ {
+		step := candles[i].Ts - candles[i-1].Ts
+		if step > maxStep && timeutil.SameDay(candles[i].Ts, candles[i-1].Ts) {
+			return true
+		}
+	}
+	return false
+}
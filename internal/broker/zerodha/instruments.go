@@ -0,0 +1,136 @@
+package zerodha
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+// placeholderTokens are used when the instrument dump cannot be fetched
+// (no credentials, STATIC data source, or the API is unreachable).
+var placeholderTokens = map[string]uint32{
+	"RELIANCE":   256265,
+	"TCS":        2953217,
+	"HDFCBANK":   341249,
+	"INFY":       408065,
+	"HCLTECH":    1850625,
+	"LT":         2939649,
+	"SBIN":       779521,
+	"ICICIBANK":  1270529,
+	"AXISBANK":   1510401,
+	"KOTAKBANK":  492033,
+	"ITC":        424961,
+	"TATAMOTORS": 884737,
+	"TITAN":      897537,
+	"JSWSTEEL":   3001089,
+	"ULTRACEMCO": 2952193,
+	"BAJFINANCE": 81153,
+	"HDFCLIFE":   119553,
+	"BHARTIARTL": 2714625,
+	"ASIANPAINT": 60417,
+	"MARUTI":     2815745,
+}
+
+// instrument is a resolved exchange listing.
+type instrument struct {
+	exchange      string
+	tradingsymbol string
+	token         uint32
+}
+
+type exchangeInstruments struct {
+	bySymbol map[string]instrument
+	byCode   map[string]instrument // BSE scrip codes / exchange tokens
+}
+
+// instrumentResolver maps symbols, optionally exchange-qualified
+// ("BSE:RELIANCE", "BSE:500325"), to Kite instruments. Each exchange's
+// instrument dump is loaded on first use.
+type instrumentResolver struct {
+	kc       *kiteconnect.Client
+	exchange string // Default for unqualified symbols
+
+	mu        sync.Mutex
+	exchanges map[string]*exchangeInstruments
+}
+
+func newInstrumentResolver(kc *kiteconnect.Client, exchange string) *instrumentResolver {
+	return &instrumentResolver{
+		kc:        kc,
+		exchange:  exchange,
+		exchanges: make(map[string]*exchangeInstruments),
+	}
+}
+
+// token returns the instrument token for a symbol.
+func (ir *instrumentResolver) token(symbol string) (uint32, error) {
+	inst, err := ir.resolve(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return inst.token, nil
+}
+
+// resolve returns the exchange listing for a symbol, accepting BSE scrip
+// codes in place of trading symbols.
+func (ir *instrumentResolver) resolve(symbol string) (instrument, error) {
+	exchange, ts := types.SplitSymbol(symbol, ir.exchange)
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	ex, ok := ir.exchanges[exchange]
+	if !ok {
+		ex = ir.load(exchange)
+		ir.exchanges[exchange] = ex
+	}
+
+	if inst, ok := ex.bySymbol[ts]; ok {
+		return inst, nil
+	}
+	if _, err := strconv.Atoi(ts); err == nil {
+		if inst, ok := ex.byCode[ts]; ok {
+			return inst, nil
+		}
+	}
+	if token, ok := placeholderTokens[ts]; ok && exchange == types.ExchangeNSE {
+		return instrument{exchange: exchange, tradingsymbol: ts, token: token}, nil
+	}
+	return instrument{}, fmt.Errorf("unknown instrument %s:%s", exchange, ts)
+}
+
+func (ir *instrumentResolver) load(exchange string) *exchangeInstruments {
+	ex := &exchangeInstruments{
+		bySymbol: make(map[string]instrument),
+		byCode:   make(map[string]instrument),
+	}
+	if ir.kc == nil {
+		return ex
+	}
+
+	instruments, err := ir.kc.GetInstrumentsByExchange(exchange)
+	if err != nil {
+		logger.Warn(context.Background(), "Failed to load instrument list - using placeholder tokens",
+			"exchange", exchange,
+			"error", err,
+		)
+		return ex
+	}
+
+	for _, in := range instruments {
+		inst := instrument{
+			exchange:      exchange,
+			tradingsymbol: in.Tradingsymbol,
+			token:         uint32(in.InstrumentToken),
+		}
+		ex.bySymbol[in.Tradingsymbol] = inst
+		ex.byCode[strconv.Itoa(in.ExchangeToken)] = inst
+	}
+	return ex
+}
@@ -0,0 +1,148 @@
+package zerodha
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/types"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+func (z *Zerodha) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	if z.p.Mode == "DRY_RUN" {
+		return types.OrderResp{
+			OrderID: fmt.Sprintf("SIM-%d", time.Now().UnixNano()),
+			Status:  "SIMULATED",
+			Message: "dry-run",
+		}, nil
+	}
+
+	orderType := req.Type
+	if orderType == "" {
+		orderType = kiteconnect.OrderTypeMarket
+	}
+
+	exchange, ts := z.listing(req.Symbol)
+	product := z.p.Product
+	if product == kiteconnect.ProductCNC && (exchange == "NFO" || exchange == "BFO") {
+		product = kiteconnect.ProductNRML // Delivery product for derivatives (hedge puts)
+	}
+
+	var resp kiteconnect.OrderResponse
+	err := z.withToken(ctx, func() (err error) {
+		resp, err = z.kc.PlaceOrder(kiteconnect.VarietyRegular, kiteconnect.OrderParams{
+			Exchange:        exchange,
+			Tradingsymbol:   ts,
+			Validity:        kiteconnect.ValidityDay,
+			Product:         product,
+			OrderType:       orderType,
+			TransactionType: req.Side,
+			Quantity:        req.Qty,
+			Price:           req.Price,
+			TriggerPrice:    req.TriggerPrice,
+			Tag:             req.Tag,
+		})
+		return err
+	})
+	if err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return types.OrderResp{
+		OrderID: resp.OrderID,
+		Status:  "PLACED",
+		Message: "ok",
+	}, nil
+}
+
+func (z *Zerodha) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	if z.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run modify"}, nil
+	}
+
+	var resp kiteconnect.OrderResponse
+	err := z.withToken(ctx, func() (err error) {
+		resp, err = z.kc.ModifyOrder(kiteconnect.VarietyRegular, orderID, kiteconnect.OrderParams{
+			OrderType:    mod.Type,
+			Quantity:     mod.Qty,
+			Price:        mod.Price,
+			TriggerPrice: mod.TriggerPrice,
+		})
+		return err
+	})
+	if err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to modify order %s: %w", orderID, err)
+	}
+
+	return types.OrderResp{OrderID: resp.OrderID, Status: "MODIFIED", Message: "ok"}, nil
+}
+
+func (z *Zerodha) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	if z.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderResp{OrderID: orderID, Status: "SIMULATED", Message: "dry-run cancel"}, nil
+	}
+
+	var resp kiteconnect.OrderResponse
+	err := z.withToken(ctx, func() (err error) {
+		resp, err = z.kc.CancelOrder(kiteconnect.VarietyRegular, orderID, nil)
+		return err
+	})
+	if err != nil {
+		return types.OrderResp{}, fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+
+	return types.OrderResp{OrderID: resp.OrderID, Status: types.OrderCancelled, Message: "ok"}, nil
+}
+
+func (z *Zerodha) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	if z.p.Mode == "DRY_RUN" || isSimulated(orderID) {
+		return types.OrderStatus{OrderID: orderID, Status: types.OrderComplete, Message: "dry-run"}, nil
+	}
+
+	var history []kiteconnect.Order
+	err := z.withToken(ctx, func() (err error) {
+		history, err = z.kc.GetOrderHistory(orderID)
+		return err
+	})
+	if err != nil {
+		return types.OrderStatus{}, fmt.Errorf("failed to fetch order %s: %w", orderID, err)
+	}
+	if len(history) == 0 {
+		return types.OrderStatus{}, fmt.Errorf("no history for order %s", orderID)
+	}
+
+	// History is chronological; the last entry is the current state
+	o := history[len(history)-1]
+	return types.OrderStatus{
+		OrderID:    o.OrderID,
+		Status:     kiteStatus(o.Status),
+		FilledQty:  int(o.FilledQuantity),
+		PendingQty: int(o.PendingQuantity),
+		AvgPrice:   o.AveragePrice,
+		Message:    o.StatusMessage,
+	}, nil
+}
+
+// kiteStatus maps Kite's order states (which include transient ones such as
+// "PUT ORDER REQ RECEIVED" or "VALIDATION PENDING") onto the normalized set.
+func kiteStatus(status string) string {
+	switch status {
+	case "COMPLETE":
+		return types.OrderComplete
+	case "CANCELLED":
+		return types.OrderCancelled
+	case "REJECTED":
+		return types.OrderRejected
+	case "TRIGGER PENDING":
+		return types.OrderTriggerPending
+	default:
+		return types.OrderOpen
+	}
+}
+
+func isSimulated(orderID string) bool {
+	return strings.HasPrefix(orderID, "SIM-")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"llm-trading-bot/internal/crash"
 	"llm-trading-bot/internal/logger"
 	"llm-trading-bot/internal/types"
 
@@ -17,12 +18,40 @@ func (tm *tickerManager) setupEventHandlers() {
 	tm.ticker.OnClose(tm.onClose)
 	tm.ticker.OnReconnect(tm.onReconnect)
 	tm.ticker.OnNoReconnect(tm.onNoReconnect)
-	tm.ticker.OnTick(tm.onTick)
-	tm.ticker.OnOrderUpdate(tm.onOrderUpdate)
+	// Panics in the data callbacks would take down the ticker goroutine
+	tm.ticker.OnTick(func(tick models.Tick) {
+		defer crash.Recover(context.Background(), "zerodha.tick", "token", tick.InstrumentToken)
+		tm.onTick(tick)
+	})
+	tm.ticker.OnOrderUpdate(func(order kiteconnect.Order) {
+		defer crash.Recover(context.Background(), "zerodha.order_update", "order_id", order.OrderID)
+		tm.onOrderUpdate(order)
+	})
 }
 
-
+// onConnect releases Start on the first connect. Later connects are
+// reconnects: the ticker library resubscribes on its own, and the bars
+// missed while disconnected are backfilled.
 func (tm *tickerManager) onConnect() {
+	tm.mu.Lock()
+	tm.connects++
+	first := tm.connects == 1
+	connected := tm.connected
+	symbols := tm.symbols
+	tm.mu.Unlock()
+
+	if first {
+		close(connected)
+		return
+	}
+
+	logger.Info(context.Background(), "WebSocket reconnected - backfilling gap", "symbols", len(symbols))
+	if tm.onResume != nil {
+		go func() {
+			defer crash.Recover(context.Background(), "zerodha.backfill")
+			tm.onResume(symbols)
+		}()
+	}
 }
 
 func (tm *tickerManager) onError(err error) {
@@ -37,30 +66,55 @@ func (tm *tickerManager) onClose(code int, reason string) {
 }
 
 func (tm *tickerManager) onReconnect(attempt int, delay time.Duration) {
+	logger.Warn(context.Background(), "WebSocket reconnecting",
+		"attempt", attempt,
+		"delay", delay,
+	)
 }
 
 func (tm *tickerManager) onNoReconnect(attempt int) {
-	logger.Warn(context.Background(), "WebSocket reconnection failed - giving up",
+	logger.Warn(context.Background(), "WebSocket reconnection failed - restarting ticker",
 		"attempts", attempt,
+		"restart_delay", restartDelay,
 	)
+	go func() {
+		defer crash.Recover(context.Background(), "zerodha.restart")
+		tm.restart()
+	}()
 }
 
 func (tm *tickerManager) onTick(tick models.Tick) {
+	tm.mu.RLock()
 	symbol, exists := tm.tokenToSymbol[tick.InstrumentToken]
+	tm.mu.RUnlock()
 	if !exists {
 		return
 	}
 
-	candle := types.Candle{
-		Ts:    tick.Timestamp.Time.Unix(),
-		Open:  tick.OHLC.Open,
-		High:  tick.OHLC.High,
-		Low:   tick.OHLC.Low,
-		Close: tick.LastPrice,
-		Vol:   float64(tick.VolumeTraded),
+	ts := tick.Timestamp.Time.Unix()
+	if tick.Timestamp.Time.IsZero() {
+		ts = time.Now().Unix()
+	}
+
+	tm.mu.Lock()
+	closed := tm.bars.add(symbol, ts, tick.LastPrice, float64(tick.VolumeTraded))
+	tm.mu.Unlock()
+
+	for _, bar := range closed {
+		tm.addCandle(symbol, bar)
+		tm.publishTick(types.Tick{
+			Symbol:   symbol,
+			Price:    bar.Close,
+			Ts:       bar.Ts,
+			BarClose: true,
+		})
 	}
 
-	tm.addCandle(symbol, candle)
+	tm.publishTick(types.Tick{
+		Symbol: symbol,
+		Price:  tick.LastPrice,
+		Ts:     ts,
+	})
 }
 
 func (tm *tickerManager) onOrderUpdate(order kiteconnect.Order) {
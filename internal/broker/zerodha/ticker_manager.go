@@ -6,7 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"llm-trading-bot/internal/crash"
 	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
 	"llm-trading-bot/internal/types"
 
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
@@ -14,39 +16,111 @@ import (
 )
 
 const (
-	maxCandlesPerSymbol = 200
+	maxCandlesPerSymbol = 250
 
-	connectionWaitTime = 2 * time.Second
+	connectionWaitTime = 10 * time.Second
+
+	// Delay before rebuilding a ticker that gave up reconnecting
+	restartDelay = 30 * time.Second
+
+	tickBufferSize = 1024
 )
 
 type tickerManager struct {
 	kc          *kiteconnect.Client
 	ticker      *kiteticker.Ticker
 	apiKey      string
-	accessToken string
+	accessToken func() string // Current token; refreshed daily by the token manager
 	exchange    string
 
-	candles map[string][]types.Candle
+	candles map[string][]types.Candle // Closed bars per symbol
+	bars    *barAggregator            // Builds bars from ticks; guarded by mu
 	mu      sync.RWMutex
 
 	tokenToSymbol map[uint32]string
+	instruments   *instrumentResolver
+
+	ticks chan types.Tick
+
+	symbols   []string               // Subscribed symbols, replayed after a restart
+	connected chan struct{}          // Closed on the first connect of the current ticker
+	connects  int                    // Connects seen by the current ticker
+	onResume  func(symbols []string) // Called after a reconnect to backfill the gap
 }
 
 var _ interfaces.TickerManager = (*tickerManager)(nil)
 
+// Start connects the websocket and waits up to connectionWaitTime for the
+// first connect so that Subscribe can be called straight after.
 func (tm *tickerManager) Start(ctx context.Context) error {
+	connected := tm.serve()
+
+	select {
+	case <-connected:
+		return nil
+	case <-time.After(connectionWaitTime):
+		return fmt.Errorf("websocket did not connect within %s", connectionWaitTime)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serve builds a ticker with the current access token and runs it in the
+// background, returning a channel closed on its first connect.
+func (tm *tickerManager) serve() <-chan struct{} {
+	token := tm.accessToken()
 	tm.kc = kiteconnect.New(tm.apiKey)
-	tm.kc.SetAccessToken(tm.accessToken)
+	tm.kc.SetAccessToken(token)
 
-	tm.ticker = kiteticker.New(tm.apiKey, tm.accessToken)
+	connected := make(chan struct{})
+	tm.mu.Lock()
+	tm.ticker = kiteticker.New(tm.apiKey, token)
+	tm.connected = connected
+	tm.connects = 0
+	tm.mu.Unlock()
 
 	tm.setupEventHandlers()
 
+	// A panic in the ticker is treated like a lost connection
 	go func() {
-		tm.ticker.Serve()
+		defer crash.Recover(context.Background(), "zerodha.restart")
+		if crash.Guard(context.Background(), "zerodha.ticker", tm.ticker.Serve) {
+			tm.restart()
+		}
 	}()
 
-	return nil
+	return connected
+}
+
+// restart replaces a ticker that exhausted its reconnect attempts, picking
+// up a refreshed access token, and resubscribes every symbol.
+func (tm *tickerManager) restart() {
+	ctx := context.Background()
+	for attempt := 1; ; attempt++ {
+		time.Sleep(restartDelay)
+
+		tm.ticker.Stop()
+		select {
+		case <-tm.serve():
+		case <-time.After(connectionWaitTime):
+			logger.Warn(ctx, "WebSocket restart failed - retrying", "attempt", attempt)
+			continue
+		}
+
+		tm.mu.RLock()
+		symbols := tm.symbols
+		tm.mu.RUnlock()
+		if err := tm.Subscribe(ctx, symbols); err != nil {
+			logger.Warn(ctx, "WebSocket resubscribe failed - retrying", "attempt", attempt, "error", err)
+			continue
+		}
+
+		logger.Info(ctx, "WebSocket restarted", "attempt", attempt, "symbols", len(symbols))
+		if tm.onResume != nil {
+			tm.onResume(symbols)
+		}
+		return
+	}
 }
 
 func (tm *tickerManager) Stop(ctx context.Context) {
@@ -59,17 +133,25 @@ func (tm *tickerManager) Subscribe(ctx context.Context, symbols []string) error
 	tokens := make([]uint32, 0, len(symbols))
 
 	for _, symbol := range symbols {
-		token := tm.getPlaceholderToken(symbol)
-
-		tm.tokenToSymbol[token] = symbol
+		token, err := tm.instruments.token(symbol)
+		if err != nil {
+			return err
+		}
 
 		tm.mu.Lock()
-		tm.candles[symbol] = make([]types.Candle, 0, maxCandlesPerSymbol)
+		tm.tokenToSymbol[token] = symbol
+		if _, ok := tm.candles[symbol]; !ok {
+			tm.candles[symbol] = make([]types.Candle, 0, maxCandlesPerSymbol)
+		}
 		tm.mu.Unlock()
 
 		tokens = append(tokens, token)
 	}
 
+	tm.mu.Lock()
+	tm.symbols = symbols
+	tm.mu.Unlock()
+
 	if err := tm.ticker.Subscribe(tokens); err != nil {
 		return fmt.Errorf("failed to subscribe to symbols: %w", err)
 	}
@@ -90,58 +172,71 @@ func (tm *tickerManager) GetRecentCandles(symbol string, n int) ([]types.Candle,
 		return nil, fmt.Errorf("no candle data for symbol %s", symbol)
 	}
 
-	if len(symbolCandles) == 0 {
+	// Closed bars plus the bar still forming, so the last close is current
+	out := make([]types.Candle, 0, len(symbolCandles)+1)
+	out = append(out, symbolCandles...)
+	if bar, ok := tm.bars.forming(symbol); ok && (len(out) == 0 || bar.Ts > out[len(out)-1].Ts) {
+		out = append(out, bar)
+	}
+
+	if len(out) == 0 {
 		return nil, fmt.Errorf("no candles available for %s", symbol)
 	}
 
-	if len(symbolCandles) < n {
-		return symbolCandles, nil
+	if len(out) < n {
+		return out, nil
 	}
 
-	return symbolCandles[len(symbolCandles)-n:], nil
+	return out[len(out)-n:], nil
 }
 
-func (tm *tickerManager) addCandle(symbol string, candle types.Candle) {
+// Backfill seeds the candle cache with historical bars, keeping any cached
+// candles newer than the last historical one.
+func (tm *tickerManager) Backfill(symbol string, history []types.Candle) {
+	if len(history) == 0 {
+		return
+	}
+
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	symbolCandles := tm.candles[symbol]
-	symbolCandles = append(symbolCandles, candle)
+	last := history[len(history)-1].Ts
+	merged := make([]types.Candle, 0, len(history)+len(tm.candles[symbol]))
+	merged = append(merged, history...)
+	for _, c := range tm.candles[symbol] {
+		if c.Ts > last {
+			merged = append(merged, c)
+		}
+	}
 
-	if len(symbolCandles) > maxCandlesPerSymbol {
-		symbolCandles = symbolCandles[1:]
+	if len(merged) > maxCandlesPerSymbol {
+		merged = merged[len(merged)-maxCandlesPerSymbol:]
 	}
+	tm.candles[symbol] = merged
+}
 
-	tm.candles[symbol] = symbolCandles
+func (tm *tickerManager) Ticks() <-chan types.Tick {
+	return tm.ticks
 }
 
-func (tm *tickerManager) getPlaceholderToken(symbol string) uint32 {
-	placeholderTokens := map[string]uint32{
-		"RELIANCE":   256265,
-		"TCS":        2953217,
-		"HDFCBANK":   341249,
-		"INFY":       408065,
-		"HCLTECH":    1850625,
-		"LT":         2939649,
-		"SBIN":       779521,
-		"ICICIBANK":  1270529,
-		"AXISBANK":   1510401,
-		"KOTAKBANK":  492033,
-		"ITC":        424961,
-		"TATAMOTORS": 884737,
-		"TITAN":      897537,
-		"JSWSTEEL":   3001089,
-		"ULTRACEMCO": 2952193,
-		"BAJFINANCE": 81153,
-		"HDFCLIFE":   119553,
-		"BHARTIARTL": 2714625,
-		"ASIANPAINT": 60417,
-		"MARUTI":     2815745,
+func (tm *tickerManager) publishTick(tick types.Tick) {
+	select {
+	case tm.ticks <- tick:
+	default:
+		// Consumer is behind; drop rather than block the websocket goroutine
 	}
+}
 
-	if token, exists := placeholderTokens[symbol]; exists {
-		return token
+func (tm *tickerManager) addCandle(symbol string, candle types.Candle) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	symbolCandles := tm.candles[symbol]
+	symbolCandles = append(symbolCandles, candle)
+
+	if len(symbolCandles) > maxCandlesPerSymbol {
+		symbolCandles = symbolCandles[1:]
 	}
 
-	return 256265
+	tm.candles[symbol] = symbolCandles
 }
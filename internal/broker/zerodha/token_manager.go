@@ -0,0 +1,268 @@
+package zerodha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+const kiteLoginBase = "https://kite.zerodha.com"
+
+// ErrReloginRequired matches api.ErrAuth.
+var ErrReloginRequired = fmt.Errorf("kite access token expired: manual re-login required (%w)", api.ErrAuth)
+
+type TokenParams struct {
+	APISecret    string // Needed for the request-token exchange
+	RequestToken string // One-off request token from a manual login redirect
+	UserID       string // Automated login (optional)
+	Password     string // Automated login (optional)
+	TOTPSecret   string // Base32 TOTP secret for automated 2FA (optional)
+	TokenFile    string // Where the current access token is persisted
+}
+
+type persistedToken struct {
+	AccessToken string    `json:"access_token"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+// tokenManager keeps a valid Kite access token. Tokens expire at 06:00 IST
+// every day; a new one is obtained from the persisted file, a supplied
+// request token, or an automated TOTP login, in that order.
+type tokenManager struct {
+	kc *kiteconnect.Client
+	p  TokenParams
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func newTokenManager(kc *kiteconnect.Client, initialToken string, p TokenParams) *tokenManager {
+	tm := &tokenManager{kc: kc, p: p}
+	if initialToken != "" {
+		// Assume an env-provided token is from today; a 403 will correct this
+		tm.token, tm.issuedAt = initialToken, time.Now()
+	}
+	return tm
+}
+
+// lastExpiry returns the most recent 06:00 IST before now.
+func lastExpiry(now time.Time) time.Time {
+	expiry := timeutil.At(now, 6, 0)
+	if now.Before(expiry) {
+		expiry = expiry.AddDate(0, 0, -1)
+	}
+	return expiry
+}
+
+func (tm *tokenManager) valid() bool {
+	return tm.token != "" && tm.issuedAt.After(lastExpiry(time.Now()))
+}
+
+// ensure returns a valid access token, refreshing it if needed.
+func (tm *tokenManager) ensure(ctx context.Context) (string, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.valid() {
+		return tm.token, nil
+	}
+
+	if pt, err := tm.load(); err == nil && pt.IssuedAt.After(lastExpiry(time.Now())) {
+		tm.set(pt.AccessToken, pt.IssuedAt)
+		return tm.token, nil
+	}
+
+	requestToken := tm.p.RequestToken
+	if requestToken == "" && tm.p.UserID != "" && tm.p.Password != "" && tm.p.TOTPSecret != "" {
+		rt, err := tm.automatedLogin(ctx)
+		if err != nil {
+			logger.Warn(ctx, "Automated Kite login failed", "error", err)
+		}
+		requestToken = rt
+	}
+
+	if requestToken == "" || tm.p.APISecret == "" {
+		logger.Error(ctx, "Kite access token expired - manual re-login required",
+			"event", "KITE_RELOGIN_REQUIRED",
+			"login_url", tm.kc.GetLoginURL(),
+		)
+		return "", ErrReloginRequired
+	}
+
+	session, err := tm.kc.GenerateSession(requestToken, tm.p.APISecret)
+	if err != nil {
+		logger.Error(ctx, "Kite request token exchange failed - manual re-login required",
+			"event", "KITE_RELOGIN_REQUIRED",
+			"error", err,
+			"login_url", tm.kc.GetLoginURL(),
+		)
+		return "", fmt.Errorf("%w: %v", ErrReloginRequired, err)
+	}
+	// A request token can only be exchanged once
+	tm.p.RequestToken = ""
+
+	tm.set(session.AccessToken, time.Now())
+	if err := tm.save(); err != nil {
+		logger.Warn(ctx, "Failed to persist Kite access token", "error", err, "path", tm.p.TokenFile)
+	}
+
+	logger.Info(ctx, "Kite access token refreshed")
+	return tm.token, nil
+}
+
+// current returns the token in use without attempting a refresh.
+func (tm *tokenManager) current() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.token
+}
+
+// invalidate drops the current token after the API rejected it.
+func (tm *tokenManager) invalidate() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.token = ""
+	tm.issuedAt = time.Time{}
+	if tm.p.TokenFile != "" {
+		_ = os.Remove(tm.p.TokenFile)
+	}
+}
+
+func (tm *tokenManager) set(token string, issuedAt time.Time) {
+	tm.token, tm.issuedAt = token, issuedAt
+	tm.kc.SetAccessToken(token)
+}
+
+func (tm *tokenManager) load() (persistedToken, error) {
+	var pt persistedToken
+	if tm.p.TokenFile == "" {
+		return pt, os.ErrNotExist
+	}
+	b, err := os.ReadFile(tm.p.TokenFile)
+	if err != nil {
+		return pt, err
+	}
+	err = json.Unmarshal(b, &pt)
+	return pt, err
+}
+
+func (tm *tokenManager) save() error {
+	if tm.p.TokenFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(tm.p.TokenFile), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(persistedToken{AccessToken: tm.token, IssuedAt: tm.issuedAt})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(tm.p.TokenFile, b, 0o600)
+}
+
+// automatedLogin performs Kite's web login with password and TOTP and
+// returns the request token from the Connect redirect.
+func (tm *tokenManager) automatedLogin(ctx context.Context) (string, error) {
+	jar, _ := cookiejar.New(nil)
+	var requestToken string
+	client := &http.Client{
+		Jar:       jar,
+		Timeout:   15 * time.Second,
+		Transport: api.Transport(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if rt := req.URL.Query().Get("request_token"); rt != "" {
+				requestToken = rt
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	var login struct {
+		Data struct {
+			RequestID string `json:"request_id"`
+		} `json:"data"`
+	}
+	if err := postForm(ctx, client, kiteLoginBase+"/api/login", url.Values{
+		"user_id":  {tm.p.UserID},
+		"password": {tm.p.Password},
+	}, &login); err != nil {
+		return "", fmt.Errorf("login: %w", err)
+	}
+
+	code, err := totpCode(tm.p.TOTPSecret, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := postForm(ctx, client, kiteLoginBase+"/api/twofa", url.Values{
+		"user_id":     {tm.p.UserID},
+		"request_id":  {login.Data.RequestID},
+		"twofa_value": {code},
+		"twofa_type":  {"totp"},
+	}, nil); err != nil {
+		return "", fmt.Errorf("twofa: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tm.kc.GetLoginURL(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil && requestToken == "" {
+		return "", fmt.Errorf("connect redirect: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if requestToken == "" {
+		return "", errors.New("connect redirect did not return a request token")
+	}
+	return requestToken, nil
+}
+
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("http %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isTokenError reports whether err is Kite rejecting the access token.
+func isTokenError(err error) bool {
+	var kerr kiteconnect.Error
+	if errors.As(err, &kerr) {
+		return kerr.Code == http.StatusForbidden || kerr.ErrorType == kiteconnect.TokenError
+	}
+	return false
+}
@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
 	"llm-trading-bot/internal/types"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
 )
 
 type Params struct {
@@ -17,33 +21,67 @@ type Params struct {
 	AccessToken  string
 	Exchange     string
 	CandleSource string
+	DryRunFunds  float64
+	Product      string // Kite product for orders: CNC (delivery) or MIS (intraday)
+
+	CandleInterval  int // Bar size in minutes for historical backfill
+	BackfillCandles int // Bars to backfill per symbol at startup and on gaps
+
+	Token TokenParams // Access-token refresh settings
 }
 
 type Zerodha struct {
 	p            Params
+	kc           *kiteconnect.Client
+	tokens       *tokenManager
+	instruments  *instrumentResolver
 	tickerMgr    interfaces.TickerManager
 	isTickerInit bool
+
+	mu           sync.Mutex
+	lastBackfill map[string]time.Time
 }
 
 var _ interfaces.Broker = (*Zerodha)(nil)
 
 func NewZerodha(p Params) *Zerodha {
-	z := &Zerodha{p: p}
+	if p.CandleInterval <= 0 {
+		p.CandleInterval = 1
+	}
+	if p.BackfillCandles <= 0 {
+		p.BackfillCandles = 250
+	}
+	if p.Product == "" {
+		p.Product = kiteconnect.ProductCNC
+	}
+
+	z := &Zerodha{p: p, lastBackfill: make(map[string]time.Time)}
+
+	if p.APIKey != "" {
+		z.kc = kiteconnect.New(p.APIKey)
+		z.kc.SetAccessToken(p.AccessToken)
+		z.tokens = newTokenManager(z.kc, p.AccessToken, p.Token)
+	}
+	z.instruments = newInstrumentResolver(z.kc, p.Exchange)
 
 	if p.CandleSource == "LIVE" {
-		z.tickerMgr = newTickerManager(p.APIKey, p.AccessToken, p.Exchange)
+		z.tickerMgr = newTickerManager(p.APIKey, z.currentToken, p.Exchange, p.CandleInterval, z.instruments, z.resume)
 	}
 
 	return z
 }
 
-func newTickerManager(apiKey, accessToken, exchange string) interfaces.TickerManager {
+func newTickerManager(apiKey string, accessToken func() string, exchange string, intervalMinutes int, instruments *instrumentResolver, onResume func(symbols []string)) interfaces.TickerManager {
 	return &tickerManager{
 		apiKey:        apiKey,
 		accessToken:   accessToken,
 		exchange:      exchange,
+		instruments:   instruments,
 		candles:       make(map[string][]types.Candle),
+		bars:          newBarAggregator(intervalMinutes),
 		tokenToSymbol: make(map[uint32]string),
+		ticks:         make(chan types.Tick, tickBufferSize),
+		onResume:      onResume,
 	}
 }
 
@@ -52,6 +90,50 @@ func (z *Zerodha) LTP(ctx context.Context, symbol string) (float64, error) {
 	return price, nil
 }
 
+func (z *Zerodha) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	if z.kc == nil {
+		return types.Quote{}, errors.New("quotes require API key/access token")
+	}
+
+	exchange, ts := z.listing(symbol)
+	key := exchange + ":" + ts
+	var quotes kiteconnect.Quote
+	err := z.withToken(ctx, func() (err error) {
+		quotes, err = z.kc.GetQuote(key)
+		return err
+	})
+	if err != nil {
+		return types.Quote{}, fmt.Errorf("failed to fetch quote for %s: %w", symbol, err)
+	}
+	data, ok := quotes[key]
+	if !ok {
+		return types.Quote{}, fmt.Errorf("no quote for %s", key)
+	}
+
+	q := types.Quote{
+		Symbol: symbol,
+		LTP:    data.LastPrice,
+		Ts:     data.Timestamp.Time.Unix(),
+	}
+	for _, d := range data.Depth.Buy {
+		if d.Price > 0 {
+			q.Bids = append(q.Bids, types.DepthLevel{Price: d.Price, Qty: int(d.Quantity), Orders: int(d.Orders)})
+		}
+	}
+	for _, d := range data.Depth.Sell {
+		if d.Price > 0 {
+			q.Asks = append(q.Asks, types.DepthLevel{Price: d.Price, Qty: int(d.Quantity), Orders: int(d.Orders)})
+		}
+	}
+	if len(q.Bids) > 0 {
+		q.Bid = q.Bids[0].Price
+	}
+	if len(q.Asks) > 0 {
+		q.Ask = q.Asks[0].Price
+	}
+	return q, nil
+}
+
 func (z *Zerodha) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
 	if z.p.CandleSource == "LIVE" {
 		return z.fetchLiveCandles(ctx, symbol, n)
@@ -88,6 +170,13 @@ func (z *Zerodha) fetchLiveCandles(ctx context.Context, symbol string, n int) ([
 	}
 
 	candles, err := z.tickerMgr.GetRecentCandles(symbol, n)
+	if err != nil || hasGap(candles, n, z.p.CandleInterval) {
+		if bfErr := z.backfill(ctx, symbol); bfErr != nil {
+			logger.Warn(ctx, "Historical backfill failed", "symbol", symbol, "error", bfErr)
+		} else {
+			candles, err = z.tickerMgr.GetRecentCandles(symbol, n)
+		}
+	}
 	if err != nil {
 		return z.fetchStaticCandles(ctx, symbol, n)
 	}
@@ -95,6 +184,13 @@ func (z *Zerodha) fetchLiveCandles(ctx context.Context, symbol string, n int) ([
 	return candles, nil
 }
 
+func (z *Zerodha) Ticks() <-chan types.Tick {
+	if z.tickerMgr == nil {
+		return nil
+	}
+	return z.tickerMgr.Ticks()
+}
+
 func (z *Zerodha) Start(ctx context.Context, symbols []string) error {
 	if z.tickerMgr == nil {
 		return nil // Not in live mode, nothing to start
@@ -104,16 +200,24 @@ func (z *Zerodha) Start(ctx context.Context, symbols []string) error {
 		return nil // Already started
 	}
 
+	if _, err := z.ensureToken(ctx); err != nil {
+		return fmt.Errorf("failed to obtain access token: %w", err)
+	}
+
 	if err := z.tickerMgr.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start ticker manager: %w", err)
 	}
 
-	time.Sleep(2 * time.Second)
-
 	if err := z.tickerMgr.Subscribe(ctx, symbols); err != nil {
 		return fmt.Errorf("failed to subscribe to symbols: %w", err)
 	}
 
+	for _, symbol := range symbols {
+		if err := z.backfill(ctx, symbol); err != nil {
+			logger.Warn(ctx, "Historical backfill failed", "symbol", symbol, "error", err)
+		}
+	}
+
 	z.isTickerInit = true
 	return nil
 }
@@ -125,22 +229,73 @@ func (z *Zerodha) Stop(ctx context.Context) {
 	}
 }
 
-func (z *Zerodha) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+func (z *Zerodha) Funds(ctx context.Context) (types.Funds, error) {
 	if z.p.Mode == "DRY_RUN" {
-		return types.OrderResp{
-			OrderID: fmt.Sprintf("SIM-%d", time.Now().UnixNano()),
-			Status:  "SIMULATED",
-			Message: "dry-run",
+		return types.Funds{
+			Available: z.p.DryRunFunds,
+			Net:       z.p.DryRunFunds,
 		}, nil
 	}
 
-	if z.p.APIKey == "" || z.p.AccessToken == "" {
-		return types.OrderResp{}, errors.New("missing API key/access token")
+	var margins kiteconnect.Margins
+	err := z.withToken(ctx, func() (err error) {
+		margins, err = z.kc.GetUserSegmentMargins("equity")
+		return err
+	})
+	if err != nil {
+		return types.Funds{}, fmt.Errorf("failed to fetch equity margins: %w", err)
 	}
 
-	return types.OrderResp{
-		OrderID: fmt.Sprintf("LIVE-%d", time.Now().UnixNano()),
-		Status:  "PLACED",
-		Message: "ok",
+	return types.Funds{
+		Available: margins.Available.LiveBalance,
+		Utilised:  margins.Used.Debits,
+		Net:       margins.Net,
 	}, nil
 }
+
+// currentToken returns the access token in use, for components that
+// connect with it directly (the websocket ticker).
+func (z *Zerodha) currentToken() string {
+	if z.tokens == nil {
+		return z.p.AccessToken
+	}
+	return z.tokens.current()
+}
+
+// ensureToken returns a valid access token, refreshing an expired one.
+func (z *Zerodha) ensureToken(ctx context.Context) (string, error) {
+	if z.tokens == nil {
+		return "", errors.New("missing API key/access token")
+	}
+	return z.tokens.ensure(ctx)
+}
+
+// withToken runs a Kite call with a valid access token. If Kite rejects the
+// token (403), it is dropped, refreshed once, and the call retried.
+func (z *Zerodha) withToken(ctx context.Context, call func() error) error {
+	if _, err := z.ensureToken(ctx); err != nil {
+		return err
+	}
+
+	err := call()
+	if !isTokenError(err) {
+		return err
+	}
+
+	logger.Warn(ctx, "Kite rejected access token - refreshing", "error", err)
+	z.tokens.invalidate()
+	if _, err := z.ensureToken(ctx); err != nil {
+		return err
+	}
+	return call()
+}
+
+// listing returns the exchange and Kite trading symbol for a possibly
+// exchange-qualified symbol, translating BSE scrip codes when the
+// instrument dump is available.
+func (z *Zerodha) listing(symbol string) (exchange, tradingsymbol string) {
+	if inst, err := z.instruments.resolve(symbol); err == nil {
+		return inst.exchange, inst.tradingsymbol
+	}
+	return types.SplitSymbol(symbol, z.p.Exchange)
+}
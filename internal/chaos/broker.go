@@ -0,0 +1,209 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+// faultyBroker injects timeouts and latency into broker calls and drops
+// into the tick stream.
+type faultyBroker struct {
+	broker interfaces.Broker
+	opts   Options
+	dice   *dice
+
+	mu      sync.Mutex
+	src     <-chan types.Tick // Inner stream currently forwarded
+	out     chan types.Tick
+	stopFwd chan struct{}
+}
+
+var (
+	_ interfaces.Broker     = (*faultyBroker)(nil)
+	_ interfaces.StopBroker = (*faultyBroker)(nil)
+)
+
+// WrapBroker injects o's broker and stream faults into broker's calls.
+func WrapBroker(broker interfaces.Broker, o Options) interfaces.Broker {
+	return &faultyBroker{broker: broker, opts: o, dice: newDice(o.Seed)}
+}
+
+// fault delays a call by the configured latency and, at the timeout rate,
+// hangs it until BrokerTimeout or the call's deadline and fails it with an
+// error matching both api.ErrUnavailable and context.DeadlineExceeded.
+func (fb *faultyBroker) fault(ctx context.Context, method string) error {
+	if err := sleep(ctx, fb.dice.upTo(fb.opts.BrokerLatency)); err != nil {
+		return err
+	}
+	if !fb.dice.roll(fb.opts.BrokerTimeoutRate) {
+		return nil
+	}
+	logger.WarnSkip(ctx, 1, "Chaos: injecting broker timeout", "method", method, "hang", fb.opts.BrokerTimeout)
+	if err := sleep(ctx, fb.opts.BrokerTimeout); err != nil {
+		return err
+	}
+	return fmt.Errorf("chaos: broker %s timed out: %w: %w", method, api.ErrUnavailable, context.DeadlineExceeded)
+}
+
+func (fb *faultyBroker) LTP(ctx context.Context, symbol string) (float64, error) {
+	if err := fb.fault(ctx, "LTP"); err != nil {
+		return 0, err
+	}
+	return fb.broker.LTP(ctx, symbol)
+}
+
+func (fb *faultyBroker) GetQuote(ctx context.Context, symbol string) (types.Quote, error) {
+	if err := fb.fault(ctx, "GetQuote"); err != nil {
+		return types.Quote{}, err
+	}
+	return fb.broker.GetQuote(ctx, symbol)
+}
+
+func (fb *faultyBroker) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	if err := fb.fault(ctx, "RecentCandles"); err != nil {
+		return nil, err
+	}
+	return fb.broker.RecentCandles(ctx, symbol, n)
+}
+
+func (fb *faultyBroker) PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error) {
+	if err := fb.fault(ctx, "PlaceOrder"); err != nil {
+		return types.OrderResp{}, err
+	}
+	return fb.broker.PlaceOrder(ctx, req)
+}
+
+func (fb *faultyBroker) ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error) {
+	if err := fb.fault(ctx, "ModifyOrder"); err != nil {
+		return types.OrderResp{}, err
+	}
+	return fb.broker.ModifyOrder(ctx, orderID, mod)
+}
+
+func (fb *faultyBroker) CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error) {
+	if err := fb.fault(ctx, "CancelOrder"); err != nil {
+		return types.OrderResp{}, err
+	}
+	return fb.broker.CancelOrder(ctx, orderID)
+}
+
+func (fb *faultyBroker) GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error) {
+	if err := fb.fault(ctx, "GetOrderStatus"); err != nil {
+		return types.OrderStatus{}, err
+	}
+	return fb.broker.GetOrderStatus(ctx, orderID)
+}
+
+func (fb *faultyBroker) Funds(ctx context.Context) (types.Funds, error) {
+	if err := fb.fault(ctx, "Funds"); err != nil {
+		return types.Funds{}, err
+	}
+	return fb.broker.Funds(ctx)
+}
+
+func (fb *faultyBroker) PlaceStop(ctx context.Context, req types.StopReq) (string, error) {
+	sb, ok := fb.broker.(interfaces.StopBroker)
+	if !ok {
+		return "", interfaces.ErrStopsUnsupported
+	}
+	if err := fb.fault(ctx, "PlaceStop"); err != nil {
+		return "", err
+	}
+	return sb.PlaceStop(ctx, req)
+}
+
+func (fb *faultyBroker) ModifyStop(ctx context.Context, stopID string, req types.StopReq) error {
+	sb, ok := fb.broker.(interfaces.StopBroker)
+	if !ok {
+		return interfaces.ErrStopsUnsupported
+	}
+	if err := fb.fault(ctx, "ModifyStop"); err != nil {
+		return err
+	}
+	return sb.ModifyStop(ctx, stopID, req)
+}
+
+func (fb *faultyBroker) CancelStop(ctx context.Context, stopID string) error {
+	sb, ok := fb.broker.(interfaces.StopBroker)
+	if !ok {
+		return interfaces.ErrStopsUnsupported
+	}
+	if err := fb.fault(ctx, "CancelStop"); err != nil {
+		return err
+	}
+	return sb.CancelStop(ctx, stopID)
+}
+
+// Ticks forwards the inner stream through a drop filter. A broker that
+// restarts its stream hands out a new channel, which replaces the old one.
+func (fb *faultyBroker) Ticks() <-chan types.Tick {
+	src := fb.broker.Ticks()
+	if src == nil || fb.opts.StreamDropRate <= 0 {
+		return src
+	}
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	if src != fb.src {
+		if fb.stopFwd != nil {
+			close(fb.stopFwd)
+		}
+		fb.src = src
+		fb.out = make(chan types.Tick, cap(src))
+		fb.stopFwd = make(chan struct{})
+		go fb.forward(src, fb.out, fb.stopFwd)
+	}
+	return fb.out
+}
+
+// forward copies ticks from src to out until src closes or stop, going
+// silent for StreamDropFor whenever a drop is rolled, as a websocket
+// that disconnects and reconnects would.
+func (fb *faultyBroker) forward(src <-chan types.Tick, out chan<- types.Tick, stop <-chan struct{}) {
+	ctx := context.Background()
+	var silentUntil time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case tk, ok := <-src:
+			if !ok {
+				close(out)
+				return
+			}
+			now := time.Now()
+			if now.Before(silentUntil) {
+				continue
+			}
+			if !silentUntil.IsZero() {
+				silentUntil = time.Time{}
+				logger.Info(ctx, "Chaos: tick stream restored")
+			}
+			if fb.dice.roll(fb.opts.StreamDropRate) {
+				silentUntil = now.Add(fb.opts.StreamDropFor)
+				logger.Warn(ctx, "Chaos: dropping tick stream", "for", fb.opts.StreamDropFor)
+				continue
+			}
+			select {
+			case out <- tk:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// Start and Stop pass through, so a run always gets going and shuts down.
+func (fb *faultyBroker) Start(ctx context.Context, symbols []string) error {
+	return fb.broker.Start(ctx, symbols)
+}
+
+func (fb *faultyBroker) Stop(ctx context.Context) {
+	fb.broker.Stop(ctx)
+}
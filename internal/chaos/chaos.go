@@ -0,0 +1,76 @@
+// Package chaos injects faults for testing: broker calls that hang until
+// they time out, LLM responses that do not parse and websocket streams
+// that go silent. It sits between the engine and the real dependencies,
+// so the retry, fallback and circuit-breaker paths run as they would on a
+// bad day. It is only wired up outside LIVE mode.
+package chaos
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	BrokerTimeoutRate float64       // Share of broker calls that hang, then fail as timed out
+	BrokerTimeout     time.Duration // How long an injected timeout hangs, cut short by the call's deadline
+	BrokerLatency     time.Duration // Random extra delay, up to this, before every broker call
+	LLMMalformedRate  float64       // Share of LLM provider responses replaced with malformed JSON
+	StreamDropRate    float64       // Chance per tick that the tick stream drops
+	StreamDropFor     time.Duration // How long a dropped stream stays silent
+	Seed              int64         // Fixes the fault sequence; 0 = random
+}
+
+// Enabled reports whether o injects anything.
+func (o Options) Enabled() bool {
+	return o.BrokerTimeoutRate > 0 || o.BrokerLatency > 0 || o.LLMMalformedRate > 0 || o.StreamDropRate > 0
+}
+
+// dice is a random source shared by the goroutines of one wrapper.
+type dice struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newDice(seed int64) *dice {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &dice{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// roll reports true with probability rate.
+func (d *dice) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rnd.Float64() < rate
+}
+
+// upTo returns a random duration in [0, max).
+func (d *dice) upTo(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return time.Duration(d.rnd.Int63n(int64(max)))
+}
+
+// sleep waits for dur or until ctx is done, whichever is first.
+func sleep(ctx context.Context, dur time.Duration) error {
+	if dur <= 0 {
+		return nil
+	}
+	t := time.NewTimer(dur)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
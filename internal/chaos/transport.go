@@ -0,0 +1,56 @@
+package chaos
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"llm-trading-bot/internal/logger"
+)
+
+// llmHosts are the LLM provider endpoints whose responses are corrupted.
+var llmHosts = map[string]bool{
+	"api.openai.com":    true,
+	"api.anthropic.com": true,
+}
+
+// malformedBody is cut off mid-object, as a dropped connection or a
+// misbehaving proxy would leave it.
+const malformedBody = `{"id":"chaos","choices":[{"message":{"content":"{\"action\":\"BU`
+
+type faultyTransport struct {
+	next http.RoundTripper
+	opts Options
+	dice *dice
+}
+
+// WrapTransport returns a wrapper for api.Options.WrapTransport that
+// answers a share of LLM provider calls with a 200 and a malformed body,
+// without sending them, so the providers' parsers report api.ErrParse.
+func WrapTransport(o Options) func(http.RoundTripper) http.RoundTripper {
+	d := newDice(o.Seed)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &faultyTransport{next: next, opts: o, dice: d}
+	}
+}
+
+func (ft *faultyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || !llmHosts[req.URL.Hostname()] || !ft.dice.roll(ft.opts.LLMMalformedRate) {
+		return ft.next.RoundTrip(req)
+	}
+	logger.Warn(req.Context(), "Chaos: injecting malformed LLM response", "host", req.URL.Host)
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(malformedBody)),
+		ContentLength: int64(len(malformedBody)),
+		Request:       req,
+	}, nil
+}
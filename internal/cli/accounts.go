@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/lifecycle"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/marketdata"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/universe"
+)
+
+// account is one broker account traded by the run loop: its settings,
+// broker, engine and universe. Without accounts: there is a single one
+// with an empty name.
+type account struct {
+	cfg     *store.Config
+	brk     interfaces.Broker
+	eng     interfaces.Engine
+	uni     *universe.Manager
+	symbols []string
+	ticks   *marketdata.Sanitizer // Drops bad stream ticks before they reach the engine
+
+	stopTicks chan struct{} // Closed to stop forwarding the current tick stream
+}
+
+// name is the account's name, empty for a single account.
+func (a *account) name() string {
+	return a.cfg.Account
+}
+
+// with tags logs and notifications under ctx with the account's name.
+func (a *account) with(ctx context.Context) context.Context {
+	if a.name() == "" {
+		return ctx
+	}
+	return logger.WithFields(notify.WithAccount(ctx, a.name()), "account", a.name())
+}
+
+// accountTick is a tick from one account's stream.
+type accountTick struct {
+	acct *account
+	tick types.Tick
+}
+
+// forwardTicks copies the account's current tick stream into out until the
+// stream closes or is replaced by the next call.
+func (a *account) forwardTicks(ctx context.Context, out chan<- accountTick) {
+	if a.stopTicks != nil {
+		close(a.stopTicks)
+		a.stopTicks = nil
+	}
+	src := a.brk.Ticks()
+	if src == nil {
+		return
+	}
+	stop := make(chan struct{})
+	a.stopTicks = stop
+	go func() {
+		for {
+			select {
+			case tk, ok := <-src:
+				if !ok {
+					return
+				}
+				select {
+				case out <- accountTick{acct: a, tick: tk}:
+				case <-stop:
+					return
+				case <-ctx.Done():
+					return
+				}
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// eachAccount runs fn for every account concurrently and waits for all.
+func eachAccount(accts []*account, fn func(a *account)) {
+	if len(accts) == 1 {
+		fn(accts[0])
+		return
+	}
+	var wg sync.WaitGroup
+	for _, a := range accts {
+		wg.Add(1)
+		go func(a *account) {
+			defer wg.Done()
+			fn(a)
+		}(a)
+	}
+	wg.Wait()
+}
+
+// accountsEngine presents several accounts' engines as one to the control
+// API and crash reports: reads merge, actions apply to every account.
+type accountsEngine []*account
+
+var _ interfaces.Engine = accountsEngine(nil)
+
+// engineFor returns the engine behind the accounts, or the merged view
+// when there are several.
+func engineFor(accts []*account) interfaces.Engine {
+	if len(accts) == 1 {
+		return accts[0].eng
+	}
+	return accountsEngine(accts)
+}
+
+// Step steps symbol in every account that trades it and returns the
+// first account's result.
+func (ae accountsEngine) Step(ctx context.Context, symbol string) (*types.StepResult, error) {
+	var (
+		first *types.StepResult
+		errs  []error
+	)
+	for _, a := range ae {
+		if !a.trades(symbol) {
+			continue
+		}
+		st, err := a.eng.Step(a.with(ctx), symbol)
+		if err != nil {
+			errs = append(errs, err)
+		} else if first == nil {
+			first = st
+		}
+	}
+	return first, errors.Join(errs...)
+}
+
+func (ae accountsEngine) ShouldEvaluate(ctx context.Context, symbol string, price float64) bool {
+	for _, a := range ae {
+		if a.trades(symbol) && a.eng.ShouldEvaluate(a.with(ctx), symbol, price) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ae accountsEngine) PlanTick(ctx context.Context, symbols []string) {
+	for _, a := range ae {
+		a.eng.PlanTick(a.with(ctx), a.symbols)
+	}
+}
+
+func (ae accountsEngine) Positions() []types.Position {
+	var out []types.Position
+	for _, a := range ae {
+		out = append(out, a.eng.Positions()...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+func (ae accountsEngine) Flatten(ctx context.Context, reason string) ([]types.OrderResp, error) {
+	orders := []types.OrderResp{}
+	var errs []error
+	for _, a := range ae {
+		o, err := a.eng.Flatten(a.with(ctx), reason)
+		orders = append(orders, o...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return orders, errors.Join(errs...)
+}
+
+func (ae accountsEngine) CancelOpenOrders(ctx context.Context) (int, error) {
+	total := 0
+	var errs []error
+	for _, a := range ae {
+		n, err := a.eng.CancelOpenOrders(a.with(ctx))
+		total += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+func (ae accountsEngine) Protect(ctx context.Context) error {
+	var errs []error
+	for _, a := range ae {
+		if err := a.eng.Protect(a.with(ctx)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ae accountsEngine) SymbolStates() []lifecycle.State {
+	var out []lifecycle.State
+	for _, a := range ae {
+		out = append(out, a.eng.SymbolStates()...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+// SetBlocked blocks or releases the symbol in every account.
+func (ae accountsEngine) SetBlocked(ctx context.Context, symbol string, blocked bool, reason string) error {
+	var errs []error
+	for _, a := range ae {
+		if err := a.eng.SetBlocked(a.with(ctx), symbol, blocked, reason); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// trades reports whether symbol is in the account's universe.
+func (a *account) trades(symbol string) bool {
+	for _, s := range a.symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"llm-trading-bot/internal/backtest"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/llm/noop"
+	"llm-trading-bot/internal/llm/replay"
+	"llm-trading-bot/internal/llm/rules"
+	"llm-trading-bot/internal/marketdata"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/types"
+)
+
+// runBacktest replays candle CSVs through the engine, or sweeps config
+// keys over them, and writes the results under -out.
+func runBacktest(args []string) int {
+	fs := newFlagSet("backtest")
+	cf := addConfigFlags(fs)
+	dataDir := fs.String("data", "data/candles", "directory of <SYMBOL>.csv candle files or a candle store")
+	symbolsFlag := fs.String("symbols", "", "comma-separated symbols (default: universe_static)")
+	cash := fs.Float64("cash", 100000, "initial cash")
+	slippage := fs.Float64("slippage-bps", 5, "slippage per fill in basis points")
+	warmup := fs.Int("warmup", 200, "bars replayed before the engine starts deciding")
+	barsPerYear := fs.Float64("bars-per-year", 252, "annualization factor (252 daily, 94500 for 1-minute NSE bars)")
+	deciderName := fs.String("decider", "config", "decider to use: config (llm.provider), rules or noop")
+	recordPath := fs.String("record", "", "record decisions to this JSONL file (reused on later runs)")
+	replayPath := fs.String("replay", "", "replay decisions from this JSONL file instead of calling the decider")
+	outDir := fs.String("out", "backtest-out", "output directory for equity/trade CSVs and trade logs")
+	folds := fs.Int("folds", 0, "walk-forward folds for a sweep (0 = sweep over the full range)")
+	trainPct := fs.Float64("train-pct", 0.7, "share of each walk-forward fold used for selection")
+	metric := fs.String("metric", "sharpe", "sweep selection metric: sharpe or return")
+	var params []backtest.Param
+	fs.Func("param", "sweep a config key, e.g. stop.atr_mult=1,1.5,2 (repeatable)", func(s string) error {
+		p, err := backtest.ParseParam(s)
+		if err != nil {
+			return err
+		}
+		params = append(params, p)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// Keep backtest trade logs away from the live logs directory
+	_ = os.Setenv("TRADER_LOG_DIR", filepath.Join(*outDir, "logs"))
+	if os.Getenv("LOG_LEVEL") == "" {
+		_ = os.Setenv("LOG_LEVEL", "WARN")
+	}
+	if err := initializeSystem(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	// Symbol statuses and order counts start fresh each run and never
+	// touch the live state files
+	cfg.Lifecycle.StateFile = ""
+	cfg.Risk.OrderCountFile = ""
+
+	symbols := cfg.UniverseStatic
+	if *symbolsFlag != "" {
+		symbols = strings.Split(*symbolsFlag, ",")
+	}
+
+	candles, err := marketdata.LoadDir(*dataDir, symbols)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load candles: %v\n", err)
+		return 1
+	}
+
+	decider, err := buildDecider(*deciderName, *recordPath, *replayPath, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up decider: %v\n", err)
+		return 1
+	}
+
+	opts := backtest.Options{
+		InitialCash: *cash,
+		SlippageBps: *slippage,
+		Warmup:      *warmup,
+		BarsPerYear: *barsPerYear,
+	}
+
+	if len(params) > 0 {
+		return runSweep(cfg, decider, candles, params, backtest.SweepOptions{
+			Options:  opts,
+			Folds:    *folds,
+			TrainPct: *trainPct,
+			Metric:   *metric,
+		}, *outDir)
+	}
+
+	res, err := backtest.Run(context.Background(), cfg, decider, candles, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backtest failed: %v\n", err)
+		return 1
+	}
+
+	res.WriteSummary(os.Stdout)
+	if err := res.WriteCSV(*outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write results: %v\n", err)
+		return 1
+	}
+	fmt.Printf("\nResults written to %s\n", *outDir)
+	return 0
+}
+
+// buildDecider picks the base decider and wraps it for recording, or
+// replaces it with a replayer that falls back to HOLD on unseen states.
+func buildDecider(name, recordPath, replayPath string, cfg *store.Config) (interfaces.Decider, error) {
+	if replayPath != "" {
+		return replay.NewReplayer(replayPath, nil)
+	}
+	base := backtestDecider(name, cfg)
+	if recordPath != "" {
+		return replay.NewRecorder(base, recordPath)
+	}
+	return base, nil
+}
+
+// backtestDecider honours -decider noop|rules; anything else uses
+// llm.provider like the live bot
+func backtestDecider(name string, cfg *store.Config) interfaces.Decider {
+	switch name {
+	case "noop":
+		return noop.NewNoopDecider()
+	case "rules":
+		return rules.NewRuleDecider()
+	}
+	return newDecider(cfg)
+}
+
+func runSweep(cfg *store.Config, decider interfaces.Decider, candles map[string][]types.Candle, params []backtest.Param, opts backtest.SweepOptions, outDir string) int {
+	rows, err := backtest.Sweep(context.Background(), cfg, decider, candles, params, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sweep failed: %v\n", err)
+		return 1
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create output directory: %v\n", err)
+		return 1
+	}
+
+	keys := backtest.ParamKeys(params)
+	csvPath := filepath.Join(outDir, "sweep.csv")
+	htmlPath := filepath.Join(outDir, "sweep.html")
+	if err := backtest.WriteSweepCSV(csvPath, rows, keys); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", csvPath, err)
+		return 1
+	}
+	if err := backtest.WriteSweepHTML(htmlPath, rows, keys); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", htmlPath, err)
+		return 1
+	}
+
+	fmt.Printf("Sweep of %d runs written to %s and %s\n", len(rows), csvPath, htmlPath)
+	return 0
+}
@@ -0,0 +1,728 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/broker/alpaca"
+	"llm-trading-bot/internal/broker/brokerobs"
+	"llm-trading-bot/internal/broker/paper"
+	"llm-trading-bot/internal/broker/upstox"
+	"llm-trading-bot/internal/broker/zerodha"
+	"llm-trading-bot/internal/chaos"
+	"llm-trading-bot/internal/clock"
+	"llm-trading-bot/internal/control"
+	"llm-trading-bot/internal/engine"
+	"llm-trading-bot/internal/engine/engineobs"
+	"llm-trading-bot/internal/eod"
+	"llm-trading-bot/internal/eod/eodobs"
+	"llm-trading-bot/internal/health"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/killswitch"
+	"llm-trading-bot/internal/llm/claude"
+	"llm-trading-bot/internal/llm/llmobs"
+	"llm-trading-bot/internal/llm/llmqueue"
+	"llm-trading-bot/internal/llm/noop"
+	"llm-trading-bot/internal/llm/openai"
+	"llm-trading-bot/internal/llm/rules"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/marketdata"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/secrets"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/symbols"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/trace"
+	"llm-trading-bot/internal/tradelog"
+	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/universe"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// initializeTracing installs the configured trace exporter and sampler,
+// tagging spans with the bot version and trading mode
+func initializeTracing(ctx context.Context, cfg *store.Config) {
+	attrs := map[string]string{"bot.mode": cfg.Mode}
+	for k, v := range cfg.Tracing.Attributes {
+		attrs[k] = v
+	}
+	err := trace.Init(trace.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPProtocol: cfg.Tracing.OTLPProtocol,
+		OTLPHeaders:  cfg.Tracing.OTLPHeaders,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		Version:      version,
+		Attributes:   attrs,
+	})
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to initialize tracer - continuing without traces", err)
+	}
+}
+
+// initializeAPIClient configures the shared HTTP client used for LLM and
+// data-source calls
+func initializeAPIClient(ctx context.Context, cfg *store.Config) error {
+	if err := api.Configure(apiOptions(cfg)); err != nil {
+		logger.ErrorWithErr(ctx, "Failed to configure API client", err)
+		return err
+	}
+	return nil
+}
+
+func apiOptions(cfg *store.Config) api.Options {
+	opts := api.Options{
+		Timeout:          time.Duration(cfg.API.TimeoutSeconds) * time.Second,
+		RatePerSecond:    cfg.API.RatePerSecond,
+		Burst:            cfg.API.Burst,
+		HostRates:        cfg.API.HostRates,
+		FailureThreshold: cfg.API.FailureThreshold,
+		Cooldown:         time.Duration(cfg.API.CooldownSeconds) * time.Second,
+		Cookies:          cfg.API.Cookies,
+		WarmUp:           cfg.API.WarmUp,
+		WarmUpTTL:        time.Duration(cfg.API.WarmUpTTLSeconds) * time.Second,
+		CacheDir:         cfg.API.CacheDir,
+		CacheTTL:         time.Duration(cfg.API.CacheTTLSeconds) * time.Second,
+		CacheMaxBytes:    int64(cfg.API.CacheMaxMB) << 20,
+		CacheBypass:      cfg.API.CacheBypass,
+		Retry: api.RetryPolicy{
+			MaxAttempts: cfg.API.RetryAttempts,
+			BaseDelay:   time.Duration(cfg.API.RetryBaseMs) * time.Millisecond,
+			MaxDelay:    time.Duration(cfg.API.RetryMaxMs) * time.Millisecond,
+			Budget:      time.Duration(cfg.API.RetryBudgetSeconds) * time.Second,
+		},
+		Proxy:       cfg.API.Proxy,
+		HostProxies: cfg.API.HostProxies,
+		UserAgents:  cfg.API.UserAgents,
+		FixtureMode: cfg.API.FixtureMode,
+		FixtureDir:  cfg.API.FixtureDir,
+	}
+	if o := chaosOptions(cfg); o.LLMMalformedRate > 0 {
+		opts.WrapTransport = chaos.WrapTransport(o)
+	}
+	return opts
+}
+
+// chaosOptions returns the configured fault injection, or none when chaos
+// is disabled or the bot trades live
+func chaosOptions(cfg *store.Config) chaos.Options {
+	if !cfg.Chaos.Enabled || cfg.Mode == "LIVE" {
+		return chaos.Options{}
+	}
+	return chaos.Options{
+		BrokerTimeoutRate: cfg.Chaos.BrokerTimeoutRate,
+		BrokerTimeout:     time.Duration(cfg.Chaos.BrokerTimeoutSeconds) * time.Second,
+		BrokerLatency:     time.Duration(cfg.Chaos.BrokerLatencyMs) * time.Millisecond,
+		LLMMalformedRate:  cfg.Chaos.LLMMalformedRate,
+		StreamDropRate:    cfg.Chaos.StreamDropRate,
+		StreamDropFor:     time.Duration(cfg.Chaos.StreamDropSeconds) * time.Second,
+		Seed:              cfg.Chaos.Seed,
+	}
+}
+
+// initializeSourceQuality starts scoring data sources so fallback chains
+// try the most reliable one first
+func initializeSourceQuality(cfg *store.Config) {
+	quality.SetDefault(quality.New(cfg.API.QualityFile))
+}
+
+// initializeSecrets builds the secret provider chain from config. Remote
+// providers are read once here, so a bad path or credential stops startup.
+func initializeSecrets(ctx context.Context, cfg *store.Config) error {
+	var chain []interfaces.SecretProvider
+	for _, name := range cfg.Secrets.Providers {
+		var (
+			p   interfaces.SecretProvider
+			err error
+		)
+		switch name {
+		case "env":
+			p = secrets.NewEnv()
+		case "file":
+			p, err = secrets.NewFile(cfg.Secrets.FileDir)
+		case "aws":
+			p, err = secrets.NewAWS(ctx, cfg.Secrets.AWSRegion, cfg.Secrets.AWSSecretID)
+		case "vault":
+			p, err = secrets.NewVault(ctx, os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"), cfg.Secrets.VaultPath)
+		}
+		if err != nil {
+			logger.ErrorWithErr(ctx, "Failed to load secrets", err, "provider", name)
+			return err
+		}
+		chain = append(chain, p)
+	}
+	secrets.SetDefault(chain...)
+
+	checkSecrets(ctx, requiredSecrets(cfg))
+	return nil
+}
+
+// secretRequirement lists the keys a feature needs; alternatives are
+// separated by "|" (e.g. a stored access token or the refresh credentials).
+type secretRequirement struct {
+	feature string
+	keys    []string
+}
+
+func requiredSecrets(cfg *store.Config) []secretRequirement {
+	reqs := brokerSecrets(cfg)
+
+	switch cfg.LLM.Provider {
+	case "OPENAI":
+		reqs = append(reqs, secretRequirement{"llm OPENAI", []string{"OPENAI_API_KEY"}})
+	case "CLAUDE":
+		reqs = append(reqs, secretRequirement{"llm CLAUDE", []string{"CLAUDE_API_KEY"}})
+	}
+
+	if cfg.Control.Enabled {
+		reqs = append(reqs, secretRequirement{"control api", []string{"CONTROL_API_TOKEN"}})
+	}
+	if cfg.Notify.Telegram.Enabled {
+		reqs = append(reqs, secretRequirement{"notify telegram", []string{"TELEGRAM_BOT_TOKEN", "TELEGRAM_CHAT_ID"}})
+	}
+	if cfg.Notify.Slack.Enabled {
+		reqs = append(reqs, secretRequirement{"notify slack", []string{"SLACK_WEBHOOK_URL"}})
+	}
+	return reqs
+}
+
+// brokerSecrets lists the credentials of cfg's broker, under its
+// secret_prefix.
+func brokerSecrets(cfg *store.Config) []secretRequirement {
+	if cfg.Mode != "LIVE" && cfg.DataSource != "LIVE" {
+		return nil
+	}
+	broker := cfg.Broker
+	if broker == "paper" {
+		broker = cfg.Paper.Feed
+	}
+	var keys []string
+	switch broker {
+	case "zerodha":
+		keys = []string{"KITE_API_KEY", "KITE_ACCESS_TOKEN|KITE_API_SECRET"}
+	case "upstox":
+		keys = []string{"UPSTOX_API_KEY", "UPSTOX_ACCESS_TOKEN|UPSTOX_AUTH_CODE"}
+	case "alpaca":
+		keys = []string{"ALPACA_API_KEY_ID", "ALPACA_API_SECRET_KEY"}
+	default:
+		return nil
+	}
+	if cfg.SecretPrefix != "" {
+		for i, alt := range keys {
+			keys[i] = cfg.SecretPrefix + strings.ReplaceAll(alt, "|", "|"+cfg.SecretPrefix)
+		}
+	}
+	feature := "broker " + broker
+	if cfg.Account != "" {
+		feature += " (account " + cfg.Account + ")"
+	}
+	return []secretRequirement{{feature, keys}}
+}
+
+// checkSecrets logs, per enabled feature, where each secret came from
+// (masked) and which ones are missing.
+func checkSecrets(ctx context.Context, reqs []secretRequirement) {
+	for _, req := range reqs {
+		var missing []string
+		for _, alt := range req.keys {
+			found := false
+			for _, key := range strings.Split(alt, "|") {
+				if v, src := secrets.Lookup(key); v != "" {
+					logger.Debug(ctx, "Secret resolved", "feature", req.feature, "key", key, "source", src, "value", secrets.Mask(v))
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, alt)
+			}
+		}
+		if len(missing) > 0 {
+			logger.Warn(ctx, "Missing secrets for enabled feature", "feature", req.feature, "missing", missing)
+		}
+	}
+}
+
+// initializeNotifier routes alerts to the enabled chat channels. It
+// returns nil when none is enabled.
+func initializeNotifier(ctx context.Context, cfg *store.Config) *notify.Dispatcher {
+	var routes []notify.Route
+
+	if cfg.Notify.Telegram.Enabled {
+		token, chatID := secrets.Get("TELEGRAM_BOT_TOKEN"), secrets.Get("TELEGRAM_CHAT_ID")
+		if token == "" || chatID == "" {
+			logger.Warn(ctx, "Telegram notifications enabled but TELEGRAM_BOT_TOKEN/TELEGRAM_CHAT_ID missing")
+		} else {
+			routes = append(routes, notify.Route{Name: "telegram", Notifier: notify.NewTelegram(token, chatID), Events: cfg.Notify.Telegram.Events})
+		}
+	}
+	if cfg.Notify.Slack.Enabled {
+		if webhook := secrets.Get("SLACK_WEBHOOK_URL"); webhook == "" {
+			logger.Warn(ctx, "Slack notifications enabled but SLACK_WEBHOOK_URL missing")
+		} else {
+			routes = append(routes, notify.Route{Name: "slack", Notifier: notify.NewSlack(webhook), Events: cfg.Notify.Slack.Events})
+		}
+	}
+
+	if len(routes) == 0 {
+		return nil
+	}
+	d := notify.NewDispatcher(routes...)
+	notify.SetDefaultNotifier(d)
+	return d
+}
+
+// compressOldLogs compresses old tradelog files if retention is configured
+func compressOldLogs(ctx context.Context) {
+	if err := tradelog.CompressOlder(logRetentionDays()); err != nil {
+		logger.Warn(ctx, "Failed to compress old logs", "error", err)
+	}
+}
+
+// logRetentionDays reads TRADER_LOG_RETENTION_DAYS; 0 keeps logs uncompressed
+func logRetentionDays() int {
+	var n int
+	fmt.Sscanf(os.Getenv("TRADER_LOG_RETENTION_DAYS"), "%d", &n)
+	return n
+}
+
+// initializeBroker initializes and returns the broker instance with
+// observability. A paper broker takes its data from shared when set, and
+// from its own connection to paper.feed otherwise.
+func initializeBroker(ctx context.Context, cfg *store.Config, shared *paper.Feed) interfaces.Broker {
+	// Create base broker
+	var brk interfaces.Broker
+	if cfg.Broker == "paper" {
+		logger.Warn(ctx, "Using PAPER broker - orders fill against a simulated book", "feed", cfg.Paper.Feed)
+		var feed interfaces.Broker
+		if shared != nil {
+			feed = shared.View()
+		} else {
+			feed = newVenueBroker(cfg, cfg.Paper.Feed)
+		}
+		brk = paper.New(feed, paper.Params{
+			InitialCash:    cfg.Paper.InitialCash,
+			SlippageBps:    cfg.Paper.SlippageBps,
+			LatencyMs:      cfg.Paper.LatencyMs,
+			MaxVolumePct:   cfg.Paper.MaxVolumePct,
+			MarketOrderTTL: cfg.Paper.MarketOrderTTL,
+		})
+	} else {
+		brk = newVenueBroker(cfg, cfg.Broker)
+	}
+
+	// Log initialization info
+	if cfg.Mode == "DRY_RUN" && cfg.Broker != "paper" {
+		logger.Warn(ctx, "Running in DRY_RUN mode - orders will be simulated")
+	}
+
+	switch cfg.DataSource {
+	case "LIVE":
+		logger.Info(ctx, "Using LIVE candle data", "broker", cfg.Broker)
+	case "FILE":
+		logger.Info(ctx, "Using recorded candle data", "dir", cfg.Candles.FileDir)
+	default:
+		logger.Info(ctx, "Using STATIC mock candle data for testing")
+	}
+
+	// Inject faults below the observability middleware, so they are
+	// logged and counted like real ones
+	if o := chaosOptions(cfg); o.Enabled() {
+		logger.Warn(ctx, "CHAOS enabled - injecting broker, stream and LLM failures",
+			"broker_timeout_rate", o.BrokerTimeoutRate,
+			"broker_latency", o.BrokerLatency,
+			"stream_drop_rate", o.StreamDropRate,
+			"llm_malformed_rate", o.LLMMalformedRate,
+		)
+		brk = chaos.WrapBroker(brk, o)
+	}
+
+	// Wrap with observability middleware
+	return brokerobs.Wrap(brk)
+}
+
+// newVenueBroker creates the adapter for a real broker: zerodha, upstox or alpaca
+func newVenueBroker(cfg *store.Config, name string) interfaces.Broker {
+	switch name {
+	case "upstox":
+		return upstox.NewUpstox(upstox.Params{
+			Mode:         cfg.Mode,
+			Exchange:     cfg.Exchange,
+			CandleSource: cfg.DataSource,
+			DryRunFunds:  cfg.Risk.DryRunFunds,
+
+			CandleInterval: cfg.Candles.IntervalMinutes,
+
+			Auth: upstox.AuthParams{
+				APIKey:      brokerSecret(cfg, "UPSTOX_API_KEY"),
+				APISecret:   brokerSecret(cfg, "UPSTOX_API_SECRET"),
+				RedirectURI: os.Getenv(cfg.SecretPrefix + "UPSTOX_REDIRECT_URI"),
+				AuthCode:    brokerSecret(cfg, "UPSTOX_AUTH_CODE"),
+				AccessToken: brokerSecret(cfg, "UPSTOX_ACCESS_TOKEN"),
+				TokenFile:   getEnvDefault(cfg.SecretPrefix+"UPSTOX_TOKEN_FILE", store.AccountPath(".upstox_token.json", cfg.Account)),
+			},
+		})
+	case "alpaca":
+		return alpaca.NewAlpaca(alpaca.Params{
+			Mode:         cfg.Mode,
+			KeyID:        brokerSecret(cfg, "ALPACA_API_KEY_ID"),
+			SecretKey:    brokerSecret(cfg, "ALPACA_API_SECRET_KEY"),
+			TradingURL:   cfg.Alpaca.TradingURL,
+			DataURL:      cfg.Alpaca.DataURL,
+			StreamURL:    cfg.Alpaca.StreamURL,
+			Feed:         cfg.Alpaca.Feed,
+			CandleSource: cfg.DataSource,
+			DryRunFunds:  cfg.Risk.DryRunFunds,
+
+			CandleInterval: cfg.Candles.IntervalMinutes,
+		})
+	}
+
+	return zerodha.NewZerodha(zerodha.Params{
+		Mode:         cfg.Mode,
+		APIKey:       brokerSecret(cfg, "KITE_API_KEY"),
+		AccessToken:  brokerSecret(cfg, "KITE_ACCESS_TOKEN"),
+		Exchange:     cfg.Exchange,
+		CandleSource: cfg.DataSource,
+		DryRunFunds:  cfg.Risk.DryRunFunds,
+
+		CandleInterval:  cfg.Candles.IntervalMinutes,
+		BackfillCandles: cfg.Candles.Backfill,
+
+		Token: zerodha.TokenParams{
+			APISecret:    brokerSecret(cfg, "KITE_API_SECRET"),
+			RequestToken: brokerSecret(cfg, "KITE_REQUEST_TOKEN"),
+			UserID:       brokerSecret(cfg, "KITE_USER_ID"),
+			Password:     brokerSecret(cfg, "KITE_PASSWORD"),
+			TOTPSecret:   brokerSecret(cfg, "KITE_TOTP_SECRET"),
+			TokenFile:    getEnvDefault(cfg.SecretPrefix+"KITE_TOKEN_FILE", store.AccountPath(".kite_token.json", cfg.Account)),
+		},
+	})
+}
+
+// brokerSecret reads a broker credential under the account's
+// secret_prefix
+func brokerSecret(cfg *store.Config, key string) string {
+	return secrets.Get(cfg.SecretPrefix + key)
+}
+
+// getEnvDefault returns the environment variable or a fallback when unset
+func getEnvDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// initializeDecider initializes and returns the LLM decider with observability
+func initializeDecider(ctx context.Context, cfg *store.Config) interfaces.Decider {
+	switch cfg.LLM.Provider {
+	case "OPENAI", "CLAUDE", "RULES":
+	default:
+		logger.Warn(ctx, "No LLM provider configured - using Noop decider (always HOLD)")
+	}
+
+	if err := llmqueue.Configure(llmqueue.Options{
+		Concurrency: cfg.LLM.Queue.Concurrency,
+		Providers:   cfg.LLM.Queue.Providers,
+		Reserved:    cfg.LLM.Queue.ReservedForDecisions,
+		MaxWaiting:  cfg.LLM.Queue.MaxWaiting,
+	}); err != nil {
+		logger.Warn(ctx, "LLM queue not configured - calls are not limited", "error", err)
+	}
+
+	return observedDecider(cfg)
+}
+
+// observedDecider wraps the decider named by cfg with observability
+// middleware; API providers share the LLM queue
+func observedDecider(cfg *store.Config) interfaces.Decider {
+	d := llmobs.Wrap(newDecider(cfg))
+	switch cfg.LLM.Provider {
+	case "OPENAI", "CLAUDE":
+		d = llmqueue.Wrap(d, cfg.LLM.Provider)
+	}
+	return d
+}
+
+// newDecider creates the decider named by llm.provider, falling back to
+// the Noop decider
+func newDecider(cfg *store.Config) interfaces.Decider {
+	switch cfg.LLM.Provider {
+	case "OPENAI":
+		return openai.NewOpenAIDecider(cfg)
+	case "CLAUDE":
+		return claude.NewClaudeDecider(cfg)
+	case "RULES":
+		return rules.NewRuleDecider()
+	default:
+		return noop.NewNoopDecider()
+	}
+}
+
+// initializeEngine initializes and returns the trading engine with observability
+func initializeEngine(cfg *store.Config, brk interfaces.Broker, decider interfaces.Decider) interfaces.Engine {
+	// Create base engine
+	eng := engine.New(cfg, brk, decider, clock.Real{})
+
+	// Wrap with observability middleware
+	return engineobs.Wrap(eng)
+}
+
+// initializeUniverse builds the universe manager from the configured
+// sources and resolves the starting universe. Symbols with open positions
+// are passed to later refreshes so they are never dropped.
+func initializeUniverse(ctx context.Context, cfg *store.Config) (*universe.Manager, error) {
+	var sources []universe.Source
+	for _, name := range cfg.Universe.Sources {
+		switch name {
+		case "static":
+			sources = append(sources, universe.Static("static", cfg.UniverseStatic))
+		case "index":
+			sources = append(sources, universe.Index(universe.IndexParams{
+				Names:    cfg.Universe.Index.Names,
+				CacheDir: cfg.Universe.Index.CacheDir,
+				MaxAge:   time.Duration(cfg.Universe.Index.RefreshDays) * 24 * time.Hour,
+			}))
+		}
+	}
+	mgr := universe.New(universe.Rules{
+		Include: cfg.Universe.Include,
+		Exclude: cfg.Universe.Exclude,
+		MaxSize: cfg.Universe.MaxSize,
+	}, cfg.Universe.AuditFile, sources...)
+
+	if _, err := mgr.Refresh(ctx, nil); err != nil {
+		logger.Warn(ctx, "Universe resolved with failed sources", "error", err)
+	}
+	if len(mgr.Symbols()) == 0 {
+		err := fmt.Errorf("universe is empty (sources %v)", cfg.Universe.Sources)
+		logger.ErrorWithErr(ctx, "Failed to resolve universe", err)
+		return nil, err
+	}
+	return mgr, nil
+}
+
+// initializeKillSwitch installs the halt file and block list checked
+// before every order
+func initializeKillSwitch(ctx context.Context, cfg *store.Config) {
+	ks := killswitch.New(cfg.KillSwitch.HaltFile, cfg.KillSwitch.BlockFile)
+	killswitch.SetDefault(ks)
+	if halted, reason := ks.Halted(); halted {
+		logger.Warn(ctx, "Halt file present - no new orders until it is removed", "file", cfg.KillSwitch.HaltFile, "reason", reason)
+	}
+}
+
+// initializeSymbols loads the NSE symbol master when enabled. Without it
+// orders are not checked against the listing.
+func initializeSymbols(ctx context.Context, cfg *store.Config) {
+	if !cfg.Symbols.Enabled {
+		return
+	}
+	reg, err := symbols.Load(ctx, symbols.Params{
+		CacheDir: cfg.Symbols.CacheDir,
+		MaxAge:   time.Duration(cfg.Symbols.RefreshHours) * time.Hour,
+	})
+	if err != nil {
+		logger.Warn(ctx, "Symbol registry unavailable - orders not checked against the NSE listing", "error", err)
+		return
+	}
+	symbols.SetDefault(reg)
+}
+
+// initializeCandleStore keeps every candle the engine receives. Bars
+// replayed from files are not stored again.
+func initializeCandleStore(ctx context.Context, cfg *store.Config) {
+	if cfg.Candles.StoreDir == "" || cfg.DataSource == "FILE" {
+		return
+	}
+	warehouse.SetDefault(warehouse.New(cfg.Candles.StoreDir))
+	logger.Info(ctx, "Storing candles", "dir", cfg.Candles.StoreDir)
+}
+
+// initializeHealth builds the dependency checks, one broker check per
+// account, and runs them once. It returns false when LIVE trading must
+// not start because a critical dependency failed; the checks then keep
+// running every health.interval_minutes.
+func initializeHealth(ctx context.Context, cfg *store.Config, accts []*account) bool {
+	critical := make(map[string]bool, len(cfg.Health.Critical))
+	for _, dep := range cfg.Health.Critical {
+		critical[dep] = true
+	}
+
+	var checks []health.Check
+	live := false
+	for _, a := range accts {
+		brk, symbols := a.brk, a.symbols
+		name := "broker"
+		if a.name() != "" {
+			name += ":" + a.name()
+		}
+		checks = append(checks, health.Check{Name: name, Critical: critical["broker"], Run: func(ctx context.Context) error {
+			if _, err := brk.Funds(ctx); err != nil {
+				return err
+			}
+			if len(symbols) > 0 {
+				_, err := brk.LTP(ctx, symbols[0])
+				return err
+			}
+			return nil
+		}})
+		live = live || a.cfg.Mode == "LIVE"
+	}
+	switch cfg.LLM.Provider {
+	case "CLAUDE":
+		checks = append(checks, health.Check{Name: "llm", Critical: critical["llm"], Run: claude.NewClaudeDecider(cfg).Ping})
+	case "OPENAI":
+		checks = append(checks, health.Check{Name: "llm", Critical: critical["llm"], Run: openai.NewOpenAIDecider(cfg).Ping})
+	}
+	if usesNSE(cfg) {
+		checks = append(checks, health.Check{Name: "nse", Critical: critical["nse"], Run: pingNSE})
+	}
+
+	checker := health.New(time.Duration(cfg.Health.TimeoutSeconds)*time.Second, checks...)
+	health.SetDefault(checker)
+	report := checker.Run(ctx)
+	go checker.Loop(ctx, time.Duration(cfg.Health.IntervalMinutes)*time.Minute)
+
+	failed := report.Failed(true)
+	if len(failed) == 0 {
+		logger.Info(ctx, "Dependency health checks passed", "checks", len(report.Checks), "failed", len(report.Failed(false)))
+		return true
+	}
+	names := make([]string, len(failed))
+	for i, f := range failed {
+		names[i] = f.Name
+	}
+	if live {
+		logger.Error(ctx, "Critical dependencies unhealthy - refusing to start LIVE trading", "failed", names)
+		return false
+	}
+	logger.Warn(ctx, "Critical dependencies unhealthy - continuing in DRY_RUN", "failed", names)
+	return true
+}
+
+// usesNSE reports whether any configured data source calls nseindia.com.
+func usesNSE(cfg *store.Config) bool {
+	for _, list := range [][]string{cfg.CorporateActions.Sources, cfg.Earnings.Sources, cfg.Surveillance.Sources} {
+		for _, src := range list {
+			if src == "nse" {
+				return true
+			}
+		}
+	}
+	for _, src := range cfg.Universe.Sources {
+		if src == "index" {
+			return true
+		}
+	}
+	return cfg.Options.Enabled || cfg.Symbols.Enabled || cfg.Deals.Enabled
+}
+
+// pingNSE fetches the NSE market status, which needs the same session
+// cookies as the data endpoints.
+func pingNSE(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.nseindia.com/api/marketStatus", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := api.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return api.CheckStatus(resp)
+}
+
+// initializeControl starts the control API when enabled. It returns nil
+// when disabled or when no CONTROL_API_TOKEN is available.
+func initializeControl(ctx context.Context, cfg *store.Config, eng interfaces.Engine) *control.Server {
+	if !cfg.Control.Enabled {
+		return nil
+	}
+	token := secrets.Get("CONTROL_API_TOKEN")
+	if token == "" {
+		logger.Error(ctx, "Control API enabled but CONTROL_API_TOKEN missing - not starting it")
+		return nil
+	}
+	ctl := control.New(token, eng)
+	go ctl.Serve(ctx, cfg.Control.Addr)
+	return ctl
+}
+
+// initializeLogging replaces the environment's stdout logging with the
+// sinks and levels in config.yaml.
+func initializeLogging(cfg *store.Config) error {
+	c := logger.Config{Level: cfg.Logging.Level, Modules: cfg.Logging.Modules}
+	for _, s := range cfg.Logging.Sinks {
+		c.Sinks = append(c.Sinks, logger.Sink{
+			Type:      s.Type,
+			Path:      s.Path,
+			Level:     s.Level,
+			MaxSizeMB: s.MaxSizeMB,
+			MaxFiles:  s.MaxFiles,
+		})
+	}
+	return logger.Configure(c)
+}
+
+// initializeEOD wraps the default EOD summarizer with observability
+func initializeEOD(cfg *store.Config) {
+	// Create base summarizer
+	baseSummarizer := eod.NewSummarizer(eod.Params{
+		FeeBps:         cfg.EOD.FeeBps,
+		LLMCostPerCall: cfg.EOD.LLMCostPerCall,
+		Capital:        cfg.EOD.Capital,
+		RiskFreePct:    cfg.EOD.RiskFreePct,
+		Window:         cfg.EOD.WindowDays,
+		Benchmark:      cfg.EOD.Benchmark,
+		BenchmarkClose: benchmarkClose(cfg),
+	}, clock.Real{})
+
+	// Wrap with observability middleware
+	observableSummarizer := eodobs.Wrap(baseSummarizer)
+
+	// Set as default summarizer
+	eod.SetDefaultSummarizer(observableSummarizer)
+}
+
+// benchmarkClose reads the EOD benchmark's last bar of a day from the
+// candle files or the candle store, which keeps the index bars the
+// relative strength feed fetches. It is nil when neither is configured.
+func benchmarkClose(cfg *store.Config) func(day time.Time) (float64, bool) {
+	if cfg.EOD.Benchmark == "" {
+		return nil
+	}
+	symbol := types.NormalizeSymbol(cfg.EOD.Benchmark)
+	var load func(day time.Time) ([]types.Candle, error)
+	switch {
+	case cfg.DataSource == "FILE":
+		files := marketdata.NewFile(cfg.Candles.FileDir)
+		load = func(time.Time) ([]types.Candle, error) { return files.Candles(symbol) }
+	case cfg.Candles.StoreDir != "":
+		st := warehouse.New(cfg.Candles.StoreDir)
+		load = func(day time.Time) ([]types.Candle, error) { return st.Load(symbol, day, day) }
+	default:
+		return nil
+	}
+	return func(day time.Time) (float64, bool) {
+		bars, err := load(day)
+		if err != nil {
+			return 0, false
+		}
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, timeutil.IST).AddDate(0, 0, 1).Unix()
+		for i := len(bars) - 1; i >= 0; i-- {
+			if bars[i].Ts < end {
+				return bars[i].Close, true
+			}
+		}
+		return 0, false
+	}
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"llm-trading-bot/internal/brief"
+	"llm-trading-bot/internal/engine"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// runBrief compiles today's pre-market briefing and prints it, optionally
+// saving it under brief.dir and sending the summary as the brief alert.
+func runBrief(args []string) int {
+	fs := newFlagSet("brief")
+	cf := addConfigFlags(fs)
+	format := fs.String("format", "md", "output format: md | html | text")
+	write := fs.Bool("write", false, "also save <date>.md and <date>.html under brief.dir")
+	send := fs.Bool("notify", false, "send the summary to the notify channels")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "md" && *format != "html" && *format != "text" {
+		fmt.Fprintf(os.Stderr, "invalid -format %q: must be md, html or text\n", *format)
+		return 2
+	}
+
+	if err := initializeSystem(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	cfg, err := loadConfig(ctx, cf)
+	if err != nil {
+		return 1
+	}
+	if err := initializeAPIClient(ctx, cfg); err != nil {
+		return 1
+	}
+	initializeSourceQuality(cfg)
+	if *send {
+		if err := initializeSecrets(ctx, cfg); err != nil {
+			return 1
+		}
+		if notifier := initializeNotifier(ctx, cfg); notifier != nil {
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				notifier.Close(closeCtx)
+			}()
+		}
+	}
+
+	b, err := buildBriefing(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build briefing: %v\n", err)
+		return 1
+	}
+	switch *format {
+	case "html":
+		page, err := b.HTML()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to render briefing: %v\n", err)
+			return 1
+		}
+		fmt.Print(page)
+	case "text":
+		fmt.Println(b.Text())
+	default:
+		fmt.Print(b.Markdown())
+	}
+
+	if *write {
+		paths, err := b.Write(cfg.Brief.Dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write briefing: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "briefing written to %v\n", paths)
+	}
+	if *send {
+		notify.Send(ctx, notify.EventBrief, "", b.Text())
+	}
+	return 0
+}
+
+// buildBriefing resolves the universe and compiles its briefing from the
+// candle store and the configured event and surveillance sources.
+func buildBriefing(ctx context.Context, cfg *store.Config) (*brief.Briefing, error) {
+	initializeSymbols(ctx, cfg)
+
+	// Resolving the universe here must not show up in the live audit trail
+	c := *cfg
+	c.Universe.AuditFile = ""
+	uni, err := initializeUniverse(ctx, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	p := brief.Params{
+		Earnings:      engine.NewEarningsCalendar(cfg),
+		Actions:       engine.NewCorporateActions(cfg),
+		Surveillance:  engine.NewSurveillance(cfg),
+		LookaheadDays: cfg.Brief.LookaheadDays,
+	}
+	if cfg.Candles.StoreDir != "" {
+		p.Store = warehouse.New(cfg.Candles.StoreDir)
+	}
+	return brief.Build(ctx, p, uni.Symbols(), time.Now()), nil
+}
+
+// runBriefJob is the brief scheduler task: it saves the briefing and
+// sends its summary.
+func runBriefJob(ctx context.Context, cfg *store.Config) error {
+	b, err := buildBriefing(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	paths, err := b.Write(cfg.Brief.Dir)
+	if err != nil {
+		return err
+	}
+	notify.Send(ctx, notify.EventBrief, "", b.Text())
+	logger.Info(ctx, "Pre-market briefing written", "paths", paths, "symbols", len(b.Entries))
+	return nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// runCandles reports the bars missing from the candle store, per symbol
+// and session, so gaps are found before a backtest or an indicator audit
+// relies on the data.
+func runCandles(args []string) int {
+	today := timeutil.DateKey(timeutil.Now())
+
+	fs := newFlagSet("candles")
+	cf := addConfigFlags(fs)
+	from := fs.String("from", today, "first day to check (YYYY-MM-DD, IST)")
+	to := fs.String("to", today, "last day to check (YYYY-MM-DD, IST)")
+	symbol := fs.String("symbol", "", "only check these symbols (comma-separated); default all stored")
+	all := fs.Bool("all", false, "also list complete sessions")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	if cfg.Candles.StoreDir == "" {
+		fmt.Fprintln(os.Stderr, "candles.store_dir is not set: no candles are stored")
+		return 1
+	}
+	fromT, err := timeutil.ParseDate(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -from: %v\n", err)
+		return 2
+	}
+	toT, err := timeutil.ParseDate(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -to: %v\n", err)
+		return 2
+	}
+
+	wh := warehouse.New(cfg.Candles.StoreDir)
+	var symbols []string
+	if *symbol != "" {
+		symbols = strings.Split(*symbol, ",")
+	} else if symbols, err = wh.Symbols(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list stored symbols: %v\n", err)
+		return 1
+	}
+
+	coverage, err := wh.Gaps(symbols, fromT, toT, cfg.Candles.IntervalMinutes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read candles: %v\n", err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Day\tSymbol\tBars\tMissing")
+	incomplete := 0
+	for _, c := range coverage {
+		if c.Complete() && !*all {
+			continue
+		}
+		var missing []string
+		for _, g := range c.Gaps {
+			if g.Bars == 1 {
+				missing = append(missing, g.From.Format("15:04"))
+			} else {
+				missing = append(missing, fmt.Sprintf("%s-%s (%d)", g.From.Format("15:04"), g.To.Format("15:04"), g.Bars))
+			}
+		}
+		if !c.Complete() {
+			incomplete++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\n", c.Day, c.Symbol, c.Stored, c.Expected, strings.Join(missing, ", "))
+	}
+	tw.Flush()
+	fmt.Printf("\n%d of %d sessions incomplete (%d-minute bars, 09:15-15:30 IST)\n", incomplete, len(coverage), cfg.Candles.IntervalMinutes)
+	return 0
+}
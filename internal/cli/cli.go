@@ -0,0 +1,153 @@
+// Package cli implements the tradingbot command: one binary whose
+// subcommands (run, backtest, journal, ...) share environment loading,
+// logger setup and config/profile loading.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/store"
+
+	"github.com/joho/godotenv"
+)
+
+// version is set at build time with
+// -ldflags "-X llm-trading-bot/internal/cli.version=..."
+var version = "1.0.0"
+
+// command is one tradingbot subcommand. run receives the arguments after
+// the command name and returns the process exit code.
+type command struct {
+	summary string
+	run     func(args []string) int
+}
+
+var commands = map[string]command{
+	"run":       {"run the trading loop", runBot},
+	"compete":   {"run competition.entrants side by side on paper", runCompete},
+	"config":    {"check config.yaml (config validate)", runConfig},
+	"daemon":    {"run the scheduled jobs in scheduler.jobs", runDaemon},
+	"backtest":  {"replay candle CSVs through the engine", runBacktest},
+	"journal":   {"print trade cards and hit rate by signal source", runJournal},
+	"dashboard": {"serve the web dashboard", runDashboard},
+	"tax":       {"write the capital-gains report for a financial year", runTax},
+	"export":    {"write decisions, orders and trades as partitioned CSV", runExport},
+	"candles":   {"report bars missing from the candle store", runCandles},
+	"brief":     {"compile the pre-market briefing", runBrief},
+	"sources":   {"rank data sources by recent quality", runSources},
+	"symbol":    {"look up symbols or ISINs in the NSE symbol master", runSymbol},
+	"version":   {"print the version", runVersion},
+}
+
+// Main dispatches to the subcommand named by args[0] and returns the
+// process exit code.
+func Main(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+
+	// Every command may read .env; variables already set win
+	_ = godotenv.Load()
+	return cmd.run(args[1:])
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: tradingbot <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'tradingbot <command> -h' for its flags.")
+}
+
+func runVersion(args []string) int {
+	fmt.Println(version)
+	return 0
+}
+
+// newFlagSet returns a flag set for a subcommand that reports parse errors
+// instead of exiting.
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("tradingbot "+name, flag.ContinueOnError)
+}
+
+// configFlags are the -config and -profile flags shared by commands that
+// read config.yaml.
+type configFlags struct {
+	path    *string
+	profile *string
+}
+
+func addConfigFlags(fs *flag.FlagSet) configFlags {
+	return configFlags{
+		path:    fs.String("config", "config.yaml", "path to config file"),
+		profile: fs.String("profile", os.Getenv("TRADER_PROFILE"), "config profile to layer over the config file (e.g. paper, live)"),
+	}
+}
+
+// initializeSystem initializes the logger
+func initializeSystem() error {
+	if err := logger.Init(); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	return nil
+}
+
+// loadConfig loads and returns the configuration, with the named profile
+// layered over the base settings, and switches logging to its sinks and
+// levels
+func loadConfig(ctx context.Context, cf configFlags) (*store.Config, error) {
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to load config", err, "path", *cf.path, "profile", *cf.profile)
+		return nil, err
+	}
+	if err := initializeLogging(cfg); err != nil {
+		logger.ErrorWithErr(ctx, "Failed to configure logging", err)
+		return nil, err
+	}
+	if *cf.profile != "" {
+		logger.Info(ctx, "Config profile applied", "profile", *cf.profile, "mode", cfg.Mode, "broker", cfg.Broker)
+	}
+	return cfg, nil
+}
+
+// loadAccounts loads one config per entry of accounts:, or the shared
+// config alone when there are none
+func loadAccounts(ctx context.Context, cf configFlags) ([]*store.Config, error) {
+	accounts, err := store.LoadAccounts(*cf.path, *cf.profile)
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to load accounts", err, "path", *cf.path, "profile", *cf.profile)
+		return nil, err
+	}
+	if len(accounts) > 1 || accounts[0].Account != "" {
+		names := make([]string, len(accounts))
+		for i, a := range accounts {
+			names[i] = a.Account
+		}
+		logger.Info(ctx, "Trading several accounts", "accounts", names)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"llm-trading-bot/internal/competition"
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// runCompete runs the competition entrants side by side on paper until
+// SIGINT/SIGTERM and returns the process exit code.
+func runCompete(args []string) int {
+	fs := newFlagSet("compete")
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	return runSession(cf, true)
+}
+
+// loadEntrants loads one config per competition entrant.
+func loadEntrants(ctx context.Context, cf configFlags) ([]*store.Config, error) {
+	entrants, err := store.LoadCompetition(*cf.path, *cf.profile)
+	if err == nil && len(entrants) == 0 {
+		err = errors.New("no competition.entrants configured")
+	}
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to load competition entrants", err, "path", *cf.path, "profile", *cf.profile)
+		return nil, err
+	}
+	names := make([]string, len(entrants))
+	for i, e := range entrants {
+		names[i] = e.Account
+	}
+	logger.Info(ctx, "Competition mode - entrants trade paper books side by side", "entrants", names)
+	return entrants, nil
+}
+
+// updateLeaderboard samples every entrant's equity, ranks the entrants
+// and rewrites the leaderboard file.
+func updateLeaderboard(ctx context.Context, cfg *store.Config, board *competition.Board, accts []*account) *competition.Leaderboard {
+	for _, a := range accts {
+		funds, err := a.brk.Funds(a.with(ctx))
+		if err != nil {
+			logger.Warn(a.with(ctx), "Failed to read entrant's equity", "error", err)
+			continue
+		}
+		board.Record(a.name(), funds.Net)
+	}
+
+	now := time.Now()
+	since := board.Since()
+	entries, err := journal.Load(since, now)
+	if err != nil {
+		logger.Warn(ctx, "Failed to read competition trades", "error", err)
+	}
+	// Orders of earlier runs are not in this run's books
+	start := since.In(timeutil.IST).Format("2006-01-02 15:04:05")
+	var current []tradelog.Entry
+	for _, e := range entries {
+		if e.Time >= start {
+			current = append(current, e)
+		}
+	}
+
+	l := board.Standings(now, journal.Build(current))
+	if err := competition.Write(cfg.Competition.LeaderboardFile, l); err != nil {
+		logger.Warn(ctx, "Failed to write leaderboard", "path", cfg.Competition.LeaderboardFile, "error", err)
+	}
+	return l
+}
+
+// finishCompetition publishes the final standings.
+func finishCompetition(ctx context.Context, cfg *store.Config, board *competition.Board, accts []*account) {
+	l := updateLeaderboard(ctx, cfg, board, accts)
+	for _, s := range l.Standings {
+		logger.Info(ctx, "Final standing",
+			"rank", s.Rank,
+			"entrant", s.Entrant,
+			"return_pct", s.ReturnPct,
+			"max_drawdown_pct", s.MaxDrawdownPct,
+			"trades", s.Trades,
+			"win_rate_pct", s.WinRatePct,
+		)
+	}
+	notify.Send(ctx, notify.EventEOD, "", l.Text())
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"llm-trading-bot/internal/store"
+)
+
+// runConfig handles `tradingbot config validate [-profile name] [path]` and
+// returns the process exit code.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: tradingbot config validate [-profile name] [config.yaml]")
+		return 2
+	}
+	fs := newFlagSet("config validate")
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	path, profile := *cf.path, *cf.profile
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+	accounts, err := store.LoadAccounts(path, profile)
+	if err == nil {
+		var entrants []*store.Config
+		entrants, err = store.LoadCompetition(path, profile)
+		accounts = append(accounts, entrants...)
+	}
+	if profile != "" {
+		path += " (profile " + profile + ")"
+	}
+	var verr *store.ValidationError
+	if errors.As(err, &verr) {
+		fmt.Fprintf(os.Stderr, "%s has %d problem(s):\n", path, len(verr.Problems))
+		for _, p := range verr.Problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", p)
+		}
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+
+	for _, cfg := range accounts {
+		name := path
+		switch {
+		case cfg.Competing:
+			name += " entrant " + cfg.Account
+		case cfg.Account != "":
+			name += " account " + cfg.Account
+		}
+		fmt.Printf("%s is valid: mode=%s broker=%s data_source=%s, %d symbols, polling every %ds\n",
+			name, cfg.Mode, cfg.Broker, cfg.DataSource, len(cfg.UniverseStatic), cfg.PollSeconds)
+	}
+	return 0
+}
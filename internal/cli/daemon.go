@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"llm-trading-bot/internal/eod"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/scheduler"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/trace"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// runDaemon runs the jobs in scheduler.jobs until SIGINT/SIGTERM, or runs
+// one of them immediately with -once.
+func runDaemon(args []string) int {
+	fs := newFlagSet("daemon")
+	cf := addConfigFlags(fs)
+	once := fs.String("once", "", "run the named job now and exit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := initializeSystem(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	cfg, err := loadConfig(ctx, cf)
+	if err != nil {
+		return 1
+	}
+	initializeTracing(ctx, cfg)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = trace.Shutdown(shutdownCtx)
+	}()
+	if err := initializeAPIClient(ctx, cfg); err != nil {
+		return 1
+	}
+	initializeSourceQuality(cfg)
+	if err := initializeSecrets(ctx, cfg); err != nil {
+		return 1
+	}
+	initializeEOD(cfg)
+	if notifier := initializeNotifier(ctx, cfg); notifier != nil {
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			notifier.Close(closeCtx)
+		}()
+	}
+
+	jobs, err := buildJobs(cfg)
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Invalid scheduler job", err)
+		return 1
+	}
+	sched := scheduler.New(jobs...)
+
+	if *once != "" {
+		for _, j := range jobs {
+			if j.Name == *once {
+				if err := sched.RunNow(ctx, j); err != nil {
+					return 1
+				}
+				return 0
+			}
+		}
+		fmt.Fprintf(os.Stderr, "no job named %q in scheduler.jobs\n", *once)
+		return 2
+	}
+	if len(jobs) == 0 {
+		logger.Warn(ctx, "No jobs in scheduler.jobs - nothing to run")
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info(ctx, "Scheduler started", "jobs", len(jobs))
+	sched.Run(ctx)
+	logger.Info(context.Background(), "Scheduler stopped")
+	return 0
+}
+
+// buildJobs turns scheduler.jobs into runnable jobs.
+func buildJobs(cfg *store.Config) ([]scheduler.Job, error) {
+	var jobs []scheduler.Job
+	for _, jc := range cfg.Scheduler.Jobs {
+		sched, err := scheduler.Parse(jc.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", jc.Name, err)
+		}
+		run, err := jobTask(cfg, jc.Task)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", jc.Name, err)
+		}
+		jobs = append(jobs, scheduler.Job{
+			Name:     jc.Name,
+			Schedule: sched,
+			Timeout:  time.Duration(jc.TimeoutSeconds) * time.Second,
+			Run:      run,
+		})
+	}
+	return jobs, nil
+}
+
+// jobTask maps a task name from config to the function that performs it.
+func jobTask(cfg *store.Config, task string) (func(context.Context) error, error) {
+	switch task {
+	case "brief":
+		return func(ctx context.Context) error {
+			return runBriefJob(ctx, cfg)
+		}, nil
+	case "eod":
+		return func(ctx context.Context) error {
+			p, err := eod.SummarizeToday()
+			if err == nil && p != "" {
+				logger.Info(ctx, "EOD CSV written successfully", "path", p)
+			}
+			return err
+		}, nil
+	case "compress_logs":
+		return func(context.Context) error {
+			return tradelog.CompressOlder(logRetentionDays())
+		}, nil
+	case "reindex_logs":
+		return func(context.Context) error {
+			return tradelog.Reindex()
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown task %q", task)
+}
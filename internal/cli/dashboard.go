@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+
+	"llm-trading-bot/internal/eod"
+	"llm-trading-bot/internal/store"
+)
+
+//go:embed static
+var static embed.FS
+
+// runDashboard serves the read-only web dashboard over the trade and
+// decision logs.
+func runDashboard(args []string) int {
+	flags := newFlagSet("dashboard")
+	cf := addConfigFlags(flags)
+	addr := flags.String("addr", "127.0.0.1:8080", "listen address; the dashboard has no auth, so it binds to localhost by default")
+	days := flags.Int("days", 30, "days of trade and decision logs to read")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+
+	ui, err := fs.Sub(static, "static")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load UI: %v\n", err)
+		return 1
+	}
+
+	s := &dashboard{days: *days, stateFile: cfg.Lifecycle.StateFile, eod: eod.Params{
+		RiskFreePct: cfg.EOD.RiskFreePct,
+		Window:      cfg.EOD.WindowDays,
+		Benchmark:   cfg.EOD.Benchmark,
+	}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/summary", s.summary)
+	mux.HandleFunc("/api/positions", s.positions)
+	mux.HandleFunc("/api/trades", s.trades)
+	mux.HandleFunc("/api/decisions", s.decisions)
+	mux.HandleFunc("/api/symbols", s.symbols)
+	mux.HandleFunc("/api/performance", s.performance)
+	mux.Handle("/", http.FileServer(http.FS(ui)))
+
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	fmt.Printf("Dashboard on http://localhost%s (reading %d days of logs)\n", *addr, *days)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "dashboard stopped: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"llm-trading-bot/internal/eod"
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/lifecycle"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// dashboard answers from the trade and decision logs on every request; they
+// are small and the bot appends to them while the dashboard runs.
+type dashboard struct {
+	days      int
+	stateFile string     // lifecycle.state_file; empty when states are not saved
+	eod       eod.Params // Window, risk-free rate and benchmark of the equity statistics
+}
+
+type positionView struct {
+	Symbol     string  `json:"symbol"`
+	Qty        int     `json:"qty"`
+	Avg        float64 `json:"avg"`
+	Mark       float64 `json:"mark"`
+	MarkedAt   string  `json:"marked_at"`
+	Unrealized float64 `json:"unrealized"`
+}
+
+type tradeView struct {
+	Symbol  string   `json:"symbol"`
+	Time    string   `json:"time"`
+	Qty     int      `json:"qty"`
+	Entry   float64  `json:"entry"`
+	ExitAvg float64  `json:"exit_avg"`
+	PnL     float64  `json:"pnl"`
+	Closed  bool     `json:"closed"`
+	Reason  string   `json:"reason"`
+	Sources []string `json:"sources"`
+}
+
+type summaryView struct {
+	Realized      float64 `json:"realized"`
+	Unrealized    float64 `json:"unrealized"`
+	OpenPositions int     `json:"open_positions"`
+	ClosedTrades  int     `json:"closed_trades"`
+	WinRate       float64 `json:"win_rate"`
+}
+
+func (s *dashboard) window() (time.Time, time.Time) {
+	to := timeutil.Now()
+	return to.AddDate(0, 0, -s.days+1), to
+}
+
+func (s *dashboard) load() ([]*journal.Trade, []tradelog.DecisionEntry, error) {
+	from, to := s.window()
+	entries, err := journal.Load(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	decisions, err := tradelog.Decisions(tradelog.Query{From: from, To: to})
+	if err != nil {
+		return nil, nil, err
+	}
+	return journal.Build(entries), decisions, nil
+}
+
+// openPositions nets open trades per symbol and marks them at the price of
+// the latest decision logged for that symbol.
+func openPositions(trades []*journal.Trade, decisions []tradelog.DecisionEntry) []positionView {
+	marks := make(map[string]tradelog.DecisionEntry)
+	for _, d := range decisions {
+		marks[d.Symbol] = d
+	}
+
+	bySymbol := make(map[string]*positionView)
+	costs := make(map[string]money.Amount)
+	for _, t := range trades {
+		if t.Closed() {
+			continue
+		}
+		qty := t.Entry.Qty - t.ExitQty
+		p := bySymbol[t.Entry.Symbol]
+		if p == nil {
+			p = &positionView{Symbol: t.Entry.Symbol}
+			bySymbol[t.Entry.Symbol] = p
+		}
+		costs[p.Symbol] += t.Entry.Price.Mul(qty)
+		p.Qty += qty
+		p.Avg = costs[p.Symbol].Div(p.Qty).Float()
+	}
+
+	out := make([]positionView, 0, len(bySymbol))
+	for sym, p := range bySymbol {
+		if m, ok := marks[sym]; ok {
+			p.Mark, p.MarkedAt = m.Price, m.Time
+			p.Unrealized = m.Price*float64(p.Qty) - costs[sym].Float()
+		}
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+func (s *dashboard) summary(w http.ResponseWriter, r *http.Request) {
+	trades, decisions, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var v summaryView
+	wins := 0
+	for _, t := range trades {
+		v.Realized += t.PnL()
+		if t.Closed() {
+			v.ClosedTrades++
+			if t.PnL() > 0 {
+				wins++
+			}
+		}
+	}
+	if v.ClosedTrades > 0 {
+		v.WinRate = float64(wins) / float64(v.ClosedTrades) * 100
+	}
+	positions := openPositions(trades, decisions)
+	v.OpenPositions = len(positions)
+	for _, p := range positions {
+		v.Unrealized += p.Unrealized
+	}
+	writeJSON(w, v)
+}
+
+func (s *dashboard) positions(w http.ResponseWriter, r *http.Request) {
+	trades, decisions, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, openPositions(trades, decisions))
+}
+
+func (s *dashboard) trades(w http.ResponseWriter, r *http.Request) {
+	trades, _, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	out := make([]tradeView, 0, len(trades))
+	for i := len(trades) - 1; i >= 0 && len(out) < limit(r, 100); i-- {
+		t := trades[i]
+		out = append(out, tradeView{
+			Symbol:  t.Entry.Symbol,
+			Time:    t.Entry.Time,
+			Qty:     t.Entry.Qty,
+			Entry:   t.Entry.Price.Float(),
+			ExitAvg: t.ExitAvg(),
+			PnL:     t.PnL(),
+			Closed:  t.Closed(),
+			Reason:  t.Entry.Reason,
+			Sources: t.Sources(),
+		})
+	}
+	writeJSON(w, out)
+}
+
+func (s *dashboard) decisions(w http.ResponseWriter, r *http.Request) {
+	_, decisions, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	n := limit(r, 50)
+	out := make([]tradelog.DecisionEntry, 0, n)
+	for i := len(decisions) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, decisions[i])
+	}
+	writeJSON(w, out)
+}
+
+type performanceView struct {
+	Performance eod.Performance   `json:"performance"`
+	Curve       []eod.EquityPoint `json:"curve"`
+}
+
+// performance returns the EOD equity curve and its rolling statistics.
+func (s *dashboard) performance(w http.ResponseWriter, r *http.Request) {
+	points, err := eod.LoadEquity()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if points == nil {
+		points = []eod.EquityPoint{}
+	}
+	writeJSON(w, performanceView{Performance: eod.Measure(points, s.eod), Curve: points})
+}
+
+// symbols returns the lifecycle status of each symbol saved by the bot.
+func (s *dashboard) symbols(w http.ResponseWriter, r *http.Request) {
+	out := []lifecycle.State{}
+	if s.stateFile != "" {
+		states, err := lifecycle.Load(s.stateFile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		now := time.Now()
+		for _, st := range states {
+			out = append(out, st.At(now))
+		}
+	}
+	writeJSON(w, out)
+}
+
+func limit(r *http.Request, def int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"llm-trading-bot/internal/export"
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// runExport writes the decision and trade logs of a date range as CSV
+// partitioned by date and symbol, for offline analysis.
+func runExport(args []string) int {
+	today := timeutil.DateKey(timeutil.Now())
+
+	fs := newFlagSet("export")
+	from := fs.String("from", today, "first day to include (YYYY-MM-DD, IST)")
+	to := fs.String("to", today, "last day to include (YYYY-MM-DD, IST)")
+	symbol := fs.String("symbol", "", "only export these symbols (comma-separated)")
+	datasets := fs.String("datasets", strings.Join(export.Datasets, ","), "datasets to write: "+strings.Join(export.Datasets, ", "))
+	out := fs.String("out", "export", "output directory")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fromT, err := timeutil.ParseDate(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -from: %v\n", err)
+		return 2
+	}
+	toT, err := timeutil.ParseDate(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -to: %v\n", err)
+		return 2
+	}
+	var symbols []string
+	if *symbol != "" {
+		symbols = strings.Split(*symbol, ",")
+	}
+
+	for _, ds := range strings.Split(*datasets, ",") {
+		ds = strings.TrimSpace(ds)
+		var (
+			parts int
+			err   error
+		)
+		switch ds {
+		case "decisions":
+			decisions, lerr := journal.LoadDecisions(fromT, toT, symbols...)
+			if lerr != nil {
+				fmt.Fprintf(os.Stderr, "failed to read decision log: %v\n", lerr)
+				return 1
+			}
+			parts, err = export.Decisions(*out, decisions)
+		case "orders", "trades":
+			entries, lerr := journal.Load(fromT, toT, symbols...)
+			if lerr != nil {
+				fmt.Fprintf(os.Stderr, "failed to read trade log: %v\n", lerr)
+				return 1
+			}
+			if ds == "orders" {
+				parts, err = export.Orders(*out, entries)
+			} else {
+				parts, err = export.Trades(*out, journal.Build(entries))
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "unknown dataset %q: must be one of %s\n", ds, strings.Join(export.Datasets, ", "))
+			return 2
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export %s: %v\n", ds, err)
+			return 1
+		}
+		fmt.Printf("%s: %d partitions written under %s\n", ds, parts, *out)
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// runJournal prints a card per trade in the date range and the hit rate
+// of each signal source, or with -calibration how often decisions at each
+// stated confidence turned out right, or with -attribution where the
+// realized P&L of the period came from.
+func runJournal(args []string) int {
+	today := timeutil.DateKey(timeutil.Now())
+
+	fs := newFlagSet("journal")
+	from := fs.String("from", today, "first day to include (YYYY-MM-DD, IST)")
+	to := fs.String("to", today, "last day to include (YYYY-MM-DD, IST)")
+	symbol := fs.String("symbol", "", "only show trades for this symbol")
+	account := fs.String("account", "", "only show trades of this account")
+	calibration := fs.Bool("calibration", false, "print confidence calibration of logged BUY/SELL decisions instead")
+	horizon := fs.Int("horizon", 5, "calibration: judge a decision by the price this many decisions later")
+	bins := fs.Int("bins", 10, "calibration: number of confidence bins")
+	attribution := fs.Bool("attribution", false, "print realized P&L by driver, symbol, entry time and holding period instead")
+	month := fs.String("month", "", "attribution: report this month (YYYY-MM) instead of -from/-to")
+	lookback := fs.Int("lookback", 90, "attribution: days before the period to search for the entries of trades closed in it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fromT, err := timeutil.ParseDate(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -from: %v\n", err)
+		return 2
+	}
+	toT, err := timeutil.ParseDate(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -to: %v\n", err)
+		return 2
+	}
+
+	if *month != "" {
+		m, err := time.ParseInLocation("2006-01", *month, timeutil.IST)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -month: %v\n", err)
+			return 2
+		}
+		fromT, toT = m, m.AddDate(0, 1, -1)
+	}
+
+	var symbols []string
+	if *symbol != "" {
+		symbols = []string{*symbol}
+	}
+	if *calibration {
+		decisions, err := journal.LoadDecisions(fromT, toT, symbols...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read decision log: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Confidence calibration (outcome %d decisions later):\n", *horizon)
+		journal.RenderCalibration(os.Stdout, journal.Calibrate(decisions, *horizon, *bins))
+		return 0
+	}
+
+	if *attribution {
+		entries, err := journal.Load(fromT.AddDate(0, 0, -*lookback), toT, symbols...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read trade log: %v\n", err)
+			return 1
+		}
+		entries = journal.OfAccount(entries, *account)
+		journal.RenderAttribution(os.Stdout, journal.Attribute(journal.Build(entries), fromT, toT))
+		return 0
+	}
+
+	entries, err := journal.Load(fromT, toT, symbols...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read trade log: %v\n", err)
+		return 1
+	}
+
+	trades := journal.Build(journal.OfAccount(entries, *account))
+
+	if len(trades) == 0 {
+		fmt.Println("No trades found")
+		return 0
+	}
+
+	journal.RenderCards(os.Stdout, trades)
+	fmt.Println("Hit rate by signal source (closed trades):")
+	journal.RenderSourceStats(os.Stdout, journal.HitRateBySource(trades))
+	return 0
+}
@@ -0,0 +1,452 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"llm-trading-bot/internal/broker/paper"
+	"llm-trading-bot/internal/competition"
+	"llm-trading-bot/internal/crash"
+	"llm-trading-bot/internal/eod"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/marketdata"
+	"llm-trading-bot/internal/metrics"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/trace"
+	"llm-trading-bot/internal/universe"
+)
+
+// runBot runs the trading loop until SIGINT/SIGTERM and returns the
+// process exit code.
+func runBot(args []string) int {
+	fs := newFlagSet("run")
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	return runSession(cf, false)
+}
+
+// runSession runs the trading loop over the configured accounts, or over
+// the competition entrants when compete is set, until SIGINT/SIGTERM and
+// returns the process exit code.
+func runSession(cf configFlags, compete bool) int {
+	// Initialize logger
+	if err := initializeSystem(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	ctx := context.Background()
+	logger.Info(ctx, "=== LLM Trading Bot Starting ===", "version", version)
+
+	// Load configuration: the shared settings, then each account's or
+	// competition entrant's
+	cfg, err := loadConfig(ctx, cf)
+	if err != nil {
+		return 1
+	}
+	load := loadAccounts
+	if compete {
+		load = loadEntrants
+	}
+	accountCfgs, err := load(ctx, cf)
+	if err != nil {
+		return 1
+	}
+
+	// Tracer, then the root span for the session
+	initializeTracing(ctx, cfg)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = trace.Shutdown(shutdownCtx)
+	}()
+	ctx, mainSpan := trace.StartSpan(ctx, "trading-bot-session")
+	defer mainSpan.End()
+
+	if err := initializeAPIClient(ctx, cfg); err != nil {
+		return 1
+	}
+	initializeSourceQuality(cfg)
+	if err := initializeSecrets(ctx, cfg); err != nil {
+		return 1
+	}
+
+	// EOD summarizer with observability
+	initializeEOD(cfg)
+
+	// Setup cancellation context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Compress old logs
+	compressOldLogs(ctx)
+
+	// Chat notifications
+	if notifier := initializeNotifier(ctx, cfg); notifier != nil {
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			notifier.Close(closeCtx)
+		}()
+	}
+
+	// Expose Prometheus metrics
+	if cfg.Metrics.Enabled {
+		go metrics.Serve(ctx, cfg.Metrics.Addr)
+	}
+
+	// Setup signal handling
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+
+	// Manual override checked before every order
+	initializeKillSwitch(ctx, cfg)
+	initializeCandleStore(ctx, cfg)
+	initializeSymbols(ctx, cfg)
+
+	// Initialize components: one decider shared by every account, and a
+	// broker, engine and universe per account. Competition entrants get a
+	// decider each and paper books on one shared feed.
+	decider := initializeDecider(ctx, cfg)
+	var feed *paper.Feed
+	if compete {
+		feed = paper.Share(newVenueBroker(cfg, cfg.Paper.Feed))
+	}
+	var accts []*account
+	for _, acfg := range accountCfgs {
+		a := &account{cfg: acfg, ticks: marketdata.NewSanitizer(acfg.DataFilter.MaxJumpPct)}
+		actx := a.with(ctx)
+		d := decider
+		switch {
+		case acfg.Competing:
+			logger.Info(actx, "Entrant configured", "provider", acfg.LLM.Provider, "model", acfg.LLM.Model, "stop_mode", acfg.Stop.Mode)
+			d = observedDecider(acfg)
+		case a.name() != "":
+			logger.Info(actx, "Account configured", "mode", acfg.Mode, "broker", acfg.Broker, "secret_prefix", acfg.SecretPrefix)
+			checkSecrets(actx, brokerSecrets(acfg))
+		}
+		a.brk = initializeBroker(actx, acfg, feed)
+		a.eng = initializeEngine(acfg, a.brk, d)
+
+		// Resolve the symbols to trade
+		a.uni, err = initializeUniverse(actx, acfg)
+		if err != nil {
+			return 1
+		}
+		a.symbols = a.uni.Symbols()
+		accts = append(accts, a)
+	}
+	eng := engineFor(accts)
+	crash.SetPositions(eng.Positions)
+
+	// Remote control API; its engine calls run in this loop
+	var ops <-chan func()
+	ctl := initializeControl(ctx, cfg, eng)
+	if ctl != nil {
+		ops = ctl.Ops()
+	}
+	paused := func() bool { return ctl != nil && ctl.Paused() }
+
+	// Start brokers (WebSocket connections if in LIVE mode)
+	for _, a := range accts {
+		actx := a.with(ctx)
+		if err := a.brk.Start(actx, a.symbols); err != nil {
+			logger.ErrorWithErr(actx, "Failed to start broker", err)
+			return 1
+		}
+		defer a.brk.Stop(actx)
+	}
+
+	// Dependency health; LIVE trading waits for the critical ones
+	if !initializeHealth(ctx, cfg, accts) {
+		return 1
+	}
+
+	// Competition standings, sampled after every poll
+	var board *competition.Board
+	if compete {
+		board = competition.NewBoard(time.Now())
+		updateLeaderboard(ctx, cfg, board, accts)
+	}
+
+	// Setup tickers
+	tick := time.NewTicker(time.Duration(cfg.PollSeconds) * time.Second)
+	defer tick.Stop()
+	eodTick := time.NewTicker(60 * time.Second)
+	defer eodTick.Stop()
+
+	// Universe refresh (nil channel never fires)
+	var refresh <-chan time.Time
+	if cfg.Universe.RefreshMinutes > 0 {
+		t := time.NewTicker(time.Duration(cfg.Universe.RefreshMinutes) * time.Minute)
+		defer t.Stop()
+		refresh = t.C
+	}
+
+	// Tick streams of the accounts with event-driven evaluation, merged
+	ticks := make(chan accountTick, 256)
+	for _, a := range accts {
+		if a.cfg.Event.Enabled {
+			a.forwardTicks(ctx, ticks)
+			logger.Info(a.with(ctx), "Event-driven evaluation enabled",
+				"stop_proximity_pct", a.cfg.Event.StopProximityPct,
+				"min_interval_seconds", a.cfg.Event.MinIntervalSeconds,
+			)
+		}
+	}
+
+	logger.Info(ctx, "Bot started - entering main loop",
+		"poll_interval_seconds", cfg.PollSeconds,
+		"symbols", allSymbols(accts),
+	)
+
+	// Main event loop
+	for {
+		select {
+		case <-tick.C:
+			if paused() {
+				logger.Debug(ctx, "Trading paused through control API - skipping tick")
+				continue
+			}
+
+			// Create a new span for this tick
+			tickCtx, tickSpan := trace.StartSpan(ctx, "tick-processing")
+			symbols := allSymbols(accts)
+			logger.Debug(tickCtx, "Tick - processing symbols", "count", len(symbols))
+
+			// Accounts are stepped side by side, each with its own workers
+			tickStart := time.Now()
+			eachAccount(accts, func(a *account) {
+				actx := a.with(tickCtx)
+				crash.Guard(actx, "tick", func() {
+					a.eng.PlanTick(actx, a.symbols)
+					processSymbols(actx, a.eng, a.symbols, a.cfg.PollWorkers)
+				})
+			})
+			elapsed := time.Since(tickStart)
+			metrics.TickSeconds.Observe(elapsed.Seconds())
+			if poll := time.Duration(cfg.PollSeconds) * time.Second; elapsed > poll {
+				metrics.TickOverruns.Inc()
+				logger.Warn(tickCtx, "Poll took longer than poll_seconds - raise poll_workers or poll_seconds",
+					"elapsed_seconds", elapsed.Seconds(),
+					"poll_seconds", cfg.PollSeconds,
+					"symbols", len(symbols),
+					"workers", cfg.PollWorkers,
+				)
+			}
+			if board != nil {
+				updateLeaderboard(tickCtx, cfg, board, accts)
+			}
+			tickSpan.End()
+
+		case at := <-ticks:
+			if paused() {
+				continue
+			}
+			a, tk := at.acct, at.tick
+			actx := a.with(ctx)
+			if !a.ticks.Tick(actx, tk) {
+				continue
+			}
+			if tk.BarClose || a.eng.ShouldEvaluate(actx, tk.Symbol, tk.Price) {
+				evCtx, evSpan := trace.StartSpan(actx, "tick-event")
+				processSymbol(evCtx, a.eng, tk.Symbol)
+				evSpan.End()
+			}
+
+		case op := <-ops:
+			crash.Guard(ctx, "control", op)
+
+		case <-refresh:
+			for _, a := range accts {
+				actx := a.with(ctx)
+				var next []string
+				var ok bool
+				crash.Guard(actx, "universe-refresh", func() { next, ok = refreshUniverse(actx, a.uni, a.brk, a.eng) })
+				if ok {
+					a.symbols = next
+					if a.cfg.Event.Enabled {
+						a.forwardTicks(ctx, ticks)
+					}
+				}
+			}
+
+		case <-eodTick.C:
+			eodCtx, eodSpan := trace.StartSpan(ctx, "eod-check")
+			crash.Guard(eodCtx, "eod", func() {
+				if ok, _ := eod.ShouldRunNow(); ok {
+					logger.Info(eodCtx, "Running end-of-day summary")
+					if p, err := eod.SummarizeToday(); err == nil && p != "" {
+						logger.Info(eodCtx, "EOD CSV written successfully", "path", p)
+					} else if err != nil {
+						logger.ErrorWithErr(eodCtx, "Failed to write EOD CSV", err)
+					}
+				}
+			})
+			eodSpan.End()
+
+		case <-sigc:
+			if board != nil {
+				finishCompetition(ctx, cfg, board, accts)
+			}
+			shutdown(ctx, accts)
+			return 0
+
+		case <-ctx.Done():
+			logger.Info(ctx, "Context cancelled - exiting")
+			return 0
+		}
+	}
+}
+
+// allSymbols lists the symbols traded by any account.
+func allSymbols(accts []*account) []string {
+	if len(accts) == 1 {
+		return accts[0].symbols
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, a := range accts {
+		for _, sym := range a.symbols {
+			if !seen[sym] {
+				seen[sym] = true
+				out = append(out, sym)
+			}
+		}
+	}
+	return out
+}
+
+// shutdown applies each account's shutdown policy while its broker is
+// still connected, then stops the brokers and writes the final EOD
+// summary. Logs, traces and notifications are flushed by the deferred
+// closers.
+func shutdown(ctx context.Context, accts []*account) {
+	ctx, span := trace.StartSpan(ctx, "graceful-shutdown")
+	defer span.End()
+
+	eachAccount(accts, func(a *account) {
+		shutdownAccount(a.with(ctx), a.cfg, a.brk, a.eng)
+	})
+
+	// Generate final EOD summary
+	logger.Info(ctx, "Generating final end-of-day summary")
+	if p, err := eod.SummarizeToday(); err == nil && p != "" {
+		logger.Info(ctx, "Final EOD CSV written", "path", p)
+	} else if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to write final EOD CSV", err)
+	}
+
+	logger.Info(ctx, "=== LLM Trading Bot Shutdown Complete ===")
+}
+
+// shutdownAccount cancels, flattens or protects as the account's shutdown
+// policy says, within its timeout, and stops its broker.
+func shutdownAccount(ctx context.Context, cfg *store.Config, brk interfaces.Broker, eng interfaces.Engine) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Shutdown.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	logger.Info(ctx, "Shutdown signal received - gracefully shutting down", "positions", cfg.Shutdown.Positions)
+
+	if cfg.Shutdown.CancelOpenOrders {
+		_, _ = eng.CancelOpenOrders(ctx)
+	}
+
+	switch cfg.Shutdown.Positions {
+	case "flatten":
+		if _, err := eng.Flatten(ctx, "SHUTDOWN"); err != nil {
+			// Whatever could not be sold should at least keep a stop
+			_ = eng.Protect(ctx)
+		}
+	case "protect":
+		_ = eng.Protect(ctx)
+	}
+	if open := eng.Positions(); len(open) > 0 {
+		logger.Warn(ctx, "Exiting with open positions", "positions", open)
+	}
+
+	// Stop broker connections
+	logger.Info(ctx, "Stopping broker connections")
+	brk.Stop(ctx)
+}
+
+// refreshUniverse re-resolves the universe, keeping symbols with open
+// positions, and restarts the broker streams on the new symbols when it
+// changed. It reports whether the universe changed.
+func refreshUniverse(ctx context.Context, uni *universe.Manager, brk interfaces.Broker, eng interfaces.Engine) ([]string, bool) {
+	ctx, span := trace.StartSpan(ctx, "universe-refresh")
+	defer span.End()
+
+	var held []string
+	for _, p := range eng.Positions() {
+		held = append(held, p.Symbol)
+	}
+	changes, err := uni.Refresh(ctx, held)
+	if err != nil {
+		logger.Warn(ctx, "Universe refresh incomplete", "error", err)
+	}
+	if len(changes) == 0 {
+		return nil, false
+	}
+
+	symbols := uni.Symbols()
+	logger.Info(ctx, "Universe refreshed - restarting broker streams", "changes", len(changes), "symbols", symbols)
+	brk.Stop(ctx)
+	if err := brk.Start(ctx, symbols); err != nil {
+		logger.ErrorWithErr(ctx, "Failed to restart broker on the new universe", err)
+	}
+	return symbols, true
+}
+
+// processSymbols steps every symbol with up to workers steps in flight
+// and returns when all are done
+func processSymbols(ctx context.Context, eng interfaces.Engine, symbols []string, workers int) {
+	if workers <= 1 {
+		for _, sym := range symbols {
+			processSymbol(ctx, eng, sym)
+		}
+		return
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, sym := range symbols {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(sym string) {
+			defer func() { <-sem; wg.Done() }()
+			processSymbol(ctx, eng, sym)
+		}(sym)
+	}
+	wg.Wait()
+}
+
+// processSymbol runs one engine step for a symbol and prints the result. A
+// panic in the step is reported and the symbol is stepped again next tick.
+func processSymbol(ctx context.Context, eng interfaces.Engine, sym string) {
+	symCtx, symSpan := trace.StartSpan(ctx, "process-symbol")
+	defer symSpan.End()
+	defer crash.Recover(symCtx, "step", "symbol", sym)
+
+	st, err := eng.Step(symCtx, sym)
+	if err != nil {
+		logger.ErrorWithErr(symCtx, "Symbol processing failed", err, "symbol", sym)
+		return
+	}
+
+	if st != nil {
+		logger.Debug(symCtx, "Symbol state updated", "symbol", sym, "state", st)
+		b, _ := json.Marshal(st)
+		fmt.Println(string(b))
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// runSources prints the data sources ranked by their recent quality
+// score, as saved by the running bot.
+func runSources(args []string) int {
+	fs := newFlagSet("sources")
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	if cfg.API.QualityFile == "" {
+		fmt.Fprintln(os.Stderr, "api.quality_file is not set: scores are only kept in memory")
+		return 1
+	}
+	stats, err := quality.Load(cfg.API.QualityFile)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No data source calls recorded yet.")
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", cfg.API.QualityFile, err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Rank\tSource\tScore\tSuccess\tLatency\tComplete\tCalls\tLast call\tLast error")
+	for i, s := range stats {
+		fmt.Fprintf(tw, "%d\t%s\t%.2f\t%.0f%%\t%.0fms\t%.0f%%\t%d\t%s\t%s\n",
+			i+1, s.Source, s.Score(), s.SuccessRate*100, s.LatencyMS, s.Completeness*100, s.Calls,
+			s.LastCall.In(timeutil.IST).Format(time.DateTime), s.LastError)
+	}
+	tw.Flush()
+	return 0
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/symbols"
+)
+
+// runSymbol looks symbols or ISINs up in the NSE symbol master, fetching
+// it when the cache in symbols.cache_dir is stale.
+func runSymbol(args []string) int {
+	fs := newFlagSet("symbol")
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: tradingbot symbol [flags] SYMBOL|ISIN...")
+		return 2
+	}
+	if err := initializeSystem(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg, err := store.LoadProfile(*cf.path, *cf.profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	if err := initializeAPIClient(ctx, cfg); err != nil {
+		return 1
+	}
+	reg, err := symbols.Load(ctx, symbols.Params{
+		CacheDir: cfg.Symbols.CacheDir,
+		MaxAge:   time.Duration(cfg.Symbols.RefreshHours) * time.Hour,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load symbol master: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Symbol\tName\tISIN\tSeries\tIndustry\tMarket lot\tF&O lot")
+	for _, q := range fs.Args() {
+		info, ok := reg.ByISIN(q)
+		if !ok {
+			info, ok = reg.Get(strings.ToUpper(q))
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s: not found\n", q)
+			code = 1
+			continue
+		}
+		lot := "-"
+		if info.LotSize > 0 {
+			lot = fmt.Sprint(info.LotSize)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", info.Symbol, info.Name, info.ISIN, info.Series, info.Industry, info.MarketLot, lot)
+	}
+	tw.Flush()
+	return code
+}
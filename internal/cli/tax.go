@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/tax"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// runTax matches the trade log's buys and sells FIFO and writes the
+// financial year's lots as CSV or XLSX.
+func runTax(args []string) int {
+	fs := newFlagSet("tax")
+	fy := fs.String("fy", tax.FY(time.Now()), "financial year to report (e.g. 2025-26)")
+	years := fs.Int("history", 3, "years of trade log before the FY to read for older buys")
+	format := fs.String("format", "csv", "output format: csv | xlsx")
+	out := fs.String("out", "", "output file (default tax-<fy>[-<account>].<format>)")
+	account := fs.String("account", "", "report this account's lots only (each account holder files their own)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, to, err := tax.FYRange(*fy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+	if *format != "csv" && *format != "xlsx" {
+		fmt.Fprintf(os.Stderr, "invalid -format %q: must be csv or xlsx\n", *format)
+		return 2
+	}
+	if *out == "" && *account != "" {
+		*out = fmt.Sprintf("tax-%s-%s.%s", *fy, *account, *format)
+	} else if *out == "" {
+		*out = fmt.Sprintf("tax-%s.%s", *fy, *format)
+	}
+
+	if today := timeutil.Now(); to.After(today) {
+		to = today
+	}
+	entries, err := journal.Load(from.AddDate(-*years, 0, 0), to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read trade log: %v\n", err)
+		return 1
+	}
+
+	lots, unmatched := tax.Match(journal.OfAccount(entries, *account))
+	lots = tax.InFY(lots, *fy)
+	if unmatched > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d sold shares had no buy in the log; widen -history or add them manually\n", unmatched)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create %s: %v\n", *out, err)
+		return 1
+	}
+	if *format == "xlsx" {
+		err = tax.WriteXLSX(f, lots)
+	} else {
+		err = tax.WriteCSV(f, lots)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *out, err)
+		return 1
+	}
+
+	fmt.Printf("FY %s: %d lots written to %s\n", *fy, len(lots), *out)
+	for _, t := range tax.Totals(lots) {
+		fmt.Printf("  %-12s %4d lots  gain %16s\n", t.Class, t.Lots, money.Format(t.Gain))
+	}
+	return 0
+}
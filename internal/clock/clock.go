@@ -0,0 +1,46 @@
+// Package clock provides the interfaces.Clock implementations. Code that
+// makes decisions from "now" (stop holding periods, cooldowns, the EOD
+// cutoff, log dates) is given a clock so a backtest can replay history
+// with a Sim set to each bar.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Real reads the wall clock.
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+// Sim is a clock that only moves when told to. It is safe for concurrent
+// use.
+type Sim struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func NewSim(t time.Time) *Sim {
+	return &Sim{t: t}
+}
+
+func (s *Sim) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.t
+}
+
+// Set moves the clock to t, backwards if need be.
+func (s *Sim) Set(t time.Time) {
+	s.mu.Lock()
+	s.t = t
+	s.mu.Unlock()
+}
+
+// Advance moves the clock forward by d.
+func (s *Sim) Advance(d time.Duration) {
+	s.mu.Lock()
+	s.t = s.t.Add(d)
+	s.mu.Unlock()
+}
@@ -0,0 +1,162 @@
+// Package competition ranks strategies traded side by side on paper
+// (tradingbot compete) by the equity of their virtual books.
+package competition
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// Standing is one entrant's place on the leaderboard.
+type Standing struct {
+	Rank           int
+	Entrant        string
+	StartEquity    float64
+	Equity         float64
+	ReturnPct      float64
+	MaxDrawdownPct float64 // Peak-to-trough fall of the sampled equity
+	Realized       float64 // P&L of closed trades
+	Trades         int     // Closed trades
+	Wins           int
+	WinRatePct     float64
+}
+
+// Leaderboard ranks the entrants by return, best first.
+type Leaderboard struct {
+	Time      time.Time
+	Since     time.Time
+	Standings []Standing
+}
+
+type track struct {
+	start, equity, peak, maxDDPct float64
+}
+
+// Board samples each entrant's equity over a competition. It is safe for
+// concurrent use.
+type Board struct {
+	since time.Time
+
+	mu     sync.Mutex
+	tracks map[string]*track
+}
+
+func NewBoard(since time.Time) *Board {
+	return &Board{since: since, tracks: make(map[string]*track)}
+}
+
+// Since is when the competition started.
+func (b *Board) Since() time.Time {
+	return b.since
+}
+
+// Record samples an entrant's equity. The first sample is its starting
+// equity.
+func (b *Board) Record(entrant string, equity float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.tracks[entrant]
+	if t == nil {
+		t = &track{start: equity, peak: equity}
+		b.tracks[entrant] = t
+	}
+	t.equity = equity
+	if equity > t.peak {
+		t.peak = equity
+	}
+	if t.peak > 0 {
+		if dd := (t.peak - equity) / t.peak * 100; dd > t.maxDDPct {
+			t.maxDDPct = dd
+		}
+	}
+}
+
+// Standings ranks the recorded entrants at now. trades are the
+// competition's round trips, tagged with the entrant as their account;
+// open ones are skipped.
+func (b *Board) Standings(now time.Time, trades []*journal.Trade) *Leaderboard {
+	b.mu.Lock()
+	rows := make([]Standing, 0, len(b.tracks))
+	for name, t := range b.tracks {
+		s := Standing{
+			Entrant:        name,
+			StartEquity:    t.start,
+			Equity:         t.equity,
+			MaxDrawdownPct: t.maxDDPct,
+		}
+		if t.start > 0 {
+			s.ReturnPct = (t.equity - t.start) / t.start * 100
+		}
+		rows = append(rows, s)
+	}
+	b.mu.Unlock()
+
+	byEntrant := make(map[string]*Standing, len(rows))
+	for i := range rows {
+		byEntrant[rows[i].Entrant] = &rows[i]
+	}
+	for _, tr := range trades {
+		s := byEntrant[tr.Entry.Account]
+		if s == nil || !tr.Closed() {
+			continue
+		}
+		pnl := tr.PnL()
+		s.Realized += pnl
+		s.Trades++
+		if pnl > 0 {
+			s.Wins++
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].ReturnPct != rows[j].ReturnPct {
+			return rows[i].ReturnPct > rows[j].ReturnPct
+		}
+		return rows[i].Entrant < rows[j].Entrant
+	})
+	for i := range rows {
+		rows[i].Rank = i + 1
+		if rows[i].Trades > 0 {
+			rows[i].WinRatePct = float64(rows[i].Wins) / float64(rows[i].Trades) * 100
+		}
+	}
+	return &Leaderboard{Time: now, Since: b.since, Standings: rows}
+}
+
+// Write saves the leaderboard as JSON, replacing the previous one.
+func Write(path string, l *Leaderboard) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Text renders the leaderboard for logs and chat notifications.
+func (l *Leaderboard) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Leaderboard since %s", l.Since.In(timeutil.IST).Format("2006-01-02 15:04"))
+	for _, s := range l.Standings {
+		fmt.Fprintf(&sb, "\n%d. %s: %+.2f%% (equity %s) | Max DD %.2f%% | Realized %s | %d trades, %.0f%% won",
+			s.Rank, s.Entrant, s.ReturnPct, money.Format(s.Equity), s.MaxDrawdownPct, money.Signed(s.Realized), s.Trades, s.WinRatePct)
+	}
+	return sb.String()
+}
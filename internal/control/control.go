@@ -0,0 +1,332 @@
+// Package control serves an authenticated HTTP API for operating a running
+// bot: pause and resume trading, halt orders, flatten positions, block
+// symbols, query decisions and change log levels. Engine calls are handed
+// to the bot's main loop through Ops so they never run concurrently with a
+// step.
+package control
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"llm-trading-bot/internal/health"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/killswitch"
+	"llm-trading-bot/internal/lifecycle"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+	"llm-trading-bot/internal/types"
+)
+
+// opTimeout bounds how long a request waits for the main loop, which may
+// be in the middle of a poll over the whole universe.
+const opTimeout = 60 * time.Second
+
+type Server struct {
+	token  string
+	eng    interfaces.Engine
+	ops    chan func()
+	paused atomic.Bool
+}
+
+// New returns a server that accepts requests bearing token. It never
+// serves without one.
+func New(token string, eng interfaces.Engine) *Server {
+	return &Server{token: token, eng: eng, ops: make(chan func())}
+}
+
+// Ops delivers engine calls queued by requests; the main loop runs each
+// one as it receives it.
+func (s *Server) Ops() <-chan func() {
+	return s.ops
+}
+
+// Paused reports whether trading was stopped through the API.
+func (s *Server) Paused() bool {
+	return s.paused.Load()
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", s.status)
+	mux.HandleFunc("GET /api/positions", s.positions)
+	mux.HandleFunc("GET /api/decisions", s.decisions)
+	mux.HandleFunc("GET /api/symbols", s.symbols)
+	mux.HandleFunc("POST /api/symbols/{symbol}/block", s.block)
+	mux.HandleFunc("POST /api/symbols/{symbol}/unblock", s.block)
+	mux.HandleFunc("POST /api/trading/start", s.start)
+	mux.HandleFunc("POST /api/trading/stop", s.stop)
+	mux.HandleFunc("POST /api/positions/flatten", s.flatten)
+	mux.HandleFunc("POST /api/halt", s.halt)
+	mux.HandleFunc("DELETE /api/halt", s.halt)
+	mux.HandleFunc("GET /api/logging", s.logging)
+	mux.HandleFunc("POST /api/logging", s.logging)
+	mux.HandleFunc("DELETE /api/logging", s.logging)
+	return s.auth(mux)
+}
+
+// Serve exposes the API on addr until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, addr string) {
+	srv := &http.Server{Addr: addr, Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info(ctx, "Control API listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.ErrorWithErr(ctx, "Control API failed", err)
+	}
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// do runs fn on the main loop and waits for it to finish.
+func (s *Server) do(ctx context.Context, fn func()) error {
+	ctx, cancel := context.WithTimeout(ctx, opTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	select {
+	case s.ops <- func() { defer close(done); fn() }:
+	case <-ctx.Done():
+		return errors.New("bot main loop is busy")
+	}
+	<-done
+	return nil
+}
+
+type statusView struct {
+	Paused     bool             `json:"paused"`
+	Halted     bool             `json:"halted"`
+	HaltReason string           `json:"halt_reason,omitempty"`
+	Health     []health.Status  `json:"health,omitempty"`
+	Positions  []types.Position `json:"positions"`
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	var v statusView
+	if err := s.do(r.Context(), func() { v.Positions = s.eng.Positions() }); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	v.Paused = s.Paused()
+	v.Halted, v.HaltReason = killswitch.Default().Halted()
+	v.Health = health.Default().Last().Checks
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) positions(w http.ResponseWriter, r *http.Request) {
+	var positions []types.Position
+	if err := s.do(r.Context(), func() { positions = s.eng.Positions() }); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, positions)
+}
+
+func (s *Server) start(w http.ResponseWriter, r *http.Request) {
+	if s.paused.Swap(false) {
+		logger.Info(r.Context(), "Trading resumed through control API", "remote", r.RemoteAddr)
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"paused": false})
+}
+
+func (s *Server) stop(w http.ResponseWriter, r *http.Request) {
+	if !s.paused.Swap(true) {
+		logger.Warn(r.Context(), "Trading paused through control API", "remote", r.RemoteAddr)
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"paused": true})
+}
+
+func (s *Server) symbols(w http.ResponseWriter, r *http.Request) {
+	var states []lifecycle.State
+	if err := s.do(r.Context(), func() { states = s.eng.SymbolStates() }); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+// block blocks or releases the symbol in the path; ?reason= is recorded
+// with the new status.
+func (s *Server) block(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	blocked := strings.HasSuffix(r.URL.Path, "/block")
+	reason := r.URL.Query().Get("reason")
+	if reason == "" {
+		reason = "control API"
+	}
+
+	var err error
+	if derr := s.do(r.Context(), func() { err = s.eng.SetBlocked(r.Context(), symbol, blocked, reason) }); derr != nil {
+		writeError(w, http.StatusServiceUnavailable, derr.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	logger.Warn(r.Context(), "Symbol block changed through control API", "symbol", symbol, "blocked", blocked, "remote", r.RemoteAddr)
+	writeJSON(w, http.StatusOK, map[string]any{"symbol": symbol, "blocked": blocked})
+}
+
+// halt creates (POST) or removes (DELETE) the kill switch halt file.
+// Unlike pausing it bypasses the main loop, so it takes effect even
+// mid-poll, and it survives restarts until released; ?reason= is written
+// to the file.
+func (s *Server) halt(w http.ResponseWriter, r *http.Request) {
+	ks := killswitch.Default()
+	var err error
+	if r.Method == http.MethodDelete {
+		err = ks.Resume()
+	} else {
+		reason := r.URL.Query().Get("reason")
+		if reason == "" {
+			reason = "control API"
+		}
+		err = ks.Halt(reason)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	halted, reason := ks.Halted()
+	logger.Warn(r.Context(), "Kill switch changed through control API", "halted", halted, "reason", reason, "remote", r.RemoteAddr)
+	writeJSON(w, http.StatusOK, map[string]any{"halted": halted, "reason": reason})
+}
+
+type loggingView struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// logging reports the log levels (GET), sets the level of ?module= or the
+// global level to ?level= (POST), or removes the override of ?module=
+// (DELETE).
+func (s *Server) logging(w http.ResponseWriter, r *http.Request) {
+	module, level := r.URL.Query().Get("module"), r.URL.Query().Get("level")
+	switch r.Method {
+	case http.MethodPost:
+		if err := logger.SetLevel(module, level); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logger.Warn(r.Context(), "Log level changed through control API", "module", module, "level", level, "remote", r.RemoteAddr)
+	case http.MethodDelete:
+		if module == "" {
+			writeError(w, http.StatusBadRequest, "module is required")
+			return
+		}
+		logger.ResetLevel(module)
+		logger.Warn(r.Context(), "Log level override removed through control API", "module", module, "remote", r.RemoteAddr)
+	}
+	var v loggingView
+	v.Level, v.Modules = logger.Levels()
+	writeJSON(w, http.StatusOK, v)
+}
+
+type flattenView struct {
+	Orders []types.OrderResp `json:"orders"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// flatten sells every open position and pauses trading so the next poll
+// does not buy back in.
+func (s *Server) flatten(w http.ResponseWriter, r *http.Request) {
+	s.paused.Store(true)
+	logger.Warn(r.Context(), "Flatten requested through control API - trading paused", "remote", r.RemoteAddr)
+
+	var (
+		v   flattenView
+		err error
+	)
+	if derr := s.do(r.Context(), func() {
+		v.Orders, err = s.eng.Flatten(context.WithoutCancel(r.Context()), "MANUAL_FLATTEN")
+	}); derr != nil {
+		writeError(w, http.StatusServiceUnavailable, derr.Error())
+		return
+	}
+	if err != nil {
+		v.Error = err.Error()
+		writeJSON(w, http.StatusBadGateway, v)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// decisions returns logged decisions, newest first. Query parameters:
+// from, to (YYYY-MM-DD, IST; default today), symbol (comma-separated),
+// action and limit (default 100).
+func (s *Server) decisions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	today := timeutil.DateKey(timeutil.Now())
+
+	var query tradelog.Query
+	var err error
+	if query.From, err = parseDay(q.Get("from"), today); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid from: "+err.Error())
+		return
+	}
+	if query.To, err = parseDay(q.Get("to"), today); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid to: "+err.Error())
+		return
+	}
+	if sym := q.Get("symbol"); sym != "" {
+		query.Symbols = strings.Split(sym, ",")
+	}
+	query.Action = strings.ToUpper(q.Get("action"))
+
+	limit := 100
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+
+	entries, err := tradelog.Decisions(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]tradelog.DecisionEntry, 0, min(limit, len(entries)))
+	for i := len(entries) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, entries[i])
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func parseDay(v, def string) (time.Time, error) {
+	if v == "" {
+		v = def
+	}
+	return timeutil.ParseDate(v)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
@@ -0,0 +1,230 @@
+// Package corpactions back-adjusts candle history for corporate actions.
+// A split or bonus cuts the price overnight; left alone, the gap reads as
+// a crash to every indicator. Adjusting scales the bars before each
+// ex-date so the series is continuous at today's share count.
+package corpactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+const (
+	Split    = "SPLIT"
+	Bonus    = "BONUS"
+	Dividend = "DIVIDEND"
+)
+
+// Action is one corporate action. Ratio is the price multiplier for bars
+// before ExDate (splits and bonuses); Amount is the dividend per share.
+type Action struct {
+	Symbol  string    `json:"symbol"`
+	ExDate  time.Time `json:"ex_date"` // Midnight IST
+	Kind    string    `json:"kind"`
+	Ratio   float64   `json:"ratio,omitempty"`
+	Amount  float64   `json:"amount,omitempty"`
+	Subject string    `json:"subject,omitempty"` // As announced
+}
+
+// Source returns the corporate actions of a symbol, in any order. Name
+// identifies it in quality scores.
+type Source interface {
+	Name() string
+	Actions(ctx context.Context, symbol string) ([]Action, error)
+}
+
+// Adjust returns a copy of candles with prices before each action's
+// ex-date scaled by the action's factor and volumes scaled inversely for
+// splits and bonuses. Dividends are applied only when dividends is set.
+func Adjust(candles []types.Candle, actions []Action, dividends bool) []types.Candle {
+	out := append([]types.Candle(nil), candles...)
+	for _, a := range actions {
+		ex := a.ExDate.Unix()
+		cut := sort.Search(len(out), func(i int) bool { return out[i].Ts >= ex })
+		if cut == 0 || cut == len(out) {
+			continue // Ex-date outside the window: nothing to join up
+		}
+
+		price, vol := 1.0, 1.0
+		switch a.Kind {
+		case Split, Bonus:
+			if a.Ratio <= 0 {
+				continue
+			}
+			price, vol = a.Ratio, 1/a.Ratio
+		case Dividend:
+			prev := out[cut-1].Close
+			if !dividends || a.Amount <= 0 || a.Amount >= prev {
+				continue
+			}
+			price = 1 - a.Amount/prev
+		default:
+			continue
+		}
+
+		for i := 0; i < cut; i++ {
+			c := &out[i]
+			c.Open *= price
+			c.High *= price
+			c.Low *= price
+			c.Close *= price
+			c.Vol *= vol
+		}
+	}
+	return out
+}
+
+// Adjuster adjusts candles with the actions its sources report, caching
+// each symbol's actions for ttl.
+type Adjuster struct {
+	sources   []Source
+	ttl       time.Duration
+	dividends bool
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+type cached struct {
+	actions   []Action
+	fetchedAt time.Time
+}
+
+// NewAdjuster returns an adjuster reading sources best quality score
+// first (configured order until scored); when two report the same kind of
+// action on the same ex-date, the first wins.
+func NewAdjuster(ttl time.Duration, dividends bool, sources ...Source) *Adjuster {
+	return &Adjuster{sources: sources, ttl: ttl, dividends: dividends, cache: make(map[string]cached)}
+}
+
+// Adjust returns symbol's candles adjusted for its corporate actions. When
+// every source fails the last known actions are used, or the candles are
+// returned unadjusted.
+func (a *Adjuster) Adjust(ctx context.Context, symbol string, candles []types.Candle) []types.Candle {
+	actions := a.actions(ctx, symbol)
+	if len(actions) == 0 {
+		return candles
+	}
+	return Adjust(candles, actions, a.dividends)
+}
+
+// Actions returns symbol's known corporate actions, past and announced,
+// from the same cache Adjust uses.
+func (a *Adjuster) Actions(ctx context.Context, symbol string) []Action {
+	return a.actions(ctx, symbol)
+}
+
+func (a *Adjuster) actions(ctx context.Context, symbol string) []Action {
+	a.mu.Lock()
+	c, ok := a.cache[symbol]
+	a.mu.Unlock()
+	if ok && time.Since(c.fetchedAt) < a.ttl {
+		return c.actions
+	}
+
+	var (
+		merged []Action
+		seen   = make(map[string]bool)
+		failed int
+	)
+	for _, src := range quality.Rank(quality.Default(), a.sources, Source.Name) {
+		actions, err := src.Actions(ctx, symbol)
+		if errors.Is(err, api.ErrNotFound) {
+			continue // The source has nothing on this symbol
+		}
+		if err != nil {
+			logger.Warn(ctx, "Corporate actions unavailable", "symbol", symbol, "error", err)
+			failed++
+			continue
+		}
+		for _, act := range actions {
+			key := act.Kind + act.ExDate.Format("2006-01-02")
+			if !seen[key] {
+				seen[key] = true
+				merged = append(merged, act)
+			}
+		}
+	}
+	if failed == len(a.sources) && ok {
+		merged = c.actions
+	}
+
+	a.mu.Lock()
+	a.cache[symbol] = cached{actions: merged, fetchedAt: time.Now()}
+	a.mu.Unlock()
+	return merged
+}
+
+var (
+	bonusRe    = regexp.MustCompile(`(?i)bonus\s*(\d+(?:\.\d+)?)\s*:\s*(\d+(?:\.\d+)?)`)
+	splitRe    = regexp.MustCompile(`(?i)from\s+r[se]\.?\s*([\d.]+).*?to\s+r[se]\.?\s*([\d.]+)`)
+	dividendRe = regexp.MustCompile(`(?i)r[se]\.?\s*([\d.]+)\s*(?:/-)?\s*per\s+share`)
+)
+
+// ParseSubject reads an announcement subject as NSE words it, e.g.
+// "Bonus 1:1", "Face Value Split (Sub-Division) - From Rs 10/- Per Share
+// To Rs 2/- Per Share" or "Final Dividend - Rs 8 Per Share". Subjects it
+// does not understand (rights, buybacks, AGMs) return ok false.
+func ParseSubject(subject string) (kind string, ratio, amount float64, ok bool) {
+	lower := strings.ToLower(subject)
+	switch {
+	case strings.Contains(lower, "bonus"):
+		m := bonusRe.FindStringSubmatch(subject)
+		if m == nil {
+			return "", 0, 0, false
+		}
+		issued, held := atof(m[1]), atof(m[2])
+		if issued <= 0 || held <= 0 {
+			return "", 0, 0, false
+		}
+		return Bonus, held / (issued + held), 0, true
+
+	case strings.Contains(lower, "split") || strings.Contains(lower, "sub-division"):
+		m := splitRe.FindStringSubmatch(subject)
+		if m == nil {
+			return "", 0, 0, false
+		}
+		from, to := atof(m[1]), atof(m[2])
+		if from <= 0 || to <= 0 {
+			return "", 0, 0, false
+		}
+		return Split, to / from, 0, true
+
+	case strings.Contains(lower, "dividend"):
+		// "Interim Dividend - Rs 5 Per Share And Special Dividend - Rs 3 Per Share"
+		for _, m := range dividendRe.FindAllStringSubmatch(subject, -1) {
+			amount += atof(m[1])
+		}
+		return Dividend, 0, amount, amount > 0
+	}
+	return "", 0, 0, false
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimRight(s, "."), 64)
+	return f
+}
+
+// parseDate reads an ex-date in either NSE ("28-Oct-2024") or ISO form.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"02-Jan-2006", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.IST); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid ex-date %q", s)
+}
@@ -0,0 +1,65 @@
+package corpactions
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/types"
+)
+
+// File is a source read from a CSV with columns symbol,ex_date,subject,
+// where subject is worded as NSE announces it:
+//
+//	RELIANCE,2024-10-28,Bonus 1:1
+//	BSE:500325,2024-10-28,Bonus 1:1
+//	INFY,2024-10-29,Interim Dividend - Rs 21 Per Share
+//
+// It covers exchanges NSE does not and backtests run offline. The file is
+// read on every call; the Adjuster caches the result.
+type File struct {
+	Path string
+}
+
+func (File) Name() string { return "corpactions.file" }
+
+func (f File) Actions(ctx context.Context, symbol string) (actions []Action, err error) {
+	defer func(start time.Time) { quality.Record(f.Name(), start, err, len(actions), len(actions)) }(time.Now())
+
+	fh, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	r := csv.NewReader(fh)
+	r.Comment = '#'
+	r.FieldsPerRecord = 3
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	for i, row := range rows {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "symbol") {
+			continue // Header
+		}
+		if types.NormalizeSymbol(row[0]) != symbol {
+			continue
+		}
+		ex, err := parseDate(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", f.Path, i+1, err)
+		}
+		kind, ratio, amount, ok := ParseSubject(row[2])
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: cannot read %q as a split, bonus or dividend", f.Path, i+1, row[2])
+		}
+		actions = append(actions, Action{Symbol: symbol, ExDate: ex, Kind: kind, Ratio: ratio, Amount: amount, Subject: strings.TrimSpace(row[2])})
+	}
+	return actions, nil
+}
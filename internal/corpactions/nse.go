@@ -0,0 +1,73 @@
+package corpactions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/types"
+)
+
+const nseURL = "https://www.nseindia.com/api/corporates-corporateActions?index=equities&symbol="
+
+// NSE reads corporate actions from the NSE website. NSE rejects requests
+// without its session cookies, so api.warm_up should map www.nseindia.com
+// to its home page. BSE-listed symbols are not covered.
+type NSE struct{}
+
+func (NSE) Name() string { return "corpactions.nse" }
+
+func (n NSE) Actions(ctx context.Context, symbol string) (actions []Action, err error) {
+	exchange, sym := types.SplitSymbol(symbol, types.ExchangeNSE)
+	if exchange != types.ExchangeNSE {
+		return nil, nil
+	}
+	var usable, total int
+	defer func(start time.Time) { quality.Record(n.Name(), start, err, usable, total) }(time.Now())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nseURL+url.QueryEscape(sym), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("nse: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return nil, fmt.Errorf("nse: corporate actions for %s: %w", sym, err)
+	}
+
+	var rows []struct {
+		Symbol  string `json:"symbol"`
+		Subject string `json:"subject"`
+		ExDate  string `json:"exDate"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("nse: decode corporate actions: %w: %w", api.ErrParse, err)
+	}
+
+	for _, r := range rows {
+		if !strings.EqualFold(r.Symbol, sym) {
+			continue
+		}
+		total++
+		ex, perr := parseDate(r.ExDate)
+		if perr != nil || strings.TrimSpace(r.Subject) == "" {
+			continue
+		}
+		usable++
+		if kind, ratio, amount, ok := ParseSubject(r.Subject); ok {
+			actions = append(actions, Action{Symbol: symbol, ExDate: ex, Kind: kind, Ratio: ratio, Amount: amount, Subject: r.Subject})
+		}
+	}
+	return actions, nil
+}
@@ -0,0 +1,162 @@
+// Package crash recovers panics in the bot's long-running goroutines. A
+// recovered panic is logged and saved as a crash report with its stack,
+// the caller's context and a snapshot of the open positions, and raised
+// as a crash alert; Supervise restarts the component that panicked.
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+// Restart backoff for Supervise, doubled after each crash.
+const (
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+)
+
+// Report is one recovered panic, written to logs/crash as JSON.
+type Report struct {
+	Time      string            `json:"time"`
+	Component string            `json:"component"`
+	Panic     string            `json:"panic"`
+	Stack     string            `json:"stack"`
+	Context   map[string]string `json:"context,omitempty"`
+	Positions []types.Position  `json:"positions"`
+}
+
+var (
+	mu        sync.RWMutex
+	positions func() []types.Position
+)
+
+// SetPositions installs the snapshot of open positions taken into crash
+// reports; it must be safe to call from any goroutine.
+func SetPositions(fn func() []types.Position) {
+	mu.Lock()
+	positions = fn
+	mu.Unlock()
+}
+
+// Recover must be deferred directly; it recovers a panic in the calling
+// goroutine and reports it. keysAndValues describe what was running.
+func Recover(ctx context.Context, component string, keysAndValues ...any) {
+	if r := recover(); r != nil {
+		report(ctx, component, r, keysAndValues)
+	}
+}
+
+// Guard runs fn and reports whether it panicked; the panic is reported
+// and does not propagate.
+func Guard(ctx context.Context, component string, fn func(), keysAndValues ...any) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			report(ctx, component, r, keysAndValues)
+		}
+	}()
+	fn()
+	return false
+}
+
+// Supervise runs fn until it returns or ctx is done, restarting it after
+// each panic with a growing delay.
+func Supervise(ctx context.Context, component string, fn func(ctx context.Context)) {
+	delay := minRestartDelay
+	for Guard(ctx, component, func() { fn(ctx) }) {
+		logger.Warn(ctx, "Restarting component after panic", "component", component, "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(delay*2, maxRestartDelay)
+	}
+}
+
+func report(ctx context.Context, component string, r any, keysAndValues []any) {
+	now := time.Now()
+	rep := Report{
+		Time:      now.In(timeutil.IST).Format(time.RFC3339),
+		Component: component,
+		Panic:     fmt.Sprint(r),
+		Stack:     string(debug.Stack()),
+		Context:   fields(keysAndValues),
+		Positions: snapshot(),
+	}
+
+	path, err := write(rep, now)
+	if err != nil {
+		logger.Warn(ctx, "Failed to write crash report", "error", err)
+	}
+	logger.Error(ctx, "Panic recovered",
+		"component", component,
+		"panic", rep.Panic,
+		"context", rep.Context,
+		"positions", len(rep.Positions),
+		"report", path,
+		"stack", rep.Stack,
+	)
+	notify.Send(ctx, notify.EventCrash, "", fmt.Sprintf("%s panicked: %s (%d open positions, report %s)", component, rep.Panic, len(rep.Positions), path))
+}
+
+// snapshot takes the open positions, or none if that panics too.
+func snapshot() (out []types.Position) {
+	mu.RLock()
+	fn := positions
+	mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	defer func() {
+		if recover() != nil {
+			out = nil
+		}
+	}()
+	return fn()
+}
+
+func fields(kv []any) map[string]string {
+	if len(kv) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		out[fmt.Sprint(kv[i])] = fmt.Sprint(kv[i+1])
+	}
+	return out
+}
+
+func logDir() string {
+	if v := os.Getenv("TRADER_LOG_DIR"); v != "" {
+		return v
+	}
+	return "logs"
+}
+
+// write saves rep as logs/crash/<yyyymmdd-hhmmss.mmm>-<component>.json.
+func write(rep Report, at time.Time) (string, error) {
+	dir := filepath.Join(logDir(), "crash")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	component := strings.NewReplacer(":", "-", "/", "-", " ", "-").Replace(rep.Component)
+	path := filepath.Join(dir, at.In(timeutil.IST).Format("20060102-150405.000")+"-"+component+".json")
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, b, 0o644)
+}
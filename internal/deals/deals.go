@@ -0,0 +1,373 @@
+// Package deals tracks NSE bulk and block deals and daily delivery
+// percentages, and flags two patterns worth staying out of: a promoter
+// group selling in bulk, and delivery staying low session after session,
+// which points to speculative churn rather than investors taking stock.
+// The engine flags them to the decider and can refuse new positions in
+// them.
+package deals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+// Patterns a stock can be flagged for.
+const (
+	PromoterExit = "PROMOTER_EXIT"
+	LowDelivery  = "LOW_DELIVERY"
+)
+
+// Deal is one bulk or block deal.
+type Deal struct {
+	Date   time.Time
+	Symbol string
+	Client string
+	Side   string // BUY | SELL
+	Qty    int64
+	Price  float64
+	Kind   string // BULK | BLOCK
+}
+
+// Delivery is one session's deliverable share of the traded quantity.
+type Delivery struct {
+	Date time.Time
+	Pct  float64
+}
+
+// Flag is one pattern found on a stock.
+type Flag struct {
+	Pattern string
+	Detail  string // e.g. "ACME PROMOTER TRUST sold 1200000 in a bulk deal on 2025-11-03"
+}
+
+func (f Flag) String() string {
+	return f.Pattern + " (" + f.Detail + ")"
+}
+
+// Source returns the deals and delivery data. Name identifies it in
+// quality scores.
+type Source interface {
+	Name() string
+	Deals(ctx context.Context, from, to time.Time) ([]Deal, error)
+	Delivery(ctx context.Context, symbol string, from, to time.Time) ([]Delivery, error)
+}
+
+// Params sets what is flagged.
+type Params struct {
+	LookbackDays   int                 // Promoter sells this many days back are flagged
+	Promoters      map[string][]string // Symbol -> promoter group client names, matched as substrings
+	DeliveryDays   int                 // Sessions that must all deliver below MinDeliveryPct
+	MinDeliveryPct float64             // 0 disables the low-delivery check
+}
+
+// Monitor fetches deals and delivery data as of a day and caches them for
+// ttl. It is safe for concurrent use.
+type Monitor struct {
+	src    Source
+	params Params
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	deals     []Deal
+	dealsDay  string
+	dealsAt   time.Time
+	delivery  map[string]deliveryCache
+	promoters map[string][]string
+}
+
+type deliveryCache struct {
+	day  string
+	at   time.Time
+	rows []Delivery
+}
+
+func NewMonitor(ttl time.Duration, p Params, src Source) *Monitor {
+	promoters := make(map[string][]string, len(p.Promoters))
+	for sym, names := range p.Promoters {
+		sym = types.NormalizeSymbol(sym)
+		for _, n := range names {
+			promoters[sym] = append(promoters[sym], strings.ToUpper(strings.TrimSpace(n)))
+		}
+	}
+	return &Monitor{src: src, params: p, ttl: ttl, delivery: make(map[string]deliveryCache), promoters: promoters}
+}
+
+// Flags returns the patterns symbol shows as of t, nil when none.
+func (m *Monitor) Flags(ctx context.Context, symbol string, t time.Time) []Flag {
+	var out []Flag
+	if f, ok := m.promoterExit(ctx, symbol, t); ok {
+		out = append(out, f)
+	}
+	if f, ok := m.lowDelivery(ctx, symbol, t); ok {
+		out = append(out, f)
+	}
+	return out
+}
+
+// promoterExit finds the latest sell by symbol's promoter group within
+// the lookback.
+func (m *Monitor) promoterExit(ctx context.Context, symbol string, t time.Time) (Flag, bool) {
+	names := m.promoters[symbol]
+	since := timeutil.Midnight(t).AddDate(0, 0, -m.params.LookbackDays)
+	deals := m.loadDeals(ctx, t)
+	var latest *Deal
+	for i, d := range deals {
+		if d.Symbol != symbol || d.Side != "SELL" || d.Date.Before(since) || d.Date.After(t) || !promoter(d.Client, names) {
+			continue
+		}
+		if latest == nil || d.Date.After(latest.Date) {
+			latest = &deals[i]
+		}
+	}
+	if latest == nil {
+		return Flag{}, false
+	}
+	return Flag{
+		Pattern: PromoterExit,
+		Detail:  fmt.Sprintf("%s sold %d in a %s deal on %s", latest.Client, latest.Qty, strings.ToLower(latest.Kind), timeutil.DateKey(latest.Date)),
+	}, true
+}
+
+// promoter reports whether client is one of names, or calls itself a
+// promoter.
+func promoter(client string, names []string) bool {
+	client = strings.ToUpper(client)
+	if strings.Contains(client, "PROMOTER") {
+		return true
+	}
+	for _, n := range names {
+		if n != "" && strings.Contains(client, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowDelivery flags symbol when each of its last DeliveryDays sessions up
+// to t delivered below MinDeliveryPct.
+func (m *Monitor) lowDelivery(ctx context.Context, symbol string, t time.Time) (Flag, bool) {
+	n := m.params.DeliveryDays
+	if m.params.MinDeliveryPct <= 0 || n <= 0 {
+		return Flag{}, false
+	}
+	var rows []Delivery
+	for _, r := range m.loadDelivery(ctx, symbol, t) {
+		if !r.Date.After(t) {
+			rows = append(rows, r)
+		}
+	}
+	if len(rows) < n {
+		return Flag{}, false
+	}
+	sum := 0.0
+	for _, r := range rows[len(rows)-n:] {
+		if r.Pct >= m.params.MinDeliveryPct {
+			return Flag{}, false
+		}
+		sum += r.Pct
+	}
+	return Flag{
+		Pattern: LowDelivery,
+		Detail:  fmt.Sprintf("delivery %.1f%% over %d sessions", sum/float64(n), n),
+	}, true
+}
+
+// loadDeals returns the deals of the lookback up to t's day, refetched
+// once older than ttl or when the day changes. On failure the previous
+// deals are kept.
+func (m *Monitor) loadDeals(ctx context.Context, t time.Time) []Deal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	day := timeutil.DateKey(t)
+	if m.dealsDay == day && time.Since(m.dealsAt) < m.ttl {
+		return m.deals
+	}
+	deals, err := m.src.Deals(ctx, timeutil.Midnight(t).AddDate(0, 0, -m.params.LookbackDays), t)
+	m.dealsAt = time.Now()
+	if err != nil {
+		logger.Warn(ctx, "Bulk/block deals unavailable", "source", m.src.Name(), "error", err)
+		return m.deals
+	}
+	m.deals, m.dealsDay = deals, day
+	logger.Debug(ctx, "Bulk/block deals loaded", "deals", len(deals), "day", day)
+	return deals
+}
+
+// loadDelivery returns symbol's delivery data up to t's day in date
+// order, cached like loadDeals.
+func (m *Monitor) loadDelivery(ctx context.Context, symbol string, t time.Time) []Delivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	day := timeutil.DateKey(t)
+	cached, ok := m.delivery[symbol]
+	if ok && cached.day == day && time.Since(cached.at) < m.ttl {
+		return cached.rows
+	}
+	// Calendar days spanning the sessions, with room for weekends and holidays
+	from := timeutil.Midnight(t).AddDate(0, 0, -2*m.params.DeliveryDays-7)
+	rows, err := m.src.Delivery(ctx, symbol, from, t)
+	if err != nil {
+		logger.Warn(ctx, "Delivery data unavailable", "symbol", symbol, "source", m.src.Name(), "error", err)
+		cached.at = time.Now()
+		m.delivery[symbol] = cached
+		return cached.rows
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Date.Before(rows[j].Date) })
+	m.delivery[symbol] = deliveryCache{day: day, at: time.Now(), rows: rows}
+	return rows
+}
+
+// NSE endpoints for historical bulk and block deals and a security's
+// deliverable quantity. Like every nseindia.com API they need the session
+// cookies set up by api.warm_up.
+const (
+	nseBulkURL     = "https://www.nseindia.com/api/historical/bulk-deals"
+	nseBlockURL    = "https://www.nseindia.com/api/historical/block-deals"
+	nseDeliveryURL = "https://www.nseindia.com/api/historical/securityArchives"
+)
+
+// NSE reads deals and delivery data from NSE's historical reports.
+type NSE struct{}
+
+func (NSE) Name() string { return "deals.nse" }
+
+func (n NSE) Deals(ctx context.Context, from, to time.Time) (out []Deal, err error) {
+	var usable, total int
+	defer func(start time.Time) { quality.Record(n.Name(), start, err, usable, total) }(time.Now())
+
+	q := url.Values{"from": {nseDate(from)}, "to": {nseDate(to)}}
+	for _, kind := range []string{"BULK", "BLOCK"} {
+		u := nseBulkURL
+		if kind == "BLOCK" {
+			u = nseBlockURL
+		}
+		var body struct {
+			Data []map[string]any `json:"data"`
+		}
+		if err := fetchJSON(ctx, u+"?"+q.Encode(), &body); err != nil {
+			return nil, fmt.Errorf("nse: %s deals: %w", strings.ToLower(kind), err)
+		}
+		total += len(body.Data)
+		for _, row := range body.Data {
+			date, derr := parseDate(text(row["BD_DT_DATE"]))
+			sym := text(row["BD_SYMBOL"])
+			side := strings.ToUpper(text(row["BD_BUY_SELL"]))
+			if derr != nil || sym == "" || (side != "BUY" && side != "SELL") {
+				continue
+			}
+			usable++
+			out = append(out, Deal{
+				Date:   date,
+				Symbol: types.NormalizeSymbol(sym),
+				Client: text(row["BD_CLIENT_NAME"]),
+				Side:   side,
+				Qty:    int64(number(row["BD_QTY_TRD"])),
+				Price:  number(row["BD_TP_WATP"]),
+				Kind:   kind,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (n NSE) Delivery(ctx context.Context, symbol string, from, to time.Time) (out []Delivery, err error) {
+	var usable, total int
+	defer func(start time.Time) { quality.Record(n.Name(), start, err, usable, total) }(time.Now())
+
+	q := url.Values{
+		"from":     {nseDate(from)},
+		"to":       {nseDate(to)},
+		"symbol":   {symbol},
+		"dataType": {"priceVolumeDeliverable"},
+		"series":   {"EQ"},
+	}
+	var body struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := fetchJSON(ctx, nseDeliveryURL+"?"+q.Encode(), &body); err != nil {
+		return nil, fmt.Errorf("nse: %s delivery: %w", symbol, err)
+	}
+	total = len(body.Data)
+	for _, row := range body.Data {
+		date, derr := parseDate(text(row["CH_TIMESTAMP"]))
+		pct, ok := row["COP_DELIV_PERC"]
+		if derr != nil || !ok {
+			continue
+		}
+		usable++
+		out = append(out, Delivery{Date: date, Pct: number(pct)})
+	}
+	return out, nil
+}
+
+func fetchJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", api.ErrParse, err)
+	}
+	return nil
+}
+
+func nseDate(t time.Time) string {
+	return t.In(timeutil.IST).Format("02-01-2006")
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02-Jan-2006", "02-01-2006"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.IST); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// text returns a field as trimmed text; NSE reports mix strings and
+// numbers.
+func text(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return ""
+}
+
+// number returns a numeric field, which NSE sometimes sends as a string
+// with thousands separators.
+func number(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(t), ",", ""), 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,202 @@
+// Package earnings keeps a calendar of scheduled results announcements so
+// the engine can stay out of new positions in the days before one.
+package earnings
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+// Source returns upcoming results dates (midnight IST) by symbol. Name
+// identifies it in quality scores.
+type Source interface {
+	Name() string
+	Dates(ctx context.Context) (map[string][]time.Time, error)
+}
+
+// Calendar merges its sources and caches the result for ttl. It is safe
+// for concurrent use.
+type Calendar struct {
+	sources []Source
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	dates     map[string][]time.Time // Sorted
+	fetchedAt time.Time
+}
+
+func NewCalendar(ttl time.Duration, sources ...Source) *Calendar {
+	return &Calendar{sources: sources, ttl: ttl}
+}
+
+// Next returns symbol's first results date on or after the IST day of t.
+func (c *Calendar) Next(ctx context.Context, symbol string, t time.Time) (time.Time, bool) {
+	day := timeutil.Midnight(t)
+	for _, d := range c.load(ctx)[symbol] {
+		if !d.Before(day) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// load refreshes the calendar once it is older than ttl. When every
+// source fails the previous calendar is kept.
+func (c *Calendar) load(ctx context.Context) map[string][]time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dates != nil && time.Since(c.fetchedAt) < c.ttl {
+		return c.dates
+	}
+
+	merged := make(map[string][]time.Time)
+	failed := 0
+	for _, src := range quality.Rank(quality.Default(), c.sources, Source.Name) {
+		dates, err := src.Dates(ctx)
+		if err != nil {
+			logger.Warn(ctx, "Earnings calendar source failed", "error", err)
+			failed++
+			continue
+		}
+		for sym, ds := range dates {
+			merged[sym] = append(merged[sym], ds...)
+		}
+	}
+	c.fetchedAt = time.Now()
+	if failed == len(c.sources) && c.dates != nil {
+		return c.dates
+	}
+
+	for sym, ds := range merged {
+		sort.Slice(ds, func(i, j int) bool { return ds[i].Before(ds[j]) })
+		merged[sym] = ds
+	}
+	c.dates = merged
+	logger.Debug(ctx, "Earnings calendar loaded", "symbols", len(merged))
+	return merged
+}
+
+// DaysUntil counts IST calendar days from t to date; 0 is the same day.
+func DaysUntil(t, date time.Time) int {
+	return int(timeutil.Midnight(date).Sub(timeutil.Midnight(t)).Hours()+12) / 24
+}
+
+// File is a source read from a CSV with columns symbol,date (YYYY-MM-DD
+// or 28-Oct-2024), for exchanges and periods NSE does not cover.
+type File struct {
+	Path string
+}
+
+func (File) Name() string { return "earnings.file" }
+
+func (f File) Dates(ctx context.Context) (out map[string][]time.Time, err error) {
+	rows := 0
+	defer func(start time.Time) { quality.Record(f.Name(), start, err, rows, rows) }(time.Now())
+
+	fh, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	r := csv.NewReader(fh)
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	out = make(map[string][]time.Time)
+	for i, row := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "symbol") {
+			continue // Header
+		}
+		d, err := parseDate(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", f.Path, i+1, err)
+		}
+		sym := types.NormalizeSymbol(row[0])
+		out[sym] = append(out[sym], d)
+		rows++
+	}
+	return out, nil
+}
+
+const nseURL = "https://www.nseindia.com/api/event-calendar?index=equities"
+
+// NSE reads board meetings scheduled to consider financial results from
+// the NSE event calendar. Like every nseindia.com API it needs the
+// session cookies set up by api.warm_up.
+type NSE struct{}
+
+func (NSE) Name() string { return "earnings.nse" }
+
+func (n NSE) Dates(ctx context.Context) (out map[string][]time.Time, err error) {
+	var usable, total int
+	defer func(start time.Time) { quality.Record(n.Name(), start, err, usable, total) }(time.Now())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("nse: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return nil, fmt.Errorf("nse: event calendar: %w", err)
+	}
+
+	var rows []struct {
+		Symbol  string `json:"symbol"`
+		Purpose string `json:"purpose"`
+		Date    string `json:"date"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("nse: decode event calendar: %w: %w", api.ErrParse, err)
+	}
+
+	out = make(map[string][]time.Time)
+	total = len(rows)
+	for _, r := range rows {
+		d, perr := parseDate(r.Date)
+		if perr != nil || strings.TrimSpace(r.Symbol) == "" {
+			continue
+		}
+		usable++
+		if !strings.Contains(strings.ToLower(r.Purpose), "results") {
+			continue
+		}
+		sym := types.NormalizeSymbol(r.Symbol)
+		out[sym] = append(out[sym], d)
+	}
+	return out, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", "02-Jan-2006"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.IST); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
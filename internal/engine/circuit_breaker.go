@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/notify"
+)
+
+const (
+	depBroker = "broker"
+	depLLM    = "llm"
+)
+
+type circuitBreaker struct {
+	clock           interfaces.Clock
+	symbolThreshold int           // Consecutive failures before a symbol is paused
+	globalThreshold int           // Consecutive failures before all symbols are paused
+	cooldown        time.Duration // How long a tripped scope stays paused
+
+	mu             sync.Mutex
+	symbolFailures map[string]int       // Keyed by dependency + symbol
+	globalFailures map[string]int       // Keyed by dependency
+	openUntil      map[string]time.Time // Keyed by dependency (global) or dependency + symbol
+}
+
+func newCircuitBreaker(clk interfaces.Clock, symbolThreshold, globalThreshold, cooldownSeconds int) *circuitBreaker {
+	return &circuitBreaker{
+		clock:           clk,
+		symbolThreshold: symbolThreshold,
+		globalThreshold: globalThreshold,
+		cooldown:        time.Duration(cooldownSeconds) * time.Second,
+		symbolFailures:  make(map[string]int),
+		globalFailures:  make(map[string]int),
+		openUntil:       make(map[string]time.Time),
+	}
+}
+
+func symbolKey(dep, symbol string) string {
+	return dep + ":" + symbol
+}
+
+// allow reports whether calls to dep are permitted for symbol. Once a
+// cooldown expires the scope is half-open: a single further failure
+// trips it again.
+func (cb *circuitBreaker) allow(dep, symbol string) (bool, time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.clock.Now()
+
+	for _, key := range []string{dep, symbolKey(dep, symbol)} {
+		until, open := cb.openUntil[key]
+		if !open {
+			continue
+		}
+		if now.Before(until) {
+			return false, until
+		}
+
+		delete(cb.openUntil, key)
+		if key == dep {
+			cb.globalFailures[dep] = cb.globalThreshold - 1
+		} else {
+			cb.symbolFailures[key] = cb.symbolThreshold - 1
+		}
+	}
+
+	return true, time.Time{}
+}
+
+func (cb *circuitBreaker) recordSuccess(dep, symbol string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	delete(cb.symbolFailures, symbolKey(dep, symbol))
+	delete(cb.globalFailures, dep)
+}
+
+// recordFailure counts a failed call. An auth failure will not clear by
+// retrying, so it pauses the whole dependency at once and alerts the
+// operator instead of waiting for the thresholds.
+func (cb *circuitBreaker) recordFailure(ctx context.Context, dep, symbol string, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if errors.Is(err, api.ErrAuth) {
+		if until, open := cb.openUntil[dep]; !open || cb.clock.Now().After(until) {
+			cb.trip(ctx, dep, dep, "*", cb.globalFailures[dep]+1, err)
+			notify.Send(ctx, notify.EventAuth, symbol, fmt.Sprintf("%s rejected the bot's credentials, paused for %s: %v", dep, cb.cooldown, err))
+		}
+		cb.globalFailures[dep] = 0
+		return
+	}
+	key := symbolKey(dep, symbol)
+	cb.symbolFailures[key]++
+	cb.globalFailures[dep]++
+
+	if cb.symbolThreshold > 0 && cb.symbolFailures[key] >= cb.symbolThreshold {
+		cb.trip(ctx, key, dep, symbol, cb.symbolFailures[key], err)
+		cb.symbolFailures[key] = 0
+	}
+
+	if cb.globalThreshold > 0 && cb.globalFailures[dep] >= cb.globalThreshold {
+		cb.trip(ctx, dep, dep, "*", cb.globalFailures[dep], err)
+		cb.globalFailures[dep] = 0
+	}
+}
+
+func (cb *circuitBreaker) trip(ctx context.Context, key, dep, scope string, failures int, err error) {
+	until := cb.clock.Now().Add(cb.cooldown)
+	cb.openUntil[key] = until
+
+	logger.ErrorWithErr(ctx, "Circuit breaker opened - pausing trading", err,
+		"event", "CIRCUIT_OPEN",
+		"dependency", dep,
+		"scope", scope,
+		"consecutive_failures", failures,
+		"cooldown_seconds", cb.cooldown.Seconds(),
+		"paused_until", until,
+	)
+	// Credential failures are alerted as auth by the caller
+	if errors.Is(err, api.ErrAuth) {
+		return
+	}
+	symbol, paused := scope, "this symbol"
+	if scope == "*" {
+		symbol, paused = "", "all symbols"
+	}
+	notify.Send(ctx, notify.EventCircuit, symbol, fmt.Sprintf("%s failed %d times in a row, %s paused for %s: %v",
+		dep, failures, paused, cb.cooldown, err))
+}
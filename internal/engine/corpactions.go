@@ -0,0 +1,26 @@
+package engine
+
+import (
+	"time"
+
+	"llm-trading-bot/internal/corpactions"
+	"llm-trading-bot/internal/store"
+)
+
+// NewCorporateActions builds the adjuster from corporate_actions.sources;
+// nil when none are configured.
+func NewCorporateActions(cfg *store.Config) *corpactions.Adjuster {
+	if len(cfg.CorporateActions.Sources) == 0 {
+		return nil
+	}
+	var sources []corpactions.Source
+	for _, name := range cfg.CorporateActions.Sources {
+		switch name {
+		case "file":
+			sources = append(sources, corpactions.File{Path: cfg.CorporateActions.File})
+		case "nse":
+			sources = append(sources, corpactions.NSE{})
+		}
+	}
+	return corpactions.NewAdjuster(time.Duration(cfg.CorporateActions.RefreshHours)*time.Hour, cfg.CorporateActions.Dividends, sources...)
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"llm-trading-bot/internal/deals"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// NewDeals builds the bulk/block deal and delivery monitor; nil unless
+// deals.enabled is set.
+func NewDeals(cfg *store.Config) *deals.Monitor {
+	if !cfg.Deals.Enabled {
+		return nil
+	}
+	return deals.NewMonitor(time.Duration(cfg.Deals.RefreshHours)*time.Hour, deals.Params{
+		LookbackDays:   cfg.Deals.LookbackDays,
+		Promoters:      cfg.Deals.Promoters,
+		DeliveryDays:   cfg.Deals.DeliveryDays,
+		MinDeliveryPct: cfg.Deals.MinDeliveryPct,
+	}, deals.NSE{})
+}
+
+// dealFlags returns the deal and delivery patterns symbol shows as of the
+// bar time ts.
+func (e *Engine) dealFlags(ctx context.Context, symbol string, ts int64) []deals.Flag {
+	if e.deals == nil {
+		return nil
+	}
+	return e.deals.Flags(ctx, symbol, timeutil.FromUnix(ts))
+}
+
+// dealsBlock returns the first flag of symbol for a pattern in
+// deals.block.
+func (e *Engine) dealsBlock(ctx context.Context, symbol string, ts int64) (deals.Flag, bool) {
+	for _, f := range e.dealFlags(ctx, symbol, ts) {
+		for _, p := range e.cfg.Deals.Block {
+			if f.Pattern == p {
+				return f, true
+			}
+		}
+	}
+	return deals.Flag{}, false
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"llm-trading-bot/internal/earnings"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// NewEarningsCalendar builds the calendar from earnings.sources; nil when
+// none are configured.
+func NewEarningsCalendar(cfg *store.Config) *earnings.Calendar {
+	if len(cfg.Earnings.Sources) == 0 {
+		return nil
+	}
+	var sources []earnings.Source
+	for _, name := range cfg.Earnings.Sources {
+		switch name {
+		case "file":
+			sources = append(sources, earnings.File{Path: cfg.Earnings.File})
+		case "nse":
+			sources = append(sources, earnings.NSE{})
+		}
+	}
+	return earnings.NewCalendar(time.Duration(cfg.Earnings.RefreshHours)*time.Hour, sources...)
+}
+
+// earningsAhead returns symbol's next results date when it falls within
+// earnings.blackout_days of the bar time ts.
+func (e *Engine) earningsAhead(ctx context.Context, symbol string, ts int64) (time.Time, bool) {
+	if e.earnings == nil {
+		return time.Time{}, false
+	}
+	now := timeutil.FromUnix(ts)
+	date, ok := e.earnings.Next(ctx, symbol, now)
+	if !ok || earnings.DaysUntil(now, date) > e.cfg.Earnings.BlackoutDays {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// tightenForEarnings raises the stop of an open position to
+// earnings.tighten_stop_pct below price while results are due. Stops
+// only move up, so a tighter stop already in place is kept.
+func (e *Engine) tightenForEarnings(ctx context.Context, symbol string, price float64, ts int64) {
+	if e.cfg.Earnings.TightenStopPct <= 0 {
+		return
+	}
+	pos := e.positions.get(symbol)
+	if pos == nil || pos.qty <= 0 {
+		return
+	}
+	date, ok := e.earningsAhead(ctx, symbol, ts)
+	if !ok {
+		return
+	}
+
+	stop := roundToTick(price*(1-e.cfg.Earnings.TightenStopPct/100), e.cfg.Stop.MinTick)
+	if e.positions.updateTrailingStop(ctx, symbol, stop, pos.lastATR) {
+		logger.Info(ctx, "Stop tightened ahead of results", "symbol", symbol, "results", date.Format("2006-01-02"), "stop", stop)
+		e.stops.sync(ctx, symbol, pos, price)
+	}
+}
@@ -3,88 +3,243 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"llm-trading-bot/internal/broker/benchmark"
+	"llm-trading-bot/internal/broker/options"
+	"llm-trading-bot/internal/corpactions"
+	"llm-trading-bot/internal/deals"
+	"llm-trading-bot/internal/earnings"
 	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/killswitch"
+	"llm-trading-bot/internal/lifecycle"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/marketdata"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/notify"
 	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/surveillance"
+	"llm-trading-bot/internal/symbols"
+	"llm-trading-bot/internal/ta/patterns"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
 	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/warehouse"
 )
 
 type Engine struct {
 	cfg      *store.Config
 	broker   interfaces.Broker
+	candles  interfaces.CandleSource // The broker unless data_source is FILE
 	llm      interfaces.Decider
+	clock    interfaces.Clock
 	dayStart time.Time
 
-	positions *positionManager
-	risk      *riskManager
-	stop      *stopManager
-	executor  *orderExecutor
+	positions  *positionManager
+	risk       *riskManager
+	stop       *stopManager
+	stops      *serverStops
+	executor   *orderExecutor
+	trigger    *tickTrigger
+	breaker    *circuitBreaker
+	indicators *indicatorCache
+	benchmark  *benchmark.Feed
+	actions    *corpactions.Adjuster
+	earnings   *earnings.Calendar
+	measures   *surveillance.Monitor
+	deals      *deals.Monitor
+	options    *options.Feed
+	lifecycle  *lifecycle.Tracker
+	budget     *prioritizer
+	halts      *haltMonitor
+	sanity     *marketdata.Sanitizer
+
+	locks   symbolLocks // Serializes steps of the same symbol
+	entryMu sync.Mutex  // Held from the funds check to the recorded fill of an entry
 }
 
-func newEngine(cfg *store.Config, brk interfaces.Broker, d interfaces.Decider) *Engine {
+func newEngine(cfg *store.Config, brk interfaces.Broker, d interfaces.Decider, clk interfaces.Clock) *Engine {
+	var bench *benchmark.Feed
+	if cfg.Benchmark.Symbol != "" {
+		bench = benchmark.New(brk, cfg.Benchmark.Symbol, cfg.Benchmark.Sectors)
+	}
+	var optionsFeed *options.Feed
+	if cfg.Options.Enabled {
+		optionsFeed = options.NewFeed(time.Duration(cfg.Options.CacheSeconds) * time.Second)
+	}
+	var candles interfaces.CandleSource = brk
+	if cfg.DataSource == "FILE" {
+		candles = marketdata.NewFile(cfg.Candles.FileDir)
+	}
+
 	return &Engine{
 		cfg:      cfg,
 		broker:   brk,
+		candles:  candles,
 		llm:      d,
-		dayStart: midnightIST(),
+		clock:    clk,
+		dayStart: timeutil.Midnight(clk.Now()),
 
-		positions: newPositionManager(),
+		positions: newPositionManager(clk),
 		risk:      newRiskManager(),
 		stop: newStopManager(
+			clk,
 			cfg.Stop.Mode,
 			cfg.Stop.Pct,
 			cfg.Stop.ATRMult,
 			cfg.Stop.MinTick,
 			cfg.Stop.Trailing,
 		),
-		executor: newOrderExecutor(brk),
+		stops:    newServerStops(brk, cfg.Stop.ServerSide, cfg.Stop.ServerLimitPct, cfg.Stop.MinTick),
+		executor: newOrderExecutor(brk, cfg.Account, cfg.Execution.Style, cfg.Execution.MaxCrossSpreadBps, cfg.Stop.MinTick, openOrderLimits(cfg, clk), tradelog.NewWriter(clk)),
+		trigger:  newTickTrigger(clk, cfg.Event.StopProximityPct, cfg.Event.MinIntervalSeconds),
+		breaker: newCircuitBreaker(
+			clk,
+			cfg.CircuitBreaker.SymbolFailures,
+			cfg.CircuitBreaker.GlobalFailures,
+			cfg.CircuitBreaker.CooldownSeconds,
+		),
+		indicators: newIndicatorCache(indicatorParams{
+			SMAWindows: cfg.Indicators.SMAWindows,
+			RSIPeriod:  cfg.Indicators.RSIPeriod,
+			BBWindow:   cfg.Indicators.BBWindow,
+			BBStdDev:   cfg.Indicators.BBStdDev,
+			ATRPeriod:  cfg.Indicators.ATRPeriod,
+
+			SupertrendPeriod: cfg.Indicators.SupertrendPeriod,
+			SupertrendMult:   cfg.Indicators.SupertrendMult,
+
+			IchimokuTenkan:       cfg.Indicators.IchimokuTenkan,
+			IchimokuKijun:        cfg.Indicators.IchimokuKijun,
+			IchimokuSenkou:       cfg.Indicators.IchimokuSenkou,
+			IchimokuDisplacement: cfg.Indicators.IchimokuDisplacement,
+
+			KeltnerPeriod:    cfg.Indicators.KeltnerPeriod,
+			KeltnerATRPeriod: cfg.Indicators.KeltnerATRPeriod,
+			KeltnerMult:      cfg.Indicators.KeltnerMult,
+		}, cfg.Indicators.Recompute),
+		benchmark: bench,
+		actions:   NewCorporateActions(cfg),
+		earnings:  NewEarningsCalendar(cfg),
+		measures:  NewSurveillance(cfg),
+		deals:     NewDeals(cfg),
+		options:   optionsFeed,
+		lifecycle: openLifecycle(cfg, clk),
+		budget:    newPrioritizer(cfg.LLM.MaxCallsPerTick),
+		halts:     newHaltMonitor(cfg.Halt.FrozenBars, cfg.Halt.StaleMinutes),
+		sanity:    marketdata.NewSanitizer(cfg.DataFilter.MaxJumpPct),
 	}
 }
 
-func New(cfg *store.Config, brk interfaces.Broker, d interfaces.Decider) interfaces.Engine {
-	return newEngine(cfg, brk, d)
+func New(cfg *store.Config, brk interfaces.Broker, d interfaces.Decider, clk interfaces.Clock) interfaces.Engine {
+	return newEngine(cfg, brk, d, clk)
 }
 
+// Step evaluates one symbol. Steps of different symbols may run
+// concurrently; steps of the same symbol run one at a time.
 func (e *Engine) Step(ctx context.Context, symbol string) (*types.StepResult, error) {
+	defer e.locks.lock(symbol)()
+
+	if ok, until := e.breaker.allow(depBroker, symbol); !ok {
+		return e.pausedResult(symbol, depBroker, until), nil
+	}
 
 	candles, err := e.fetchCandles(ctx, symbol)
 	if err != nil {
+		e.breaker.recordFailure(ctx, depBroker, symbol, err)
 		return nil, err
 	}
+	e.breaker.recordSuccess(depBroker, symbol)
 
-	indicators := calculateIndicators(candles, struct {
-		SMAWindows []int
-		RSIPeriod  int
-		BBWindow   int
-		BBStdDev   float64
-		ATRPeriod  int
-	}{
-		SMAWindows: e.cfg.Indicators.SMAWindows,
-		RSIPeriod:  e.cfg.Indicators.RSIPeriod,
-		BBWindow:   e.cfg.Indicators.BBWindow,
-		BBStdDev:   e.cfg.Indicators.BBStdDev,
-		ATRPeriod:  e.cfg.Indicators.ATRPeriod,
-	})
+	indicators := e.indicators.compute(symbol, candles)
+	indicators.RS = e.relativeStrength(ctx, symbol, candles)
 
 	e.logIndicators(ctx, symbol, indicators)
 
 	latest := candles[len(candles)-1]
 	price := latest.Close
 
-	if result := e.handleStopLoss(ctx, symbol, price, latest.Ts); result != nil {
+	e.trigger.updateLevels(symbol, indicators, price)
+	e.budget.observe(symbol, indicators, price)
+	e.applyFills(ctx, symbol, price, indicators.ATR, latest.Ts)
+
+	// Nobody can trade at a halted symbol's price: no stop-loss orders, no
+	// decisions until it moves again
+	if result := e.checkHalt(ctx, symbol, candles); result != nil {
+		return result, nil
+	}
+	if result := e.handleStopLoss(ctx, symbol, price, latest.Ts, indicators); result != nil {
 		return result, nil
 	}
+	e.tightenForEarnings(ctx, symbol, price, latest.Ts)
+
+	// Only WATCHING and ENTERED symbols are sent to the decider
+	switch st := e.lifecycle.Get(symbol, timeutil.FromUnix(latest.Ts)); st.Status {
+	case lifecycle.Blocked, lifecycle.Cooldown, lifecycle.Exiting, lifecycle.Screened:
+		return &types.StepResult{
+			Symbol: symbol,
+			Price:  price,
+			Time:   latest.Ts,
+			Reason: statusReason(st),
+		}, nil
+	}
 
-	decision, err := e.llm.Decide(ctx, symbol, latest, indicators, map[string]any{
+	if ok, until := e.breaker.allow(depLLM, symbol); !ok {
+		return e.pausedResult(symbol, depLLM, until), nil
+	}
+	if !e.budget.allow(symbol) {
+		return &types.StepResult{
+			Symbol: symbol,
+			Price:  price,
+			Time:   latest.Ts,
+			Reason: fmt.Sprintf("DECIDER_BUDGET: not among the %d symbols decided this poll", e.budget.maxPerTick),
+		}, nil
+	}
+
+	contextData := map[string]any{
 		"price": price,
 		"risk":  e.cfg.Risk,
-	})
+	}
+	if info, ok := symbols.Get(symbol); ok {
+		company := map[string]any{"name": info.Name}
+		if info.Industry != "" {
+			company["industry"] = info.Industry
+		}
+		contextData["company"] = company
+	}
+	if ls := e.surveillanceListings(ctx, symbol); len(ls) > 0 {
+		names := make([]string, len(ls))
+		for i, l := range ls {
+			names[i] = l.String()
+		}
+		contextData["surveillance"] = names
+	}
+	if fs := e.dealFlags(ctx, symbol, latest.Ts); len(fs) > 0 {
+		names := make([]string, len(fs))
+		for i, f := range fs {
+			names[i] = f.String()
+		}
+		contextData["deals"] = names
+	}
+	if pats := patterns.Detect(candles, e.cfg.Indicators.PatternLookback); len(pats) > 0 {
+		contextData["patterns"] = patterns.Names(pats)
+	}
+	if sum, ok := e.optionSummary(ctx, symbol, latest.Ts); ok {
+		contextData["options"] = sum
+	}
+
+	decision, err := e.llm.Decide(ctx, symbol, latest, indicators, contextData)
 	if err != nil {
 		logger.ErrorWithErr(ctx, "LLM decision failed", err, "symbol", symbol)
+		e.breaker.recordFailure(ctx, depLLM, symbol, err)
 		return nil, err
 	}
+	e.breaker.recordSuccess(depLLM, symbol)
+	e.budget.decided(symbol, e.clock.Now())
 
 	e.executor.logDecision(ctx, symbol, decision, price, indicators)
 
@@ -98,12 +253,15 @@ func (e *Engine) Step(ctx context.Context, symbol string) (*types.StepResult, er
 		DefaultSell: e.cfg.Qty.DefaultSell,
 	})
 
-
-	orders, reason := e.executeDecision(ctx, symbol, decision, qty, price, indicators.ATR)
+	orders, reason := e.executeDecision(ctx, symbol, decision, qty, price, latest.Ts, orderContext{
+		reason:     decision.Reason,
+		confidence: decision.Confidence,
+		indicators: indicators,
+		signals:    signalSnapshot(contextData),
+	})
 
 	e.updateTrailingStop(ctx, symbol, price, indicators.ATR)
 
-
 	return &types.StepResult{
 		Symbol:   symbol,
 		Decision: decision,
@@ -114,13 +272,57 @@ func (e *Engine) Step(ctx context.Context, symbol string) (*types.StepResult, er
 	}, nil
 }
 
+func (e *Engine) ShouldEvaluate(ctx context.Context, symbol string, price float64) bool {
+	// Without a position there is no stop to check, and these skip the decider
+	if !e.positions.has(symbol) {
+		switch e.lifecycle.Status(symbol, e.clock.Now()) {
+		case lifecycle.Blocked, lifecycle.Cooldown, lifecycle.Screened:
+			return false
+		}
+	}
+
+	ok, trigger := e.trigger.shouldEvaluate(symbol, price, e.positions.get(symbol))
+	if ok {
+		e.budget.grant(symbol)
+		logger.Debug(ctx, "Tick triggered evaluation",
+			"symbol", symbol,
+			"price", price,
+			"trigger", trigger,
+		)
+	}
+	return ok
+}
+
+// PlanTick ranks symbols by signal interest before a poll and lets only
+// the top llm.max_calls_per_tick of them call the decider during it.
+// Without a budget it does nothing.
+func (e *Engine) PlanTick(ctx context.Context, symbols []string) {
+	selected := e.budget.plan(symbols, e.positions.has)
+	if len(selected) == len(symbols) {
+		return
+	}
+	logger.Debug(ctx, "Decider budget planned",
+		"symbols", len(symbols),
+		"budget", e.budget.maxPerTick,
+		"selected", selected,
+	)
+}
+
 func (e *Engine) fetchCandles(ctx context.Context, symbol string) ([]types.Candle, error) {
-	candles, err := e.broker.RecentCandles(ctx, symbol, 250)
+	candles, err := e.candles.RecentCandles(ctx, symbol, 250)
 	if err != nil {
 		logger.ErrorWithErr(ctx, "Failed to fetch candles", err, "symbol", symbol)
 		return nil, err
 	}
+	if err := warehouse.Put(symbol, candles); err != nil {
+		logger.Warn(ctx, "Failed to store candles", "symbol", symbol, "error", err)
+	}
 
+	// Adjust first, so a split is not mistaken for a price jump
+	if e.actions != nil {
+		candles = e.actions.Adjust(ctx, symbol, candles)
+	}
+	candles = e.sanity.Candles(ctx, symbol, candles)
 
 	if len(candles) < 50 {
 		err := errors.New("not enough candles")
@@ -134,7 +336,7 @@ func (e *Engine) fetchCandles(ctx context.Context, symbol string) ([]types.Candl
 func (e *Engine) logIndicators(ctx context.Context, symbol string, inds types.Indicators) {
 }
 
-func (e *Engine) handleStopLoss(ctx context.Context, symbol string, price float64, timestamp int64) *types.StepResult {
+func (e *Engine) handleStopLoss(ctx context.Context, symbol string, price float64, timestamp int64, indicators types.Indicators) *types.StepResult {
 	pos := e.positions.get(symbol)
 	if pos == nil || pos.qty <= 0 {
 		return nil
@@ -143,14 +345,52 @@ func (e *Engine) handleStopLoss(ctx context.Context, symbol string, price float6
 	if !e.stop.checkStopLoss(ctx, symbol, price, pos.stop, pos) {
 		return nil
 	}
+	// An exit already working covers the shares
+	if e.executor.working(symbol, "SELL") {
+		return nil
+	}
+	e.cancelBuys(ctx, symbol, price, timestamp)
+
+	triggered, err := e.stops.cancel(ctx, symbol, pos)
+	if err != nil {
+		// Selling now could fill alongside the live broker stop; retry next step
+		e.breaker.recordFailure(ctx, depBroker, symbol, err)
+		return &types.StepResult{
+			Symbol: symbol,
+			Price:  price,
+			Time:   timestamp,
+			Reason: "STOP_LOSS_DEFERRED: " + err.Error(),
+		}
+	}
+	if triggered {
+		e.closePosition(ctx, symbol)
+		e.advance(ctx, symbol, lifecycle.Cooldown, "server-side stop triggered", timestamp)
+		notify.Send(ctx, notify.EventStop, symbol, fmt.Sprintf("server-side stop filled near %.2f", pos.stop))
+		return &types.StepResult{
+			Symbol: symbol,
+			Price:  price,
+			Time:   timestamp,
+			Reason: "SERVER_STOP_TRIGGERED",
+		}
+	}
 
-	resp, err := e.executor.placeSellOrder(ctx, symbol, pos.qty, price, "STOP_LOSS", 1.0, "SL")
+	e.advance(ctx, symbol, lifecycle.Exiting, "stop-loss", timestamp)
+	resp, f, err := e.executor.placeSellOrder(ctx, symbol, pos.qty, money.FromFloat(price), orderContext{
+		reason:     "STOP_LOSS",
+		confidence: 1.0,
+		indicators: indicators,
+		extra:      map[string]any{"stop_price": pos.stop},
+	}, "SL")
 	if err != nil {
 		logger.ErrorWithErr(ctx, "Failed to execute stop-loss order", err, "symbol", symbol, "qty", pos.qty, "price", price)
+		e.stops.sync(ctx, symbol, pos, price)
+		e.advance(ctx, symbol, lifecycle.Entered, "stop-loss order failed", timestamp)
 		return nil
 	}
 
-	e.positions.close(symbol)
+	qty := pos.qty
+	e.sold(ctx, symbol, pos, f.qty, f.price.Float(), timestamp, "stop-loss")
+	notify.Send(ctx, notify.EventStop, symbol, fmt.Sprintf("stop %.2f hit at %.2f, sold %d of %d", pos.stop, price, f.qty, qty))
 
 	return &types.StepResult{
 		Symbol: symbol,
@@ -161,7 +401,8 @@ func (e *Engine) handleStopLoss(ctx context.Context, symbol string, price float6
 	}
 }
 
-func (e *Engine) executeDecision(ctx context.Context, symbol string, decision types.Decision, qty int, price, atr float64) ([]types.OrderResp, string) {
+func (e *Engine) executeDecision(ctx context.Context, symbol string, decision types.Decision, qty int, price float64, ts int64, oc orderContext) ([]types.OrderResp, string) {
+	atr := oc.indicators.ATR
 	orders := []types.OrderResp{}
 	reason := decision.Reason
 
@@ -170,7 +411,48 @@ func (e *Engine) executeDecision(ctx context.Context, symbol string, decision ty
 		if qty <= 0 {
 			return orders, reason
 		}
+		if floor := e.cfg.LLM.MinConfidenceBuy; decision.Confidence < floor {
+			reason += fmt.Sprintf(" | blocked: confidence %.2f below %.2f", decision.Confidence, floor)
+			return orders, reason
+		}
 
+		if e.executor.working(symbol, "BUY") {
+			reason += " | skipped: buy order still working"
+			return orders, reason
+		}
+		if date, ok := e.earningsAhead(ctx, symbol, ts); ok {
+			reason += " | blocked: results on " + date.Format("2006-01-02")
+			return orders, reason
+		}
+		if l, ok := e.surveillanceBlock(ctx, symbol); ok {
+			reason += " | blocked: under " + l.String()
+			return orders, reason
+		}
+		if f, ok := e.dealsBlock(ctx, symbol, ts); ok {
+			reason += " | blocked: " + f.String()
+			return orders, reason
+		}
+		if iv, high := e.ivTooHigh(ctx, symbol, ts); high {
+			reason += e.ivReason(iv)
+			return orders, reason
+		}
+
+		// Concurrent entries must not both spend the same available cash
+		e.entryMu.Lock()
+		defer e.entryMu.Unlock()
+
+		funds, err := e.broker.Funds(ctx)
+		if err != nil {
+			e.breaker.recordFailure(ctx, depBroker, symbol, err)
+			reason += " | blocked: funds unavailable"
+			return orders, reason
+		}
+		e.risk.updateFunds(funds)
+
+		if !e.risk.checkFunds(ctx, symbol, price, qty) {
+			reason += " | blocked: insufficient funds"
+			return orders, reason
+		}
 
 		riskExceeded, _ := e.risk.validateTrade(ctx, symbol, price, qty, e.cfg.Risk.PerTradeRiskPct)
 		if riskExceeded {
@@ -178,33 +460,103 @@ func (e *Engine) executeDecision(ctx context.Context, symbol string, decision ty
 			return orders, reason
 		}
 
-		resp, err := e.executor.placeBuyOrder(ctx, symbol, qty, price, decision.Reason, decision.Confidence)
+		oc.extra = map[string]any{
+			"margin_utilization_pct": e.risk.marginUtilization(),
+			"funds_available":        funds.Available,
+		}
+		resp, f, err := e.executor.placeBuyOrder(ctx, symbol, qty, money.FromFloat(price), oc)
+		if refused(err) {
+			reason += " | blocked: " + err.Error()
+			return orders, reason
+		}
 		if err != nil {
+			e.breaker.recordFailure(ctx, depBroker, symbol, err)
 			reason += " | order_err:" + err.Error()
 			return orders, reason
 		}
 
 		orders = append(orders, resp)
 
-		stopPrice := e.stop.calculateStopPrice(price, atr)
+		if f.qty < qty {
+			reason += fmt.Sprintf(" | filled %d of %d", f.qty, qty)
+		}
+		if f.qty == 0 {
+			return orders, reason
+		}
+		stopPrice := e.stop.calculateStopPrice(f.price.Float(), atr)
 
-		e.positions.addBuy(ctx, symbol, qty, price, atr, stopPrice)
+		e.positions.addBuy(ctx, symbol, f.qty, f.price, atr, stopPrice)
+		e.stops.sync(ctx, symbol, e.positions.get(symbol), price)
+		e.advance(ctx, symbol, lifecycle.Entered, "buy filled", ts)
+		e.hedge(ctx, symbol, price, ts)
 
 	case "SELL":
 		if qty <= 0 {
 			return orders, reason
 		}
+		if floor := e.cfg.LLM.MinConfidenceSell; decision.Confidence < floor {
+			reason += fmt.Sprintf(" | blocked: confidence %.2f below %.2f", decision.Confidence, floor)
+			return orders, reason
+		}
+
+		pos := e.positions.get(symbol)
+		if pos == nil || pos.qty <= 0 {
+			reason += " | skipped: no position"
+			return orders, reason
+		}
+		if qty > pos.qty {
+			qty = pos.qty
+		}
+		if e.executor.working(symbol, "SELL") {
+			reason += " | skipped: sell order still working"
+			return orders, reason
+		}
 
+		// Refuse before the broker stop is cancelled, not after
+		if err := killswitch.Check(symbol, false); err != nil {
+			reason += " | blocked: " + err.Error()
+			return orders, reason
+		}
 
-		resp, err := e.executor.placeSellOrder(ctx, symbol, qty, price, decision.Reason, decision.Confidence, "LLM")
+		// Release the shares held by the broker stop before selling them
+		triggered, err := e.stops.cancel(ctx, symbol, pos)
 		if err != nil {
+			e.breaker.recordFailure(ctx, depBroker, symbol, err)
+			reason += " | blocked: " + err.Error()
+			return orders, reason
+		}
+		if triggered {
+			e.closePosition(ctx, symbol)
+			e.advance(ctx, symbol, lifecycle.Cooldown, "server-side stop triggered", ts)
+			reason += " | skipped: server-side stop already triggered"
+			return orders, reason
+		}
+
+		exit := qty == pos.qty
+		if exit {
+			e.cancelBuys(ctx, symbol, price, ts)
+			qty = pos.qty
+			e.advance(ctx, symbol, lifecycle.Exiting, "sell decision", ts)
+		}
+		resp, f, err := e.executor.placeSellOrder(ctx, symbol, qty, money.FromFloat(price), oc, "LLM")
+		if err != nil {
+			if !refused(err) {
+				e.breaker.recordFailure(ctx, depBroker, symbol, err)
+			}
+			e.stops.sync(ctx, symbol, pos, price)
+			if exit {
+				e.advance(ctx, symbol, lifecycle.Entered, "sell order failed", ts)
+			}
 			reason += " | order_err:" + err.Error()
 			return orders, reason
 		}
 
 		orders = append(orders, resp)
 
-		e.positions.reduceSell(ctx, symbol, qty, price)
+		if f.qty < qty {
+			reason += fmt.Sprintf(" | filled %d of %d", f.qty, qty)
+		}
+		e.sold(ctx, symbol, pos, f.qty, f.price.Float(), ts, "sold")
 
 	case "HOLD":
 	}
@@ -223,5 +575,136 @@ func (e *Engine) updateTrailingStop(ctx context.Context, symbol string, price, a
 	}
 
 	newStop := e.stop.calculateStopPrice(price, atr)
-	e.positions.updateTrailingStop(ctx, symbol, newStop, atr)
+	if e.positions.updateTrailingStop(ctx, symbol, newStop, atr) {
+		e.stops.sync(ctx, symbol, pos, price)
+	}
+}
+
+func (e *Engine) Positions() []types.Position {
+	syms := e.positions.symbols()
+	out := make([]types.Position, 0, len(syms))
+	for _, sym := range syms {
+		if p := e.positions.get(sym); p != nil {
+			pos := types.Position{Account: e.cfg.Account, Symbol: sym, Qty: p.qty, Avg: p.avg().Float(), Stop: p.stop}
+			if h, ok := e.halts.get(sym); ok {
+				pos.Halted = h.reason
+			}
+			out = append(out, pos)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+// Flatten sells each open position at market, releasing its broker-held
+// stop first so the shares are not sold twice.
+func (e *Engine) Flatten(ctx context.Context, reason string) ([]types.OrderResp, error) {
+	orders := []types.OrderResp{}
+	var failed []string
+
+	for _, p := range e.Positions() {
+		pos := e.positions.get(p.Symbol)
+		now := e.clock.Now().Unix()
+		if e.executor.working(p.Symbol, "SELL") {
+			continue // An exit is already working on the shares
+		}
+		triggered, err := e.stops.cancel(ctx, p.Symbol, pos)
+		if err != nil {
+			failed = append(failed, p.Symbol)
+			continue
+		}
+		if triggered {
+			e.closePosition(ctx, p.Symbol)
+			e.advance(ctx, p.Symbol, lifecycle.Cooldown, "server-side stop triggered", now)
+			continue
+		}
+
+		price, err := e.broker.LTP(ctx, p.Symbol)
+		if err != nil {
+			price = pos.avg().Float()
+		}
+		e.cancelBuys(ctx, p.Symbol, price, now)
+		if !e.positions.has(p.Symbol) {
+			continue
+		}
+		e.advance(ctx, p.Symbol, lifecycle.Exiting, reason, now)
+		resp, f, err := e.executor.placeSellOrder(ctx, p.Symbol, pos.qty, money.FromFloat(price), orderContext{
+			reason:     reason,
+			confidence: 1.0,
+		}, "FLATTEN")
+		if err != nil {
+			e.stops.sync(ctx, p.Symbol, pos, price)
+			e.advance(ctx, p.Symbol, lifecycle.Entered, "flatten order failed", now)
+			failed = append(failed, p.Symbol)
+			continue
+		}
+
+		orders = append(orders, resp)
+		e.sold(ctx, p.Symbol, pos, f.qty, f.price.Float(), now, reason)
+	}
+
+	if len(failed) > 0 {
+		return orders, fmt.Errorf("failed to flatten %s", strings.Join(failed, ", "))
+	}
+	return orders, nil
+}
+
+// CancelOpenOrders cancels the orders this engine placed that are still
+// working at the broker, such as resting SPREAD-style limits.
+func (e *Engine) CancelOpenOrders(ctx context.Context) (int, error) {
+	return e.executor.cancelOpen(ctx)
+}
+
+// Protect covers each open position with a broker-held stop at its current
+// stop level, even when stop.server_side is off, so it stays protected
+// after the bot exits.
+func (e *Engine) Protect(ctx context.Context) error {
+	positions := e.Positions()
+	if len(positions) == 0 {
+		return nil
+	}
+	ss := e.stops
+	if ss == nil {
+		ss = newServerStops(e.broker, true, e.cfg.Stop.ServerLimitPct, e.cfg.Stop.MinTick)
+	}
+	if ss == nil {
+		return interfaces.ErrStopsUnsupported
+	}
+
+	var failed []string
+	for _, p := range positions {
+		pos := e.positions.get(p.Symbol)
+		price, err := e.broker.LTP(ctx, p.Symbol)
+		if err != nil {
+			price = pos.avg().Float()
+		}
+		ss.sync(ctx, p.Symbol, pos, price)
+		if pos.stopID == "" {
+			failed = append(failed, p.Symbol)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("no broker stop for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// statusReason explains why a symbol in st was not sent to the decider.
+func statusReason(st lifecycle.State) string {
+	r := st.Status
+	if st.Status == lifecycle.Cooldown {
+		r += " until " + st.Until.Format(time.RFC3339)
+	}
+	if st.Reason != "" {
+		r += ": " + st.Reason
+	}
+	return r
+}
+
+func (e *Engine) pausedResult(symbol, dep string, until time.Time) *types.StepResult {
+	return &types.StepResult{
+		Symbol: symbol,
+		Time:   e.clock.Now().Unix(),
+		Reason: "CIRCUIT_OPEN: " + dep + " paused until " + until.Format(time.RFC3339),
+	}
 }
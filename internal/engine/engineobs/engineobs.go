@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/lifecycle"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
 	"llm-trading-bot/internal/trace"
 	"llm-trading-bot/internal/types"
 )
@@ -34,6 +36,7 @@ func (oe *observableEngine) Step(ctx context.Context, symbol string) (*types.Ste
 
 	result, err := oe.engine.Step(ctx, symbol)
 	if err != nil {
+		metrics.StepSeconds.Observe(time.Since(start).Seconds(), "error")
 		logger.ErrorWithErrSkip(ctx, 1, "Trading cycle failed", err,
 			"symbol", symbol,
 			"duration_ms", time.Since(start).Milliseconds(),
@@ -41,6 +44,8 @@ func (oe *observableEngine) Step(ctx context.Context, symbol string) (*types.Ste
 		return nil, err
 	}
 
+	metrics.StepSeconds.Observe(time.Since(start).Seconds(), "ok")
+
 	logger.InfoSkip(ctx, 1, "Trading cycle completed",
 		"symbol", symbol,
 		"action", result.Decision.Action,
@@ -51,3 +56,69 @@ func (oe *observableEngine) Step(ctx context.Context, symbol string) (*types.Ste
 
 	return result, nil
 }
+
+func (oe *observableEngine) ShouldEvaluate(ctx context.Context, symbol string, price float64) bool {
+	return oe.engine.ShouldEvaluate(ctx, symbol, price)
+}
+
+func (oe *observableEngine) PlanTick(ctx context.Context, symbols []string) {
+	oe.engine.PlanTick(ctx, symbols)
+}
+
+func (oe *observableEngine) Positions() []types.Position {
+	return oe.engine.Positions()
+}
+
+func (oe *observableEngine) Flatten(ctx context.Context, reason string) ([]types.OrderResp, error) {
+	ctx, span := trace.StartSpan(ctx, "engine.Flatten")
+	defer span.End()
+
+	positions := oe.engine.Positions()
+	logger.InfoSkip(ctx, 1, "Flattening positions", "count", len(positions), "reason", reason)
+
+	orders, err := oe.engine.Flatten(ctx, reason)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Flatten incomplete", err, "orders", len(orders))
+		return orders, err
+	}
+
+	logger.InfoSkip(ctx, 1, "Positions flattened", "orders", len(orders))
+	return orders, nil
+}
+
+func (oe *observableEngine) CancelOpenOrders(ctx context.Context) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "engine.CancelOpenOrders")
+	defer span.End()
+
+	n, err := oe.engine.CancelOpenOrders(ctx)
+	if err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Open orders not all cancelled", err, "cancelled", n)
+		return n, err
+	}
+	logger.InfoSkip(ctx, 1, "Open orders cancelled", "cancelled", n)
+	return n, nil
+}
+
+func (oe *observableEngine) Protect(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "engine.Protect")
+	defer span.End()
+
+	if err := oe.engine.Protect(ctx); err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Positions not all protected", err)
+		return err
+	}
+	logger.InfoSkip(ctx, 1, "Positions protected by broker stops", "count", len(oe.engine.Positions()))
+	return nil
+}
+
+func (oe *observableEngine) SymbolStates() []lifecycle.State {
+	return oe.engine.SymbolStates()
+}
+
+func (oe *observableEngine) SetBlocked(ctx context.Context, symbol string, blocked bool, reason string) error {
+	if err := oe.engine.SetBlocked(ctx, symbol, blocked, reason); err != nil {
+		logger.ErrorWithErrSkip(ctx, 1, "Symbol block not changed", err, "symbol", symbol, "blocked", blocked)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"llm-trading-bot/internal/lifecycle"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+	"llm-trading-bot/internal/types"
+)
+
+// pendingOrder is a placed order that had not filled in full when it was
+// placed. Its later fills are applied to the position and journaled as
+// the broker reports them.
+type pendingOrder struct {
+	entry   tradelog.Entry // The placement: symbol, side, requested qty, reference price and context
+	applied int            // Quantity already applied to the position
+}
+
+// fill is quantity an order filled since it was last checked.
+type fill struct {
+	side   string
+	qty    int
+	price  money.Amount
+	status string // Broker order status; empty when it could not be read
+	done   bool   // The order can fill no further
+}
+
+// filled returns how much of the just-placed order of e has filled,
+// remembering the rest when the order is still working. A broker that
+// cannot report the order is taken to have filled it in full at e's price.
+func (oe *orderExecutor) filled(ctx context.Context, e tradelog.Entry) fill {
+	f := fill{side: e.Side, qty: e.Qty, price: e.Price, done: true}
+	if e.OrderID == "" {
+		return f
+	}
+	st, err := oe.broker.GetOrderStatus(ctx, e.OrderID)
+	if err != nil {
+		logger.Warn(ctx, "Order status unavailable - assuming a full fill", "symbol", e.Symbol, "order_id", e.OrderID, "error", err)
+		return f
+	}
+	f.status = st.Status
+	if st.AvgPrice > 0 {
+		f.price = money.FromFloat(st.AvgPrice)
+	}
+	if st.Status == types.OrderComplete {
+		return f
+	}
+	f.qty = min(st.FilledQty, e.Qty)
+	f.done = st.Terminal()
+	if !f.done {
+		oe.mu.Lock()
+		oe.pending[e.OrderID] = &pendingOrder{entry: e, applied: f.qty}
+		oe.mu.Unlock()
+	}
+	logger.Info(ctx, "Order partly filled", "symbol", e.Symbol, "side", e.Side, "order_id", e.OrderID, "filled", f.qty, "qty", e.Qty, "status", st.Status)
+	return f
+}
+
+// journal appends e as what its order executed: f's quantity at f's price.
+// When the order is done, unfilled is the quantity it never filled.
+func (oe *orderExecutor) journal(e tradelog.Entry, f fill, unfilled int) {
+	requested := e.Qty
+	e.Qty, e.Price, e.Status = f.qty, f.price, f.status
+	if f.done {
+		e.Unfilled = unfilled
+	}
+	if requested > 0 && f.qty != requested {
+		e.SpreadCost = e.SpreadCost.MulDiv(f.qty, requested)
+	}
+	_ = oe.log.Append(e)
+}
+
+// fills returns what symbol's working orders filled since the last check,
+// journals it, and forgets the orders that are done.
+func (oe *orderExecutor) fills(ctx context.Context, symbol string) []fill {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+
+	var out []fill
+	for id, p := range oe.pending {
+		if p.entry.Symbol != symbol {
+			continue
+		}
+		st, err := oe.broker.GetOrderStatus(ctx, id)
+		if err != nil {
+			logger.Warn(ctx, "Order status unavailable", "symbol", symbol, "order_id", id, "error", err)
+			continue
+		}
+		f := fill{side: p.entry.Side, qty: st.FilledQty - p.applied, price: p.entry.Price, status: st.Status, done: st.Terminal()}
+		if st.AvgPrice > 0 {
+			f.price = money.FromFloat(st.AvgPrice)
+		}
+		if f.qty < 0 {
+			f.qty = 0
+		}
+		p.applied += f.qty
+		unfilled := p.entry.Qty - p.applied
+		if f.done {
+			delete(oe.pending, id)
+		}
+		if f.qty > 0 || (f.done && unfilled > 0) {
+			oe.journal(p.entry, f, unfilled)
+		}
+		if f.qty > 0 || f.done {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// working reports whether symbol has an order on side still filling.
+func (oe *orderExecutor) working(symbol, side string) bool {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	for _, p := range oe.pending {
+		if p.entry.Symbol == symbol && p.entry.Side == side {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelWorking cancels symbol's orders on side that are still filling.
+// Their last fills are picked up by the next fills call.
+func (oe *orderExecutor) cancelWorking(ctx context.Context, symbol, side string) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	for id, p := range oe.pending {
+		if p.entry.Symbol != symbol || p.entry.Side != side {
+			continue
+		}
+		if _, err := oe.broker.CancelOrder(ctx, id); err != nil {
+			logger.Warn(ctx, "Failed to cancel working order", "symbol", symbol, "order_id", id, "error", err)
+			continue
+		}
+		logger.Info(ctx, "Working order cancelled", "symbol", symbol, "side", side, "order_id", id)
+	}
+}
+
+// applyFills brings symbol's position up to date with the later fills of
+// its working orders. A sell order that ends with shares still held
+// returns an exiting symbol to ENTERED, so its stop is checked again.
+func (e *Engine) applyFills(ctx context.Context, symbol string, price, atr float64, ts int64) {
+	for _, f := range e.executor.fills(ctx, symbol) {
+		switch f.side {
+		case "BUY":
+			if f.qty == 0 {
+				continue
+			}
+			opened := !e.positions.has(symbol)
+			e.positions.addBuy(ctx, symbol, f.qty, f.price, atr, e.stop.calculateStopPrice(f.price.Float(), atr))
+			e.stops.sync(ctx, symbol, e.positions.get(symbol), price)
+			if opened {
+				e.advance(ctx, symbol, lifecycle.Entered, "buy filled", ts)
+			}
+			logger.Info(ctx, "Buy fill applied", "symbol", symbol, "qty", f.qty, "price", f.price)
+
+		case "SELL":
+			pos := e.positions.get(symbol)
+			if pos == nil {
+				continue
+			}
+			if f.qty > 0 {
+				logger.Info(ctx, "Sell fill applied", "symbol", symbol, "qty", f.qty, "price", f.price)
+			}
+			e.sold(ctx, symbol, pos, f.qty, f.price.Float(), ts, "sold")
+			if f.done && e.positions.has(symbol) && e.lifecycle.Get(symbol, timeutil.FromUnix(ts)).Status == lifecycle.Exiting {
+				e.advance(ctx, symbol, lifecycle.Entered, fmt.Sprintf("sell order ended with %d left", e.positions.get(symbol).qty), ts)
+			}
+		}
+	}
+}
+
+// sold applies qty sold shares to the position. Once it is gone its puts
+// are sold and the symbol cools down; otherwise the broker stop is synced
+// to what is left, unless a sell order is still working on it.
+func (e *Engine) sold(ctx context.Context, symbol string, pos *position, qty int, price float64, ts int64, why string) {
+	if qty > 0 {
+		e.positions.reduceSell(ctx, symbol, qty, money.FromFloat(price))
+	}
+	if !e.positions.has(symbol) {
+		e.unhedge(ctx, symbol, pos)
+		e.advance(ctx, symbol, lifecycle.Cooldown, why, ts)
+		return
+	}
+	if !e.executor.working(symbol, "SELL") {
+		e.stops.sync(ctx, symbol, e.positions.get(symbol), price)
+	}
+}
+
+// cancelBuys cancels symbol's working buy orders before an exit, so their
+// remainder does not reopen the position, and applies what they filled.
+func (e *Engine) cancelBuys(ctx context.Context, symbol string, price float64, ts int64) {
+	if !e.executor.working(symbol, "BUY") {
+		return
+	}
+	e.executor.cancelWorking(ctx, symbol, "BUY")
+	atr := 0.0
+	if pos := e.positions.get(symbol); pos != nil {
+		atr = pos.lastATR
+	}
+	e.applyFills(ctx, symbol, price, atr, ts)
+}
@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/types"
+)
+
+// haltMonitor tracks symbols whose price has stopped moving: pinned at an
+// exchange circuit limit, suspended, or without ticks. A halted symbol
+// gets no decisions and no stop-loss orders, which would only chase a
+// price nobody can trade at, until its price moves again.
+type haltMonitor struct {
+	frozenBars int           // Flat bars in a row that mean frozen; 0 disables
+	stale      time.Duration // Lag behind the newest bar of any symbol that means no ticks; 0 disables
+
+	mu     sync.Mutex
+	halted map[string]halt
+	newest int64 // Newest bar time seen for any symbol
+}
+
+type halt struct {
+	reason string
+	since  time.Time
+}
+
+func newHaltMonitor(frozenBars, staleMinutes int) *haltMonitor {
+	return &haltMonitor{
+		frozenBars: frozenBars,
+		stale:      time.Duration(staleMinutes) * time.Minute,
+		halted:     make(map[string]halt),
+	}
+}
+
+// frozen reports whether the last frozenBars candles that traded all
+// traded at one price: high and low equal and unchanged from bar to bar.
+// Zero-volume bars are skipped: the tick aggregator fills quiet minutes
+// with flat bars at the last price, which say nothing about a halt.
+func (hm *haltMonitor) frozen(candles []types.Candle) bool {
+	if hm.frozenBars <= 0 {
+		return false
+	}
+	n := 0
+	last := 0.0
+	for i := len(candles) - 1; i >= 0 && n < hm.frozenBars; i-- {
+		c := candles[i]
+		if c.Vol <= 0 {
+			continue
+		}
+		if n == 0 {
+			last = c.Close
+		}
+		if c.High != c.Low || c.Close != last {
+			return false
+		}
+		n++
+	}
+	return n == hm.frozenBars
+}
+
+// staleFor returns how far symbol's newest bar lags the newest bar of any
+// symbol, when that is more than the stale limit. Measuring against other
+// symbols rather than the clock keeps closed markets from reading as
+// halts.
+func (hm *haltMonitor) staleFor(latest types.Candle) (time.Duration, bool) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	if latest.Ts > hm.newest {
+		hm.newest = latest.Ts
+	}
+	if hm.stale <= 0 {
+		return 0, false
+	}
+	lag := time.Duration(hm.newest-latest.Ts) * time.Second
+	return lag, lag > hm.stale
+}
+
+// get returns the halt recorded for symbol, if any.
+func (hm *haltMonitor) get(symbol string) (halt, bool) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	h, ok := hm.halted[symbol]
+	return h, ok
+}
+
+// set records symbol as halted, or clears it with an empty reason, and
+// reports whether that changed whether it is halted.
+func (hm *haltMonitor) set(symbol, reason string, now time.Time) bool {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	_, was := hm.halted[symbol]
+	if reason == "" {
+		delete(hm.halted, symbol)
+		return was
+	}
+	if h, ok := hm.halted[symbol]; ok {
+		h.reason = reason
+		hm.halted[symbol] = h
+		return false
+	}
+	hm.halted[symbol] = halt{reason: reason, since: now}
+	return true
+}
+
+// checkHalt detects a halt in symbol's candles and returns the step result
+// for a halted symbol; nil lets the step go on. Halts and resumptions are
+// logged and alerted once each.
+func (e *Engine) checkHalt(ctx context.Context, symbol string, candles []types.Candle) *types.StepResult {
+	latest := candles[len(candles)-1]
+	now := e.clock.Now()
+
+	// Recorded and replayed bars end whenever the recording did
+	var lag time.Duration
+	stale := false
+	if e.cfg.DataSource == "LIVE" {
+		lag, stale = e.halts.staleFor(latest)
+	}
+
+	// A frozen price or missing ticks only raise the question; the book
+	// answers it. With bids and offers both present the symbol trades, and
+	// its stop is enforced.
+	reason := ""
+	if e.halts.frozen(candles) || stale {
+		reason = e.circuitReason(ctx, symbol)
+		if reason != "" && stale {
+			reason += fmt.Sprintf(", no ticks for %s while other symbols trade", lag.Round(time.Minute))
+		}
+	}
+
+	pos := e.positions.get(symbol)
+	if reason == "" {
+		if h, ok := e.halts.get(symbol); ok && e.halts.set(symbol, "", now) {
+			logger.Info(ctx, "Trading resumed - symbol no longer halted", "symbol", symbol, "halted_for", now.Sub(h.since).Round(time.Second), "price", latest.Close)
+			notify.Send(ctx, notify.EventHalt, symbol, fmt.Sprintf("trading resumed at %.2f after %s", latest.Close, h.reason))
+		}
+		return nil
+	}
+
+	if e.halts.set(symbol, reason, now) {
+		msg := fmt.Sprintf("halted: %s at %.2f; decisions paused", reason, latest.Close)
+		if pos != nil && pos.qty > 0 {
+			msg += fmt.Sprintf("; position of %d cannot be managed, stop %.2f not enforced", pos.qty, pos.stop)
+			if pos.stopID != "" {
+				msg += " (broker-held stop stays in place)"
+			}
+		}
+		logger.Warn(ctx, "Symbol halted - pausing decisions and stop-loss orders", "symbol", symbol, "reason", reason, "price", latest.Close)
+		notify.Send(ctx, notify.EventHalt, symbol, msg)
+	}
+	return &types.StepResult{
+		Symbol: symbol,
+		Price:  latest.Close,
+		Time:   latest.Ts,
+		Reason: "HALTED: " + reason,
+	}
+}
+
+// circuitReason reads a halt from the order book: at the upper circuit
+// there are no sellers, at the lower no buyers, and a suspended symbol has
+// neither. A two-sided book, or no quote at all, is not a halt and returns
+// "".
+func (e *Engine) circuitReason(ctx context.Context, symbol string) string {
+	q, err := e.broker.GetQuote(ctx, symbol)
+	if err != nil {
+		logger.Debug(ctx, "No quote to confirm halt - treating symbol as trading", "symbol", symbol, "error", err)
+		return ""
+	}
+	switch {
+	case q.Bid > 0 && q.Ask <= 0:
+		return "upper circuit"
+	case q.Ask > 0 && q.Bid <= 0:
+		return "lower circuit"
+	case q.Bid <= 0 && q.Ask <= 0:
+		return "trading halted"
+	}
+	return ""
+}
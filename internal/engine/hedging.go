@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"llm-trading-bot/internal/broker/options"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/symbols"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// optionChain returns symbol's option chain, or nil when options are off
+// or the chain is unavailable.
+func (e *Engine) optionChain(ctx context.Context, symbol string) *options.Chain {
+	if e.options == nil {
+		return nil
+	}
+	chain, err := e.options.Chain(ctx, symbol)
+	if err != nil {
+		logger.Warn(ctx, "Option chain unavailable", "symbol", symbol, "error", err)
+		return nil
+	}
+	return chain
+}
+
+// optionSummary summarizes symbol's chain for the decider and the IV gate.
+func (e *Engine) optionSummary(ctx context.Context, symbol string, ts int64) (options.Summary, bool) {
+	chain := e.optionChain(ctx, symbol)
+	if chain == nil {
+		return options.Summary{}, false
+	}
+	return chain.Summarize(timeutil.FromUnix(ts), e.cfg.Options.MinDaysToExpiry)
+}
+
+// ivTooHigh reports whether ATM implied volatility is above
+// options.max_entry_iv, returning the IV.
+func (e *Engine) ivTooHigh(ctx context.Context, symbol string, ts int64) (float64, bool) {
+	if e.cfg.Options.MaxEntryIV <= 0 {
+		return 0, false
+	}
+	sum, ok := e.optionSummary(ctx, symbol, ts)
+	if !ok {
+		return 0, false
+	}
+	return sum.ATMIV, sum.ATMIV > e.cfg.Options.MaxEntryIV
+}
+
+// hedge buys protective puts for a position worth at least
+// options.hedge.min_notional: the nearest eligible expiry, at the highest
+// strike otm_pct or more below price, enough lots to cover the shares.
+// A position is hedged once; later buys do not add puts.
+func (e *Engine) hedge(ctx context.Context, symbol string, price float64, ts int64) {
+	h := e.cfg.Options.Hedge
+	pos := e.positions.get(symbol)
+	if e.options == nil || h.MinNotional <= 0 || pos == nil || pos.hedgeSymbol != "" {
+		return
+	}
+	if float64(pos.qty)*price < h.MinNotional {
+		return
+	}
+	lot := e.cfg.Options.LotSizes[symbol]
+	if lot <= 0 {
+		info, _ := symbols.Get(symbol)
+		lot = info.LotSize
+	}
+	if lot <= 0 {
+		logger.Warn(ctx, "Position not hedged - no lot size in options.lot_sizes or the symbol registry", "symbol", symbol)
+		return
+	}
+
+	chain := e.optionChain(ctx, symbol)
+	if chain == nil {
+		return
+	}
+	exp, ok := chain.Expiry(timeutil.FromUnix(ts), e.cfg.Options.MinDaysToExpiry)
+	if !ok {
+		logger.Warn(ctx, "Position not hedged - no expiry far enough out", "symbol", symbol)
+		return
+	}
+	put, ok := chain.Put(exp, price*(1-h.OTMPct/100))
+	if !ok {
+		logger.Warn(ctx, "Position not hedged - no quoted put at or below target strike", "symbol", symbol)
+		return
+	}
+	if premium := put.Put.LTP / price * 100; h.MaxPremiumPct > 0 && premium > h.MaxPremiumPct {
+		logger.Warn(ctx, "Position not hedged - put too expensive", "symbol", symbol, "strike", put.Strike, "premium_pct", premium)
+		return
+	}
+
+	qty := (pos.qty + lot - 1) / lot * lot
+	sym := options.NFOSymbol(chain.Underlying, exp, put.Strike, "PE")
+	resp, err := e.executor.placeHedgeOrder(ctx, symbol, sym, "BUY", qty, money.FromFloat(put.Put.LTP), fmt.Sprintf("protective put for %d shares", pos.qty))
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to buy protective put", err, "symbol", symbol, "option", sym, "qty", qty)
+		return
+	}
+	pos.hedgeSymbol, pos.hedgeQty = sym, qty
+	logger.Info(ctx, "Protective put bought",
+		"symbol", symbol,
+		"option", sym,
+		"qty", qty,
+		"premium", put.Put.LTP,
+		"iv", put.Put.IV,
+		"order_id", resp.OrderID,
+	)
+}
+
+// unhedge sells the protective puts of a position being closed.
+func (e *Engine) unhedge(ctx context.Context, symbol string, pos *position) {
+	if pos == nil || pos.hedgeSymbol == "" {
+		return
+	}
+	var price money.Amount
+	if q, err := e.broker.GetQuote(ctx, pos.hedgeSymbol); err == nil {
+		price = money.FromFloat(q.LTP)
+	}
+	_, err := e.executor.placeHedgeOrder(ctx, symbol, pos.hedgeSymbol, "SELL", pos.hedgeQty, price, "position closed")
+	if err != nil {
+		logger.ErrorWithErr(ctx, "Failed to sell protective put - close it manually", err, "symbol", symbol, "option", pos.hedgeSymbol, "qty", pos.hedgeQty)
+		return
+	}
+	logger.Info(ctx, "Protective put sold", "symbol", symbol, "option", pos.hedgeSymbol, "qty", pos.hedgeQty)
+	pos.hedgeSymbol, pos.hedgeQty = "", 0
+}
+
+// closePosition drops a position that is no longer held, selling its
+// protective puts first.
+func (e *Engine) closePosition(ctx context.Context, symbol string) {
+	e.unhedge(ctx, symbol, e.positions.get(symbol))
+	e.positions.close(symbol)
+}
+
+// ivReason formats the IV gate's block reason.
+func (e *Engine) ivReason(iv float64) string {
+	return fmt.Sprintf(" | blocked: IV %.1f above %.1f", iv, e.cfg.Options.MaxEntryIV)
+}
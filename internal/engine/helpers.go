@@ -2,7 +2,6 @@ package engine
 
 import (
 	"math"
-	"time"
 
 	"llm-trading-bot/internal/ta"
 	"llm-trading-bot/internal/types"
@@ -15,30 +14,41 @@ func roundToTick(price, tick float64) float64 {
 	return math.Round(price/tick) * tick
 }
 
-func midnightIST() time.Time {
-	now := time.Now().UTC()
-	ist := time.FixedZone("IST", 19800) // IST is UTC+5:30 (19800 seconds)
-	znow := now.In(ist)
-	return time.Date(znow.Year(), znow.Month(), znow.Day(), 0, 0, 0, 0, ist)
-}
-
-//
-//
-func calculateIndicators(candles []types.Candle, cfg struct {
+type indicatorParams struct {
 	SMAWindows []int
 	RSIPeriod  int
 	BBWindow   int
 	BBStdDev   float64
 	ATRPeriod  int
-}) types.Indicators {
+
+	SupertrendPeriod int
+	SupertrendMult   float64
+
+	IchimokuTenkan       int
+	IchimokuKijun        int
+	IchimokuSenkou       int
+	IchimokuDisplacement int
+
+	KeltnerPeriod    int
+	KeltnerATRPeriod int
+	KeltnerMult      float64
+}
+
+// calculateIndicators recomputes every indicator from the full candle
+// window; indicatorCache is the incremental equivalent.
+func calculateIndicators(candles []types.Candle, cfg indicatorParams) types.Indicators {
 	closes := make([]float64, len(candles))
 	highs := make([]float64, len(candles))
 	lows := make([]float64, len(candles))
+	vols := make([]float64, len(candles))
+	ts := make([]int64, len(candles))
 
 	for i, c := range candles {
 		closes[i] = c.Close
 		highs[i] = c.High
 		lows[i] = c.Low
+		vols[i] = c.Vol
+		ts[i] = c.Ts
 	}
 
 	indicators := types.Indicators{SMA: map[int]float64{}}
@@ -56,6 +66,17 @@ func calculateIndicators(candles []types.Candle, cfg struct {
 
 	indicators.ATR = ta.ATR(highs, lows, closes, cfg.ATRPeriod)
 
+	indicators.VWAP = ta.VWAP(ts, highs, lows, closes, vols)
+	indicators.Supertrend.Value, indicators.Supertrend.Dir = ta.Supertrend(highs, lows, closes, cfg.SupertrendPeriod, cfg.SupertrendMult)
+	indicators.OBV = ta.OBV(closes, vols)
+
+	ichi := &indicators.Ichimoku
+	ichi.Tenkan, ichi.Kijun, ichi.SenkouA, ichi.SenkouB = ta.Ichimoku(highs, lows,
+		cfg.IchimokuTenkan, cfg.IchimokuKijun, cfg.IchimokuSenkou, cfg.IchimokuDisplacement)
+
+	indicators.Keltner.Middle, indicators.Keltner.Upper, indicators.Keltner.Lower = ta.Keltner(highs, lows, closes,
+		cfg.KeltnerPeriod, cfg.KeltnerATRPeriod, cfg.KeltnerMult)
+
 	return indicators
 }
 
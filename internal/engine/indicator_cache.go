@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"sync"
+
+	"llm-trading-bot/internal/ta"
+	"llm-trading-bot/internal/types"
+)
+
+// indicatorCache keeps streaming indicator state per symbol so a step only
+// feeds the bars that closed since the previous one, instead of
+// recomputing everything from the full candle window. The last candle is
+// treated as still forming and is peeked, never committed.
+type indicatorCache struct {
+	params indicatorParams
+	batch  bool
+
+	mu     sync.Mutex
+	states map[string]*indicatorState
+}
+
+type indicatorState struct {
+	lastTs    int64 // Last committed bar
+	lastClose float64
+
+	sma  map[int]*ta.SMAStream
+	rsi  *ta.RSIStream
+	bb   *ta.BollingerStream
+	atr  *ta.ATRStream
+	vwap *ta.VWAPStream
+	st   *ta.SupertrendStream
+	obv  *ta.OBVStream
+	ichi *ta.IchimokuStream
+	kc   *ta.KeltnerStream
+}
+
+func newIndicatorCache(params indicatorParams, batch bool) *indicatorCache {
+	return &indicatorCache{
+		params: params,
+		batch:  batch,
+		states: make(map[string]*indicatorState),
+	}
+}
+
+func (ic *indicatorCache) compute(symbol string, candles []types.Candle) types.Indicators {
+	if ic.batch || len(candles) == 0 {
+		return calculateIndicators(candles, ic.params)
+	}
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	closed, forming := candles[:len(candles)-1], candles[len(candles)-1]
+
+	st := ic.states[symbol]
+	start := -1
+	if st != nil {
+		start = resumeIndex(closed, st.lastTs, st.lastClose)
+	}
+	if start < 0 {
+		// First step for the symbol, or history was rewritten (backfill,
+		// trimmed window): rebuild from the bars we have.
+		st = ic.newState()
+		ic.states[symbol] = st
+		start = 0
+	}
+
+	for _, c := range closed[start:] {
+		st.add(c)
+	}
+	st.vwap.Trim(candles[0].Ts)
+	st.obv.Trim(candles[0].Ts)
+
+	return st.peek(forming, ic.params.SMAWindows)
+}
+
+// resumeIndex returns the index after the last committed bar, or -1 if it
+// is no longer in closed unchanged.
+func resumeIndex(closed []types.Candle, lastTs int64, lastClose float64) int {
+	for i := len(closed) - 1; i >= 0; i-- {
+		if closed[i].Ts == lastTs {
+			if closed[i].Close != lastClose {
+				return -1
+			}
+			return i + 1
+		}
+		if closed[i].Ts < lastTs {
+			break
+		}
+	}
+	return -1
+}
+
+func (ic *indicatorCache) newState() *indicatorState {
+	p := ic.params
+	st := &indicatorState{
+		sma:  make(map[int]*ta.SMAStream, len(p.SMAWindows)),
+		rsi:  ta.NewRSIStream(p.RSIPeriod),
+		bb:   ta.NewBollingerStream(p.BBWindow, p.BBStdDev),
+		atr:  ta.NewATRStream(p.ATRPeriod),
+		vwap: ta.NewVWAPStream(),
+		st:   ta.NewSupertrendStream(p.SupertrendPeriod, p.SupertrendMult),
+		obv:  ta.NewOBVStream(),
+		ichi: ta.NewIchimokuStream(p.IchimokuTenkan, p.IchimokuKijun, p.IchimokuSenkou, p.IchimokuDisplacement),
+		kc:   ta.NewKeltnerStream(p.KeltnerPeriod, p.KeltnerATRPeriod, p.KeltnerMult),
+	}
+	for _, w := range p.SMAWindows {
+		st.sma[w] = ta.NewSMAStream(w)
+	}
+	return st
+}
+
+func (st *indicatorState) add(c types.Candle) {
+	for _, s := range st.sma {
+		s.Add(c.Close)
+	}
+	st.rsi.Add(c.Close)
+	st.bb.Add(c.Close)
+	st.atr.Add(c.High, c.Low, c.Close)
+	st.vwap.Add(c.Ts, c.High, c.Low, c.Close, c.Vol)
+	st.st.Add(c.High, c.Low, c.Close)
+	st.obv.Add(c.Ts, c.Close, c.Vol)
+	st.ichi.Add(c.High, c.Low)
+	st.kc.Add(c.High, c.Low, c.Close)
+
+	st.lastTs, st.lastClose = c.Ts, c.Close
+}
+
+func (st *indicatorState) peek(c types.Candle, smaWindows []int) types.Indicators {
+	indicators := types.Indicators{SMA: map[int]float64{}}
+
+	for _, window := range smaWindows {
+		indicators.SMA[window] = st.sma[window].Peek(c.Close)
+	}
+
+	indicators.RSI = st.rsi.Peek(c.Close)
+
+	indicators.BB.Middle, indicators.BB.Upper, indicators.BB.Lower = st.bb.Peek(c.Close)
+
+	indicators.ATR = st.atr.Peek(c.High, c.Low, c.Close)
+
+	indicators.VWAP = st.vwap.Peek(c.Ts, c.High, c.Low, c.Close, c.Vol)
+	indicators.Supertrend.Value, indicators.Supertrend.Dir = st.st.Peek(c.High, c.Low, c.Close)
+	indicators.OBV = st.obv.Peek(c.Close, c.Vol)
+
+	ichi := &indicators.Ichimoku
+	ichi.Tenkan, ichi.Kijun, ichi.SenkouA, ichi.SenkouB = st.ichi.Peek(c.High, c.Low)
+
+	indicators.Keltner.Middle, indicators.Keltner.Upper, indicators.Keltner.Lower = st.kc.Peek(c.High, c.Low, c.Close)
+
+	return indicators
+}
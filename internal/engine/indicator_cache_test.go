@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"llm-trading-bot/internal/types"
+)
+
+var testParams = indicatorParams{
+	SMAWindows: []int{20, 50, 200},
+	RSIPeriod:  14,
+	BBWindow:   20,
+	BBStdDev:   2,
+	ATRPeriod:  14,
+
+	SupertrendPeriod: 10,
+	SupertrendMult:   3,
+
+	IchimokuTenkan:       9,
+	IchimokuKijun:        26,
+	IchimokuSenkou:       52,
+	IchimokuDisplacement: 26,
+
+	KeltnerPeriod:    20,
+	KeltnerATRPeriod: 10,
+	KeltnerMult:      2,
+}
+
+// randomWalk returns n one-minute bars of a seeded random walk, with some
+// flat closes so OBV sees unchanged bars too.
+func randomWalk(n int) []types.Candle {
+	r := rand.New(rand.NewSource(7))
+	out := make([]types.Candle, n)
+	price := 1000.0
+	for i := range out {
+		open := price
+		if r.Intn(5) > 0 {
+			price *= 1 + (r.Float64()-0.5)*0.01
+		}
+		out[i] = types.Candle{
+			Ts:    1735700400 + int64(i)*60, // 2025-01-01 09:00 IST
+			Open:  open,
+			High:  math.Max(open, price) * (1 + r.Float64()*0.002),
+			Low:   math.Min(open, price) * (1 - r.Float64()*0.002),
+			Close: price,
+			Vol:   float64(1000 + r.Intn(5000)),
+		}
+	}
+	return out
+}
+
+func near(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.IsNaN(got) && math.IsNaN(want) {
+		return
+	}
+	if math.Abs(got-want) > tol*math.Max(1, math.Abs(want)) {
+		t.Errorf("%s: stream %v, batch %v", name, got, want)
+	}
+}
+
+// TestIndicatorCacheParity slides a 250-bar window over the bars, as the
+// engine does each poll, and checks the streaming values against the batch
+// ones. The bars cross an IST midnight, so VWAP resets once. EMA and Wilder based values may differ by the memory carried from
+// before the window, which has decayed far below the tolerance by then.
+func TestIndicatorCacheParity(t *testing.T) {
+	const window = 250
+	bars := randomWalk(1200)
+	ic := newIndicatorCache(testParams, false)
+
+	for end := window; end <= len(bars); end++ {
+		candles := bars[end-window : end]
+		got := ic.compute("X", candles)
+		want := calculateIndicators(candles, testParams)
+
+		for _, w := range testParams.SMAWindows {
+			near(t, "SMA", got.SMA[w], want.SMA[w], 1e-9)
+		}
+		near(t, "RSI", got.RSI, want.RSI, 1e-9)
+		near(t, "BB.Middle", got.BB.Middle, want.BB.Middle, 1e-9)
+		near(t, "BB.Upper", got.BB.Upper, want.BB.Upper, 1e-9)
+		near(t, "BB.Lower", got.BB.Lower, want.BB.Lower, 1e-9)
+		near(t, "ATR", got.ATR, want.ATR, 1e-9)
+		near(t, "VWAP", got.VWAP, want.VWAP, 1e-9)
+		near(t, "OBV", got.OBV, want.OBV, 1e-9)
+		near(t, "Tenkan", got.Ichimoku.Tenkan, want.Ichimoku.Tenkan, 1e-9)
+		near(t, "Kijun", got.Ichimoku.Kijun, want.Ichimoku.Kijun, 1e-9)
+		near(t, "SenkouA", got.Ichimoku.SenkouA, want.Ichimoku.SenkouA, 1e-9)
+		near(t, "SenkouB", got.Ichimoku.SenkouB, want.Ichimoku.SenkouB, 1e-9)
+		near(t, "Keltner.Middle", got.Keltner.Middle, want.Keltner.Middle, 1e-6)
+		near(t, "Keltner.Upper", got.Keltner.Upper, want.Keltner.Upper, 1e-6)
+		near(t, "Keltner.Lower", got.Keltner.Lower, want.Keltner.Lower, 1e-6)
+		near(t, "Supertrend", got.Supertrend.Value, want.Supertrend.Value, 1e-6)
+		if got.Supertrend.Dir != want.Supertrend.Dir {
+			t.Errorf("Supertrend.Dir: stream %d, batch %d", got.Supertrend.Dir, want.Supertrend.Dir)
+		}
+		if t.Failed() {
+			t.Fatalf("window ending at bar %d", end)
+		}
+	}
+}
+
+// TestIndicatorCacheRewrite checks that a change to the last committed bar,
+// as a backfill makes, rebuilds the state instead of resuming from it.
+func TestIndicatorCacheRewrite(t *testing.T) {
+	bars := randomWalk(300)
+	ic := newIndicatorCache(testParams, false)
+	ic.compute("X", bars[:250])
+
+	rewritten := append([]types.Candle(nil), bars[1:251]...)
+	rewritten[247].Close *= 1.05 // The last bar committed by the first compute
+	got := ic.compute("X", rewritten)
+	want := calculateIndicators(rewritten, testParams)
+	near(t, "SMA20", got.SMA[20], want.SMA[20], 1e-9)
+	near(t, "OBV", got.OBV, want.OBV, 1e-9)
+}
+
+func benchmarkIndicators(b *testing.B, batch bool) {
+	const window = 250
+	bars := randomWalk(window + b.N)
+	ic := newIndicatorCache(testParams, batch)
+	ic.compute("X", bars[:window])
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ic.compute("X", bars[i+1:i+1+window])
+	}
+}
+
+// BenchmarkIndicatorsBatch is the per-step cost with indicators.recompute.
+func BenchmarkIndicatorsBatch(b *testing.B) { benchmarkIndicators(b, true) }
+
+// BenchmarkIndicatorsStream is the per-step cost of the streaming default.
+func BenchmarkIndicatorsStream(b *testing.B) { benchmarkIndicators(b, false) }
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/lifecycle"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// openLifecycle loads the saved symbol states and reconciles them with a
+// fresh engine: positions are not restored across runs, so ENTERED and
+// EXITING fall back to WATCHING, and lifecycle.blocked is applied.
+func openLifecycle(cfg *store.Config, clk interfaces.Clock) *lifecycle.Tracker {
+	ctx := context.Background()
+	now := clk.Now()
+
+	lc, err := lifecycle.Open(cfg.Lifecycle.StateFile, time.Duration(cfg.Lifecycle.CooldownMinutes)*time.Minute)
+	if err != nil {
+		logger.Warn(ctx, "Failed to load symbol states - starting fresh", "path", cfg.Lifecycle.StateFile, "error", err)
+	}
+
+	blocked := make(map[string]bool, len(cfg.Lifecycle.Blocked))
+	for _, sym := range cfg.Lifecycle.Blocked {
+		blocked[sym] = true
+	}
+	for _, st := range lc.All(now) {
+		switch {
+		case st.Status == lifecycle.Entered || st.Status == lifecycle.Exiting:
+			logger.Warn(ctx, "Position not restored after restart - symbol back to watching", "symbol", st.Symbol, "status", st.Status)
+			moveStatus(ctx, lc, st.Symbol, lifecycle.Watching, "position not restored after restart", now)
+		case st.Status == lifecycle.Blocked && st.Reason == blockedByConfig && !blocked[st.Symbol]:
+			moveStatus(ctx, lc, st.Symbol, lifecycle.Watching, "removed from lifecycle.blocked", now)
+		}
+	}
+	for sym := range blocked {
+		moveStatus(ctx, lc, sym, lifecycle.Blocked, blockedByConfig, now)
+	}
+	return lc
+}
+
+const blockedByConfig = "lifecycle.blocked"
+
+func moveStatus(ctx context.Context, lc *lifecycle.Tracker, symbol, to, reason string, now time.Time) {
+	from := lc.Status(symbol, now)
+	if err := lc.Move(symbol, to, reason, now); err != nil {
+		logger.Warn(ctx, "Symbol status not changed", "symbol", symbol, "from", from, "to", to, "error", err)
+		return
+	}
+	if from != to {
+		logger.Info(ctx, "Symbol status changed", "symbol", symbol, "from", from, "to", to, "reason", reason)
+	}
+}
+
+// advance records a status change caused by trading. A blocked symbol
+// stays blocked until SetBlocked releases it.
+func (e *Engine) advance(ctx context.Context, symbol, to, reason string, ts int64) {
+	now := timeutil.FromUnix(ts)
+	if e.lifecycle.Status(symbol, now) == lifecycle.Blocked {
+		return
+	}
+	moveStatus(ctx, e.lifecycle, symbol, to, reason, now)
+}
+
+// SymbolStates returns the status of every universe symbol plus any other
+// symbol with a saved state.
+func (e *Engine) SymbolStates() []lifecycle.State {
+	now := e.clock.Now()
+	states := e.lifecycle.All(now)
+	seen := make(map[string]bool, len(states))
+	for _, st := range states {
+		seen[st.Symbol] = true
+	}
+	for _, sym := range e.cfg.UniverseStatic {
+		if !seen[sym] {
+			states = append(states, e.lifecycle.Get(sym, now))
+		}
+	}
+	for i := range states {
+		states[i].Account = e.cfg.Account
+	}
+	return states
+}
+
+// SetBlocked blocks or releases a symbol. A released symbol returns to
+// ENTERED when a position is open, else WATCHING.
+func (e *Engine) SetBlocked(ctx context.Context, symbol string, blocked bool, reason string) error {
+	now := e.clock.Now()
+	to := lifecycle.Blocked
+	if !blocked {
+		if e.lifecycle.Status(symbol, now) != lifecycle.Blocked {
+			return nil
+		}
+		to = lifecycle.Watching
+		if e.positions.has(symbol) {
+			to = lifecycle.Entered
+		}
+	}
+	if err := e.lifecycle.Move(symbol, to, reason, now); err != nil {
+		return err
+	}
+	logger.Info(ctx, "Symbol status changed", "symbol", symbol, "to", to, "reason", reason)
+	return nil
+}
@@ -2,108 +2,375 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/killswitch"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/symbols"
 	"llm-trading-bot/internal/tradelog"
 	"llm-trading-bot/internal/types"
 )
 
 type orderExecutor struct {
-	broker interfaces.Broker
+	broker  interfaces.Broker
+	account string // Tags the trade log when several accounts trade in one process
+
+	style       string  // MARKET | SPREAD
+	maxCrossBps float64 // SPREAD: cross the spread when it is at most this wide, else join
+	minTick     float64
+	limits      *orderLimits
+	log         *tradelog.Writer
+
+	mu      sync.Mutex
+	placed  map[string]string        // Order ID -> symbol, until seen in a terminal state
+	pending map[string]*pendingOrder // Order ID -> order whose later fills are still to be applied
 }
 
-func newOrderExecutor(broker interfaces.Broker) *orderExecutor {
+func newOrderExecutor(broker interfaces.Broker, account, style string, maxCrossBps, minTick float64, limits *orderLimits, log *tradelog.Writer) *orderExecutor {
 	return &orderExecutor{
-		broker: broker,
+		broker:      broker,
+		account:     account,
+		style:       style,
+		maxCrossBps: maxCrossBps,
+		minTick:     minTick,
+		limits:      limits,
+		log:         log,
+		placed:      make(map[string]string),
+		pending:     make(map[string]*pendingOrder),
+	}
+}
+
+// priceOrder picks the order type and price from the current quote. Under
+// SPREAD style a tight spread is crossed with a marketable limit at the far
+// touch; a wide one is joined at the near touch. Urgent orders (stop-loss,
+// flatten) always cross. The returned fields record the quote and the effective
+// spread cost (fill price vs mid, times qty) for the trade log.
+func (oe *orderExecutor) priceOrder(ctx context.Context, req *types.OrderReq, urgent bool) map[string]any {
+	quote, err := oe.broker.GetQuote(ctx, req.Symbol)
+	if err != nil || quote.Bid <= 0 || quote.Ask <= 0 {
+		return nil
+	}
+
+	bid, ask := money.FromFloat(quote.Bid), money.FromFloat(quote.Ask)
+	near, far := bid, ask
+	if req.Side == "SELL" {
+		near, far = ask, bid
+	}
+
+	price := far // Expected fill for a market order
+	if oe.style == "SPREAD" {
+		if !urgent && quote.SpreadBps() > oe.maxCrossBps {
+			price = near
+		}
+		price = money.FromFloat(roundToTick(price.Float(), oe.minTick))
+		req.Type = "LIMIT"
+		req.Price = price.Float()
+	}
+
+	mid := (bid + ask).Div(2)
+	cost := (price - mid).Mul(req.Qty)
+	if req.Side == "SELL" {
+		cost = (mid - price).Mul(req.Qty)
+	}
+
+	return map[string]any{
+		"quote_bid":   quote.Bid,
+		"quote_ask":   quote.Ask,
+		"spread_bps":  quote.SpreadBps(),
+		"spread_cost": cost,
+	}
+}
+
+func mergeExtra(dst, src map[string]any) map[string]any {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
 	}
+	return dst
 }
 
-//
-//
-func (oe *orderExecutor) placeBuyOrder(ctx context.Context, symbol string, qty int, price float64, reason string, confidence float64) (types.OrderResp, error) {
+// orderContext is the decision snapshot journaled alongside every order.
+type orderContext struct {
+	reason     string
+	confidence float64
+	indicators types.Indicators
+	signals    map[string]any // Research inputs given to the decider (news, PEAD, forensic)
+	extra      map[string]any
+}
+
+func (oe *orderExecutor) placeBuyOrder(ctx context.Context, symbol string, qty int, price money.Amount, oc orderContext) (types.OrderResp, fill, error) {
+	if err := killswitch.Check(symbol, false); err != nil {
+		logger.Warn(ctx, "BUY order refused by kill switch", "symbol", symbol, "qty", qty, "error", err)
+		return types.OrderResp{}, fill{}, err
+	}
+	if err := symbols.Check(symbol, qty); err != nil {
+		logger.Warn(ctx, "BUY order refused by symbol registry", "symbol", symbol, "qty", qty, "error", err)
+		return types.OrderResp{}, fill{}, err
+	}
+	if err := oe.limits.reserve(ctx, symbol, false); err != nil {
+		logger.Warn(ctx, "BUY order refused by daily order limit", "symbol", symbol, "qty", qty, "error", err)
+		return types.OrderResp{}, fill{}, err
+	}
 	req := types.OrderReq{
 		Symbol: symbol,
 		Side:   "BUY",
 		Qty:    qty,
 		Tag:    "LLM",
 	}
+	execInfo := oe.priceOrder(ctx, &req, false)
 
 	resp, err := oe.broker.PlaceOrder(ctx, req)
 	if err != nil {
+		oe.limits.release(ctx, symbol)
 		logger.ErrorWithErr(ctx, "Failed to place BUY order", err,
 			"symbol", symbol,
 			"qty", qty,
 			"price", price,
 		)
-		return types.OrderResp{}, err
+		return types.OrderResp{}, fill{}, err
 	}
+	oe.track(resp.OrderID, symbol)
 
-
-	_ = tradelog.Append(tradelog.Entry{
+	entry := tradelog.Entry{
 		Symbol:     symbol,
 		Side:       "BUY",
 		Qty:        qty,
 		Price:      price,
 		OrderID:    resp.OrderID,
-		Reason:     reason,
-		Confidence: confidence,
-	})
+		Reason:     oc.reason,
+		Confidence: oc.confidence,
+		Account:    oe.account,
+		Tag:        req.Tag,
+		Indicators: indicatorSnapshot(oc.indicators),
+		Signals:    oc.signals,
+		Extra:      mergeExtra(oc.extra, execInfo),
+		OrderType:  req.Type,
+		LimitPrice: money.FromFloat(req.Price),
+		SpreadCost: spreadCost(execInfo),
+	}
+	f := oe.filled(ctx, entry)
+	oe.journal(entry, f, qty-f.qty)
+	notify.Send(ctx, notify.EventTrade, symbol, fmt.Sprintf("BUY %d @ %s (%s)", qty, price, oc.reason))
 
-	return resp, nil
+	return resp, f, nil
 }
 
-//
-//
-func (oe *orderExecutor) placeSellOrder(ctx context.Context, symbol string, qty int, price float64, reason string, confidence float64, tag string) (types.OrderResp, error) {
+func (oe *orderExecutor) placeSellOrder(ctx context.Context, symbol string, qty int, price money.Amount, oc orderContext, tag string) (types.OrderResp, fill, error) {
+	if err := killswitch.Check(symbol, protective(tag)); err != nil {
+		logger.Warn(ctx, "SELL order refused by kill switch", "symbol", symbol, "qty", qty, "error", err)
+		return types.OrderResp{}, fill{}, err
+	}
+	if err := oe.limits.reserve(ctx, symbol, protective(tag)); err != nil {
+		logger.Warn(ctx, "SELL order refused by daily order limit", "symbol", symbol, "qty", qty, "error", err)
+		return types.OrderResp{}, fill{}, err
+	}
 	req := types.OrderReq{
 		Symbol: symbol,
 		Side:   "SELL",
 		Qty:    qty,
 		Tag:    tag,
 	}
+	execInfo := oe.priceOrder(ctx, &req, protective(tag))
 
 	resp, err := oe.broker.PlaceOrder(ctx, req)
 	if err != nil {
+		oe.limits.release(ctx, symbol)
 		logger.ErrorWithErr(ctx, "Failed to place SELL order", err,
 			"symbol", symbol,
 			"qty", qty,
 			"price", price,
 		)
-		return types.OrderResp{}, err
+		return types.OrderResp{}, fill{}, err
 	}
+	oe.track(resp.OrderID, symbol)
 
-
-	_ = tradelog.Append(tradelog.Entry{
+	entry := tradelog.Entry{
 		Symbol:     symbol,
 		Side:       "SELL",
 		Qty:        qty,
 		Price:      price,
 		OrderID:    resp.OrderID,
-		Reason:     reason,
-		Confidence: confidence,
+		Reason:     oc.reason,
+		Confidence: oc.confidence,
+		Account:    oe.account,
+		Tag:        req.Tag,
+		Indicators: indicatorSnapshot(oc.indicators),
+		Signals:    oc.signals,
+		Extra:      mergeExtra(oc.extra, execInfo),
+		OrderType:  req.Type,
+		LimitPrice: money.FromFloat(req.Price),
+		SpreadCost: spreadCost(execInfo),
+	}
+	f := oe.filled(ctx, entry)
+	oe.journal(entry, f, qty-f.qty)
+	notify.Send(ctx, notify.EventTrade, symbol, fmt.Sprintf("SELL %d @ %s (%s)", qty, price, oc.reason))
+
+	return resp, f, nil
+}
+
+// placeHedgeOrder places a market order for a position's protective puts.
+// It is checked and counted against the underlying symbol; buying the
+// puts is a new order, selling them closes the hedge and is protective.
+func (oe *orderExecutor) placeHedgeOrder(ctx context.Context, underlying, option, side string, qty int, price money.Amount, reason string) (types.OrderResp, error) {
+	closing := side == "SELL"
+	if err := killswitch.Check(underlying, closing); err != nil {
+		logger.Warn(ctx, "Hedge order refused by kill switch", "symbol", underlying, "option", option, "side", side, "error", err)
+		return types.OrderResp{}, err
+	}
+	if err := oe.limits.reserve(ctx, underlying, closing); err != nil {
+		logger.Warn(ctx, "Hedge order refused by daily order limit", "symbol", underlying, "option", option, "side", side, "error", err)
+		return types.OrderResp{}, err
+	}
+
+	req := types.OrderReq{Symbol: option, Side: side, Qty: qty, Tag: "HEDGE"}
+	resp, err := oe.broker.PlaceOrder(ctx, req)
+	if err != nil {
+		oe.limits.release(ctx, underlying)
+		return types.OrderResp{}, err
+	}
+	oe.track(resp.OrderID, option)
+
+	_ = oe.log.Append(tradelog.Entry{
+		Symbol:  option,
+		Side:    side,
+		Qty:     qty,
+		Price:   price,
+		OrderID: resp.OrderID,
+		Reason:  reason,
+		Account: oe.account,
+		Tag:     req.Tag,
+		Extra:   map[string]any{"underlying": underlying},
 	})
+	notify.Send(ctx, notify.EventTrade, underlying, fmt.Sprintf("%s %d %s @ %s (%s)", side, qty, option, price, reason))
 
 	return resp, nil
 }
 
+// protective reports whether an order tag is a risk-reducing exit, which
+// is priced to fill and allowed through a halt.
+func protective(tag string) bool {
+	return tag == "SL" || tag == "FLATTEN"
+}
+
+// refused reports whether err is a kill switch, symbol registry or order
+// limit refusal rather than a broker failure.
+func refused(err error) bool {
+	return errors.Is(err, killswitch.ErrHalted) || errors.Is(err, killswitch.ErrBlocked) ||
+		errors.Is(err, symbols.ErrUnknown) || errors.Is(err, symbols.ErrLot) ||
+		errors.Is(err, ErrOrderLimit)
+}
+
 func (oe *orderExecutor) logDecision(ctx context.Context, symbol string, decision types.Decision, price float64, indicators types.Indicators) {
 
-	_ = tradelog.AppendDecision(tradelog.DecisionEntry{
+	_ = oe.log.AppendDecision(tradelog.DecisionEntry{
 		Symbol:     symbol,
 		Action:     decision.Action,
 		Confidence: decision.Confidence,
 		Reason:     decision.Reason,
 		Price:      price,
-		Indicators: map[string]float64{
-			"RSI":    indicators.RSI,
-			"SMA20":  indicators.SMA[20],
-			"SMA50":  indicators.SMA[50],
-			"SMA200": indicators.SMA[200],
-			"BB_MID": indicators.BB.Middle,
-			"BB_UP":  indicators.BB.Upper,
-			"BB_LOW": indicators.BB.Lower,
-			"ATR":    indicators.ATR,
-		},
+		Account:    oe.account,
+		Indicators: indicatorSnapshot(indicators),
 	})
 }
+
+func spreadCost(execInfo map[string]any) money.Amount {
+	cost, _ := execInfo["spread_cost"].(money.Amount)
+	return cost
+}
+
+func indicatorSnapshot(indicators types.Indicators) map[string]float64 {
+	snap := map[string]float64{
+		"RSI":    indicators.RSI,
+		"SMA20":  indicators.SMA[20],
+		"SMA50":  indicators.SMA[50],
+		"SMA200": indicators.SMA[200],
+		"BB_MID": indicators.BB.Middle,
+		"BB_UP":  indicators.BB.Upper,
+		"BB_LOW": indicators.BB.Lower,
+		"ATR":    indicators.ATR,
+		"VWAP":   indicators.VWAP,
+		"ST":     indicators.Supertrend.Value,
+		"ST_DIR": float64(indicators.Supertrend.Dir),
+		"OBV":    indicators.OBV,
+
+		"TENKAN":   indicators.Ichimoku.Tenkan,
+		"KIJUN":    indicators.Ichimoku.Kijun,
+		"SENKOU_A": indicators.Ichimoku.SenkouA,
+		"SENKOU_B": indicators.Ichimoku.SenkouB,
+		"KC_MID":   indicators.Keltner.Middle,
+		"KC_UP":    indicators.Keltner.Upper,
+		"KC_LOW":   indicators.Keltner.Lower,
+	}
+	for w, v := range indicators.RS {
+		snap[fmt.Sprintf("RS%d", w)] = v
+	}
+	return snap
+}
+
+// signalSnapshot copies the research signals out of the decider context,
+// leaving out values that are already journaled on their own.
+func signalSnapshot(contextData map[string]any) map[string]any {
+	signals := make(map[string]any, len(contextData))
+	for k, v := range contextData {
+		if k == "price" || k == "risk" {
+			continue
+		}
+		signals[k] = v
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+	return signals
+}
+
+func (oe *orderExecutor) track(orderID, symbol string) {
+	if orderID != "" {
+		oe.mu.Lock()
+		oe.placed[orderID] = symbol
+		oe.mu.Unlock()
+	}
+}
+
+// cancelOpen cancels every order placed this session that the broker still
+// reports as working, and returns how many were cancelled.
+func (oe *orderExecutor) cancelOpen(ctx context.Context) (int, error) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+
+	cancelled := 0
+	var failed []string
+	for id, symbol := range oe.placed {
+		st, err := oe.broker.GetOrderStatus(ctx, id)
+		if err != nil {
+			failed = append(failed, id)
+			continue
+		}
+		if st.Terminal() {
+			delete(oe.placed, id)
+			continue
+		}
+		if _, err := oe.broker.CancelOrder(ctx, id); err != nil {
+			logger.Warn(ctx, "Failed to cancel open order", "symbol", symbol, "order_id", id, "error", err)
+			failed = append(failed, id)
+			continue
+		}
+		logger.Info(ctx, "Open order cancelled", "symbol", symbol, "order_id", id, "pending_qty", st.PendingQty)
+		delete(oe.placed, id)
+		cancelled++
+	}
+	if len(failed) > 0 {
+		return cancelled, fmt.Errorf("could not cancel orders %s", strings.Join(failed, ", "))
+	}
+	return cancelled, nil
+}
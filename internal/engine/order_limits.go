@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/timeutil"
+)
+
+// ErrOrderLimit refuses an order over risk.max_orders_per_symbol_per_day
+// or risk.max_total_orders_per_day.
+var ErrOrderLimit = errors.New("daily order limit reached")
+
+// orderLimits caps the orders placed each IST day, per symbol and in
+// total, against a decider that keeps trading. Protective exits are
+// counted but never refused. The counts are saved to a JSON file so a
+// restart does not reset them.
+type orderLimits struct {
+	clock     interfaces.Clock
+	perSymbol int    // 0 = no limit
+	total     int    // 0 = no limit
+	path      string // Empty: kept in memory only
+
+	mu     sync.Mutex
+	counts orderCounts
+}
+
+// orderCounts is the saved form of a day's counts.
+type orderCounts struct {
+	Date    string         `json:"date"`
+	Total   int            `json:"total"`
+	Symbols map[string]int `json:"symbols"`
+}
+
+// openOrderLimits loads today's counts from risk.order_count_file.
+func openOrderLimits(cfg *store.Config, clk interfaces.Clock) *orderLimits {
+	ol := &orderLimits{
+		clock:     clk,
+		perSymbol: cfg.Risk.MaxOrdersPerSymbolPerDay,
+		total:     cfg.Risk.MaxTotalOrdersPerDay,
+		path:      cfg.Risk.OrderCountFile,
+	}
+	if ol.path == "" {
+		return ol
+	}
+	b, err := os.ReadFile(ol.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn(context.Background(), "Failed to load order counts - starting from zero", "path", ol.path, "error", err)
+		}
+		return ol
+	}
+	if err := json.Unmarshal(b, &ol.counts); err != nil {
+		logger.Warn(context.Background(), "Invalid order count file - starting from zero", "path", ol.path, "error", err)
+		ol.counts = orderCounts{}
+	}
+	return ol
+}
+
+// reserve counts an order for symbol, or refuses it with ErrOrderLimit
+// when a limit is reached. release gives the count back when the order is
+// not placed after all.
+func (ol *orderLimits) reserve(ctx context.Context, symbol string, protective bool) error {
+	ol.mu.Lock()
+	defer ol.mu.Unlock()
+
+	today := ol.clock.Now().In(timeutil.IST).Format(timeutil.DateLayout)
+	if ol.counts.Date != today {
+		ol.counts = orderCounts{Date: today}
+	}
+	if ol.counts.Symbols == nil {
+		ol.counts.Symbols = make(map[string]int)
+	}
+
+	if !protective {
+		if ol.perSymbol > 0 && ol.counts.Symbols[symbol] >= ol.perSymbol {
+			return fmt.Errorf("%w: %d orders for %s today", ErrOrderLimit, ol.counts.Symbols[symbol], symbol)
+		}
+		if ol.total > 0 && ol.counts.Total >= ol.total {
+			return fmt.Errorf("%w: %d orders today", ErrOrderLimit, ol.counts.Total)
+		}
+	}
+	ol.counts.Symbols[symbol]++
+	ol.counts.Total++
+	ol.save(ctx)
+	return nil
+}
+
+func (ol *orderLimits) release(ctx context.Context, symbol string) {
+	ol.mu.Lock()
+	defer ol.mu.Unlock()
+
+	if ol.counts.Symbols[symbol] > 0 {
+		ol.counts.Symbols[symbol]--
+		ol.counts.Total--
+		ol.save(ctx)
+	}
+}
+
+// save writes the counts; called with ol.mu held. A failed write is
+// logged, not returned: the in-memory counts still apply.
+func (ol *orderLimits) save(ctx context.Context) {
+	if ol.path == "" {
+		return
+	}
+	err := func() error {
+		b, err := json.MarshalIndent(ol.counts, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(ol.path), 0o755); err != nil {
+			return err
+		}
+		tmp := ol.path + ".tmp"
+		if err := os.WriteFile(tmp, b, 0o644); err != nil {
+			return err
+		}
+		return os.Rename(tmp, ol.path)
+	}()
+	if err != nil {
+		logger.Warn(ctx, "Failed to save order counts", "path", ol.path, "error", err)
+	}
+}
@@ -2,53 +2,83 @@ package engine
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"llm-trading-bot/internal/interfaces"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/money"
 )
 
 type position struct {
-	qty       int       // Current quantity held
-	avg       float64   // Average entry price
-	stop      float64   // Stop-loss price
-	lastATR   float64   // Last ATR value for stop calculation
-	entryTime time.Time // Time when position was opened (for time-based stops)
+	qty       int          // Current quantity held
+	cost      money.Amount // Cost basis of qty; the average price is derived from it
+	stop      float64      // Stop-loss price
+	lastATR   float64      // Last ATR value for stop calculation
+	entryTime time.Time    // Time when position was opened (for time-based stops)
+
+	stopID     string  // Broker-held stop (GTT) mirroring stop, if any
+	serverStop float64 // Trigger of the broker-held stop
+	serverQty  int     // Quantity covered by the broker-held stop
+
+	hedgeSymbol string // Protective put bought for the position, if any
+	hedgeQty    int
 }
 
+// positionManager guards the map of positions; the fields of a position
+// are only changed by steps of its own symbol, which the engine
+// serializes.
 type positionManager struct {
+	clock     interfaces.Clock
+	mu        sync.RWMutex
 	positions map[string]*position
 }
 
-func newPositionManager() *positionManager {
+func newPositionManager(clk interfaces.Clock) *positionManager {
 	return &positionManager{
+		clock:     clk,
 		positions: make(map[string]*position),
 	}
 }
 
 func (pm *positionManager) get(symbol string) *position {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	return pm.positions[symbol]
 }
 
 func (pm *positionManager) has(symbol string) bool {
-	return pm.positions[symbol] != nil
+	return pm.get(symbol) != nil
+}
+
+// symbols returns the symbols with an open position.
+func (pm *positionManager) symbols() []string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	out := make([]string, 0, len(pm.positions))
+	for sym := range pm.positions {
+		out = append(out, sym)
+	}
+	return out
 }
 
 //
-func (pm *positionManager) addBuy(ctx context.Context, symbol string, qty int, price, atr, stopPrice float64) {
+func (pm *positionManager) addBuy(ctx context.Context, symbol string, qty int, price money.Amount, atr, stopPrice float64) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
 	p := pm.positions[symbol]
 	if p == nil {
 		p = &position{
 			qty:       qty,
-			avg:       price,
+			cost:      price.Mul(qty),
 			stop:      stopPrice,
 			lastATR:   atr,
-			entryTime: time.Now(), // Set entry time for time-based stops
+			entryTime: pm.clock.Now(), // Set entry time for time-based stops
 		}
 		pm.positions[symbol] = p
 	} else {
-		totalCost := p.avg*float64(p.qty) + price*float64(qty)
+		p.cost += price.Mul(qty)
 		p.qty += qty
-		p.avg = totalCost / float64(p.qty)
 		p.lastATR = atr
 
 		if stopPrice > p.stop {
@@ -59,17 +89,25 @@ func (pm *positionManager) addBuy(ctx context.Context, symbol string, qty int, p
 
 //
 //
-func (pm *positionManager) reduceSell(ctx context.Context, symbol string, qty int, price float64) float64 {
+func (pm *positionManager) reduceSell(ctx context.Context, symbol string, qty int, price money.Amount) money.Amount {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
 	p := pm.positions[symbol]
 	if p == nil {
 		logger.Warn(ctx, "Attempted to sell with no position", "symbol", symbol, "qty", qty)
 		return 0
 	}
 
+	if qty > p.qty {
+		qty = p.qty
+	}
+	// The sold shares release their share of the cost basis, so the
+	// realized P&L of every partial sale adds up to proceeds less cost
+	released := p.cost.MulDiv(qty, p.qty)
+	p.cost -= released
 	p.qty -= qty
 
-	realizedPnL := (price - p.avg) * float64(qty)
-
+	realizedPnL := price.Mul(qty) - released
 
 	if p.qty <= 0 {
 		delete(pm.positions, symbol)
@@ -78,14 +116,24 @@ func (pm *positionManager) reduceSell(ctx context.Context, symbol string, qty in
 	return realizedPnL
 }
 
+// avg is the average entry price of the position.
+func (p *position) avg() money.Amount {
+	if p.qty <= 0 {
+		return 0
+	}
+	return p.cost.Div(p.qty)
+}
+
 func (pm *positionManager) close(symbol string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
 	delete(pm.positions, symbol)
 }
 
 //
 //
 func (pm *positionManager) updateTrailingStop(ctx context.Context, symbol string, newStop, atr float64) bool {
-	p := pm.positions[symbol]
+	p := pm.get(symbol)
 	if p == nil || p.qty <= 0 {
 		return false
 	}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/types"
+)
+
+// prioritizer spends a per-poll budget of decider calls on the symbols
+// most worth a look. Each Step records what it saw; PlanTick then ranks
+// the universe by that signal interest and lets only the top K call the
+// decider during the poll. Held symbols always go first so exits are
+// never starved, and ties go to the symbol decided longest ago.
+type prioritizer struct {
+	maxPerTick int // 0: no budget, every symbol is decided
+
+	mu      sync.Mutex
+	seen    map[string]*interest
+	allowed map[string]bool // Nil until the first plan
+}
+
+type interest struct {
+	score   float64
+	smaUp   int // Fast SMA above slow: +1, below: -1, unknown: 0
+	trend   int // Supertrend direction
+	decided time.Time
+}
+
+func newPrioritizer(maxPerTick int) *prioritizer {
+	return &prioritizer{maxPerTick: maxPerTick, seen: make(map[string]*interest)}
+}
+
+// observe scores symbol from the indicators of its latest step.
+func (p *prioritizer) observe(symbol string, inds types.Indicators, price float64) {
+	if p.maxPerTick <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in := p.seen[symbol]
+	if in == nil {
+		in = &interest{}
+		p.seen[symbol] = in
+	}
+
+	score := 0.0
+	if !math.IsNaN(inds.RSI) && (inds.RSI <= 30 || inds.RSI >= 70) {
+		score++
+	}
+	if inds.BB.Upper > 0 && (price >= inds.BB.Upper || price <= inds.BB.Lower) {
+		score++
+	}
+	if inds.Keltner.Upper > 0 && (price >= inds.Keltner.Upper || price <= inds.Keltner.Lower) {
+		score += 0.5
+	}
+	if d := inds.Supertrend.Dir; d != 0 {
+		if in.trend != 0 && d != in.trend {
+			score += 2 // Trend flip
+		}
+		in.trend = d
+	}
+	if up := smaRelation(inds.SMA); up != 0 {
+		if in.smaUp != 0 && up != in.smaUp {
+			score += 2 // Fast/slow crossover
+		}
+		in.smaUp = up
+	}
+	in.score = score
+}
+
+// decided records that symbol was sent to the decider at t.
+func (p *prioritizer) decided(symbol string, t time.Time) {
+	if p.maxPerTick <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if in := p.seen[symbol]; in != nil {
+		in.decided = t
+	}
+}
+
+// plan picks the symbols that may call the decider until the next plan.
+// Symbols never stepped rank as mildly interesting so they get a first
+// look.
+func (p *prioritizer) plan(symbols []string, held func(string) bool) []string {
+	if p.maxPerTick <= 0 {
+		return symbols
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	type ranked struct {
+		symbol  string
+		held    bool
+		score   float64
+		decided time.Time
+	}
+	rs := make([]ranked, 0, len(symbols))
+	for _, sym := range symbols {
+		r := ranked{symbol: sym, held: held(sym), score: 1}
+		if in := p.seen[sym]; in != nil {
+			r.score, r.decided = in.score, in.decided
+		}
+		rs = append(rs, r)
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.held != b.held {
+			return a.held
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return a.decided.Before(b.decided)
+	})
+
+	p.allowed = make(map[string]bool, p.maxPerTick)
+	out := make([]string, 0, p.maxPerTick)
+	for _, r := range rs {
+		if len(out) == p.maxPerTick {
+			break
+		}
+		p.allowed[r.symbol] = true
+		out = append(out, r.symbol)
+	}
+	return out
+}
+
+// grant lets symbol call the decider until the next plan, for steps
+// triggered by a tick crossing a level.
+func (p *prioritizer) grant(symbol string) {
+	if p.maxPerTick <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.allowed != nil {
+		p.allowed[symbol] = true
+	}
+}
+
+// allow reports whether symbol may call the decider now. Before the
+// first plan everything is allowed.
+func (p *prioritizer) allow(symbol string) bool {
+	if p.maxPerTick <= 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.allowed == nil || p.allowed[symbol]
+}
+
+// smaRelation compares the two shortest SMA windows.
+func smaRelation(sma map[int]float64) int {
+	windows := make([]int, 0, len(sma))
+	for w, v := range sma {
+		if !math.IsNaN(v) && v > 0 {
+			windows = append(windows, w)
+		}
+	}
+	if len(windows) < 2 {
+		return 0
+	}
+	sort.Ints(windows)
+	switch fast, slow := sma[windows[0]], sma[windows[1]]; {
+	case fast > slow:
+		return 1
+	case fast < slow:
+		return -1
+	}
+	return 0
+}
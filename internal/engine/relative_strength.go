@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"context"
+	"math"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/ta"
+	"llm-trading-bot/internal/types"
+)
+
+// relativeStrength compares the symbol with its benchmark index over each
+// configured window. Bars are matched by timestamp so a missing index bar
+// does not shift the comparison. Returns nil when no benchmark is set or
+// its candles are unavailable.
+func (e *Engine) relativeStrength(ctx context.Context, symbol string, candles []types.Candle) map[int]float64 {
+	if e.benchmark == nil || e.benchmark.IndexFor(symbol) == symbol {
+		return nil
+	}
+
+	bench, err := e.benchmark.Candles(ctx, symbol, len(candles))
+	if err != nil {
+		logger.Warn(ctx, "Benchmark candles unavailable", "symbol", symbol, "index", e.benchmark.IndexFor(symbol), "error", err)
+		return nil
+	}
+
+	byTs := make(map[int64]float64, len(bench))
+	for _, c := range bench {
+		byTs[c.Ts] = c.Close
+	}
+	closes := make([]float64, 0, len(candles))
+	benchCloses := make([]float64, 0, len(candles))
+	for _, c := range candles {
+		if bc, ok := byTs[c.Ts]; ok {
+			closes = append(closes, c.Close)
+			benchCloses = append(benchCloses, bc)
+		}
+	}
+
+	rs := make(map[int]float64, len(e.cfg.Benchmark.RSWindows))
+	for _, w := range e.cfg.Benchmark.RSWindows {
+		if v := ta.RelativeStrength(closes, benchCloses, w); !math.IsNaN(v) {
+			rs[w] = v
+		}
+	}
+	if len(rs) == 0 {
+		return nil
+	}
+	return rs
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
 )
 
 type riskManager struct {
 	accountValue float64
+	funds        types.Funds // Last funds snapshot reported by the broker
 }
 
 func newRiskManager() *riskManager {
@@ -56,3 +58,36 @@ func (rm *riskManager) setAccountValue(value float64) {
 func (rm *riskManager) getAccountValue() float64 {
 	return rm.accountValue
 }
+
+func (rm *riskManager) updateFunds(funds types.Funds) {
+	rm.funds = funds
+	if funds.Net > 0 {
+		rm.accountValue = funds.Net
+	}
+}
+
+func (rm *riskManager) checkFunds(ctx context.Context, symbol string, price float64, qty int) bool {
+	required := rm.calculateExposure(price, qty)
+	if required <= rm.funds.Available {
+		return true
+	}
+
+	logger.Warn(ctx, "Trade blocked by insufficient funds",
+		"symbol", symbol,
+		"event", "TRADE_BLOCKED_FUNDS",
+		"qty", qty,
+		"price", price,
+		"required", required,
+		"available", rm.funds.Available,
+		"margin_utilization_pct", rm.marginUtilization(),
+	)
+	return false
+}
+
+func (rm *riskManager) marginUtilization() float64 {
+	total := rm.funds.Available + rm.funds.Utilised
+	if total <= 0 {
+		return 0
+	}
+	return rm.funds.Utilised / total * 100.0
+}
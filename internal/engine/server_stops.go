@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+// serverStops mirrors each position's stop level to a broker-held stop
+// (GTT) so the position stays protected while the bot is down. The
+// in-process stop check still runs; it cancels the broker stop before
+// selling so the position is never sold twice.
+type serverStops struct {
+	broker   interfaces.StopBroker
+	limitPct float64 // Limit price offset below the trigger
+	minTick  float64
+}
+
+func newServerStops(brk interfaces.Broker, enabled bool, limitPct, minTick float64) *serverStops {
+	sb, ok := brk.(interfaces.StopBroker)
+	if !enabled || !ok {
+		return nil
+	}
+	return &serverStops{broker: sb, limitPct: limitPct, minTick: minTick}
+}
+
+// sync places or amends the broker stop to match pos. Failures are logged
+// and retried on the next sync; the in-process stop remains the fallback.
+func (ss *serverStops) sync(ctx context.Context, symbol string, pos *position, ltp float64) {
+	if ss == nil || pos == nil || pos.qty <= 0 || pos.stop <= 0 {
+		return
+	}
+	if pos.stopID != "" && pos.serverStop == pos.stop && pos.serverQty == pos.qty {
+		return
+	}
+
+	req := types.StopReq{
+		Symbol:       symbol,
+		Qty:          pos.qty,
+		TriggerPrice: pos.stop,
+		LimitPrice:   roundToTick(pos.stop*(1-ss.limitPct/100.0), ss.minTick),
+		LastPrice:    ltp,
+	}
+
+	if pos.stopID == "" {
+		id, err := ss.broker.PlaceStop(ctx, req)
+		if err != nil {
+			logger.Warn(ctx, "Server-side stop not placed - relying on in-process stop",
+				"symbol", symbol,
+				"error", err,
+			)
+			return
+		}
+		pos.stopID = id
+	} else if err := ss.broker.ModifyStop(ctx, pos.stopID, req); err != nil {
+		logger.Warn(ctx, "Server-side stop not updated",
+			"symbol", symbol,
+			"stop_id", pos.stopID,
+			"error", err,
+		)
+		return
+	}
+
+	pos.serverStop, pos.serverQty = pos.stop, pos.qty
+}
+
+// cancel removes the broker stop for pos. It reports triggered when the
+// broker already fired the stop, meaning the position is gone at the broker.
+// When the cancel fails the stop may still be live, so it is kept on pos
+// and the error returned: the caller must not sell the shares.
+func (ss *serverStops) cancel(ctx context.Context, symbol string, pos *position) (triggered bool, err error) {
+	if ss == nil || pos == nil || pos.stopID == "" {
+		return false, nil
+	}
+
+	err = ss.broker.CancelStop(ctx, pos.stopID)
+	if errors.Is(err, interfaces.ErrStopTriggered) {
+		logger.Warn(ctx, "Server-side stop already triggered",
+			"symbol", symbol,
+			"event", "SERVER_STOP_TRIGGERED",
+			"stop_id", pos.stopID,
+			"stop_price", pos.serverStop,
+		)
+		return true, nil
+	}
+	if err != nil {
+		logger.Warn(ctx, "Failed to cancel server-side stop - not selling", "symbol", symbol, "stop_id", pos.stopID, "error", err)
+		return false, fmt.Errorf("cancel server-side stop %s: %w", pos.stopID, err)
+	}
+
+	pos.stopID, pos.serverStop, pos.serverQty = "", 0, 0
+	return false, nil
+}
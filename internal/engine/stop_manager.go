@@ -5,10 +5,13 @@ import (
 	"strings"
 	"time"
 
+	"llm-trading-bot/internal/interfaces"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/money"
 )
 
 type stopManager struct {
+	clock       interfaces.Clock
 	mode        string  // "PCT", "ATR", "VOLATILITY", "TIME"
 	pct         float64 // Stop-loss percentage (for PCT mode)
 	atrMult     float64 // ATR multiplier (for ATR mode)
@@ -19,8 +22,9 @@ type stopManager struct {
 	stopLevels map[string]float64
 }
 
-func newStopManager(mode string, pct, atrMult, minTick float64, trailing bool) *stopManager {
+func newStopManager(clk interfaces.Clock, mode string, pct, atrMult, minTick float64, trailing bool) *stopManager {
 	return &stopManager{
+		clock:       clk,
 		mode:        strings.ToUpper(mode),
 		pct:         pct,
 		atrMult:     atrMult,
@@ -73,7 +77,7 @@ func (sm *stopManager) checkStopLoss(ctx context.Context, symbol string, current
 	}
 
 	if currentPrice <= stopPrice {
-		unrealizedLoss := (currentPrice - pos.avg) * float64(pos.qty)
+		unrealizedLoss := (money.FromFloat(currentPrice) - pos.avg()).Mul(pos.qty)
 
 		logger.Warn(ctx, "Stop loss triggered",
 			"symbol", symbol,
@@ -81,7 +85,7 @@ func (sm *stopManager) checkStopLoss(ctx context.Context, symbol string, current
 			"current_price", currentPrice,
 			"stop_price", stopPrice,
 			"position_qty", pos.qty,
-			"position_avg", pos.avg,
+			"position_avg", pos.avg(),
 			"unrealized_loss", unrealizedLoss,
 		)
 
@@ -102,7 +106,7 @@ func (sm *stopManager) checkTimeBasedStop(ctx context.Context, symbol string, po
 		return false
 	}
 
-	holdDuration := time.Since(pos.entryTime)
+	holdDuration := sm.clock.Now().Sub(pos.entryTime)
 	maxDuration := time.Duration(sm.maxHoldTime) * time.Second
 
 	if holdDuration > maxDuration {
@@ -112,7 +116,7 @@ func (sm *stopManager) checkTimeBasedStop(ctx context.Context, symbol string, po
 			"hold_duration_seconds", holdDuration.Seconds(),
 			"max_hold_seconds", sm.maxHoldTime,
 			"position_qty", pos.qty,
-			"position_avg", pos.avg,
+			"position_avg", pos.avg(),
 			"entry_time", pos.entryTime,
 		)
 		return true
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"llm-trading-bot/internal/store"
+	"llm-trading-bot/internal/surveillance"
+)
+
+// NewSurveillance builds the monitor from surveillance.sources; nil when
+// neither sources nor blocked measures are configured.
+func NewSurveillance(cfg *store.Config) *surveillance.Monitor {
+	if len(cfg.Surveillance.Sources) == 0 && len(cfg.Surveillance.Block) == 0 {
+		return nil
+	}
+	var sources []surveillance.Source
+	for _, name := range cfg.Surveillance.Sources {
+		switch name {
+		case "file":
+			sources = append(sources, surveillance.File{Path: cfg.Surveillance.File})
+		case "nse":
+			sources = append(sources, surveillance.NSE{})
+		}
+	}
+	return surveillance.NewMonitor(time.Duration(cfg.Surveillance.RefreshHours)*time.Hour, sources...)
+}
+
+// surveillanceListings returns the measures symbol is under.
+func (e *Engine) surveillanceListings(ctx context.Context, symbol string) []surveillance.Listing {
+	if e.measures == nil {
+		return nil
+	}
+	return e.measures.Get(ctx, symbol)
+}
+
+// surveillanceBlock returns the first listing of symbol under a measure in
+// surveillance.block.
+func (e *Engine) surveillanceBlock(ctx context.Context, symbol string) (surveillance.Listing, bool) {
+	for _, l := range e.surveillanceListings(ctx, symbol) {
+		for _, m := range e.cfg.Surveillance.Block {
+			if l.Measure == m {
+				return l, true
+			}
+		}
+	}
+	return surveillance.Listing{}, false
+}
@@ -0,0 +1,27 @@
+package engine
+
+import "sync"
+
+// symbolLocks hands out one mutex per symbol so a symbol's position is
+// only ever changed by one step at a time.
+type symbolLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks symbol and returns the function that unlocks it.
+func (sl *symbolLocks) lock(symbol string) func() {
+	sl.mu.Lock()
+	if sl.locks == nil {
+		sl.locks = make(map[string]*sync.Mutex)
+	}
+	m := sl.locks[symbol]
+	if m == nil {
+		m = &sync.Mutex{}
+		sl.locks[symbol] = m
+	}
+	sl.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+)
+
+type triggerLevels struct {
+	bbUpper   float64   // Bollinger upper band from the last evaluation
+	bbLower   float64   // Bollinger lower band from the last evaluation
+	lastPrice float64   // Last tick price seen for the symbol
+	lastEval  time.Time // Time of the last Step for the symbol
+}
+
+type tickTrigger struct {
+	clock            interfaces.Clock
+	stopProximityPct float64       // Evaluate when price is within this % of the stop
+	minInterval      time.Duration // Debounce between evaluations per symbol
+
+	mu     sync.Mutex
+	levels map[string]*triggerLevels
+}
+
+func newTickTrigger(clk interfaces.Clock, stopProximityPct float64, minIntervalSeconds int) *tickTrigger {
+	return &tickTrigger{
+		clock:            clk,
+		stopProximityPct: stopProximityPct,
+		minInterval:      time.Duration(minIntervalSeconds) * time.Second,
+		levels:           make(map[string]*triggerLevels),
+	}
+}
+
+func (tt *tickTrigger) updateLevels(symbol string, inds types.Indicators, price float64) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.levels[symbol] = &triggerLevels{
+		bbUpper:   inds.BB.Upper,
+		bbLower:   inds.BB.Lower,
+		lastPrice: price,
+		lastEval:  tt.clock.Now(),
+	}
+}
+
+// shouldEvaluate reports whether a tick crossed a level worth acting on
+// before the next poll. Symbols without a prior Step have no levels yet
+// and are left to the polling loop.
+func (tt *tickTrigger) shouldEvaluate(symbol string, price float64, pos *position) (bool, string) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	lv := tt.levels[symbol]
+	if lv == nil {
+		return false, ""
+	}
+
+	prev := lv.lastPrice
+	lv.lastPrice = price
+
+	if tt.clock.Now().Sub(lv.lastEval) < tt.minInterval {
+		return false, ""
+	}
+
+	trigger := ""
+	switch {
+	case pos != nil && pos.qty > 0 && pos.stop > 0 && price <= pos.stop*(1.0+tt.stopProximityPct/100.0):
+		trigger = "STOP_PROXIMITY"
+	case prev <= lv.bbUpper && price > lv.bbUpper:
+		trigger = "BB_UPPER_BREAKOUT"
+	case prev >= lv.bbLower && price < lv.bbLower:
+		trigger = "BB_LOWER_BREAKDOWN"
+	}
+
+	if trigger == "" {
+		return false, ""
+	}
+
+	lv.lastEval = tt.clock.Now()
+	return true, trigger
+}
@@ -1,9 +1,8 @@
 package eod
 
 import (
-	"bufio"
+	"context"
 	"encoding/csv"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -12,47 +11,61 @@ import (
 	"strconv"
 	"time"
 
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
 )
 
-type eodSummarizer struct{}
-
+type eodSummarizer struct {
+	params Params
+	clock  interfaces.Clock
+}
 
 func (es *eodSummarizer) SummarizeDay(t time.Time) (string, error) {
-	inPath := todaysTradeFile(t)
+	orders, err := tradelog.ReadDay(t)
+	if err != nil {
+		return "", err
+	}
+	orders = tradelog.Executions(orders)
 
-	if _, err := os.Stat(inPath); err != nil {
+	aggs := es.aggregate(orders)
+	if len(aggs) == 0 {
 		return "", nil // No trades for this day
 	}
 
-	f, err := os.Open(inPath)
-	if err != nil {
+	outPath := eodCSVPath(t)
+	if err := es.writeCSVSummary(outPath, aggs); err != nil {
 		return "", err
 	}
-	defer f.Close()
 
-	aggs, err := es.parseTradeLog(f)
+	decisions, err := tradelog.ReadDecisions(t)
 	if err != nil {
 		return "", err
 	}
-
-	if len(aggs) == 0 {
-		return "", nil
+	report := buildReport(t, orders, decisions, es.params)
+	if es.params.Capital > 0 {
+		points, err := recordEquity(report, t, es.params)
+		if err != nil {
+			return "", err
+		}
+		perf := Measure(points, es.params)
+		report.Performance = &perf
 	}
-
-	outPath := eodCSVPath(t)
-	if err := es.writeCSVSummary(outPath, aggs); err != nil {
+	if err := writeReport(eodReportPath(t), report); err != nil {
 		return "", err
 	}
+	notify.Send(context.Background(), notify.EventEOD, "", report.Text())
 
 	return outPath, nil
 }
 
 func (es *eodSummarizer) SummarizeToday() (string, error) {
-	return es.SummarizeDay(istNow())
+	return es.SummarizeDay(es.clock.Now().In(timeutil.IST))
 }
 
 func (es *eodSummarizer) ShouldRunNow() (bool, string) {
-	now := istNow()
+	now := es.clock.Now().In(timeutil.IST)
 	cutoff := marketCloseTime(now)
 	outPath := eodCSVPath(now)
 
@@ -65,37 +78,31 @@ func (es *eodSummarizer) ShouldRunNow() (bool, string) {
 	return false, outPath
 }
 
-func (es *eodSummarizer) parseTradeLog(f *os.File) (map[string]*aggRow, error) {
+func (es *eodSummarizer) aggregate(orders []tradelog.Entry) map[string]*aggRow {
 	aggs := make(map[string]*aggRow)
-	scanner := bufio.NewScanner(f)
 
-	for scanner.Scan() {
-		var tl tradeLine
-		if err := json.Unmarshal([]byte(scanner.Text()), &tl); err != nil {
-			continue // Skip malformed lines
+	for _, tl := range orders {
+		row := aggs[tl.PositionKey()]
+		if row == nil {
+			row = &aggRow{Account: tl.Account, Symbol: tl.Symbol}
+			aggs[tl.PositionKey()] = row
 		}
 
-		row := aggs[tl.Symbol]
-		if row == nil {
-			row = &aggRow{Symbol: tl.Symbol}
-			aggs[tl.Symbol] = row
+		if util, ok := tl.Extra["margin_utilization_pct"].(float64); ok && util > row.MaxMarginUtilPct {
+			row.MaxMarginUtilPct = util
 		}
 
 		if tl.Side == "BUY" {
 			row.BuyQty += tl.Qty
-			row.BuyValue += float64(tl.Qty) * tl.Price
+			row.BuyValue += tl.Price.Mul(tl.Qty).Float()
 		}
 		if tl.Side == "SELL" {
 			row.SellQty += tl.Qty
-			row.SellValue += float64(tl.Qty) * tl.Price
+			row.SellValue += tl.Price.Mul(tl.Qty).Float()
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return aggs, nil
+	return aggs
 }
 
 func (es *eodSummarizer) writeCSVSummary(outPath string, aggs map[string]*aggRow) error {
@@ -112,21 +119,21 @@ func (es *eodSummarizer) writeCSVSummary(outPath string, aggs map[string]*aggRow
 	w := csv.NewWriter(out)
 	defer w.Flush()
 
-	headers := []string{"symbol", "buy_qty", "buy_avg", "sell_qty", "sell_avg", "realized_pnl", "gross_buy_value", "gross_sell_value"}
+	headers := []string{"symbol", "buy_qty", "buy_avg", "sell_qty", "sell_avg", "realized_pnl", "gross_buy_value", "gross_sell_value", "max_margin_util_pct", "account"}
 	if err := w.Write(headers); err != nil {
 		return err
 	}
 
-	symbols := make([]string, 0, len(aggs))
-	for symbol := range aggs {
-		symbols = append(symbols, symbol)
+	keys := make([]string, 0, len(aggs))
+	for key := range aggs {
+		keys = append(keys, key)
 	}
-	sort.Strings(symbols)
+	sort.Strings(keys)
 
-	var totalBuy, totalSell, totalPnL float64
+	var totalBuy, totalSell, totalPnL, maxMarginUtil float64
 
-	for _, symbol := range symbols {
-		row := aggs[symbol]
+	for _, key := range keys {
+		row := aggs[key]
 
 		var buyAvg, sellAvg float64
 		if row.BuyQty > 0 {
@@ -151,6 +158,8 @@ func (es *eodSummarizer) writeCSVSummary(outPath string, aggs map[string]*aggRow
 			fmt.Sprintf("%.2f", row.RealizedPnL),
 			fmt.Sprintf("%.2f", row.BuyValue),
 			fmt.Sprintf("%.2f", row.SellValue),
+			fmt.Sprintf("%.2f", row.MaxMarginUtilPct),
+			row.Account,
 		}
 
 		if err := w.Write(record); err != nil {
@@ -160,6 +169,9 @@ func (es *eodSummarizer) writeCSVSummary(outPath string, aggs map[string]*aggRow
 		totalBuy += row.BuyValue
 		totalSell += row.SellValue
 		totalPnL += row.RealizedPnL
+		if row.MaxMarginUtilPct > maxMarginUtil {
+			maxMarginUtil = row.MaxMarginUtilPct
+		}
 	}
 
 	totalRow := []string{
@@ -171,6 +183,8 @@ func (es *eodSummarizer) writeCSVSummary(outPath string, aggs map[string]*aggRow
 		fmt.Sprintf("%.2f", totalPnL),
 		fmt.Sprintf("%.2f", totalBuy),
 		fmt.Sprintf("%.2f", totalSell),
+		fmt.Sprintf("%.2f", maxMarginUtil),
+		"",
 	}
 
 	if err := w.Write(totalRow); err != nil {
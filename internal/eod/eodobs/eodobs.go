@@ -6,6 +6,7 @@ import (
 
 	"llm-trading-bot/internal/interfaces"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/timeutil"
 	"llm-trading-bot/internal/trace"
 )
 
@@ -27,26 +28,26 @@ func (oes *observableEodSummarizer) SummarizeDay(t time.Time) (string, error) {
 	defer span.End()
 
 	logger.InfoSkip(ctx, 1, "Starting EOD summary generation",
-		"date", t.Format("2006-01-02"),
+		"date", timeutil.DateKey(t),
 	)
 
 	csvPath, err := oes.summarizer.SummarizeDay(t)
 	if err != nil {
 		logger.ErrorWithErrSkip(ctx, 1, "EOD summary generation failed", err,
-			"date", t.Format("2006-01-02"),
+			"date", timeutil.DateKey(t),
 		)
 		return "", err
 	}
 
 	if csvPath == "" {
 		logger.InfoSkip(ctx, 1, "No trades found for EOD summary",
-			"date", t.Format("2006-01-02"),
+			"date", timeutil.DateKey(t),
 		)
 		return "", nil
 	}
 
 	logger.InfoSkip(ctx, 1, "EOD summary generated successfully",
-		"date", t.Format("2006-01-02"),
+		"date", timeutil.DateKey(t),
 		"csv_path", csvPath,
 	)
 
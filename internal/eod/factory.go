@@ -3,17 +3,18 @@ package eod
 import (
 	"time"
 
+	"llm-trading-bot/internal/clock"
 	"llm-trading-bot/internal/interfaces"
 )
 
-var defaultSummarizer interfaces.EodSummarizer = &eodSummarizer{}
+var defaultSummarizer interfaces.EodSummarizer = &eodSummarizer{clock: clock.Real{}}
 
 func SetDefaultSummarizer(summarizer interfaces.EodSummarizer) {
 	defaultSummarizer = summarizer
 }
 
-func NewSummarizer() interfaces.EodSummarizer {
-	return &eodSummarizer{}
+func NewSummarizer(p Params, c interfaces.Clock) interfaces.EodSummarizer {
+	return &eodSummarizer{params: p, clock: c}
 }
 
 func SummarizeDay(t time.Time) (string, error) {
@@ -0,0 +1,217 @@
+package eod
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tradingDays annualizes daily ratios.
+const tradingDays = 252
+
+// EquityPoint is one day of the equity curve: the capital plus every
+// day's net P&L so far.
+type EquityPoint struct {
+	Date        string
+	Net         float64 // The day's net P&L
+	Equity      float64
+	ExposurePct float64 // Open position value at the close, % of equity
+	Benchmark   float64 `json:",omitempty"` // Benchmark index close; 0 when unknown
+}
+
+// Performance is measured over the last Window points of the curve.
+// Ratios are annualized from daily returns net of the risk-free rate.
+type Performance struct {
+	Window             int
+	Days               int
+	Equity             float64
+	ReturnPct          float64
+	Benchmark          string  `json:",omitempty"`
+	BenchmarkReturnPct float64 `json:",omitempty"`
+	ExcessReturnPct    float64 `json:",omitempty"` // Return less the benchmark's
+	Sharpe             float64
+	Sortino            float64
+	MaxDrawdownPct     float64 // Largest peak-to-trough fall of equity
+	ExposurePct        float64 // Average close exposure
+}
+
+// equityPath is the curve, one JSON point per line in date order.
+func equityPath() string {
+	return filepath.Join(logDir(), "eod", "equity.jsonl")
+}
+
+// LoadEquity reads the equity curve; a missing file is an empty curve.
+func LoadEquity() ([]EquityPoint, error) {
+	f, err := os.Open(equityPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var points []EquityPoint
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var p EquityPoint
+		if json.Unmarshal(sc.Bytes(), &p) == nil {
+			points = append(points, p)
+		}
+	}
+	return points, sc.Err()
+}
+
+// recordEquity adds the report's day to the curve, replacing the day if it
+// was summarized before, and returns the updated curve.
+func recordEquity(r *Report, t time.Time, p Params) ([]EquityPoint, error) {
+	points, err := LoadEquity()
+	if err != nil {
+		return nil, err
+	}
+	kept := points[:0]
+	for _, pt := range points {
+		if pt.Date != r.Date {
+			kept = append(kept, pt)
+		}
+	}
+	point := EquityPoint{Date: r.Date, Net: r.Net}
+	if p.BenchmarkClose != nil {
+		point.Benchmark, _ = p.BenchmarkClose(t)
+	}
+	points = append(kept, point)
+	sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+
+	var open float64
+	for _, s := range r.Symbols {
+		open += float64(s.OpenQty) * s.Mark
+	}
+	// Equity is rebuilt from the capital so a replaced day carries through
+	equity := p.Capital
+	for i := range points {
+		equity += points[i].Net
+		points[i].Equity = equity
+		if points[i].Date == r.Date && equity > 0 {
+			points[i].ExposurePct = open / equity * 100
+		}
+	}
+
+	if err := writeEquity(points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func writeEquity(points []EquityPoint) error {
+	path := equityPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	for _, pt := range points {
+		b, err := json.Marshal(pt)
+		if err != nil {
+			return err
+		}
+		sb.Write(b)
+		sb.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Measure computes performance over the last p.Window points of the
+// curve, starting from the equity before the first of them.
+func Measure(points []EquityPoint, p Params) Performance {
+	perf := Performance{Window: p.Window, Benchmark: p.Benchmark}
+	if p.Window > 0 && len(points) > p.Window {
+		points = points[len(points)-p.Window:]
+	}
+	perf.Days = len(points)
+	if len(points) == 0 {
+		return perf
+	}
+
+	start := points[0].Equity - points[0].Net
+	perf.Equity = points[len(points)-1].Equity
+	if start > 0 {
+		perf.ReturnPct = (perf.Equity/start - 1) * 100
+	}
+
+	rf := p.RiskFreePct / 100 / tradingDays
+	var excess []float64
+	prev, peak := start, start
+	var exposure float64
+	for _, pt := range points {
+		if prev > 0 {
+			excess = append(excess, pt.Net/prev-rf)
+		}
+		prev = pt.Equity
+		if pt.Equity > peak {
+			peak = pt.Equity
+		}
+		if peak > 0 {
+			if dd := (peak - pt.Equity) / peak * 100; dd > perf.MaxDrawdownPct {
+				perf.MaxDrawdownPct = dd
+			}
+		}
+		exposure += pt.ExposurePct
+	}
+	perf.ExposurePct = exposure / float64(len(points))
+	perf.Sharpe, perf.Sortino = ratios(excess)
+
+	var first, last float64
+	for _, pt := range points {
+		if pt.Benchmark > 0 {
+			if first == 0 {
+				first = pt.Benchmark
+			}
+			last = pt.Benchmark
+		}
+	}
+	if first > 0 {
+		perf.BenchmarkReturnPct = (last/first - 1) * 100
+		perf.ExcessReturnPct = perf.ReturnPct - perf.BenchmarkReturnPct
+	}
+	return perf
+}
+
+// ratios returns the annualized Sharpe and Sortino ratios of daily excess
+// returns; both are 0 with fewer than two returns or no variation.
+func ratios(excess []float64) (sharpe, sortino float64) {
+	if len(excess) < 2 {
+		return 0, 0
+	}
+	var mean float64
+	for _, r := range excess {
+		mean += r
+	}
+	mean /= float64(len(excess))
+
+	var variance, downside float64
+	for _, r := range excess {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	sd := math.Sqrt(variance / float64(len(excess)-1))
+	dd := math.Sqrt(downside / float64(len(excess)))
+	annual := math.Sqrt(tradingDays)
+	if sd > 0 {
+		sharpe = mean / sd * annual
+	}
+	if dd > 0 {
+		sortino = mean / dd * annual
+	}
+	return sharpe, sortino
+}
@@ -0,0 +1,270 @@
+package eod
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// Params prices the costs that the trade log does not record.
+type Params struct {
+	FeeBps         float64 // Brokerage and charges per order, in bps of turnover
+	LLMCostPerCall float64 // Estimated cost of one logged decision
+
+	// The equity curve is kept when Capital is set
+	Capital        float64
+	RiskFreePct    float64 // Annual risk-free rate subtracted from daily returns
+	Window         int     // Days measured; 0 = the whole curve
+	Benchmark      string
+	BenchmarkClose func(day time.Time) (float64, bool) // Benchmark close on the IST day; nil when none
+}
+
+// Report is the day's P&L, written next to the CSV as JSON.
+type Report struct {
+	Date        string
+	Symbols     []SymbolReport
+	Realized    float64
+	Unrealized  float64
+	Fees        float64
+	SpreadCost  float64 // Fill price vs quote mid; already inside the P&L
+	Net         float64 // Realized + unrealized - fees - LLM cost
+	Wins        int
+	Losses      int
+	MaxDrawdown float64 // Largest peak-to-trough drop of intraday net P&L
+	LLMCalls    int
+	LLMCost     float64
+	Accounts    []AccountReport `json:",omitempty"` // Per-account totals when several accounts trade
+	Performance *Performance    `json:",omitempty"` // Rolling statistics when the equity curve is kept
+}
+
+type SymbolReport struct {
+	Account    string `json:",omitempty"`
+	Symbol     string
+	BuyQty     int
+	SellQty    int
+	OpenQty    int
+	AvgCost    float64 // Average cost of the open quantity
+	Mark       float64 // Last order or decision price of the day
+	Realized   float64
+	Unrealized float64
+	Fees       float64
+	Wins       int
+	Losses     int
+}
+
+// AccountReport totals one account's symbols. The LLM cost is shared and
+// only counted in the report's Net.
+type AccountReport struct {
+	Account    string
+	Realized   float64
+	Unrealized float64
+	Fees       float64
+	Net        float64 // Realized + unrealized - fees
+	Wins       int
+	Losses     int
+}
+
+// position is an average-cost book used to replay the day.
+type position struct {
+	rep  *SymbolReport
+	qty  int
+	cost money.Amount // Cost basis of qty
+}
+
+type event struct {
+	time     string
+	order    *tradelog.Entry
+	decision *tradelog.DecisionEntry
+}
+
+// buildReport replays the day's orders and decisions in time order,
+// marking open positions at the latest price seen for their symbol. Sells
+// of quantity bought on an earlier day have no cost basis and realize
+// nothing.
+func buildReport(t time.Time, orders []tradelog.Entry, decisions []tradelog.DecisionEntry, p Params) *Report {
+	r := &Report{Date: timeutil.DateKey(t), LLMCalls: len(decisions)}
+	r.LLMCost = float64(r.LLMCalls) * p.LLMCostPerCall
+
+	// Decisions first so an order's fill price wins over the decision
+	// logged in the same second
+	events := make([]event, 0, len(orders)+len(decisions))
+	for i := range decisions {
+		events = append(events, event{time: decisions[i].Time, decision: &decisions[i]})
+	}
+	for i := range orders {
+		events = append(events, event{time: orders[i].Time, order: &orders[i]})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].time < events[j].time })
+
+	// One position per symbol and account; a symbol's price marks it in
+	// every account
+	book := make(map[string]*position)
+	bySymbol := make(map[string][]*position)
+	get := func(e *tradelog.Entry) *position {
+		pos := book[e.PositionKey()]
+		if pos == nil {
+			pos = &position{rep: &SymbolReport{Account: e.Account, Symbol: e.Symbol}}
+			book[e.PositionKey()] = pos
+			bySymbol[e.Symbol] = append(bySymbol[e.Symbol], pos)
+		}
+		return pos
+	}
+
+	var peak float64
+	for _, ev := range events {
+		if d := ev.decision; d != nil {
+			if d.Price > 0 {
+				for _, pos := range bySymbol[d.Symbol] {
+					pos.rep.Mark = d.Price
+				}
+			}
+		} else {
+			applyOrder(get(ev.order), ev.order, p.FeeBps)
+			r.SpreadCost += ev.order.SpreadCost.Float()
+		}
+
+		var equity float64
+		for _, pos := range book {
+			equity += pos.rep.Realized - pos.rep.Fees + float64(pos.qty)*pos.rep.Mark - pos.cost.Float()
+		}
+		if equity > peak {
+			peak = equity
+		}
+		if dd := peak - equity; dd > r.MaxDrawdown {
+			r.MaxDrawdown = dd
+		}
+	}
+
+	for _, tr := range journal.Build(orders) {
+		if !tr.Closed() {
+			continue
+		}
+		rep := get(&tr.Entry).rep
+		if tr.PnL() > 0 {
+			rep.Wins++
+			r.Wins++
+		} else {
+			rep.Losses++
+			r.Losses++
+		}
+	}
+
+	for _, pos := range book {
+		pos.rep.OpenQty = pos.qty
+		if pos.qty > 0 {
+			pos.rep.AvgCost = pos.cost.Div(pos.qty).Float()
+			pos.rep.Unrealized = float64(pos.qty)*pos.rep.Mark - pos.cost.Float()
+		}
+		r.Symbols = append(r.Symbols, *pos.rep)
+		r.Realized += pos.rep.Realized
+		r.Unrealized += pos.rep.Unrealized
+		r.Fees += pos.rep.Fees
+	}
+	sort.Slice(r.Symbols, func(i, j int) bool {
+		if r.Symbols[i].Account != r.Symbols[j].Account {
+			return r.Symbols[i].Account < r.Symbols[j].Account
+		}
+		return r.Symbols[i].Symbol < r.Symbols[j].Symbol
+	})
+	r.Net = r.Realized + r.Unrealized - r.Fees - r.LLMCost
+	r.Accounts = accountTotals(r.Symbols)
+
+	return r
+}
+
+// accountTotals sums the symbols of each account, or returns nil when the
+// orders carry no account.
+func accountTotals(symbols []SymbolReport) []AccountReport {
+	var out []AccountReport
+	for _, s := range symbols {
+		if s.Account == "" {
+			continue
+		}
+		if len(out) == 0 || out[len(out)-1].Account != s.Account {
+			out = append(out, AccountReport{Account: s.Account})
+		}
+		a := &out[len(out)-1]
+		a.Realized += s.Realized
+		a.Unrealized += s.Unrealized
+		a.Fees += s.Fees
+		a.Net += s.Realized + s.Unrealized - s.Fees
+		a.Wins += s.Wins
+		a.Losses += s.Losses
+	}
+	return out
+}
+
+func applyOrder(pos *position, e *tradelog.Entry, feeBps float64) {
+	value := e.Price.Mul(e.Qty)
+	pos.rep.Fees += value.Float() * feeBps / 10000.0
+	pos.rep.Mark = e.Price.Float()
+
+	switch e.Side {
+	case "BUY":
+		pos.rep.BuyQty += e.Qty
+		pos.cost += value
+		pos.qty += e.Qty
+	case "SELL":
+		pos.rep.SellQty += e.Qty
+		matched := e.Qty
+		if matched > pos.qty {
+			matched = pos.qty
+		}
+		released := pos.cost.MulDiv(matched, pos.qty)
+		pos.rep.Realized += (e.Price.Mul(matched) - released).Float()
+		pos.cost -= released
+		pos.qty -= matched
+	}
+}
+
+func writeReport(path string, r *Report) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Text renders the report for chat notifications, with amounts in
+// Indian digit grouping.
+func (r *Report) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Day %s: net P&L %s\n", r.Date, money.Signed(r.Net))
+	fmt.Fprintf(&sb, "Realized %s | Unrealized %s | Fees %s | LLM %s (%d calls)\n",
+		money.Signed(r.Realized), money.Signed(r.Unrealized), money.Format(r.Fees), money.Format(r.LLMCost), r.LLMCalls)
+	fmt.Fprintf(&sb, "Closed trades: %d won, %d lost | Max drawdown %s", r.Wins, r.Losses, money.Format(r.MaxDrawdown))
+	if p := r.Performance; p != nil {
+		fmt.Fprintf(&sb, "\nLast %d days: equity %s (%+.2f%%) | Sharpe %.2f | Sortino %.2f | Max DD %.2f%% | Exposure %.0f%%",
+			p.Days, money.Format(p.Equity), p.ReturnPct, p.Sharpe, p.Sortino, p.MaxDrawdownPct, p.ExposurePct)
+		if p.Benchmark != "" && p.BenchmarkReturnPct != 0 {
+			fmt.Fprintf(&sb, " | %s %+.2f%%, excess %+.2f%%", p.Benchmark, p.BenchmarkReturnPct, p.ExcessReturnPct)
+		}
+	}
+	for _, a := range r.Accounts {
+		fmt.Fprintf(&sb, "\nAccount %s: net %s | Realized %s | Unrealized %s | Fees %s | %d won, %d lost",
+			a.Account, money.Signed(a.Net), money.Signed(a.Realized), money.Signed(a.Unrealized), money.Format(a.Fees), a.Wins, a.Losses)
+	}
+	for _, s := range r.Symbols {
+		name := s.Symbol
+		if s.Account != "" {
+			name = s.Account + " " + s.Symbol
+		}
+		fmt.Fprintf(&sb, "\n%s: realized %s", name, money.Signed(s.Realized))
+		if s.OpenQty > 0 {
+			fmt.Fprintf(&sb, ", %d open @ %s, unrealized %s", s.OpenQty, money.Format(s.AvgCost), money.Signed(s.Unrealized))
+		}
+	}
+	return sb.String()
+}
@@ -1,21 +1,12 @@
 package eod
 
-type tradeLine struct {
-	Time       string  // Timestamp of the trade
-	Symbol     string  // Trading symbol (e.g., "RELIANCE")
-	Side       string  // "BUY" or "SELL"
-	Qty        int     // Quantity traded
-	Price      float64 // Execution price
-	OrderID    string  // Broker order ID
-	Reason     string  // Trade reason (LLM decision or STOP_LOSS)
-	Confidence float64 // LLM confidence level (0.0 to 1.0)
-}
-
 type aggRow struct {
-	Symbol      string  // Trading symbol
-	BuyQty      int     // Total quantity bought
-	BuyValue    float64 // Total value of buy orders (qty * price)
-	SellQty     int     // Total quantity sold
-	SellValue   float64 // Total value of sell orders (qty * price)
-	RealizedPnL float64 // Realized profit/loss (calculated from matched trades)
+	Account          string  // Set when several accounts trade in one process
+	Symbol           string  // Trading symbol
+	BuyQty           int     // Total quantity bought
+	BuyValue         float64 // Total value of buy orders (qty * price)
+	SellQty          int     // Total quantity sold
+	SellValue        float64 // Total value of sell orders (qty * price)
+	RealizedPnL      float64 // Realized profit/loss (calculated from matched trades)
+	MaxMarginUtilPct float64 // Peak margin utilization seen when orders were placed
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"llm-trading-bot/internal/timeutil"
 )
 
 func logDir() string {
@@ -13,26 +15,25 @@ func logDir() string {
 	return "logs"
 }
 
-func istNow() time.Time {
-	return time.Now().In(time.FixedZone("IST", 19800))
-}
-
-//
-//
-func todaysTradeFile(t time.Time) string {
-	dateStr := t.Format("2006-01-02")
-	return filepath.Join(logDir(), dateStr+".txt")
-}
+// summaryDelay is how long after the close the day is summarized, so
+// closing orders are in the log.
+const summaryDelay = 10 * time.Minute
 
 //
 //
 func eodCSVPath(t time.Time) string {
-	dateStr := t.Format("2006-01-02")
+	dateStr := timeutil.DateKey(t)
 	return filepath.Join(logDir(), "eod", dateStr+".csv")
 }
 
+// eodReportPath is the JSON P&L report written alongside the CSV.
+func eodReportPath(t time.Time) string {
+	dateStr := timeutil.DateKey(t)
+	return filepath.Join(logDir(), "eod", dateStr+".json")
+}
+
 //
 //
 func marketCloseTime(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 15, 40, 0, 0, t.Location())
+	return timeutil.SessionClose(t).Add(summaryDelay)
 }
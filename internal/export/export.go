@@ -0,0 +1,181 @@
+// Package export writes the trade and decision logs as CSV files
+// partitioned by date and symbol, Hive style:
+//
+//	<dir>/decisions/date=2025-11-04/symbol=RELIANCE/decisions.csv
+//
+// so pandas, Polars or DuckDB can load a whole range at once, e.g.
+// read_csv('export/decisions/*/*/*.csv', hive_partitioning = true).
+// Every file of a dataset has the same columns; nested maps (signals,
+// extra) are written as JSON.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"llm-trading-bot/internal/journal"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// Datasets lists the datasets Export knows, in the order it writes them.
+var Datasets = []string{"decisions", "orders", "trades"}
+
+// Decisions writes one row per logged decision and returns the number of
+// partitions written.
+func Decisions(dir string, entries []tradelog.DecisionEntry) (int, error) {
+	keys := indicatorKeys(len(entries), func(i int) map[string]float64 { return entries[i].Indicators })
+	header := append([]string{"time", "symbol", "action", "confidence", "price", "reason"}, keys...)
+	header = append(header, "extra")
+
+	rows := make([]row, 0, len(entries))
+	for _, e := range entries {
+		rec := []string{e.Time, e.Symbol, e.Action, num(e.Confidence), num(e.Price), e.Reason}
+		rec = append(rec, indicatorValues(keys, e.Indicators)...)
+		rec = append(rec, jsonString(e.Extra))
+		rows = append(rows, row{date: day(e.Time), symbol: e.Symbol, rec: rec})
+	}
+	return write(dir, "decisions", header, rows)
+}
+
+// Orders writes one row per logged order.
+func Orders(dir string, entries []tradelog.Entry) (int, error) {
+	keys := indicatorKeys(len(entries), func(i int) map[string]float64 { return entries[i].Indicators })
+	header := append([]string{"time", "symbol", "side", "qty", "price", "order_id", "status", "unfilled", "tag", "order_type", "limit_price", "spread_cost", "confidence", "reason"}, keys...)
+	header = append(header, "signals", "extra")
+
+	rows := make([]row, 0, len(entries))
+	for _, e := range entries {
+		rec := []string{e.Time, e.Symbol, e.Side, strconv.Itoa(e.Qty), num(e.Price.Float()), e.OrderID, e.Status, strconv.Itoa(e.Unfilled), e.Tag, e.OrderType,
+			num(e.LimitPrice.Float()), num(e.SpreadCost.Float()), num(e.Confidence), e.Reason}
+		rec = append(rec, indicatorValues(keys, e.Indicators)...)
+		rec = append(rec, jsonString(e.Signals), jsonString(e.Extra))
+		rows = append(rows, row{date: day(e.Time), symbol: e.Symbol, rec: rec})
+	}
+	return write(dir, "orders", header, rows)
+}
+
+// Trades writes one row per round trip (a BUY and the SELLs that closed
+// it, matched FIFO), partitioned by entry date.
+func Trades(dir string, trades []*journal.Trade) (int, error) {
+	header := []string{"entry_time", "symbol", "qty", "entry_price", "exit_time", "exit_qty", "exit_avg", "pnl", "closed", "tag", "confidence", "reason", "sources"}
+
+	rows := make([]row, 0, len(trades))
+	for _, t := range trades {
+		exitTime := ""
+		if n := len(t.Exits); n > 0 {
+			exitTime = t.Exits[n-1].Time
+		}
+		e := t.Entry
+		rec := []string{e.Time, e.Symbol, strconv.Itoa(e.Qty), num(e.Price.Float()), exitTime, strconv.Itoa(t.ExitQty),
+			num(t.ExitAvg()), num(t.PnL()), strconv.FormatBool(t.Closed()), e.Tag, num(e.Confidence), e.Reason,
+			strings.Join(t.Sources(), ";")}
+		rows = append(rows, row{date: day(e.Time), symbol: e.Symbol, rec: rec})
+	}
+	return write(dir, "trades", header, rows)
+}
+
+type row struct {
+	date, symbol string
+	rec          []string
+}
+
+// write groups rows by partition and replaces each partition's file.
+func write(dir, dataset string, header []string, rows []row) (int, error) {
+	parts := make(map[string][][]string)
+	for _, r := range rows {
+		p := filepath.Join(dir, dataset, "date="+r.date, "symbol="+pathSafe(r.symbol))
+		parts[p] = append(parts[p], r.rec)
+	}
+
+	paths := make([]string, 0, len(parts))
+	for p := range parts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		if err := writeCSV(filepath.Join(p, dataset+".csv"), header, parts[p]); err != nil {
+			return 0, err
+		}
+	}
+	return len(paths), nil
+}
+
+func writeCSV(path string, header []string, recs [][]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	_ = cw.Write(header)
+	_ = cw.WriteAll(recs)
+	if err := cw.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// indicatorKeys is the sorted union of indicator names over n entries.
+func indicatorKeys(n int, at func(int) map[string]float64) []string {
+	seen := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		for k := range at(i) {
+			seen[k] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func indicatorValues(keys []string, m map[string]float64) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		if v, ok := m[k]; ok {
+			out[i] = num(v)
+		}
+	}
+	return out
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func jsonString(v map[string]any) string {
+	if len(v) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// day is the date part of a log time ("2006-01-02 15:04:05", IST).
+func day(t string) string {
+	if len(t) < 10 {
+		return "unknown"
+	}
+	return t[:10]
+}
+
+// pathSafe keeps exchange-qualified symbols (BSE:500325) usable as
+// directory names on every OS.
+func pathSafe(symbol string) string {
+	return strings.NewReplacer(":", "_", "/", "_", `\`, "_").Replace(symbol)
+}
@@ -0,0 +1,183 @@
+// Package health checks that the bot's external dependencies (broker,
+// LLM provider, NSE) are reachable and accept its credentials, at startup
+// and periodically while it runs. Checks marked critical must pass before
+// LIVE trading starts.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
+	"llm-trading-bot/internal/notify"
+)
+
+// Check probes one dependency; a nil error means healthy.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// Status is the outcome of one check.
+type Status struct {
+	Name      string    `json:"name"`
+	Critical  bool      `json:"critical"`
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the consolidated status of every check, sorted by name.
+type Report struct {
+	Checks []Status `json:"checks"`
+}
+
+// Healthy reports whether every critical check passed.
+func (r Report) Healthy() bool {
+	return len(r.Failed(true)) == 0
+}
+
+// Failed returns the failed checks, only the critical ones when
+// criticalOnly is set.
+func (r Report) Failed(criticalOnly bool) []Status {
+	var out []Status
+	for _, s := range r.Checks {
+		if !s.OK && (s.Critical || !criticalOnly) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Checker runs a fixed set of checks and keeps the latest report.
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+
+	mu   sync.Mutex
+	last Report
+}
+
+// New returns a checker that gives each check at most timeout.
+func New(timeout time.Duration, checks ...Check) *Checker {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// Run executes every check concurrently and returns the report. A check
+// that fails, or recovers, since the previous run is logged; a critical
+// one going down is also sent as a health alert.
+func (c *Checker) Run(ctx context.Context) Report {
+	if c == nil {
+		return Report{}
+	}
+	out := make([]Status, len(c.checks))
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func(i int, chk Check) {
+			defer wg.Done()
+			out[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	report := Report{Checks: out}
+
+	c.mu.Lock()
+	prev := make(map[string]bool, len(c.last.Checks))
+	for _, s := range c.last.Checks {
+		prev[s.Name] = s.OK
+	}
+	first := c.last.Checks == nil
+	c.last = report
+	c.mu.Unlock()
+
+	for _, s := range out {
+		up := 0.0
+		if s.OK {
+			up = 1
+		}
+		metrics.DependencyUp.Set(up, s.Name)
+
+		wasOK, seen := prev[s.Name]
+		switch {
+		case !s.OK && (first || !seen || wasOK):
+			logger.Warn(ctx, "Dependency unhealthy", "dependency", s.Name, "critical", s.Critical, "error", s.Error)
+			if s.Critical {
+				notify.Send(ctx, notify.EventHealth, "", fmt.Sprintf("%s is unhealthy: %s", s.Name, s.Error))
+			}
+		case s.OK && seen && !wasOK:
+			logger.Info(ctx, "Dependency recovered", "dependency", s.Name, "latency_ms", s.LatencyMS)
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, chk Check) (s Status) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	s = Status{Name: chk.Name, Critical: chk.Critical, CheckedAt: start}
+	defer func() {
+		if r := recover(); r != nil {
+			s.OK, s.Error = false, fmt.Sprintf("check panicked: %v", r)
+		}
+		s.LatencyMS = time.Since(start).Milliseconds()
+	}()
+
+	if err := chk.Run(ctx); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.OK = true
+	return s
+}
+
+// Last returns the report of the latest run.
+func (c *Checker) Last() Report {
+	if c == nil {
+		return Report{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Loop re-runs the checks every interval until ctx is cancelled.
+func (c *Checker) Loop(ctx context.Context, interval time.Duration) {
+	if c == nil || interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.Run(ctx)
+		}
+	}
+}
+
+var defaultChecker *Checker
+
+// SetDefault installs the checker whose report Default exposes.
+func SetDefault(c *Checker) {
+	defaultChecker = c
+}
+
+// Default returns the installed checker; nil (no checks) until SetDefault.
+func Default() *Checker {
+	return defaultChecker
+}
@@ -2,14 +2,35 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
 	"llm-trading-bot/internal/types"
 )
 
 type Broker interface {
 	LTP(ctx context.Context, symbol string) (float64, error)
+	GetQuote(ctx context.Context, symbol string) (types.Quote, error)
 	RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error)
 	PlaceOrder(ctx context.Context, req types.OrderReq) (types.OrderResp, error)
+	ModifyOrder(ctx context.Context, orderID string, mod types.OrderModify) (types.OrderResp, error)
+	CancelOrder(ctx context.Context, orderID string) (types.OrderResp, error)
+	GetOrderStatus(ctx context.Context, orderID string) (types.OrderStatus, error)
+	Funds(ctx context.Context) (types.Funds, error)
+	Ticks() <-chan types.Tick
 	Start(ctx context.Context, symbols []string) error
 	Stop(ctx context.Context)
 }
+
+var (
+	ErrStopsUnsupported = errors.New("broker does not support server-side stops")
+	ErrStopTriggered    = errors.New("server-side stop already triggered")
+)
+
+// StopBroker is implemented by brokers that can hold stop-loss orders
+// server-side (Zerodha GTT), so positions stay protected if the bot dies.
+// ModifyStop and CancelStop return ErrStopTriggered once the stop has fired.
+type StopBroker interface {
+	PlaceStop(ctx context.Context, req types.StopReq) (string, error)
+	ModifyStop(ctx context.Context, stopID string, req types.StopReq) error
+	CancelStop(ctx context.Context, stopID string) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"llm-trading-bot/internal/types"
+)
+
+// CandleSource supplies the engine's candle history. Every Broker is one;
+// marketdata.File serves recorded bars without broker credentials.
+type CandleSource interface {
+	RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "time"
+
+// Clock tells the time. Backtests and tests pass a simulated one so
+// time-dependent rules see the replayed bar's time instead of the wall
+// clock.
+type Clock interface {
+	Now() time.Time
+}
@@ -3,9 +3,28 @@ package interfaces
 import (
 	"context"
 
+	"llm-trading-bot/internal/lifecycle"
 	"llm-trading-bot/internal/types"
 )
 
 type Engine interface {
 	Step(ctx context.Context, symbol string) (*types.StepResult, error)
+	ShouldEvaluate(ctx context.Context, symbol string, price float64) bool
+	// PlanTick is called before each poll over symbols to choose which of
+	// them may call the decider during it.
+	PlanTick(ctx context.Context, symbols []string)
+	// Positions returns the open positions, ordered by symbol.
+	Positions() []types.Position
+	// Flatten sells every open position. Positions it could not sell stay
+	// open and are named in the error.
+	Flatten(ctx context.Context, reason string) ([]types.OrderResp, error)
+	// CancelOpenOrders cancels orders placed this session that are still
+	// working, returning how many were cancelled.
+	CancelOpenOrders(ctx context.Context) (int, error)
+	// Protect places broker-held stops for every open position.
+	Protect(ctx context.Context) error
+	// SymbolStates returns each symbol's lifecycle status.
+	SymbolStates() []lifecycle.State
+	// SetBlocked blocks a symbol from the decider, or releases it.
+	SetBlocked(ctx context.Context, symbol string, blocked bool, reason string) error
 }
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+
+	"llm-trading-bot/internal/types"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, n types.Notification) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+// SecretProvider looks up API keys and tokens by their environment-style
+// name (e.g. "KITE_API_KEY"). Remote providers load their values when
+// constructed, so lookups never block.
+type SecretProvider interface {
+	Name() string
+	Lookup(key string) (string, bool)
+}
@@ -11,4 +11,6 @@ type TickerManager interface {
 	Stop(ctx context.Context)
 	Subscribe(ctx context.Context, symbols []string) error
 	GetRecentCandles(symbol string, n int) ([]types.Candle, error)
+	Backfill(symbol string, candles []types.Candle)
+	Ticks() <-chan types.Tick
 }
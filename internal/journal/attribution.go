@@ -0,0 +1,188 @@
+package journal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// Drivers an entry is attributed to, from the decision context logged
+// with it. The decider always sees the indicators, so an entry with no
+// other research signal is a plain technical call.
+const (
+	DriverTechnical = "technical"
+	DriverPattern   = "pattern" // Candlestick patterns detected on the bars
+	DriverOptions   = "options" // Option chain summary (PCR, max pain, OI)
+)
+
+// driverSignals maps the signal keys in the decision context to drivers,
+// most specific first: when several are present the first one wins.
+var driverSignals = []struct {
+	key    string
+	driver string
+}{
+	{"options", DriverOptions},
+	{"patterns", DriverPattern},
+}
+
+// Driver classifies what the entry rested on. Orders not placed by the
+// decider are attributed to their tag.
+func (t *Trade) Driver() string {
+	if tag := t.Entry.Tag; tag != "" && tag != "LLM" {
+		return strings.ToLower(tag)
+	}
+	for _, s := range driverSignals {
+		if _, ok := t.Entry.Signals[s.key]; ok {
+			return s.driver
+		}
+	}
+	return DriverTechnical
+}
+
+// opened and closedAt are the entry time and the time of the last exit.
+func (t *Trade) opened() (time.Time, bool) {
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", t.Entry.Time, timeutil.IST)
+	return ts, err == nil
+}
+
+func (t *Trade) closedAt() (time.Time, bool) {
+	if len(t.Exits) == 0 {
+		return time.Time{}, false
+	}
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", t.Exits[len(t.Exits)-1].Time, timeutil.IST)
+	return ts, err == nil
+}
+
+// TimeOfDay is the IST hour the entry was placed in, e.g. "09:00-10:00".
+func (t *Trade) TimeOfDay() string {
+	ts, ok := t.opened()
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%02d:00-%02d:00", ts.Hour(), ts.Hour()+1)
+}
+
+// Holding buckets how long the position was held, in IST calendar days
+// from entry to the last exit.
+func (t *Trade) Holding() string {
+	in, ok1 := t.opened()
+	out, ok2 := t.closedAt()
+	if !ok1 || !ok2 {
+		return "unknown"
+	}
+	days := int(timeutil.Midnight(out).Sub(timeutil.Midnight(in)).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "intraday"
+	case days == 1:
+		return "overnight"
+	case days <= 5:
+		return "2-5 days"
+	default:
+		return "over 5 days"
+	}
+}
+
+// AttributionRow aggregates the closed trades sharing one key.
+type AttributionRow struct {
+	Key    string
+	Trades int
+	Wins   int
+	PnL    float64
+}
+
+func (r AttributionRow) HitRate() float64 {
+	if r.Trades == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Trades) * 100.0
+}
+
+func (r AttributionRow) AvgPnL() float64 {
+	if r.Trades == 0 {
+		return 0
+	}
+	return r.PnL / float64(r.Trades)
+}
+
+// Attribution is realized P&L over a period broken down by driver,
+// symbol, entry time of day and holding period.
+type Attribution struct {
+	From, To  time.Time
+	Total     AttributionRow
+	ByDriver  []AttributionRow
+	BySymbol  []AttributionRow
+	ByTime    []AttributionRow
+	ByHolding []AttributionRow
+}
+
+// holdingOrder sorts holding buckets from shortest to longest.
+var holdingOrder = map[string]int{"intraday": 0, "overnight": 1, "2-5 days": 2, "over 5 days": 3, "unknown": 4}
+
+// Attribute aggregates the trades closed between from and to (inclusive,
+// IST dates). Trades must be built from entries starting early enough to
+// include the BUYs of positions closed in the period.
+func Attribute(trades []*Trade, from, to time.Time) Attribution {
+	a := Attribution{From: from, To: to, Total: AttributionRow{Key: "total"}}
+	end := timeutil.Midnight(to).AddDate(0, 0, 1)
+	groups := map[string]map[string]*AttributionRow{"driver": {}, "symbol": {}, "time": {}, "holding": {}}
+	for _, t := range trades {
+		if !t.Closed() {
+			continue
+		}
+		closed, ok := t.closedAt()
+		if !ok || closed.Before(timeutil.Midnight(from)) || !closed.Before(end) {
+			continue
+		}
+		pnl := t.PnL()
+		add(&a.Total, pnl)
+		for group, key := range map[string]string{
+			"driver":  t.Driver(),
+			"symbol":  t.Entry.Symbol,
+			"time":    t.TimeOfDay(),
+			"holding": t.Holding(),
+		} {
+			r := groups[group][key]
+			if r == nil {
+				r = &AttributionRow{Key: key}
+				groups[group][key] = r
+			}
+			add(r, pnl)
+		}
+	}
+
+	byPnL := func(rows []AttributionRow) func(i, j int) bool {
+		return func(i, j int) bool { return rows[i].PnL > rows[j].PnL }
+	}
+	a.ByDriver = rows(groups["driver"])
+	sort.SliceStable(a.ByDriver, byPnL(a.ByDriver))
+	a.BySymbol = rows(groups["symbol"])
+	sort.SliceStable(a.BySymbol, byPnL(a.BySymbol))
+	a.ByTime = rows(groups["time"])
+	a.ByHolding = rows(groups["holding"])
+	sort.SliceStable(a.ByHolding, func(i, j int) bool {
+		return holdingOrder[a.ByHolding[i].Key] < holdingOrder[a.ByHolding[j].Key]
+	})
+	return a
+}
+
+func add(r *AttributionRow, pnl float64) {
+	r.Trades++
+	r.PnL += pnl
+	if pnl > 0 {
+		r.Wins++
+	}
+}
+
+// rows returns the group sorted by key.
+func rows(m map[string]*AttributionRow) []AttributionRow {
+	out := make([]AttributionRow, 0, len(m))
+	for _, r := range m {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
@@ -0,0 +1,97 @@
+package journal
+
+import (
+	"time"
+
+	"llm-trading-bot/internal/tradelog"
+)
+
+// LoadDecisions reads the decision entries between from and to
+// (inclusive, IST dates), optionally only for the given symbols.
+func LoadDecisions(from, to time.Time, symbols ...string) ([]tradelog.DecisionEntry, error) {
+	return tradelog.Decisions(tradelog.Query{From: from, To: to, Symbols: symbols})
+}
+
+// CalibrationBin groups BUY and SELL decisions whose stated confidence
+// falls in [Lo, Hi). A decision was right when the price logged horizon
+// decisions later for the same symbol moved its way: up after a BUY,
+// down after a SELL.
+type CalibrationBin struct {
+	Lo, Hi        float64
+	Decisions     int
+	Correct       int
+	confidenceSum float64
+	returnSum     float64
+}
+
+// MeanConfidence is the average stated confidence in the bin.
+func (b CalibrationBin) MeanConfidence() float64 {
+	if b.Decisions == 0 {
+		return 0
+	}
+	return b.confidenceSum / float64(b.Decisions)
+}
+
+// HitRate is the share of decisions that were right, 0-100. A
+// well-calibrated model has a hit rate close to its mean confidence.
+func (b CalibrationBin) HitRate() float64 {
+	if b.Decisions == 0 {
+		return 0
+	}
+	return float64(b.Correct) / float64(b.Decisions) * 100.0
+}
+
+// AvgReturnPct is the mean price move in the decision's direction.
+func (b CalibrationBin) AvgReturnPct() float64 {
+	if b.Decisions == 0 {
+		return 0
+	}
+	return b.returnSum / float64(b.Decisions)
+}
+
+// Calibrate bins decisions (in log order) into bins equal confidence
+// ranges over [0, 1]. Decisions without a price, or with fewer than
+// horizon later decisions for their symbol, are left out.
+func Calibrate(decisions []tradelog.DecisionEntry, horizon, bins int) []CalibrationBin {
+	if horizon < 1 {
+		horizon = 1
+	}
+	if bins < 1 {
+		bins = 10
+	}
+	out := make([]CalibrationBin, bins)
+	for i := range out {
+		out[i].Lo = float64(i) / float64(bins)
+		out[i].Hi = float64(i+1) / float64(bins)
+	}
+
+	bySymbol := make(map[string][]tradelog.DecisionEntry)
+	for _, d := range decisions {
+		bySymbol[d.Symbol] = append(bySymbol[d.Symbol], d)
+	}
+	for _, ds := range bySymbol {
+		for i, d := range ds {
+			if (d.Action != "BUY" && d.Action != "SELL") || d.Price <= 0 || i+horizon >= len(ds) {
+				continue
+			}
+			later := ds[i+horizon].Price
+			if later <= 0 {
+				continue
+			}
+			ret := (later - d.Price) / d.Price * 100.0
+			if d.Action == "SELL" {
+				ret = -ret
+			}
+
+			conf := min(max(d.Confidence, 0), 1)
+			b := &out[min(int(conf*float64(bins)), bins-1)]
+			b.Decisions++
+			b.confidenceSum += conf
+			b.returnSum += ret
+			if ret > 0 {
+				b.Correct++
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package journal
+
+import (
+	"sort"
+	"time"
+
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/tradelog"
+)
+
+// Trade is one BUY order together with the SELL orders that closed it,
+// matched first-in first-out per symbol.
+type Trade struct {
+	Entry     tradelog.Entry
+	Exits     []tradelog.Entry
+	ExitQty   int
+	ExitValue money.Amount
+}
+
+func (t *Trade) Closed() bool {
+	return t.ExitQty >= t.Entry.Qty
+}
+
+func (t *Trade) ExitAvg() float64 {
+	if t.ExitQty == 0 {
+		return 0
+	}
+	return t.ExitValue.Div(t.ExitQty).Float()
+}
+
+// PnL is the realized profit/loss on the quantity exited so far.
+func (t *Trade) PnL() float64 {
+	return (t.ExitValue - t.Entry.Price.Mul(t.ExitQty)).Float()
+}
+
+// Sources lists the signal sources that contributed to the entry: the
+// order tag plus every research signal present in the decision context.
+func (t *Trade) Sources() []string {
+	tag := t.Entry.Tag
+	if tag == "" {
+		tag = "UNKNOWN"
+	}
+	sources := []string{tag}
+	for k := range t.Entry.Signals {
+		sources = append(sources, k)
+	}
+	sort.Strings(sources[1:])
+	return sources
+}
+
+type SourceStats struct {
+	Source string
+	Trades int
+	Wins   int
+	Losses int
+	PnL    float64
+}
+
+func (s SourceStats) HitRate() float64 {
+	if s.Trades == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Trades) * 100.0
+}
+
+// Load reads the order entries between from and to (inclusive, IST dates),
+// optionally only for the given symbols.
+func Load(from, to time.Time, symbols ...string) ([]tradelog.Entry, error) {
+	return tradelog.Orders(tradelog.Query{From: from, To: to, Symbols: symbols})
+}
+
+// OfAccount keeps the entries of one account; an empty account keeps all.
+func OfAccount(entries []tradelog.Entry, account string) []tradelog.Entry {
+	if account == "" {
+		return entries
+	}
+	var out []tradelog.Entry
+	for _, e := range entries {
+		if e.Account == account {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Build pairs BUY entries with subsequent SELL entries of the same symbol
+// and account.
+// SELLs with no open BUY (e.g. positions opened before the window) and
+// entries that executed nothing are ignored.
+func Build(entries []tradelog.Entry) []*Trade {
+	var trades []*Trade
+	open := make(map[string][]*Trade)
+
+	for _, e := range entries {
+		if !e.Executed() {
+			continue
+		}
+		switch e.Side {
+		case "BUY":
+			t := &Trade{Entry: e}
+			trades = append(trades, t)
+			open[e.PositionKey()] = append(open[e.PositionKey()], t)
+		case "SELL":
+			remaining := e.Qty
+			queue := open[e.PositionKey()]
+			for remaining > 0 && len(queue) > 0 {
+				t := queue[0]
+				fill := t.Entry.Qty - t.ExitQty
+				if fill > remaining {
+					fill = remaining
+				}
+				t.ExitQty += fill
+				t.ExitValue += e.Price.Mul(fill)
+				t.Exits = append(t.Exits, e)
+				remaining -= fill
+				if t.Closed() {
+					queue = queue[1:]
+				}
+			}
+			open[e.PositionKey()] = queue
+		}
+	}
+
+	return trades
+}
+
+// HitRateBySource aggregates closed trades by each of their signal sources.
+func HitRateBySource(trades []*Trade) []SourceStats {
+	bySource := make(map[string]*SourceStats)
+	for _, t := range trades {
+		if !t.Closed() {
+			continue
+		}
+		pnl := t.PnL()
+		for _, src := range t.Sources() {
+			s := bySource[src]
+			if s == nil {
+				s = &SourceStats{Source: src}
+				bySource[src] = s
+			}
+			s.Trades++
+			s.PnL += pnl
+			if pnl > 0 {
+				s.Wins++
+			} else {
+				s.Losses++
+			}
+		}
+	}
+
+	out := make([]SourceStats, 0, len(bySource))
+	for _, s := range bySource {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out
+}
@@ -0,0 +1,117 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+func RenderCards(w io.Writer, trades []*Trade) {
+	for _, t := range trades {
+		e := t.Entry
+		fmt.Fprintf(w, "┌ %s  %s  BUY %d @ %s  [%s]\n", e.Time, e.PositionKey(), e.Qty, e.Price, e.Tag)
+		fmt.Fprintf(w, "│ order: %s  confidence: %.2f\n", e.OrderID, e.Confidence)
+		fmt.Fprintf(w, "│ reason: %s\n", e.Reason)
+		if len(e.Indicators) > 0 {
+			fmt.Fprintf(w, "│ indicators: %s\n", formatFloats(e.Indicators))
+		}
+		if len(e.Signals) > 0 {
+			fmt.Fprintf(w, "│ signals: %s\n", formatAny(e.Signals))
+		}
+		for _, x := range t.Exits {
+			fmt.Fprintf(w, "│ exit: %s  SELL %d @ %s  [%s] %s\n", x.Time, x.Qty, x.Price, x.Tag, x.Reason)
+		}
+
+		switch {
+		case t.Closed():
+			outcome := "LOSS"
+			if t.PnL() > 0 {
+				outcome = "WIN"
+			}
+			fmt.Fprintf(w, "└ closed @ %.2f  pnl %+.2f  %s\n\n", t.ExitAvg(), t.PnL(), outcome)
+		case t.ExitQty > 0:
+			fmt.Fprintf(w, "└ partially closed %d/%d  realized %+.2f\n\n", t.ExitQty, e.Qty, t.PnL())
+		default:
+			fmt.Fprintf(w, "└ open\n\n")
+		}
+	}
+}
+
+func RenderSourceStats(w io.Writer, stats []SourceStats) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tTRADES\tWINS\tLOSSES\tHIT RATE\tPNL")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f%%\t%+.2f\n", s.Source, s.Trades, s.Wins, s.Losses, s.HitRate(), s.PnL)
+	}
+	tw.Flush()
+}
+
+// RenderCalibration prints the non-empty bins of a calibration report.
+func RenderCalibration(w io.Writer, bins []CalibrationBin) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIDENCE	DECISIONS	MEAN CONF	HIT RATE	AVG MOVE")
+	for _, b := range bins {
+		if b.Decisions == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%.2f-%.2f\t%d\t%.2f\t%.1f%%\t%+.2f%%\n", b.Lo, b.Hi, b.Decisions, b.MeanConfidence(), b.HitRate(), b.AvgReturnPct())
+	}
+	tw.Flush()
+}
+
+// RenderAttribution prints the attribution report, one table per
+// breakdown.
+func RenderAttribution(w io.Writer, a Attribution) {
+	fmt.Fprintf(w, "Attribution %s to %s: %d closed trades, %d wins (%.1f%%), pnl %+.2f\n",
+		a.From.Format("2006-01-02"), a.To.Format("2006-01-02"), a.Total.Trades, a.Total.Wins, a.Total.HitRate(), a.Total.PnL)
+	for _, section := range []struct {
+		title string
+		rows  []AttributionRow
+	}{
+		{"DRIVER", a.ByDriver},
+		{"SYMBOL", a.BySymbol},
+		{"ENTRY TIME", a.ByTime},
+		{"HOLDING", a.ByHolding},
+	} {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tTRADES\tWINS\tHIT RATE\tPNL\tAVG PNL\tSHARE\n", section.title)
+		for _, r := range section.rows {
+			share := 0.0
+			if a.Total.PnL != 0 {
+				share = r.PnL / math.Abs(a.Total.PnL) * 100
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\t%+.2f\t%+.2f\t%+.1f%%\n", r.Key, r.Trades, r.Wins, r.HitRate(), r.PnL, r.AvgPnL(), share)
+		}
+		tw.Flush()
+	}
+}
+
+func formatFloats(m map[string]float64) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%.2f", k, m[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatAny(m map[string]any) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,149 @@
+// Package killswitch is the operator's manual override. A halt file stops
+// every new order the moment it appears, and a block list file stops
+// orders for single symbols; both are re-read before each order, so they
+// can be edited while the bot runs (touch HALT, or echo YESBANK >>
+// blocklist.txt). Protective exits (stop-loss, flatten) still go through
+// so a halt never leaves a position without its stop.
+package killswitch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/types"
+)
+
+var (
+	ErrHalted  = errors.New("trading halted")
+	ErrBlocked = errors.New("symbol blocked")
+)
+
+// Switch reads the halt and block list files. Empty paths disable them.
+type Switch struct {
+	haltFile  string
+	blockFile string
+
+	mu       sync.Mutex
+	blocked  map[string]bool
+	blockMod time.Time
+}
+
+func New(haltFile, blockFile string) *Switch {
+	return &Switch{haltFile: haltFile, blockFile: blockFile}
+}
+
+// Halted reports whether the halt file exists, with its contents as the
+// reason.
+func (s *Switch) Halted() (bool, string) {
+	if s == nil || s.haltFile == "" {
+		return false, ""
+	}
+	b, err := os.ReadFile(s.haltFile)
+	if err != nil {
+		return errors.Is(err, os.ErrPermission), "" // Unreadable but present: stay safe
+	}
+	return true, strings.TrimSpace(string(b))
+}
+
+// Halt creates the halt file with reason as its contents.
+func (s *Switch) Halt(reason string) error {
+	if s == nil || s.haltFile == "" {
+		return errors.New("no halt file configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.haltFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.haltFile, []byte(reason+"\n"), 0o644)
+}
+
+// Resume removes the halt file.
+func (s *Switch) Resume() error {
+	if s == nil || s.haltFile == "" {
+		return nil
+	}
+	if err := os.Remove(s.haltFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Blocked reports whether symbol is in the block list file, which is
+// reloaded whenever it changes. Lines are symbols; # starts a comment.
+func (s *Switch) Blocked(symbol string) bool {
+	if s == nil || s.blockFile == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fi, err := os.Stat(s.blockFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		s.blocked, s.blockMod = nil, time.Time{}
+	case err == nil && !fi.ModTime().Equal(s.blockMod):
+		if blocked, err := readList(s.blockFile); err == nil {
+			s.blocked, s.blockMod = blocked, fi.ModTime()
+		}
+	}
+	return s.blocked[types.NormalizeSymbol(symbol)]
+}
+
+// Check returns ErrHalted or ErrBlocked when an order for symbol must not
+// be placed. Protective exits are always allowed.
+func (s *Switch) Check(symbol string, protective bool) error {
+	if protective {
+		return nil
+	}
+	if halted, reason := s.Halted(); halted {
+		if reason != "" {
+			return fmt.Errorf("%w: %s", ErrHalted, reason)
+		}
+		return ErrHalted
+	}
+	if s.Blocked(symbol) {
+		return fmt.Errorf("%w: %s is in the block list", ErrBlocked, symbol)
+	}
+	return nil
+}
+
+func readList(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := make(map[string]bool)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if sym := types.NormalizeSymbol(line); sym != "" {
+			out[sym] = true
+		}
+	}
+	return out, sc.Err()
+}
+
+var defaultSwitch *Switch
+
+// SetDefault installs the switch used by Check and the package-level
+// helpers; until then nothing is halted or blocked.
+func SetDefault(s *Switch) {
+	defaultSwitch = s
+}
+
+// Default returns the installed switch, or nil.
+func Default() *Switch {
+	return defaultSwitch
+}
+
+// Check checks an order against the default switch.
+func Check(symbol string, protective bool) error {
+	return defaultSwitch.Check(symbol, protective)
+}
@@ -0,0 +1,203 @@
+// Package lifecycle tracks where each symbol is in its trading lifecycle:
+//
+//	SCREENED → WATCHING → ENTERED → EXITING → COOLDOWN → WATCHING
+//
+// with BLOCKED reachable from any status. The engine consults the status to
+// decide what runs for a symbol (decider calls, entries, stop checks), and
+// the states are saved to a JSON file so cooldowns and blocks survive a
+// restart and other processes (dashboard) can read them.
+package lifecycle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	Screened = "SCREENED" // Picked by a screener, not yet evaluated
+	Watching = "WATCHING" // Evaluated each poll; entries allowed
+	Entered  = "ENTERED"  // Position open; stop checks run
+	Exiting  = "EXITING"  // Exit order in flight
+	Cooldown = "COOLDOWN" // Recently exited; no entries until Until
+	Blocked  = "BLOCKED"  // No decisions; stop checks still run for an open position
+)
+
+// transitions lists the statuses each status may move to. BLOCKED is
+// always allowed and is left for WATCHING or, with a position, ENTERED.
+var transitions = map[string][]string{
+	Screened: {Watching},
+	Watching: {Entered, Screened},
+	Entered:  {Exiting, Cooldown, Watching},
+	Exiting:  {Cooldown, Entered, Watching},
+	Cooldown: {Watching, Entered},
+	Blocked:  {Watching, Entered},
+}
+
+// State is one symbol's status. Times are bar times, so cooldowns behave
+// the same in live trading and in backtests.
+type State struct {
+	Account string    `json:"account,omitempty"` // Set by the engine when several accounts trade
+	Symbol  string    `json:"symbol"`
+	Status  string    `json:"status"`
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until,omitempty"` // End of COOLDOWN
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// Tracker holds the states of all symbols. It is not safe for concurrent
+// use; the engine calls it from its loop.
+type Tracker struct {
+	path     string // Empty: kept in memory only
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	states map[string]*State
+}
+
+// Open loads the states saved at path. A missing file starts empty; an
+// empty path keeps states in memory only.
+func Open(path string, cooldown time.Duration) (*Tracker, error) {
+	t := &Tracker{path: path, cooldown: cooldown, states: make(map[string]*State)}
+	if path == "" {
+		return t, nil
+	}
+	states, err := Load(path)
+	if err != nil {
+		return t, err
+	}
+	for i := range states {
+		t.states[states[i].Symbol] = &states[i]
+	}
+	return t, nil
+}
+
+// Load reads the states saved at path, ordered by symbol.
+func Load(path string) ([]State, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []State
+	if err := json.Unmarshal(b, &states); err != nil {
+		return nil, fmt.Errorf("invalid symbol state file %s: %w", path, err)
+	}
+	return states, nil
+}
+
+// Get returns symbol's state at time now. Symbols never seen are WATCHING;
+// an expired cooldown reads as WATCHING.
+func (t *Tracker) Get(symbol string, now time.Time) State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.get(symbol, now)
+}
+
+func (t *Tracker) get(symbol string, now time.Time) State {
+	s, ok := t.states[symbol]
+	if !ok {
+		return State{Symbol: symbol, Status: Watching}
+	}
+	return s.At(now)
+}
+
+// At returns the state as of now, with an expired cooldown as WATCHING.
+func (s State) At(now time.Time) State {
+	if s.Status == Cooldown && !now.Before(s.Until) {
+		return State{Symbol: s.Symbol, Status: Watching, Since: s.Until, Reason: "cooldown over"}
+	}
+	return s
+}
+
+// Status is Get(symbol, now).Status.
+func (t *Tracker) Status(symbol string, now time.Time) string {
+	return t.Get(symbol, now).Status
+}
+
+// Move changes symbol's status and saves the states. Moving to the
+// current status is a no-op; a move the lifecycle does not allow is an
+// error and leaves the state unchanged. Moving to COOLDOWN with no
+// cooldown configured goes straight to WATCHING.
+func (t *Tracker) Move(symbol, to, reason string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	from := t.get(symbol, now).Status
+	if from == to {
+		return nil
+	}
+	if !allowed(from, to) {
+		return fmt.Errorf("%s: cannot move from %s to %s", symbol, from, to)
+	}
+
+	s := &State{Symbol: symbol, Status: to, Since: now, Reason: reason}
+	if to == Cooldown {
+		if t.cooldown <= 0 {
+			s.Status = Watching
+		} else {
+			s.Until = now.Add(t.cooldown)
+		}
+	}
+	t.states[symbol] = s
+	if err := t.save(); err != nil {
+		return fmt.Errorf("%s moved to %s but not saved: %w", symbol, s.Status, err)
+	}
+	return nil
+}
+
+func allowed(from, to string) bool {
+	if to == Blocked {
+		return true
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns every tracked state at time now, ordered by symbol.
+func (t *Tracker) All(now time.Time) []State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]State, 0, len(t.states))
+	for sym := range t.states {
+		out = append(out, t.get(sym, now))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+func (t *Tracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+	states := make([]State, 0, len(t.states))
+	for _, s := range t.states {
+		states = append(states, *s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Symbol < states[j].Symbol })
+
+	b, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.path)
+}
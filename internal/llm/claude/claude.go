@@ -11,7 +11,9 @@ import (
 	"os"
 	"strings"
 
+	"llm-trading-bot/internal/api"
 	"llm-trading-bot/internal/trace"
+	"llm-trading-bot/internal/secrets"
 	"llm-trading-bot/internal/store"
 	"llm-trading-bot/internal/types"
 )
@@ -33,7 +35,7 @@ func (d *ClaudeDecider) Decide(ctx context.Context, symbol string, latest types.
 	ctx, span := trace.StartSpan(ctx, "claude-api-call")
 	defer span.End()
 
-	apiKey := os.Getenv("CLAUDE_API_KEY")
+	apiKey := secrets.Get("CLAUDE_API_KEY")
 	if apiKey == "" {
 		return types.Decision{}, errors.New("CLAUDE_API_KEY missing")
 	}
@@ -67,15 +69,14 @@ func (d *ClaudeDecider) Decide(ctx context.Context, symbol string, latest types.
 	req.Header.Set("x-api-key", apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := api.Default().DoWithRetry(req)
 	if err != nil {
 		return types.Decision{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return types.Decision{}, fmt.Errorf("claude http %d: %s", resp.StatusCode, string(body))
+	if err := api.CheckStatus(resp); err != nil {
+		return types.Decision{}, fmt.Errorf("claude: %w", err)
 	}
 
 	respBytes, _ := io.ReadAll(resp.Body)
@@ -161,3 +162,28 @@ func normalizeDecision(d *types.Decision) {
 		d.Confidence = 0.0
 	}
 }
+
+// Ping checks that the API is reachable and accepts the key by listing
+// models, which costs no tokens.
+func (d *ClaudeDecider) Ping(ctx context.Context) error {
+	apiKey := secrets.Get("CLAUDE_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("CLAUDE_API_KEY missing: %w", api.ErrAuth)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(d.endpoint, "/messages")+"/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := api.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return fmt.Errorf("claude: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,11 @@ package llmobs
 
 import (
 	"context"
+	"time"
 
 	"llm-trading-bot/internal/interfaces"
 	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
 	"llm-trading-bot/internal/trace"
 	"llm-trading-bot/internal/types"
 )
@@ -37,8 +39,10 @@ func (od *observableDecider) Decide(
 		"rsi", indicators.RSI,
 	)
 
+	start := time.Now()
 	decision, err := od.decider.Decide(ctx, symbol, latest, indicators, contextData)
 	if err != nil {
+		metrics.LLMSeconds.Observe(time.Since(start).Seconds(), "error")
 		logger.ErrorWithErrSkip(ctx, 1, "Failed to get trading decision", err,
 			"symbol", symbol,
 			"price", latest.Close,
@@ -46,6 +50,9 @@ func (od *observableDecider) Decide(
 		return types.Decision{}, err
 	}
 
+	metrics.LLMSeconds.Observe(time.Since(start).Seconds(), "ok")
+	metrics.LLMDecisions.Inc(decision.Action)
+
 	logger.InfoSkip(ctx, 1, "Trading decision received",
 		"symbol", symbol,
 		"action", decision.Action,
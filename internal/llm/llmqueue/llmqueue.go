@@ -0,0 +1,286 @@
+// Package llmqueue is the process-wide queue for LLM calls. Each provider
+// runs a limited number of calls at once. Waiting calls start in priority
+// order, trade decisions first, and some slots are held back for
+// decisions. Research work is refused rather than queued once too much is
+// waiting, so a large job cannot starve live decision-making.
+package llmqueue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/metrics"
+	"llm-trading-bot/internal/types"
+)
+
+// Class is a priority class; lower values start first.
+type Class int
+
+const (
+	Decision Class = iota // Live trade decisions
+	News                  // News analysis
+	Forensic              // Forensic summaries of filings
+	PEAD                  // Post-earnings drift NLP
+	numClasses
+)
+
+var classNames = [numClasses]string{"decision", "news", "forensic", "pead"}
+
+func (c Class) String() string {
+	if c < 0 || c >= numClasses {
+		return fmt.Sprintf("class(%d)", int(c))
+	}
+	return classNames[c]
+}
+
+// ErrFull is returned for research calls refused, or dropped from the
+// queue for higher-priority work, because too many calls are waiting. It
+// matches api.ErrRateLimited.
+var ErrFull = fmt.Errorf("llm queue full: %w", api.ErrRateLimited)
+
+type Options struct {
+	Concurrency int            // Calls in flight per provider; 0 = unlimited
+	Providers   map[string]int // Per-provider override of Concurrency
+	Reserved    int            // Slots of each provider only trade decisions may use
+	MaxWaiting  int            // Research calls are refused once this many calls wait; 0 = no limit
+}
+
+// Queue hands out provider slots. It is safe for concurrent use.
+type Queue struct {
+	opts Options
+
+	mu    sync.Mutex
+	lanes map[string]*lane
+}
+
+// lane is one provider's slots and its waiting calls, FIFO per class.
+type lane struct {
+	provider string
+	limit    int
+	running  int
+	waiting  [numClasses][]*waiter
+}
+
+type waiter struct {
+	class Class
+	ready chan struct{}
+	err   error // Set when the call was shed instead of started
+}
+
+// New returns a queue for o. Reserved must leave research calls at least
+// one slot of every limited provider, or they would wait forever.
+func New(o Options) (*Queue, error) {
+	if o.Reserved < 0 {
+		return nil, fmt.Errorf("llm queue: reserved slots cannot be negative, got %d", o.Reserved)
+	}
+	if o.Concurrency > 0 && o.Reserved >= o.Concurrency {
+		return nil, fmt.Errorf("llm queue: %d reserved slots leave none of %d for research", o.Reserved, o.Concurrency)
+	}
+	for provider, n := range o.Providers {
+		if n > 0 && o.Reserved >= n {
+			return nil, fmt.Errorf("llm queue: %d reserved slots leave none of %s's %d for research", o.Reserved, provider, n)
+		}
+	}
+	return &Queue{opts: o, lanes: make(map[string]*lane)}, nil
+}
+
+// Do runs fn once provider has a free slot for class, or returns the
+// context's error or ErrFull without running it.
+func (q *Queue) Do(ctx context.Context, provider string, class Class, fn func(context.Context) error) error {
+	start := time.Now()
+	release, err := q.acquire(ctx, provider, class)
+	if err != nil {
+		return err
+	}
+	defer release()
+	metrics.LLMQueueSeconds.Observe(time.Since(start).Seconds(), class.String())
+	return fn(ctx)
+}
+
+func (q *Queue) acquire(ctx context.Context, provider string, class Class) (func(), error) {
+	if class < 0 || class >= numClasses {
+		return nil, fmt.Errorf("unknown llm queue class %d", int(class))
+	}
+	q.mu.Lock()
+	l := q.lane(provider)
+	if l.limit <= 0 {
+		q.mu.Unlock()
+		return func() {}, nil
+	}
+	if l.ahead(class) == 0 && q.fits(l, class) {
+		l.running++
+		q.mu.Unlock()
+		return func() { q.release(l) }, nil
+	}
+	if class != Decision && q.opts.MaxWaiting > 0 && l.ahead(numClasses-1) >= q.opts.MaxWaiting && !l.shed(class) {
+		q.mu.Unlock()
+		return nil, ErrFull
+	}
+	w := &waiter{class: class, ready: make(chan struct{})}
+	l.waiting[class] = append(l.waiting[class], w)
+	l.report(class)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		if w.err != nil {
+			return nil, w.err
+		}
+		return func() { q.release(l) }, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if l.remove(w) {
+			l.report(class)
+			return nil, ctx.Err()
+		}
+		if w.err != nil {
+			return nil, w.err
+		}
+		// Granted while giving up: pass the slot on
+		l.running--
+		q.dispatch(l)
+		return nil, ctx.Err()
+	}
+}
+
+func (q *Queue) lane(provider string) *lane {
+	l := q.lanes[provider]
+	if l == nil {
+		limit := q.opts.Concurrency
+		if n, ok := q.opts.Providers[provider]; ok {
+			limit = n
+		}
+		l = &lane{provider: provider, limit: limit}
+		q.lanes[provider] = l
+	}
+	return l
+}
+
+// fits reports whether a call of class may take a free slot now; research
+// classes leave the reserved slots to decisions.
+func (q *Queue) fits(l *lane, class Class) bool {
+	limit := l.limit
+	if class != Decision {
+		limit -= q.opts.Reserved
+	}
+	return l.running < limit
+}
+
+func (q *Queue) release(l *lane) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	l.running--
+	q.dispatch(l)
+}
+
+// dispatch starts waiting calls in priority order while slots are free.
+func (q *Queue) dispatch(l *lane) {
+	for c := Class(0); c < numClasses; c++ {
+		for len(l.waiting[c]) > 0 && q.fits(l, c) {
+			w := l.waiting[c][0]
+			l.waiting[c] = l.waiting[c][1:]
+			l.running++
+			close(w.ready)
+			l.report(c)
+		}
+	}
+}
+
+// ahead counts the calls waiting in class or a higher priority one.
+func (l *lane) ahead(class Class) int {
+	n := 0
+	for c := Class(0); c <= class; c++ {
+		n += len(l.waiting[c])
+	}
+	return n
+}
+
+// shed refuses the newest waiting call of the lowest class below class,
+// making room for a call of class; false when there is none.
+func (l *lane) shed(class Class) bool {
+	for c := numClasses - 1; c > class; c-- {
+		if n := len(l.waiting[c]); n > 0 {
+			w := l.waiting[c][n-1]
+			l.waiting[c] = l.waiting[c][:n-1]
+			w.err = ErrFull
+			close(w.ready)
+			l.report(c)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lane) remove(w *waiter) bool {
+	ws := l.waiting[w.class]
+	for i, x := range ws {
+		if x == w {
+			l.waiting[w.class] = append(ws[:i:i], ws[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lane) report(class Class) {
+	metrics.LLMQueueWaiting.Set(float64(len(l.waiting[class])), l.provider, class.String())
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultQueue = &Queue{lanes: make(map[string]*lane)}
+)
+
+// Configure replaces the process-wide queue. Calls already waiting stay
+// on the old one. Invalid options leave the current queue in place.
+func Configure(o Options) error {
+	q, err := New(o)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defaultQueue = q
+	defaultMu.Unlock()
+	return nil
+}
+
+// Default returns the process-wide queue, unlimited unless Configure was
+// called.
+func Default() *Queue {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultQueue
+}
+
+// Do runs fn through the process-wide queue.
+func Do(ctx context.Context, provider string, class Class, fn func(context.Context) error) error {
+	return Default().Do(ctx, provider, class, fn)
+}
+
+// queuedDecider sends every decision through the queue as a Decision.
+type queuedDecider struct {
+	decider  interfaces.Decider
+	provider string
+}
+
+var _ interfaces.Decider = (*queuedDecider)(nil)
+
+// Wrap queues the decider's calls under provider.
+func Wrap(decider interfaces.Decider, provider string) interfaces.Decider {
+	return &queuedDecider{decider: decider, provider: provider}
+}
+
+func (qd *queuedDecider) Decide(ctx context.Context, symbol string, latest types.Candle, inds types.Indicators, contextData map[string]any) (types.Decision, error) {
+	var decision types.Decision
+	err := Do(ctx, qd.provider, Decision, func(ctx context.Context) error {
+		var err error
+		decision, err = qd.decider.Decide(ctx, symbol, latest, inds, contextData)
+		return err
+	})
+	return decision, err
+}
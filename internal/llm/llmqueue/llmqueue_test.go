@@ -0,0 +1,69 @@
+package llmqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewReserved(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		ok   bool
+	}{
+		{"below concurrency", Options{Concurrency: 2, Reserved: 1}, true},
+		{"unlimited", Options{Reserved: 3}, true},
+		{"equal to concurrency", Options{Concurrency: 2, Reserved: 2}, false},
+		{"above concurrency", Options{Concurrency: 2, Reserved: 3}, false},
+		{"negative", Options{Concurrency: 2, Reserved: -1}, false},
+		{"below provider override", Options{Concurrency: 1, Providers: map[string]int{"CLAUDE": 4}, Reserved: 0}, true},
+		{"equal to provider override", Options{Concurrency: 4, Providers: map[string]int{"CLAUDE": 2}, Reserved: 2}, false},
+		{"unlimited provider", Options{Concurrency: 4, Providers: map[string]int{"CLAUDE": 0}, Reserved: 2}, true},
+	}
+	for _, tt := range tests {
+		_, err := New(tt.opts)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: New(%+v) error = %v, want ok %v", tt.name, tt.opts, err, tt.ok)
+		}
+	}
+}
+
+func TestConfigureKeepsQueueOnError(t *testing.T) {
+	before := Default()
+	if err := Configure(Options{Concurrency: 1, Reserved: 1}); err == nil {
+		t.Fatal("Configure accepted a reserve that takes every slot")
+	}
+	if Default() != before {
+		t.Error("Configure replaced the queue despite the error")
+	}
+}
+
+// TestReservedSlot checks that research leaves the reserved slot to
+// decisions: with two slots and one reserved, a second research call
+// waits while a decision still starts.
+func TestReservedSlot(t *testing.T) {
+	q, err := New(Options{Concurrency: 2, Reserved: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	release, err := q.acquire(ctx, "CLAUDE", News)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(waitCtx, "CLAUDE", Forensic); err != context.DeadlineExceeded {
+		t.Fatalf("second research call: err = %v, want it to wait for the deadline", err)
+	}
+
+	decided, err := q.acquire(ctx, "CLAUDE", Decision)
+	if err != nil {
+		t.Fatalf("decision did not get the reserved slot: %v", err)
+	}
+	decided()
+	release()
+}
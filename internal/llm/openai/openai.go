@@ -7,10 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
 
+	"llm-trading-bot/internal/api"
 	"llm-trading-bot/internal/trace"
+	"llm-trading-bot/internal/secrets"
 	"llm-trading-bot/internal/store"
 	"llm-trading-bot/internal/types"
 )
@@ -27,7 +28,7 @@ func (d *OpenAIDecider) Decide(ctx context.Context, symbol string, latest types.
 	ctx, span := trace.StartSpan(ctx, "openai-api-call")
 	defer span.End()
 
-	apiKey := os.Getenv("OPENAI_API_KEY")
+	apiKey := secrets.Get("OPENAI_API_KEY")
 	if apiKey == "" {
 		return types.Decision{}, errors.New("OPENAI_API_KEY missing")
 	}
@@ -48,14 +49,14 @@ func (d *OpenAIDecider) Decide(ctx context.Context, symbol string, latest types.
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := api.Default().DoWithRetry(req)
 	if err != nil {
 		return types.Decision{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		return types.Decision{}, fmt.Errorf("openai http %d", resp.StatusCode)
+	if err := api.CheckStatus(resp); err != nil {
+		return types.Decision{}, fmt.Errorf("openai: %w", err)
 	}
 
 	var r struct {
@@ -66,11 +67,11 @@ func (d *OpenAIDecider) Decide(ctx context.Context, symbol string, latest types.
 		} `json:"choices"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		return types.Decision{}, err
+		return types.Decision{}, fmt.Errorf("openai: %w: %w", api.ErrParse, err)
 	}
 
 	if len(r.Choices) == 0 {
-		return types.Decision{}, errors.New("no choices")
+		return types.Decision{}, fmt.Errorf("openai: %w: no choices", api.ErrParse)
 	}
 
 	out := strings.TrimSpace(r.Choices[0].Message.Content)
@@ -91,3 +92,27 @@ func (d *OpenAIDecider) Decide(ctx context.Context, symbol string, latest types.
 
 	return dres, nil
 }
+
+// Ping checks that the API is reachable and accepts the key by listing
+// models, which costs no tokens.
+func (d *OpenAIDecider) Ping(ctx context.Context) error {
+	apiKey := secrets.Get("OPENAI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY missing: %w", api.ErrAuth)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.openai.com/v1/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := api.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return fmt.Errorf("openai: %w", err)
+	}
+	return nil
+}
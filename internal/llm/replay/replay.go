@@ -0,0 +1,150 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/types"
+)
+
+type record struct {
+	Key      string         `json:"key"`
+	Symbol   string         `json:"symbol"`
+	Ts       int64          `json:"ts"`
+	Decision types.Decision `json:"decision"`
+}
+
+// ReplayDecider records decisions keyed by a hash of the decider input, or
+// replays previously recorded decisions so backtests are reproducible and
+// don't call the LLM again.
+type ReplayDecider struct {
+	decider   interfaces.Decider // Live decider (record) or fallback for misses (replay)
+	recording bool
+
+	mu        sync.Mutex
+	path      string
+	decisions map[string]types.Decision
+	misses    int
+}
+
+var _ interfaces.Decider = (*ReplayDecider)(nil)
+
+// NewRecorder wraps decider and appends every decision to path. Decisions
+// already in the file are reused instead of calling decider again.
+func NewRecorder(decider interfaces.Decider, path string) (*ReplayDecider, error) {
+	decisions, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayDecider{decider: decider, recording: true, path: path, decisions: decisions}, nil
+}
+
+// NewReplayer serves decisions from path. States that were never recorded
+// go to fallback, or HOLD when fallback is nil.
+func NewReplayer(path string, fallback interfaces.Decider) (*ReplayDecider, error) {
+	decisions, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(decisions) == 0 {
+		return nil, fmt.Errorf("no recorded decisions in %s", path)
+	}
+	return &ReplayDecider{decider: fallback, path: path, decisions: decisions}, nil
+}
+
+func (rd *ReplayDecider) Decide(ctx context.Context, symbol string, latest types.Candle, inds types.Indicators, ctxmap map[string]any) (types.Decision, error) {
+	key := StateKey(symbol, latest, inds, ctxmap)
+
+	rd.mu.Lock()
+	d, ok := rd.decisions[key]
+	if !ok {
+		rd.misses++
+	}
+	rd.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+
+	if !rd.recording {
+		if rd.decider == nil {
+			return types.Decision{Action: "HOLD", Reason: "replay_miss", Confidence: 0.0}, nil
+		}
+		return rd.decider.Decide(ctx, symbol, latest, inds, ctxmap)
+	}
+
+	d, err := rd.decider.Decide(ctx, symbol, latest, inds, ctxmap)
+	if err != nil {
+		return types.Decision{}, err
+	}
+
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.decisions[key] = d
+	if err := rd.append(record{Key: key, Symbol: symbol, Ts: latest.Ts, Decision: d}); err != nil {
+		return types.Decision{}, fmt.Errorf("failed to record decision: %w", err)
+	}
+	return d, nil
+}
+
+// Misses is the number of states not found in the recording.
+func (rd *ReplayDecider) Misses() int {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	return rd.misses
+}
+
+// StateKey hashes the full decider input. %v prints map keys in sorted
+// order and tolerates NaN indicators, so equal inputs give equal keys.
+func StateKey(symbol string, latest types.Candle, inds types.Indicators, ctxmap map[string]any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%+v|%+v|%+v", symbol, latest, inds, ctxmap)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (rd *ReplayDecider) append(r record) error {
+	if err := os.MkdirAll(filepath.Dir(rd.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(rd.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, string(b))
+	return err
+}
+
+func load(path string) (map[string]types.Decision, error) {
+	decisions := make(map[string]types.Decision)
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return decisions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue
+		}
+		decisions[r.Key] = r.Decision
+	}
+	return decisions, sc.Err()
+}
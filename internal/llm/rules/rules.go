@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"llm-trading-bot/internal/ta/patterns"
+	"llm-trading-bot/internal/types"
+)
+
+// RuleDecider is a deterministic stand-in for the LLM: trend-following
+// entries filtered by RSI and confirmed by candlestick patterns. It is fast
+// enough for parameter sweeps and needs no API keys.
+type RuleDecider struct {
+	RSIOverbought float64 // No new entries above this RSI
+	RSIExit       float64 // Exit when RSI rises above this level
+}
+
+func NewRuleDecider() *RuleDecider {
+	return &RuleDecider{
+		RSIOverbought: 70,
+		RSIExit:       80,
+	}
+}
+
+func (d *RuleDecider) Decide(ctx context.Context, symbol string, latest types.Candle, inds types.Indicators, ctxmap map[string]any) (types.Decision, error) {
+	fast, slow, ok := smaPair(inds.SMA)
+	if !ok || math.IsNaN(inds.RSI) {
+		return types.Decision{Action: "HOLD", Reason: "rules: indicators not ready", Confidence: 0.0}, nil
+	}
+
+	price := latest.Close
+
+	switch {
+	case price < slow || inds.RSI > d.RSIExit:
+		return types.Decision{Action: "SELL", Reason: "rules: trend broken or overbought exit", Confidence: 0.6}, nil
+	case price > fast && fast > slow && inds.RSI < d.RSIOverbought:
+		switch patternBias(ctxmap) {
+		case -1:
+			return types.Decision{Action: "HOLD", Reason: "rules: entry vetoed by bearish pattern", Confidence: 0.5}, nil
+		case 1:
+			return types.Decision{Action: "BUY", Reason: "rules: price above rising SMAs, bullish pattern", Confidence: 0.7}, nil
+		}
+		return types.Decision{Action: "BUY", Reason: "rules: price above rising SMAs", Confidence: 0.6}, nil
+	default:
+		return types.Decision{Action: "HOLD", Reason: "rules: no signal", Confidence: 0.5}, nil
+	}
+}
+
+// patternBias nets the bias of the candlestick patterns in the decider
+// context: +1 if only bullish ones fired, -1 if any bearish one did.
+func patternBias(ctxmap map[string]any) int {
+	names, _ := ctxmap["patterns"].([]string)
+	net := 0
+	for _, name := range names {
+		switch patterns.Bias(name) {
+		case -1:
+			return -1
+		case 1:
+			net = 1
+		}
+	}
+	return net
+}
+
+// smaPair returns the shortest and longest configured SMA that are ready.
+func smaPair(sma map[int]float64) (fast, slow float64, ok bool) {
+	windows := make([]int, 0, len(sma))
+	for w, v := range sma {
+		if !math.IsNaN(v) {
+			windows = append(windows, w)
+		}
+	}
+	if len(windows) < 2 {
+		return 0, 0, false
+	}
+	sort.Ints(windows)
+	return sma[windows[0]], sma[windows[len(windows)-1]], true
+}
@@ -15,6 +15,8 @@ import (
 
 var globalLogger *zap.SugaredLogger
 
+// Init logs to stdout as set by LOG_LEVEL, LOG_FORMAT and LOG_DETAILED.
+// Configure later replaces the sinks and levels from config.yaml.
 func Init() error {
 	level := getEnv("LOG_LEVEL", "INFO")
 	format := getEnv("LOG_FORMAT", "json")
@@ -32,36 +34,51 @@ func Init() error {
 		encoder = zapcore.NewConsoleEncoder(encoderConfig)
 	}
 
+	// Levels are checked by enabled before an entry reaches the core
 	core := zapcore.NewCore(
 		encoder,
 		zapcore.AddSync(os.Stdout),
-		parseLogLevel(level),
+		zapcore.DebugLevel,
 	)
+	globalLevel.SetLevel(parseLogLevel(level))
+	caller = detailed
 
-	opts := []zap.Option{zap.AddCallerSkip(1)}
-	if detailed {
-		opts = append(opts, zap.AddCaller())
-	}
-
-	logger := zap.New(core, opts...)
-	globalLogger = logger.Sugar()
-
+	setCore(core, nil)
 	return nil
 }
 
+// Sync flushes buffered log entries; call it before the process exits.
+func Sync() {
+	if globalLogger != nil {
+		_ = globalLogger.Sync()
+	}
+}
+
 func Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.DebugLevel, 0) {
+		return
+	}
 	globalLogger.With(traceFields(ctx)...).Debugw(msg, keysAndValues...)
 }
 
 func Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.InfoLevel, 0) {
+		return
+	}
 	globalLogger.With(traceFields(ctx)...).Infow(msg, keysAndValues...)
 }
 
 func Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.WarnLevel, 0) {
+		return
+	}
 	globalLogger.With(traceFields(ctx)...).Warnw(msg, keysAndValues...)
 }
 
 func Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.ErrorLevel, 0) {
+		return
+	}
 	globalLogger.With(traceFields(ctx)...).Errorw(msg, keysAndValues...)
 }
 
@@ -72,24 +89,39 @@ func ErrorWithErr(ctx context.Context, msg string, err error, keysAndValues ...i
 			span.SetStatus(codes.Error, err.Error())
 		}
 	}
+	if !enabled(zapcore.ErrorLevel, 0) {
+		return
+	}
 	args := append([]interface{}{"error", err}, keysAndValues...)
 	globalLogger.With(traceFields(ctx)...).Errorw(msg, args...)
 }
 
 
 func DebugSkip(ctx context.Context, skip int, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.DebugLevel, skip) {
+		return
+	}
 	globalLogger.WithOptions(zap.AddCallerSkip(skip)).With(traceFields(ctx)...).Debugw(msg, keysAndValues...)
 }
 
 func InfoSkip(ctx context.Context, skip int, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.InfoLevel, skip) {
+		return
+	}
 	globalLogger.WithOptions(zap.AddCallerSkip(skip)).With(traceFields(ctx)...).Infow(msg, keysAndValues...)
 }
 
 func WarnSkip(ctx context.Context, skip int, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.WarnLevel, skip) {
+		return
+	}
 	globalLogger.WithOptions(zap.AddCallerSkip(skip)).With(traceFields(ctx)...).Warnw(msg, keysAndValues...)
 }
 
 func ErrorSkip(ctx context.Context, skip int, msg string, keysAndValues ...interface{}) {
+	if !enabled(zapcore.ErrorLevel, skip) {
+		return
+	}
 	globalLogger.WithOptions(zap.AddCallerSkip(skip)).With(traceFields(ctx)...).Errorw(msg, keysAndValues...)
 }
 
@@ -100,16 +132,31 @@ func ErrorWithErrSkip(ctx context.Context, skip int, msg string, err error, keys
 			span.SetStatus(codes.Error, err.Error())
 		}
 	}
+	if !enabled(zapcore.ErrorLevel, skip) {
+		return
+	}
 	args := append([]interface{}{"error", err}, keysAndValues...)
 	globalLogger.WithOptions(zap.AddCallerSkip(skip)).With(traceFields(ctx)...).Errorw(msg, args...)
 }
 
 
+type fieldsKey struct{}
+
+// WithFields returns ctx carrying key-value pairs that are added to every
+// entry logged with it, e.g. the account a trading loop runs for.
+func WithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).([]interface{})
+	fields := append(prev[:len(prev):len(prev)], keysAndValues...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// traceFields are the trace IDs and WithFields pairs carried by ctx.
 func traceFields(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
 	if traceID, spanID, ok := trace.GetTraceFields(ctx); ok {
-		return []interface{}{"trace_id", traceID, "span_id", spanID}
+		return append([]interface{}{"trace_id", traceID, "span_id", spanID}, fields...)
 	}
-	return nil
+	return fields
 }
 
 func parseLogLevel(level string) zapcore.Level {
@@ -0,0 +1,327 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// modulePrefix is stripped from caller packages to name their module,
+// e.g. "engine" or "broker/zerodha".
+const modulePrefix = "llm-trading-bot/internal/"
+
+var (
+	globalLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	caller      bool
+
+	// modules maps a module to its level override; replaced, never
+	// modified, so enabled reads it without locking.
+	modules atomic.Pointer[map[string]zapcore.Level]
+
+	mu      sync.Mutex
+	closers []io.Closer // Files opened by the current sinks
+)
+
+// Sink is one destination for log entries.
+type Sink struct {
+	Type      string // console (readable, stdout unless Path), file (readable) or json
+	Path      string // Log file; empty writes to stdout
+	Level     string // Minimum level written to this sink; empty = every entry logged
+	MaxSizeMB int    // A file is rotated once it reaches this size; 0 = never
+	MaxFiles  int    // Rotated files kept as <path>.1 ... <path>.N
+}
+
+type Config struct {
+	Level   string            // Overrides LOG_LEVEL when set
+	Modules map[string]string // Module -> level override, e.g. engine: debug
+	Sinks   []Sink            // Replace the stdout sink from Init when set
+}
+
+// Configure applies the sinks and levels from config. Without sinks the
+// stdout sink from Init is kept.
+func Configure(c Config) error {
+	if c.Level != "" {
+		lvl, err := level(c.Level)
+		if err != nil {
+			return err
+		}
+		globalLevel.SetLevel(lvl)
+	}
+	mods := make(map[string]zapcore.Level, len(c.Modules))
+	for m, name := range c.Modules {
+		lvl, err := level(name)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", m, err)
+		}
+		mods[strings.Trim(m, "/")] = lvl
+	}
+	modules.Store(&mods)
+
+	if len(c.Sinks) == 0 {
+		return nil
+	}
+	var cores []zapcore.Core
+	var files []io.Closer
+	for _, s := range c.Sinks {
+		core, f, err := newSinkCore(s)
+		if err != nil {
+			for _, f := range files {
+				_ = f.Close()
+			}
+			return err
+		}
+		cores = append(cores, core)
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+	setCore(zapcore.NewTee(cores...), files)
+	return nil
+}
+
+func newSinkCore(s Sink) (zapcore.Core, io.Closer, error) {
+	min := zapcore.DebugLevel
+	if s.Level != "" {
+		lvl, err := level(s.Level)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s sink: %w", s.Type, err)
+		}
+		min = lvl
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	var encoder zapcore.Encoder
+	switch s.Type {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console", "file":
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, nil, fmt.Errorf("unknown sink type %q", s.Type)
+	}
+
+	if s.Path == "" {
+		if s.Type == "file" {
+			return nil, nil, fmt.Errorf("file sink needs a path")
+		}
+		return zapcore.NewCore(encoder, zapcore.AddSync(os.Stdout), min), nil, nil
+	}
+	f, err := openRotating(s.Path, int64(s.MaxSizeMB)<<20, s.MaxFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zapcore.NewCore(encoder, zapcore.AddSync(f), min), f, nil
+}
+
+// setCore swaps the logger onto core and closes the files of the previous
+// sinks.
+func setCore(core zapcore.Core, files []io.Closer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	opts := []zap.Option{zap.AddCallerSkip(1)}
+	if caller {
+		opts = append(opts, zap.AddCaller())
+	}
+	old := globalLogger
+	globalLogger = zap.New(core, opts...).Sugar()
+	if old != nil {
+		_ = old.Sync()
+	}
+	for _, f := range closers {
+		_ = f.Close()
+	}
+	closers = files
+}
+
+// SetLevel changes the level of module, or the global level when module
+// is empty, while the bot runs.
+func SetLevel(module, name string) error {
+	lvl, err := level(name)
+	if err != nil {
+		return err
+	}
+	if module == "" {
+		globalLevel.SetLevel(lvl)
+		return nil
+	}
+	updateModules(func(m map[string]zapcore.Level) { m[strings.Trim(module, "/")] = lvl })
+	return nil
+}
+
+// ResetLevel removes the override of module, which then logs at the global
+// level.
+func ResetLevel(module string) {
+	updateModules(func(m map[string]zapcore.Level) { delete(m, strings.Trim(module, "/")) })
+}
+
+func updateModules(fn func(map[string]zapcore.Level)) {
+	mu.Lock()
+	defer mu.Unlock()
+	next := make(map[string]zapcore.Level)
+	if cur := modules.Load(); cur != nil {
+		for k, v := range *cur {
+			next[k] = v
+		}
+	}
+	fn(next)
+	modules.Store(&next)
+}
+
+// Levels returns the global level and the module overrides.
+func Levels() (string, map[string]string) {
+	out := make(map[string]string)
+	if cur := modules.Load(); cur != nil {
+		for k, v := range *cur {
+			out[k] = v.String()
+		}
+	}
+	return globalLevel.Level().String(), out
+}
+
+// enabled reports whether an entry at lvl is logged for the caller skip
+// frames above the exported logging function.
+func enabled(lvl zapcore.Level, skip int) bool {
+	mods := modules.Load()
+	if mods == nil || len(*mods) == 0 {
+		return globalLevel.Enabled(lvl)
+	}
+	if min, ok := moduleLevel(*mods, skip+3); ok {
+		return lvl >= min
+	}
+	return globalLevel.Enabled(lvl)
+}
+
+// moduleLevel finds the override for the caller's package, falling back
+// from "broker/zerodha" to "broker".
+func moduleLevel(mods map[string]zapcore.Level, skip int) (zapcore.Level, bool) {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return 0, false
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return 0, false
+	}
+	module := packageOf(fn.Name())
+	for module != "" {
+		if lvl, ok := mods[module]; ok {
+			return lvl, true
+		}
+		i := strings.LastIndex(module, "/")
+		if i < 0 {
+			break
+		}
+		module = module[:i]
+	}
+	return 0, false
+}
+
+// packageOf returns the module of a function name such as
+// "llm-trading-bot/internal/engine.(*Engine).Step".
+func packageOf(fn string) string {
+	fn = strings.TrimPrefix(fn, modulePrefix)
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		fn = fn[:slash+1+dot]
+	}
+	return fn
+}
+
+func level(name string) (zapcore.Level, error) {
+	switch strings.ToUpper(name) {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+		return parseLogLevel(name), nil
+	}
+	return 0, fmt.Errorf("unknown log level %q: want debug, info, warn or error", name)
+}
+
+// rotatingFile appends to path and renames it to path.1 once it reaches
+// maxBytes, shifting older files up to path.<keep>.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	f        *os.File
+	size     int64
+}
+
+func openRotating(path string, maxBytes int64, keep int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.keep < 1 {
+		_ = os.Remove(r.path)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.keep))
+		for i := r.keep - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Sync()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
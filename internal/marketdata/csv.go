@@ -0,0 +1,63 @@
+package marketdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"llm-trading-bot/internal/types"
+)
+
+// LoadCSV reads a candle CSV with the columns ts,open,high,low,close,volume
+// (ts in Unix seconds, header optional), oldest bar first.
+func LoadCSV(path string) ([]types.Candle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+
+	var out []types.Candle
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 6 {
+			return nil, fmt.Errorf("line %d: expected 6 columns, got %d", line, len(rec))
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "ts") {
+			continue
+		}
+
+		vals := make([]float64, 6)
+		for i := 0; i < 6; i++ {
+			v, err := strconv.ParseFloat(strings.TrimSpace(rec[i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d column %d: %w", line, i+1, err)
+			}
+			vals[i] = v
+		}
+		out = append(out, types.Candle{
+			Ts:    int64(vals[0]),
+			Open:  vals[1],
+			High:  vals[2],
+			Low:   vals[3],
+			Close: vals[4],
+			Vol:   vals[5],
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Ts < out[j].Ts })
+	return out, nil
+}
@@ -0,0 +1,82 @@
+// Package marketdata provides candle sources other than a broker feed.
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"llm-trading-bot/internal/types"
+	"llm-trading-bot/internal/warehouse"
+)
+
+// File serves candles recorded on disk, so the engine, backtests and
+// research commands can run without broker credentials and see the same
+// bars on every run. Dir holds either one <SYMBOL>.csv per symbol or the
+// candle store's <SYMBOL>/<date>.csv layout. Files are read once and kept
+// in memory.
+type File struct {
+	dir   string
+	store *warehouse.Store
+
+	mu    sync.Mutex
+	cache map[string][]types.Candle
+}
+
+func NewFile(dir string) *File {
+	return &File{dir: dir, store: warehouse.New(dir), cache: make(map[string][]types.Candle)}
+}
+
+// Candles returns every bar recorded for symbol, oldest first.
+func (f *File) Candles(symbol string) ([]types.Candle, error) {
+	symbol = types.NormalizeSymbol(symbol)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cs, ok := f.cache[symbol]; ok {
+		return cs, nil
+	}
+
+	flat := filepath.Join(f.dir, strings.ReplaceAll(symbol, ":", "_")+".csv")
+	cs, err := LoadCSV(flat)
+	if errors.Is(err, os.ErrNotExist) {
+		cs, err = f.store.All(symbol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", symbol, err)
+	}
+	if len(cs) == 0 {
+		return nil, fmt.Errorf("%s: no candles in %s", symbol, f.dir)
+	}
+	f.cache[symbol] = cs
+	return cs, nil
+}
+
+// RecentCandles returns the last n recorded bars of symbol.
+func (f *File) RecentCandles(ctx context.Context, symbol string, n int) ([]types.Candle, error) {
+	cs, err := f.Candles(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && len(cs) > n {
+		cs = cs[len(cs)-n:]
+	}
+	return append([]types.Candle(nil), cs...), nil
+}
+
+// LoadDir reads every bar of each symbol from dir.
+func LoadDir(dir string, symbols []string) (map[string][]types.Candle, error) {
+	f := NewFile(dir)
+	out := make(map[string][]types.Candle, len(symbols))
+	for _, sym := range symbols {
+		cs, err := f.Candles(sym)
+		if err != nil {
+			return nil, err
+		}
+		out[sym] = cs
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package marketdata
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
+	"llm-trading-bot/internal/types"
+)
+
+// Sanitizer drops or repairs bad bars and ticks before indicators and the
+// stop manager see them. Zero or negative prices and bars or ticks out of
+// time order are always dropped. With a jump limit, so are prices that
+// leap further than it from the previous close and come straight back.
+type Sanitizer struct {
+	maxJump float64 // Fraction; 0 disables spike checks
+
+	mu       sync.Mutex
+	seen     map[string]int64      // Newest bar time already checked per symbol, so refetched bars are counted once
+	last     map[string]types.Tick // Last accepted tick per symbol
+	rejected map[string]float64    // Last spike tick price per symbol
+}
+
+func NewSanitizer(maxJumpPct float64) *Sanitizer {
+	return &Sanitizer{
+		maxJump:  maxJumpPct / 100,
+		seen:     make(map[string]int64),
+		last:     make(map[string]types.Tick),
+		rejected: make(map[string]float64),
+	}
+}
+
+// jumped reports whether price is further than the jump limit from ref.
+func (s *Sanitizer) jumped(price, ref float64) bool {
+	return s.maxJump > 0 && ref > 0 && math.Abs(price/ref-1) > s.maxJump
+}
+
+// Candles returns symbol's bars, oldest first, without the bad ones:
+//   - a price at or below zero, or a bar not after the one before it, is dropped;
+//   - a close that jumps past the limit and is undone by the next bar is
+//     dropped; on the newest bar it is held back until the next bar shows
+//     whether the move stuck;
+//   - a high or low past the limit from the bar's open and close, or a
+//     high below the low, is clamped to the bar's body.
+//
+// The input is not modified.
+func (s *Sanitizer) Candles(ctx context.Context, symbol string, candles []types.Candle) []types.Candle {
+	s.mu.Lock()
+	seen := s.seen[symbol]
+	s.mu.Unlock()
+
+	var newest int64
+	out := make([]types.Candle, 0, len(candles))
+	for i, c := range candles {
+		if c.Ts > newest {
+			newest = c.Ts
+		}
+		fresh := c.Ts > seen
+		note := func(kind, action string) {
+			if !fresh {
+				return
+			}
+			metrics.DataAnomalies.Inc("candle", kind, action)
+			logger.Warn(ctx, "Bad candle "+action, "symbol", symbol, "kind", kind, "ts", c.Ts,
+				"open", c.Open, "high", c.High, "low", c.Low, "close", c.Close)
+		}
+
+		if c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0 {
+			note("nonpositive", "dropped")
+			continue
+		}
+		var prev *types.Candle
+		if len(out) > 0 {
+			prev = &out[len(out)-1]
+		}
+		if prev != nil && c.Ts <= prev.Ts {
+			note("out_of_order", "dropped")
+			continue
+		}
+		if prev != nil && s.jumped(c.Close, prev.Close) {
+			if i+1 == len(candles) {
+				note("spike", "held")
+				continue
+			}
+			if !s.jumped(candles[i+1].Close, prev.Close) {
+				note("spike", "dropped")
+				continue
+			}
+		}
+
+		lo, hi := math.Min(c.Open, c.Close), math.Max(c.Open, c.Close)
+		if c.High < c.Low || c.High < hi || c.Low > lo || s.jumped(c.High, hi) || s.jumped(c.Low, lo) {
+			note("wick", "repaired")
+			if c.High < hi || s.jumped(c.High, hi) {
+				c.High = hi
+			}
+			if c.Low > lo || c.Low > c.High || s.jumped(c.Low, lo) {
+				c.Low = lo
+			}
+		}
+		if c.Vol < 0 {
+			note("volume", "repaired")
+			c.Vol = 0
+		}
+		out = append(out, c)
+	}
+
+	s.mu.Lock()
+	if newest > s.seen[symbol] {
+		s.seen[symbol] = newest
+	}
+	s.mu.Unlock()
+	return out
+}
+
+// Tick reports whether tk should reach the engine. A tick at or below zero
+// or older than the last accepted one is dropped. A tick past the jump
+// limit from the last accepted price is dropped unless the tick before it
+// jumped to about the same price, which makes it a real move.
+func (s *Sanitizer) Tick(ctx context.Context, tk types.Tick) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drop := func(kind string) bool {
+		metrics.DataAnomalies.Inc("tick", kind, "dropped")
+		logger.Warn(ctx, "Bad tick dropped", "symbol", tk.Symbol, "kind", kind, "price", tk.Price, "ts", tk.Ts)
+		return false
+	}
+
+	if tk.Price <= 0 {
+		return drop("nonpositive")
+	}
+	last, ok := s.last[tk.Symbol]
+	if ok && tk.Ts > 0 && tk.Ts < last.Ts {
+		return drop("out_of_order")
+	}
+	if ok && s.jumped(tk.Price, last.Price) {
+		if r, held := s.rejected[tk.Symbol]; !held || s.jumped(tk.Price, r) {
+			s.rejected[tk.Symbol] = tk.Price
+			return drop("spike")
+		}
+	}
+	delete(s.rejected, tk.Symbol)
+	s.last[tk.Symbol] = tk
+	return true
+}
@@ -0,0 +1,46 @@
+package metrics
+
+// Metrics recorded across subsystems. Label sets are kept small: no
+// per-order or per-request identifiers.
+var (
+	TickSeconds = NewHistogram("bot_tick_seconds",
+		"Time to process one poll over the whole universe.", nil)
+	TickOverruns = NewCounter("bot_tick_overruns_total",
+		"Polls that took longer than poll_seconds.")
+	StepSeconds = NewHistogram("engine_step_seconds",
+		"Engine step latency per symbol evaluation.", nil, "result")
+
+	LLMSeconds = NewHistogram("llm_call_seconds",
+		"Decider call latency.", nil, "result")
+	LLMDecisions = NewCounter("llm_decisions_total",
+		"Decisions returned by the decider.", "action")
+	LLMQueueWaiting = NewGauge("llm_queue_waiting",
+		"LLM calls waiting for a provider slot.", "provider", "class")
+	LLMQueueSeconds = NewHistogram("llm_queue_wait_seconds",
+		"Time LLM calls waited for a provider slot.", nil, "class")
+
+	BrokerOrders = NewCounter("broker_orders_total",
+		"Orders sent to the broker.", "side", "result")
+	BrokerSeconds = NewHistogram("broker_call_seconds",
+		"Broker API call latency.", nil, "method")
+
+	APIRequests = NewCounter("api_requests_total",
+		"Outbound API requests through internal/api.", "host", "result")
+	APICache = NewCounter("api_cache_total",
+		"Response cache lookups.", "result")
+
+	SourceCalls = NewCounter("data_source_calls_total",
+		"Calls to data sources (NSE endpoints, CSV files).", "source", "result")
+	SourceScore = NewGauge("data_source_score",
+		"Recent quality score of a data source, 0-1; fallback chains try the highest first.", "source")
+	DataAnomalies = NewCounter("market_data_anomalies_total",
+		"Bad candles and ticks dropped or repaired before the engine used them.", "source", "kind", "action")
+
+	DependencyUp = NewGauge("bot_dependency_up",
+		"1 while the dependency's health check passes, 0 while it fails.", "dependency")
+
+	JobRuns = NewCounter("scheduler_job_runs_total",
+		"Scheduled job runs.", "job", "result")
+	JobSeconds = NewHistogram("scheduler_job_seconds",
+		"Scheduled job duration.", nil, "job")
+)
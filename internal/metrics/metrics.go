@@ -0,0 +1,205 @@
+// Package metrics keeps process-wide counters and histograms and serves
+// them in the Prometheus text exposition format on /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type metric interface {
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []metric
+)
+
+func register(m metric) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, m)
+}
+
+// WriteAll writes every registered metric.
+func WriteAll(w io.Writer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, m := range registry {
+		m.write(w)
+	}
+}
+
+type family struct {
+	name, help string
+	labels     []string
+}
+
+func (f family) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, kind)
+}
+
+// key joins label values; labelString renders them for output.
+func key(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func (f family) labelString(k string, extra ...string) string {
+	var parts []string
+	if len(f.labels) > 0 {
+		values := strings.Split(k, "\xff")
+		for i, l := range f.labels {
+			v := ""
+			if i < len(values) {
+				v = values[i]
+			}
+			parts = append(parts, fmt.Sprintf("%s=%q", l, v))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		parts = append(parts, fmt.Sprintf("%s=%q", extra[i], extra[i+1]))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Counter is a monotonically increasing value per label set.
+type Counter struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: family{name, help, labels}, values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.mu.Lock()
+	c.values[key(labelValues)] += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, k := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %g\n", c.name, c.labelString(k), c.values[k])
+	}
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{family: family{name, help, labels}, values: make(map[string]float64)}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.mu.Lock()
+	g.values[key(labelValues)] = v
+	g.mu.Unlock()
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.header(w, "gauge")
+	for _, k := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %g\n", g.name, g.labelString(k), g.values[k])
+	}
+}
+
+// DefaultBuckets suit latencies in seconds, from 5ms to 1 minute.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histSeries
+}
+
+type histSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &Histogram{family: family{name, help, labels}, buckets: buckets, series: make(map[string]*histSeries)}
+	register(h)
+	return h
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	k := key(labelValues)
+	s, ok := h.series[k]
+	if !ok {
+		s = &histSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", formatBound(b)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %g\n", h.name, h.labelString(k), s.sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(k), s.count)
+	}
+}
+
+func formatBound(b float64) string {
+	if math.IsInf(b, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", b)
+}
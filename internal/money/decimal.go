@@ -0,0 +1,154 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of Amount units in a rupee: amounts are exact to
+// 1/10000, which holds any exchange tick and sub-paisa averages.
+const Scale = 10000
+
+// Amount is a fixed-point rupee value for prices, costs and P&L. Sums and
+// products by a quantity are exact; only division rounds, half away from
+// zero, to the nearest 1/10000. It marshals to JSON as a plain number, so
+// logs written with float64 prices read back unchanged.
+type Amount int64
+
+// FromFloat converts a float64 price, e.g. from a broker or a candle, to
+// the nearest Amount.
+func FromFloat(v float64) Amount {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return Amount(math.Round(v * Scale))
+}
+
+// Float converts a to float64 for indicators, ratios and the broker APIs.
+func (a Amount) Float() float64 {
+	return float64(a) / Scale
+}
+
+// Mul returns a times qty, e.g. the value of qty shares at price a.
+func (a Amount) Mul(qty int) Amount {
+	return a * Amount(qty)
+}
+
+// Div returns a divided by n, e.g. the average price of a total cost.
+func (a Amount) Div(n int) Amount {
+	return a.MulDiv(1, n)
+}
+
+// MulDiv returns a*num/den without overflowing on the intermediate
+// product, e.g. the share of a cost basis released by a partial sale.
+func (a Amount) MulDiv(num, den int) Amount {
+	if den == 0 {
+		return 0
+	}
+	if den < 0 {
+		num, den = -num, -den
+	}
+	q, r := int64(a)/int64(den), int64(a)%int64(den)
+	// |r| < den, so r*num stays below den*num
+	rem := r * int64(num)
+	frac := rem / int64(den)
+	if left := rem % int64(den); 2*abs64(left) >= int64(den) {
+		if rem < 0 {
+			frac--
+		} else {
+			frac++
+		}
+	}
+	return Amount(q*int64(num) + frac)
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// String renders a with at least two and at most four decimals, e.g.
+// "1234.5" as "1234.50" and a sub-paisa average as "101.3333".
+func (a Amount) String() string {
+	neg := a < 0
+	u := uint64(a)
+	if neg {
+		u = uint64(-a)
+	}
+	frac := fmt.Sprintf("%04d", u%Scale)
+	frac = strings.TrimRight(frac, "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	s := strconv.FormatUint(u/Scale, 10) + "." + frac
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// ParseAmount reads a plain decimal such as "1234.55" or "-0.05" exactly;
+// digits beyond the fourth decimal are rounded. Other number forms, such
+// as exponents, go through float64.
+func ParseAmount(s string) (Amount, error) {
+	t := strings.TrimSpace(s)
+	neg := strings.HasPrefix(t, "-")
+	t = strings.TrimPrefix(strings.TrimPrefix(t, "-"), "+")
+	whole, frac, _ := strings.Cut(t, ".")
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) || len(whole) > 14 {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		return FromFloat(v), nil
+	}
+
+	var n int64
+	for _, c := range whole {
+		n = n*10 + int64(c-'0')
+	}
+	for i := 0; i < 4; i++ {
+		n *= 10
+		if i < len(frac) {
+			n += int64(frac[i] - '0')
+		}
+	}
+	if len(frac) > 4 && frac[4] >= '5' {
+		n++
+	}
+	if neg {
+		n = -n
+	}
+	return Amount(n), nil
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" || s == "" {
+		*a = 0
+		return nil
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package money formats and parses rupee amounts the way Indian filings
+// and brokers write them: lakh/crore digit grouping (12,34,567.89) and
+// amounts quoted in lakh or crore ("₹1.2 Cr", "45 lakh"). Amount is the
+// fixed-point type that prices, costs and P&L are kept in.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	Lakh  = 1e5
+	Crore = 1e7
+)
+
+// ErrInvalid is returned by Parse for text that is not an amount.
+var ErrInvalid = errors.New("invalid amount")
+
+// Format renders v with two decimals and Indian digit grouping, e.g.
+// -1234567.8 as "-₹12,34,567.80".
+func Format(v float64) string {
+	return format(v, 2, true)
+}
+
+// Plain is Format without the rupee sign, for tables with a ₹ header.
+func Plain(v float64) string {
+	return format(v, 2, false)
+}
+
+// Signed is Format with an explicit + on gains, for P&L.
+func Signed(v float64) string {
+	if v > 0 && round(v, 2) > 0 {
+		return "+" + Format(v)
+	}
+	return Format(v)
+}
+
+// Compact renders large amounts in crore or lakh with up to two decimals
+// ("₹1.25 Cr", "₹4.5 L") and smaller ones like Format.
+func Compact(v float64) string {
+	a := math.Abs(v)
+	var unit string
+	var n float64
+	switch {
+	case a >= Crore:
+		n, unit = v/Crore, " Cr"
+	case a >= Lakh:
+		n, unit = v/Lakh, " L"
+	default:
+		return Format(v)
+	}
+	s := strconv.FormatFloat(round(math.Abs(n), 2), 'f', -1, 64)
+	if n < 0 {
+		return "-₹" + s + unit
+	}
+	return "₹" + s + unit
+}
+
+func format(v float64, decimals int, sign bool) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', decimals, 64)
+	}
+	v = round(v, decimals)
+	neg := v < 0
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	if sign {
+		sb.WriteString("₹")
+	}
+	sb.WriteString(group(whole))
+	if frac != "" {
+		sb.WriteByte('.')
+		sb.WriteString(frac)
+	}
+	return sb.String()
+}
+
+// group inserts commas after the last three digits and then every two.
+func group(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var parts []string
+	for len(head) > 2 {
+		parts = append([]string{head[len(head)-2:]}, parts...)
+		head = head[:len(head)-2]
+	}
+	parts = append([]string{head}, parts...)
+	return strings.Join(parts, ",") + "," + tail
+}
+
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
+
+// units maps the suffixes Parse understands to their multiplier.
+var units = []struct {
+	names []string
+	mult  float64
+}{
+	{[]string{"crores", "crore", "cr.", "cr"}, Crore},
+	{[]string{"lakhs", "lakh", "lacs", "lac", "l"}, Lakh},
+	{[]string{"thousand", "k"}, 1e3},
+	{[]string{"million", "mn", "m"}, 1e6},
+	{[]string{"billion", "bn", "b"}, 1e9},
+}
+
+// Parse reads an amount such as "₹1,23,456.50", "Rs. 45 lakh", "1.2 Cr",
+// "(3,400)" or "-2.5cr" and returns it in rupees. Parentheses mean a
+// negative amount, as in financial statements.
+func Parse(s string) (float64, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	neg := false
+	if strings.HasPrefix(t, "(") && strings.HasSuffix(t, ")") {
+		neg, t = true, strings.TrimSpace(t[1:len(t)-1])
+	}
+	if strings.HasPrefix(t, "-") {
+		neg, t = !neg, strings.TrimSpace(t[1:])
+	}
+	for _, p := range []string{"₹", "inr", "rs.", "rs"} {
+		if strings.HasPrefix(t, p) {
+			t = strings.TrimSpace(t[len(p):])
+			break
+		}
+	}
+	if strings.HasPrefix(t, "-") {
+		neg, t = !neg, strings.TrimSpace(t[1:])
+	}
+
+	mult := 1.0
+unit:
+	for _, u := range units {
+		for _, name := range u.names {
+			if strings.HasSuffix(t, name) {
+				num := strings.TrimSpace(t[:len(t)-len(name)])
+				// "l" and friends must follow a number, not end a word.
+				if num == "" || !strings.ContainsAny(num[len(num)-1:], "0123456789.") {
+					continue
+				}
+				mult, t = u.mult, num
+				break unit
+			}
+		}
+	}
+
+	t = strings.ReplaceAll(t, ",", "")
+	v, err := strconv.ParseFloat(t, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	v *= mult
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// Paise converts rupees to whole paise, rounding half away from zero, for
+// comparisons that must not drift with floating point.
+func Paise(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
+
+// Rupees converts paise back to rupees.
+func Rupees(p int64) float64 {
+	return float64(p) / 100
+}
@@ -0,0 +1,140 @@
+// Package notify sends operator alerts to chat channels (Telegram, Slack).
+// Alerts are queued and delivered by a background worker so a slow or
+// unreachable channel never blocks the trading loop.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+const (
+	EventTrade   = "trade"
+	EventStop    = "stop"
+	EventEOD     = "eod"
+	EventJob     = "job"     // A scheduled job failed
+	EventAuth    = "auth"    // A broker or LLM rejected the bot's credentials
+	EventHealth  = "health"  // A critical dependency failed its health check
+	EventBrief   = "brief"   // The pre-market briefing
+	EventCrash   = "crash"   // A component panicked and was recovered
+	EventHalt    = "halt"    // A symbol hit a circuit limit or stopped trading, or resumed
+	EventCircuit = "circuit" // The circuit breaker paused a dependency after repeated failures
+)
+
+const (
+	queueSize   = 100
+	sendTimeout = 10 * time.Second
+)
+
+// Route delivers the listed events to one channel; no events means all.
+type Route struct {
+	Name     string
+	Notifier interfaces.Notifier
+	Events   []string
+}
+
+func (r Route) wants(event string) bool {
+	if len(r.Events) == 0 {
+		return true
+	}
+	for _, e := range r.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher fans notifications out to its routes from a single worker.
+type Dispatcher struct {
+	routes []Route
+	queue  chan types.Notification
+	done   chan struct{}
+}
+
+var _ interfaces.Notifier = (*Dispatcher)(nil)
+
+func NewDispatcher(routes ...Route) *Dispatcher {
+	d := &Dispatcher{
+		routes: routes,
+		queue:  make(chan types.Notification, queueSize),
+		done:   make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Notify queues n; it drops the notification if the queue is full.
+func (d *Dispatcher) Notify(ctx context.Context, n types.Notification) error {
+	select {
+	case d.queue <- n:
+	default:
+		logger.Warn(ctx, "Notification queue full - dropping", "event", n.Event, "symbol", n.Symbol)
+	}
+	return nil
+}
+
+// Close delivers what is queued, waiting at most until ctx is done.
+func (d *Dispatcher) Close(ctx context.Context) {
+	close(d.queue)
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+	}
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for n := range d.queue {
+		for _, r := range d.routes {
+			if !r.wants(n.Event) {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			if err := r.Notifier.Notify(ctx, n); err != nil {
+				logger.Warn(ctx, "Failed to send notification", "channel", r.Name, "event", n.Event, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(context.Context, types.Notification) error { return nil }
+
+var defaultNotifier interfaces.Notifier = nopNotifier{}
+
+func SetDefaultNotifier(n interfaces.Notifier) {
+	defaultNotifier = n
+}
+
+type accountKey struct{}
+
+// WithAccount returns ctx whose notifications name account.
+func WithAccount(ctx context.Context, account string) context.Context {
+	return context.WithValue(ctx, accountKey{}, account)
+}
+
+// Send notifies through the default notifier (a no-op until configured).
+func Send(ctx context.Context, event, symbol, text string) {
+	account, _ := ctx.Value(accountKey{}).(string)
+	_ = defaultNotifier.Notify(ctx, types.Notification{Event: event, Account: account, Symbol: symbol, Text: text})
+}
+
+func format(n types.Notification) string {
+	subject := n.Symbol
+	if n.Account != "" {
+		subject = strings.TrimSpace(n.Account + " " + n.Symbol)
+	}
+	if subject == "" {
+		return fmt.Sprintf("[%s] %s", n.Event, n.Text)
+	}
+	return fmt.Sprintf("[%s] %s: %s", n.Event, subject, n.Text)
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/types"
+)
+
+// Slack posts to an incoming webhook.
+type Slack struct {
+	webhook string
+}
+
+func NewSlack(webhook string) *Slack {
+	return &Slack{webhook: webhook}
+}
+
+func (s *Slack) Notify(ctx context.Context, n types.Notification) error {
+	body, _ := json.Marshal(map[string]string{"text": format(n)})
+	req, err := http.NewRequestWithContext(api.WithoutFixtures(ctx), http.MethodPost, s.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack http %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/types"
+)
+
+// Telegram posts to a chat through the Bot API.
+type Telegram struct {
+	token  string
+	chatID string
+}
+
+func NewTelegram(token, chatID string) *Telegram {
+	return &Telegram{token: token, chatID: chatID}
+}
+
+func (t *Telegram) Notify(ctx context.Context, n types.Notification) error {
+	body, _ := json.Marshal(map[string]any{
+		"chat_id": t.chatID,
+		"text":    format(n),
+	})
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.token)
+	req, err := http.NewRequestWithContext(api.WithoutFixtures(ctx), http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram http %d", resp.StatusCode)
+	}
+	return nil
+}
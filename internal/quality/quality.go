@@ -0,0 +1,216 @@
+// Package quality scores the bot's data sources (NSE endpoints, CSV files)
+// by how they have been answering lately: success rate, latency and the
+// share of returned records that could be used. Chains of sources for the
+// same data are tried best score first, so a source that starts failing
+// or returning half-empty data drops behind its fallback on its own.
+package quality
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/metrics"
+)
+
+// alpha weighs the newest call in the moving averages; about the last ten
+// calls dominate a source's score.
+const alpha = 0.2
+
+// Stats is a source's recent record. Rates are exponentially weighted
+// moving averages in [0, 1].
+type Stats struct {
+	Source       string    `json:"source"`
+	Calls        int       `json:"calls"`
+	Failures     int       `json:"failures"`
+	SuccessRate  float64   `json:"success_rate"`
+	LatencyMS    float64   `json:"latency_ms"`   // Successful calls only
+	Completeness float64   `json:"completeness"` // Usable share of returned records
+	LastError    string    `json:"last_error,omitempty"`
+	LastCall     time.Time `json:"last_call"`
+}
+
+// Score is success rate times completeness, discounted by latency: a
+// source answering in one second scores half of an instant one.
+func (s Stats) Score() float64 {
+	return s.SuccessRate * s.Completeness / (1 + s.LatencyMS/1000)
+}
+
+// Tracker records calls and ranks sources. It saves its stats to path
+// after each call so scores survive restarts and the sources command can
+// read them; an empty path keeps them in memory.
+type Tracker struct {
+	path string
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// New returns a tracker seeded from path when it exists.
+func New(path string) *Tracker {
+	t := &Tracker{path: path, stats: make(map[string]*Stats)}
+	if list, err := Load(path); err == nil {
+		for i := range list {
+			s := list[i]
+			t.stats[s.Source] = &s
+			metrics.SourceScore.Set(s.Score(), s.Source)
+		}
+	}
+	return t
+}
+
+// Record adds one call to source's stats. usable and total count the
+// records returned; a call returning none leaves completeness unchanged.
+func (t *Tracker) Record(source string, latency time.Duration, err error, usable, total int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stats[source]
+	first := s == nil
+	if first {
+		s = &Stats{Source: source, Completeness: 1}
+		t.stats[source] = s
+	}
+	s.Calls++
+	s.LastCall = time.Now()
+
+	ok := 0.0
+	if err != nil {
+		s.Failures++
+		s.LastError = err.Error()
+		metrics.SourceCalls.Inc(source, "error")
+	} else {
+		ok = 1
+		s.LastError = ""
+		s.LatencyMS = ewma(s.LatencyMS, float64(latency.Milliseconds()), first || s.Calls == s.Failures+1)
+		if total > 0 {
+			s.Completeness = ewma(s.Completeness, float64(usable)/float64(total), first)
+		}
+		metrics.SourceCalls.Inc(source, "ok")
+	}
+	s.SuccessRate = ewma(s.SuccessRate, ok, first)
+	metrics.SourceScore.Set(s.Score(), source)
+
+	t.save()
+}
+
+func ewma(prev, v float64, first bool) float64 {
+	if first {
+		return v
+	}
+	return prev + alpha*(v-prev)
+}
+
+// Score returns source's score and whether it has been called yet.
+func (t *Tracker) Score(source string) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.stats[source]
+	if !ok {
+		return 0, false
+	}
+	return s.Score(), true
+}
+
+// Snapshot returns every source's stats, best score first.
+func (t *Tracker) Snapshot() []Stats {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Stats, 0, len(t.stats))
+	for _, s := range t.stats {
+		out = append(out, *s)
+	}
+	sortStats(out)
+	return out
+}
+
+func (t *Tracker) save() {
+	if t.path == "" {
+		return
+	}
+	out := make([]Stats, 0, len(t.stats))
+	for _, s := range t.stats {
+		out = append(out, *s)
+	}
+	sortStats(out)
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return
+	}
+	tmp := t.path + ".tmp"
+	if os.WriteFile(tmp, b, 0o644) == nil {
+		_ = os.Rename(tmp, t.path)
+	}
+}
+
+func sortStats(list []Stats) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if a, b := list[i].Score(), list[j].Score(); a != b {
+			return a > b
+		}
+		return list[i].Source < list[j].Source
+	})
+}
+
+// Load reads stats saved by a tracker, best score first.
+func Load(path string) ([]Stats, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []Stats
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, err
+	}
+	sortStats(list)
+	return list, nil
+}
+
+// Rank orders items best score first. Sources never called rank as if
+// perfect so they get tried; ties keep the configured order.
+func Rank[T any](t *Tracker, items []T, name func(T) string) []T {
+	out := append([]T(nil), items...)
+	if t == nil || len(out) < 2 {
+		return out
+	}
+	score := func(item T) float64 {
+		if s, ok := t.Score(name(item)); ok {
+			return s
+		}
+		return 1
+	}
+	sort.SliceStable(out, func(i, j int) bool { return score(out[i]) > score(out[j]) })
+	return out
+}
+
+var defaultTracker *Tracker
+
+// SetDefault installs the tracker used by Record and Default; until then
+// calls are not tracked and chains keep their configured order.
+func SetDefault(t *Tracker) {
+	defaultTracker = t
+}
+
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// Record adds a call to the default tracker.
+func Record(source string, start time.Time, err error, usable, total int) {
+	defaultTracker.Record(source, time.Since(start), err, usable, total)
+}
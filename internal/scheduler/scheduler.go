@@ -0,0 +1,102 @@
+// Package scheduler runs named jobs on cron-like schedules in one
+// long-lived process. Each run gets its own trace span and metrics, and a
+// failed run is logged and sent as a job alert.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/crash"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/metrics"
+	"llm-trading-bot/internal/notify"
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/trace"
+)
+
+// Job is a task run on a schedule. Timeout bounds one run; zero means no
+// limit beyond the scheduler's context.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs each job in its own goroutine. A job never overlaps with
+// itself: a run that outlasts its interval skips the runs it missed.
+type Scheduler struct {
+	jobs []Job
+}
+
+func New(jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs}
+}
+
+// Run blocks until ctx is cancelled and every in-flight run has returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j Job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j Job) {
+	for {
+		next := j.Schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.Warn(ctx, "Job schedule never fires - job disabled", "job", j.Name)
+			return
+		}
+		logger.Debug(ctx, "Job scheduled", "job", j.Name, "next_run", next.In(timeutil.IST).Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.RunNow(ctx, j)
+	}
+}
+
+// RunNow runs j once, outside its schedule, and returns its error.
+func (s *Scheduler) RunNow(ctx context.Context, j Job) (err error) {
+	ctx, span := trace.StartSpan(ctx, "job:"+j.Name)
+	defer span.End()
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	logger.Info(ctx, "Job started", "job", j.Name)
+	defer func() {
+		elapsed := time.Since(start)
+		metrics.JobSeconds.Observe(elapsed.Seconds(), j.Name)
+		if err != nil {
+			metrics.JobRuns.Inc(j.Name, "error")
+			logger.ErrorWithErr(ctx, "Job failed", err, "job", j.Name, "duration_ms", elapsed.Milliseconds())
+			notify.Send(ctx, notify.EventJob, "", fmt.Sprintf("job %s failed after %s: %v", j.Name, elapsed.Round(time.Millisecond), err))
+			return
+		}
+		metrics.JobRuns.Inc(j.Name, "ok")
+		logger.Info(ctx, "Job finished", "job", j.Name, "duration_ms", elapsed.Milliseconds())
+	}()
+
+	if crash.Guard(ctx, "job:"+j.Name, func() { err = j.Run(ctx) }, "job", j.Name) {
+		err = errors.New("panicked - see the crash report")
+	}
+	return err
+}
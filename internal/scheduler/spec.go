@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// Schedule reports the next run time strictly after t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Parse reads a schedule: a five-field cron expression
+// ("minute hour day-of-month month day-of-week", evaluated in IST), one of
+// @hourly, @daily or @weekdays, or "@every <duration>".
+//
+// Cron fields accept *, lists (1,15), ranges (1-5) and steps (*/15,
+// 9-15/2). Day of week runs 0-6 from Sunday; 7 is also Sunday. As in cron,
+// when both day fields are restricted a day matching either one runs.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily":
+		spec = "0 0 * * *"
+	case "@weekdays":
+		spec = "0 0 * * 1-5"
+	}
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least 1s", spec)
+		}
+		return every(d), nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: want 5 fields (minute hour day month weekday) or @every <duration>", spec)
+	}
+	var c cron
+	var err error
+	bounds := []struct {
+		dst      *uint64
+		min, max int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		if *b.dst, err = parseField(fields[i], b.min, b.max); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+	return c, nil
+}
+
+// parseField returns a bitmask of the values a cron field allows.
+func parseField(field string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("bad value in %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("bad range in %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+func (c cron) Next(t time.Time) time.Time {
+	t = t.In(timeutil.IST).Truncate(time.Minute).Add(time.Minute)
+	// Any valid expression matches within a few years (Feb 29 is the
+	// rarest day); give up after that rather than loop forever
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, timeutil.IST)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, timeutil.IST)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, timeutil.IST)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<t.Weekday()) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
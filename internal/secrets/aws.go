@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+)
+
+// NewAWS reads one AWS Secrets Manager secret whose SecretString is a JSON
+// object of key/value pairs. Credentials come from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and, for temporary credentials, AWS_SESSION_TOKEN.
+func NewAWS(ctx context.Context, region, secretID string) (interfaces.SecretProvider, error) {
+	keyID, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if keyID == "" || secret == "" {
+		return nil, fmt.Errorf("aws: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("aws: region is required")
+	}
+
+	body, _ := json.Marshal(map[string]string{"SecretId": secretID})
+	host := "secretsmanager." + region + ".amazonaws.com"
+	req, err := http.NewRequestWithContext(api.WithoutCache(ctx), http.MethodPost, "https://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signV4(req, body, keyID, secret, region, "secretsmanager", time.Now().UTC())
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("aws: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aws: %s reading %s: %s", resp.Status, secretID, strings.TrimSpace(string(respBody)))
+	}
+
+	var out struct {
+		SecretString string
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("aws: decode response: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out.SecretString), &raw); err != nil {
+		return nil, fmt.Errorf("aws: secret %s is not a JSON object of keys: %w", secretID, err)
+	}
+
+	return &mapProvider{name: "aws:" + secretID, values: stringValues(raw)}, nil
+}
+
+// signV4 adds an AWS Signature Version 4 Authorization header, signing
+// the host, content-type and every x-amz-* header.
+func signV4(req *http.Request, body []byte, keyID, secret, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(v[0])
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonHeaders.String(), signed, sha256Hex(body),
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+secret), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", keyID, scope, signed, sig))
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"llm-trading-bot/internal/interfaces"
+)
+
+type envProvider struct{}
+
+var _ interfaces.SecretProvider = envProvider{}
+
+// NewEnv reads secrets from the process environment (and .env, once
+// godotenv has loaded it).
+func NewEnv() interfaces.SecretProvider {
+	return envProvider{}
+}
+
+func (envProvider) Name() string { return "env" }
+
+func (envProvider) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type fileProvider struct {
+	dir    string
+	values map[string]string
+}
+
+var _ interfaces.SecretProvider = (*fileProvider)(nil)
+
+// NewFile reads one secret per file from dir, named after the key, as
+// Docker and Kubernetes mount them (e.g. /run/secrets/KITE_API_KEY).
+func NewFile(dir string) (interfaces.SecretProvider, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &fileProvider{dir: dir, values: make(map[string]string)}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		p.values[e.Name()] = strings.TrimSpace(string(b))
+	}
+	return p, nil
+}
+
+func (p *fileProvider) Name() string { return "file:" + p.dir }
+
+func (p *fileProvider) Lookup(key string) (string, bool) {
+	v, ok := p.values[key]
+	return v, ok
+}
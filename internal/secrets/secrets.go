@@ -0,0 +1,53 @@
+// Package secrets resolves API keys and tokens from a chain of providers
+// (environment, files, AWS Secrets Manager, Vault). The first provider
+// holding a key wins; the default chain is the environment alone.
+package secrets
+
+import (
+	"strings"
+	"sync"
+
+	"llm-trading-bot/internal/interfaces"
+)
+
+var (
+	mu        sync.RWMutex
+	providers = []interfaces.SecretProvider{NewEnv()}
+)
+
+// SetDefault replaces the provider chain used by Get.
+func SetDefault(chain ...interfaces.SecretProvider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers = chain
+}
+
+// Get returns the secret, or "" when no provider has it.
+func Get(key string) string {
+	v, _ := Lookup(key)
+	return v
+}
+
+// Lookup returns the secret and the name of the provider that held it.
+func Lookup(key string) (value, source string) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, p := range providers {
+		if v, ok := p.Lookup(key); ok && v != "" {
+			return v, p.Name()
+		}
+	}
+	return "", ""
+}
+
+// Mask hides all but the first few characters of a secret for logging.
+func Mask(v string) string {
+	if v == "" {
+		return ""
+	}
+	show := 4
+	if len(v) <= 8 {
+		show = 0
+	}
+	return v[:show] + strings.Repeat("*", 8)
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/interfaces"
+)
+
+// mapProvider serves a bundle of secrets fetched once at startup.
+type mapProvider struct {
+	name   string
+	values map[string]string
+}
+
+var _ interfaces.SecretProvider = (*mapProvider)(nil)
+
+func (p *mapProvider) Name() string { return p.name }
+
+func (p *mapProvider) Lookup(key string) (string, bool) {
+	v, ok := p.values[key]
+	return v, ok
+}
+
+// NewVault reads every key of one Vault secret, e.g. path
+// "secret/data/llm-trading-bot" for a KV v2 mount or "secret/llm-trading-bot"
+// for KV v1.
+func NewVault(ctx context.Context, addr, token, path string) (interfaces.SecretProvider, error) {
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault: VAULT_ADDR and VAULT_TOKEN are required")
+	}
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(api.WithoutCache(ctx), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault: %s reading %s", resp.Status, path)
+	}
+
+	var out struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("vault: decode response: %w", err)
+	}
+	// KV v2 nests the key/value pairs under data.data
+	raw := out.Data
+	if nested, ok := raw["data"]; ok {
+		if err := json.Unmarshal(nested, &raw); err != nil {
+			return nil, fmt.Errorf("vault: decode response: %w", err)
+		}
+	}
+
+	return &mapProvider{name: "vault:" + path, values: stringValues(raw)}, nil
+}
+
+func stringValues(raw map[string]json.RawMessage) map[string]string {
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			values[k] = s
+		} else {
+			values[k] = string(v)
+		}
+	}
+	return values
+}
@@ -4,17 +4,57 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"llm-trading-bot/internal/types"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Profile        string   `yaml:"-"` // Active profile, set by LoadProfile
+	Account        string   `yaml:"-"` // Name under accounts:, set by LoadAccounts; empty for a single account
+	Competing      bool     `yaml:"-"` // Account is a competition entrant, set by LoadCompetition
 	Mode           string   `yaml:"mode"`
+	Broker         string   `yaml:"broker"`
+	SecretPrefix   string   `yaml:"secret_prefix"` // Prepended to broker credential names, e.g. FAMILY_ reads FAMILY_KITE_API_KEY
 	DataSource     string   `yaml:"data_source"`
 	PollSeconds    int      `yaml:"poll_seconds"`
+	PollWorkers    int      `yaml:"poll_workers"` // Symbols stepped concurrently per poll
 	Exchange       string   `yaml:"exchange"`
 	UniverseStatic []string `yaml:"universe_static"`
-	Qty            struct {
+	Universe       struct {
+		Sources        []string `yaml:"sources"`         // Merged in order: static | index
+		Include        []string `yaml:"include"`         // Always traded
+		Exclude        []string `yaml:"exclude"`         // Never traded; wins over include and sources
+		MaxSize        int      `yaml:"max_size"`        // 0 = no limit
+		RefreshMinutes int      `yaml:"refresh_minutes"` // 0 = resolve once at startup
+		AuditFile      string   `yaml:"audit_file"`      // Additions and removals, one JSON line each
+		Index          struct {
+			Names       []string `yaml:"names"`        // e.g. "NIFTY 50", "NIFTY 100", "NIFTY MIDCAP 100"
+			CacheDir    string   `yaml:"cache_dir"`    // Fetched constituent lists
+			RefreshDays int      `yaml:"refresh_days"` // Refetch lists older than this
+		} `yaml:"index"`
+	} `yaml:"universe"`
+	Symbols struct {
+		Enabled      bool   `yaml:"enabled"`       // Load the NSE symbol master: order checks, company and industry for the decider
+		CacheDir     string `yaml:"cache_dir"`     // Fetched master files
+		RefreshHours int    `yaml:"refresh_hours"` // Refetch files older than this
+	} `yaml:"symbols"`
+	Candles struct {
+		IntervalMinutes int    `yaml:"interval_minutes"`
+		Backfill        int    `yaml:"backfill"`
+		StoreDir        string `yaml:"store_dir"` // Every received bar, one CSV per symbol and day; empty disables
+		FileDir         string `yaml:"file_dir"`  // Recorded bars read when data_source is FILE
+	} `yaml:"candles"`
+	Event struct {
+		Enabled            bool    `yaml:"enabled"`
+		StopProximityPct   float64 `yaml:"stop_proximity_pct"`
+		MinIntervalSeconds int     `yaml:"min_interval_seconds"`
+	} `yaml:"event"`
+	Qty struct {
 		DefaultBuy  int            `yaml:"default_buy"`
 		DefaultSell int            `yaml:"default_sell"`
 		PerSymbol   map[string]int `yaml:"per_symbol"`
@@ -22,21 +62,234 @@ type Config struct {
 	Risk struct {
 		MaxDailyDrawdownPct float64 `yaml:"max_daily_drawdown_pct"`
 		PerTradeRiskPct     float64 `yaml:"per_trade_risk_pct"`
+		DryRunFunds         float64 `yaml:"dry_run_funds"`
+
+		MaxOrdersPerSymbolPerDay int    `yaml:"max_orders_per_symbol_per_day"` // 0 = no limit; stop-loss and flatten exits are never refused
+		MaxTotalOrdersPerDay     int    `yaml:"max_total_orders_per_day"`      // 0 = no limit
+		OrderCountFile           string `yaml:"order_count_file"`              // Today's counts, kept across restarts; empty = in memory only
 	} `yaml:"risk"`
+	Alpaca struct {
+		TradingURL string `yaml:"trading_url"`
+		DataURL    string `yaml:"data_url"`
+		StreamURL  string `yaml:"stream_url"`
+		Feed       string `yaml:"feed"`
+	} `yaml:"alpaca"`
+	Paper struct {
+		Feed           string  `yaml:"feed"`
+		InitialCash    float64 `yaml:"initial_cash"`
+		SlippageBps    float64 `yaml:"slippage_bps"`
+		LatencyMs      int     `yaml:"latency_ms"`
+		MaxVolumePct   float64 `yaml:"max_volume_pct"`
+		MarketOrderTTL int     `yaml:"market_order_ttl_bars"`
+	} `yaml:"paper"`
+	Execution struct {
+		Style             string  `yaml:"style"`
+		MaxCrossSpreadBps float64 `yaml:"max_cross_spread_bps"`
+	} `yaml:"execution"`
 	Stop struct {
 		Mode     string  `yaml:"mode"`
 		Pct      float64 `yaml:"pct"`
 		ATRMult  float64 `yaml:"atr_mult"`
 		Trailing bool    `yaml:"trailing"`
 		MinTick  float64 `yaml:"min_tick"`
+
+		ServerSide     bool    `yaml:"server_side"`
+		ServerLimitPct float64 `yaml:"server_limit_pct"`
 	} `yaml:"stop"`
+	CircuitBreaker struct {
+		SymbolFailures  int `yaml:"symbol_failures"`
+		GlobalFailures  int `yaml:"global_failures"`
+		CooldownSeconds int `yaml:"cooldown_seconds"`
+	} `yaml:"circuit_breaker"`
+	Halt struct {
+		FrozenBars   int `yaml:"frozen_bars"`   // Flat traded bars in a row at one price that suggest a halt, confirmed by the quote; 0 disables
+		StaleMinutes int `yaml:"stale_minutes"` // LIVE data: lag behind the other symbols' newest bar that means no ticks; 0 disables
+	} `yaml:"halt"`
+	DataFilter struct {
+		MaxJumpPct float64 `yaml:"max_jump_pct"` // Drop a close or tick this far from the previous one that is undone straight after; 0 disables
+	} `yaml:"data_filter"`
+	Lifecycle struct {
+		StateFile       string   `yaml:"state_file"`       // Saved symbol statuses; empty keeps them in memory
+		CooldownMinutes int      `yaml:"cooldown_minutes"` // No re-entry for this long after an exit (bar time)
+		Blocked         []string `yaml:"blocked"`          // Symbols never sent to the decider
+	} `yaml:"lifecycle"`
 	Indicators struct {
 		SMAWindows []int   `yaml:"sma_windows"`
 		RSIPeriod  int     `yaml:"rsi_period"`
 		BBWindow   int     `yaml:"bb_window"`
 		BBStdDev   float64 `yaml:"bb_stddev"`
 		ATRPeriod  int     `yaml:"atr_period"`
+
+		SupertrendPeriod int     `yaml:"supertrend_period"`
+		SupertrendMult   float64 `yaml:"supertrend_mult"`
+
+		IchimokuTenkan       int `yaml:"ichimoku_tenkan"`
+		IchimokuKijun        int `yaml:"ichimoku_kijun"`
+		IchimokuSenkou       int `yaml:"ichimoku_senkou"`
+		IchimokuDisplacement int `yaml:"ichimoku_displacement"`
+
+		KeltnerPeriod    int     `yaml:"keltner_period"`
+		KeltnerATRPeriod int     `yaml:"keltner_atr_period"`
+		KeltnerMult      float64 `yaml:"keltner_mult"`
+
+		Recompute bool `yaml:"recompute"` // Full recompute every step instead of incremental state
+
+		PatternLookback int `yaml:"pattern_lookback"` // Candles scanned for candlestick patterns
 	} `yaml:"indicators"`
+	API struct {
+		TimeoutSeconds   int                `yaml:"timeout_seconds"`
+		RatePerSecond    float64            `yaml:"rate_per_second"` // Per host; 0 = unlimited
+		Burst            int                `yaml:"burst"`
+		HostRates        map[string]float64 `yaml:"host_rates"`
+		FailureThreshold int                `yaml:"failure_threshold"` // Consecutive failures before a host is paused
+		CooldownSeconds  int                `yaml:"cooldown_seconds"`
+
+		Cookies          bool              `yaml:"cookies"`
+		WarmUp           map[string]string `yaml:"warm_up"` // Host -> page visited first for session cookies
+		WarmUpTTLSeconds int               `yaml:"warm_up_ttl_seconds"`
+
+		CacheDir        string `yaml:"cache_dir"` // Empty disables response caching
+		CacheTTLSeconds int    `yaml:"cache_ttl_seconds"`
+		CacheMaxMB      int    `yaml:"cache_max_mb"`
+		CacheBypass     bool   `yaml:"cache_bypass"`
+
+		RetryAttempts      int `yaml:"retry_attempts"`
+		RetryBaseMs        int `yaml:"retry_base_ms"`
+		RetryMaxMs         int `yaml:"retry_max_ms"`
+		RetryBudgetSeconds int `yaml:"retry_budget_seconds"` // Max total backoff per request
+
+		Proxy       string            `yaml:"proxy"`        // http://, https:// or socks5:// URL
+		HostProxies map[string]string `yaml:"host_proxies"` // Host -> proxy URL
+		UserAgents  []string          `yaml:"user_agents"`  // Rotated when a request sets none
+
+		QualityFile string `yaml:"quality_file"` // Data source quality scores, kept across restarts; empty = in memory only
+
+		FixtureMode string `yaml:"fixture_mode"` // "" | record | replay: save HTTP exchanges, or serve them without the network
+		FixtureDir  string `yaml:"fixture_dir"`
+	} `yaml:"api"`
+	Notify struct {
+		Telegram struct {
+			Enabled bool     `yaml:"enabled"`
+			Events  []string `yaml:"events"` // trade | stop | eod; empty = all
+		} `yaml:"telegram"`
+		Slack struct {
+			Enabled bool     `yaml:"enabled"`
+			Events  []string `yaml:"events"`
+		} `yaml:"slack"`
+	} `yaml:"notify"`
+	EOD struct {
+		FeeBps         float64 `yaml:"fee_bps"`           // Brokerage and charges per order, in bps of turnover
+		LLMCostPerCall float64 `yaml:"llm_cost_per_call"` // Estimated cost of one decision
+		Capital        float64 `yaml:"capital"`           // Starting equity of the curve; 0 disables performance tracking
+		RiskFreePct    float64 `yaml:"risk_free_pct"`     // Annual risk-free rate for Sharpe/Sortino
+		WindowDays     int     `yaml:"window_days"`       // Days in the rolling window of the statistics
+		Benchmark      string  `yaml:"benchmark"`         // Index compared against; empty = benchmark.symbol
+	} `yaml:"eod"`
+	Brief struct {
+		Dir           string `yaml:"dir"`            // <date>.md and <date>.html written by the brief job and brief -write
+		LookaheadDays int    `yaml:"lookahead_days"` // Results and ex-dates this many days ahead are listed
+	} `yaml:"brief"`
+	Scheduler struct {
+		Jobs []struct {
+			Name           string `yaml:"name"`
+			Schedule       string `yaml:"schedule"`        // Cron "min hour dom month dow" in IST, @daily/@hourly/@weekdays or "@every 1h"
+			Task           string `yaml:"task"`            // eod | compress_logs | reindex_logs | brief
+			TimeoutSeconds int    `yaml:"timeout_seconds"` // 0 = no limit
+		} `yaml:"jobs"`
+	} `yaml:"scheduler"`
+	Secrets struct {
+		Providers   []string `yaml:"providers"` // Tried in order: env | file | aws | vault
+		FileDir     string   `yaml:"file_dir"`  // One file per key, e.g. /run/secrets
+		AWSRegion   string   `yaml:"aws_region"`
+		AWSSecretID string   `yaml:"aws_secret_id"` // JSON object of key/value pairs
+		VaultPath   string   `yaml:"vault_path"`    // e.g. secret/data/llm-trading-bot (KV v2)
+	} `yaml:"secrets"`
+	Tracing struct {
+		Exporter     string            `yaml:"exporter"`      // stdout | otlp | none
+		OTLPEndpoint string            `yaml:"otlp_endpoint"` // e.g. http://localhost:4318
+		OTLPProtocol string            `yaml:"otlp_protocol"` // http
+		OTLPHeaders  map[string]string `yaml:"otlp_headers"`
+		SampleRatio  float64           `yaml:"sample_ratio"` // 0..1 of new traces
+		Attributes   map[string]string `yaml:"attributes"`   // Extra resource attributes
+	} `yaml:"tracing"`
+	Logging struct {
+		Level   string            `yaml:"level"`   // Overrides LOG_LEVEL; empty keeps it
+		Modules map[string]string `yaml:"modules"` // Package -> level, e.g. engine: debug
+		Sinks   []struct {
+			Type      string `yaml:"type"`        // console | file | json
+			Path      string `yaml:"path"`        // Required for file; empty console/json write to stdout
+			Level     string `yaml:"level"`       // Minimum level for this sink; empty = all
+			MaxSizeMB int    `yaml:"max_size_mb"` // Rotate at this size; 0 = never
+			MaxFiles  int    `yaml:"max_files"`   // Rotated files kept
+		} `yaml:"sinks"` // Empty = stdout as set by LOG_FORMAT
+	} `yaml:"logging"`
+	Metrics struct {
+		Enabled bool   `yaml:"enabled"`
+		Addr    string `yaml:"addr"` // Listen address for /metrics
+	} `yaml:"metrics"`
+	Shutdown struct {
+		Positions        string `yaml:"positions"`          // keep | flatten | protect
+		CancelOpenOrders bool   `yaml:"cancel_open_orders"` // Cancel working orders placed this session
+		TimeoutSeconds   int    `yaml:"timeout_seconds"`    // Budget for the whole shutdown sequence
+	} `yaml:"shutdown"`
+	Control struct {
+		Enabled bool   `yaml:"enabled"`
+		Addr    string `yaml:"addr"` // Listen address for the control API; token from CONTROL_API_TOKEN
+	} `yaml:"control"`
+	KillSwitch struct {
+		HaltFile  string `yaml:"halt_file"`  // While it exists no new order is placed; stop-loss and flatten exits still are
+		BlockFile string `yaml:"block_file"` // Symbols to refuse orders for, one per line; edited at runtime
+	} `yaml:"kill_switch"`
+	Health struct {
+		IntervalMinutes int      `yaml:"interval_minutes"` // Re-check dependencies this often; 0 checks at startup only
+		TimeoutSeconds  int      `yaml:"timeout_seconds"`  // Per check
+		Critical        []string `yaml:"critical"`         // broker | llm | nse; LIVE mode refuses to start while one fails
+	} `yaml:"health"`
+	CorporateActions struct {
+		Sources      []string `yaml:"sources"`       // file | nse, tried best quality score first; empty disables adjustment
+		File         string   `yaml:"file"`          // CSV of symbol,ex_date,subject
+		RefreshHours int      `yaml:"refresh_hours"` // How long a symbol's actions are cached
+		Dividends    bool     `yaml:"dividends"`     // Also back-adjust for dividends
+	} `yaml:"corporate_actions"`
+	Earnings struct {
+		Sources        []string `yaml:"sources"`          // Merged: file | nse; empty disables the blackout
+		File           string   `yaml:"file"`             // CSV of symbol,date
+		BlackoutDays   int      `yaml:"blackout_days"`    // No new positions this many days before results (0 = results day only)
+		TightenStopPct float64  `yaml:"tighten_stop_pct"` // Raise open positions' stops to this % below price in the blackout; 0 = off
+		RefreshHours   int      `yaml:"refresh_hours"`
+	} `yaml:"earnings"`
+	Surveillance struct {
+		Sources      []string `yaml:"sources"` // Merged: file | nse (ASM and GSM lists); empty checks T2T only
+		File         string   `yaml:"file"`    // CSV of symbol,measure,stage
+		RefreshHours int      `yaml:"refresh_hours"`
+		Block        []string `yaml:"block"` // ASM | GSM | T2T: no new positions in stocks under these
+	} `yaml:"surveillance"`
+	Deals struct {
+		Enabled        bool                `yaml:"enabled"`          // Fetch NSE bulk/block deals and delivery percentages
+		LookbackDays   int                 `yaml:"lookback_days"`    // Promoter sells this many days back are flagged
+		Promoters      map[string][]string `yaml:"promoters"`        // Symbol -> promoter group client names, matched as substrings
+		DeliveryDays   int                 `yaml:"delivery_days"`    // Sessions that must all deliver below min_delivery_pct
+		MinDeliveryPct float64             `yaml:"min_delivery_pct"` // Low-delivery threshold; 0 = off
+		RefreshHours   int                 `yaml:"refresh_hours"`
+		Block          []string            `yaml:"block"` // PROMOTER_EXIT | LOW_DELIVERY: no new positions in stocks flagged so
+	} `yaml:"deals"`
+	Options struct {
+		Enabled         bool           `yaml:"enabled"`            // Fetch NSE option chains; the summary goes to the decider
+		CacheSeconds    int            `yaml:"cache_seconds"`      // How long a chain is reused
+		MinDaysToExpiry int            `yaml:"min_days_to_expiry"` // Skip expiries closer than this
+		MaxEntryIV      float64        `yaml:"max_entry_iv"`       // Block BUYs while ATM IV (%) is above this; 0 = off
+		LotSizes        map[string]int `yaml:"lot_sizes"`          // F&O lot size per symbol, needed to hedge
+		Hedge           struct {
+			MinNotional   float64 `yaml:"min_notional"`    // Buy puts for positions worth at least this; 0 = off
+			OTMPct        float64 `yaml:"otm_pct"`         // Put strike at least this % below price
+			MaxPremiumPct float64 `yaml:"max_premium_pct"` // Skip puts costing more than this % of price; 0 = no cap
+		} `yaml:"hedge"`
+	} `yaml:"options"`
+	Benchmark struct {
+		Symbol    string            `yaml:"symbol"`     // Market index, e.g. "NSE:NIFTY 50"; empty disables relative strength
+		Sectors   map[string]string `yaml:"sectors"`    // Symbol -> sector index override
+		RSWindows []int             `yaml:"rs_windows"` // Lookbacks in candles
+	} `yaml:"benchmark"`
 	LLM struct {
 		Provider    string  `yaml:"provider"`
 		Model       string  `yaml:"model"`
@@ -44,48 +297,513 @@ type Config struct {
 		Temperature float32 `yaml:"temperature"`
 		System      string  `yaml:"system"`
 		Schema      string  `yaml:"schema"`
+
+		MaxCallsPerTick   int     `yaml:"max_calls_per_tick"`  // Decider calls per poll, most interesting symbols first; 0 = every symbol
+		MinConfidenceBuy  float64 `yaml:"min_confidence_buy"`  // BUY decisions below this confidence are not executed
+		MinConfidenceSell float64 `yaml:"min_confidence_sell"` // Likewise for SELL; stop-losses are unaffected
+
+		// Queue shares each provider's throughput between trade decisions
+		// and research work, decisions first
+		Queue struct {
+			Concurrency          int            `yaml:"concurrency"`            // Calls in flight per provider
+			Providers            map[string]int `yaml:"providers"`              // Per-provider override, e.g. CLAUDE: 2
+			ReservedForDecisions int            `yaml:"reserved_for_decisions"` // Slots research work may not use
+			MaxWaiting           int            `yaml:"max_waiting"`            // Research calls are refused once this many wait; 0 = no limit
+		} `yaml:"queue"`
 	} `yaml:"llm"`
+	// Chaos injects failures to exercise error handling; refused in LIVE mode
+	Chaos struct {
+		Enabled              bool    `yaml:"enabled"`
+		Seed                 int64   `yaml:"seed"`                   // Fixes the fault sequence; 0 = random
+		BrokerTimeoutRate    float64 `yaml:"broker_timeout_rate"`    // Share of broker calls that hang, then time out
+		BrokerTimeoutSeconds int     `yaml:"broker_timeout_seconds"` // How long an injected timeout hangs
+		BrokerLatencyMs      int     `yaml:"broker_latency_ms"`      // Random extra delay, up to this, on every broker call
+		LLMMalformedRate     float64 `yaml:"llm_malformed_rate"`     // Share of OPENAI/CLAUDE responses replaced with malformed JSON
+		StreamDropRate       float64 `yaml:"stream_drop_rate"`       // Chance per tick that the tick stream drops
+		StreamDropSeconds    int     `yaml:"stream_drop_seconds"`    // How long a dropped stream stays silent
+	} `yaml:"chaos"`
+	// Competition runs entrants side by side on paper (tradingbot compete);
+	// entrants: is read by LoadCompetition
+	Competition struct {
+		LeaderboardFile string `yaml:"leaderboard_file"` // Standings, rewritten after every poll
+	} `yaml:"competition"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	return LoadProfile(path, "")
 }
 
-func (c *Config) Validate() error {
-	if c.Mode != "DRY_RUN" && c.Mode != "LIVE" {
-		return fmt.Errorf("invalid mode '%s': must be 'DRY_RUN' or 'LIVE'", c.Mode)
+// LoadProfile loads path and, when profile is set, deep-merges the
+// matching entry of its profiles: section over the base settings. Maps
+// merge key by key; lists and scalars are replaced. The accounts: and
+// competition.entrants: sections are left out; see LoadAccounts and
+// LoadCompetition.
+func LoadProfile(path, profile string) (*Config, error) {
+	tree, err := loadTree(path, profile)
+	if err != nil {
+		return nil, err
 	}
-	if c.DataSource != "STATIC" && c.DataSource != "LIVE" {
-		return fmt.Errorf("invalid data_source '%s': must be 'STATIC' or 'LIVE'", c.DataSource)
+	cut(tree, "accounts")
+	cut(tree, "competition", "entrants")
+	return build(tree, profile, "", false)
+}
+
+// LoadAccounts loads path like LoadProfile and returns one config per
+// entry of its accounts: section, in name order: the shared settings with
+// the account's overrides deep-merged over them. State files an account
+// does not set get a copy of their own (see AccountPath). Without accounts
+// it returns the shared config alone.
+func LoadAccounts(path, profile string) ([]*Config, error) {
+	tree, err := loadTree(path, profile)
+	if err != nil {
+		return nil, err
 	}
-	if len(c.UniverseStatic) == 0 {
-		return errors.New("universe_static cannot be empty")
+	accounts := cut(tree, "accounts")
+	cut(tree, "competition", "entrants")
+	if len(accounts) == 0 {
+		c, err := build(tree, profile, "", false)
+		if err != nil {
+			return nil, err
+		}
+		return []*Config{c}, nil
 	}
-	if c.Risk.PerTradeRiskPct <= 0 || c.Risk.PerTradeRiskPct > 100 {
-		return fmt.Errorf("risk.per_trade_risk_pct must be between 0-100, got %.2f", c.Risk.PerTradeRiskPct)
+
+	out, err := layer(tree, profile, "account", accounts, nil)
+	if err != nil {
+		return nil, err
 	}
-	if c.Stop.Mode != "FIXED" && c.Stop.Mode != "ATR" {
-		return fmt.Errorf("stop.mode must be 'FIXED' or 'ATR', got '%s'", c.Stop.Mode)
+	logins := make(map[string]string)
+	for _, c := range out {
+		// Two live accounts on one login would trade the same money twice
+		if c.Mode == "LIVE" && c.Broker != "paper" {
+			login := c.Broker + "/" + c.SecretPrefix
+			if other, dup := logins[login]; dup {
+				return nil, fmt.Errorf("accounts %s and %s use the same %s credentials; set secret_prefix on one of them", other, c.Account, c.Broker)
+			}
+			logins[login] = c.Account
+		}
 	}
-	return nil
+	return out, nil
 }
 
-func LoadConfig(path string) (*Config, error) {
+// LoadCompetition loads path like LoadProfile and returns one config per
+// entry of its competition.entrants: section, in name order, layered like
+// accounts. Entrants always trade DRY_RUN on the paper broker, on the
+// shared data_source and paper.feed, so they see the same data; their
+// overrides of those keys, and BOT_MODE/BOT_BROKER, are ignored. The
+// accounts: section is ignored.
+// Without entrants it returns nil.
+func LoadCompetition(path, profile string) ([]*Config, error) {
+	tree, err := loadTree(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	cut(tree, "accounts")
+	entrants := cut(tree, "competition", "entrants")
+	switch len(entrants) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nil, errors.New("competition.entrants needs at least two entrants to compare")
+	}
+
+	return layer(tree, profile, "entrant", entrants, func(shared, t map[string]any) {
+		t["mode"] = "DRY_RUN"
+		t["broker"] = "paper"
+		t["data_source"] = shared["data_source"]
+		paper, _ := t["paper"].(map[string]any)
+		if paper == nil {
+			paper = make(map[string]any)
+			t["paper"] = paper
+		}
+		sharedPaper, _ := shared["paper"].(map[string]any)
+		paper["feed"] = sharedPaper["feed"]
+	})
+}
+
+// AccountPath gives an account its own copy of a state file, e.g.
+// "logs/lifecycle.json" becomes "logs/lifecycle.family.json". An empty
+// path or account is returned unchanged.
+func AccountPath(path, account string) string {
+	if path == "" || account == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + account + ext
+}
+
+// loadTree reads path with profile merged in.
+func loadTree(path, profile string) (map[string]any, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+
+	profiles, _ := tree["profiles"].(map[string]any)
+	delete(tree, "profiles")
+	if profile != "" {
+		overrides, ok := profiles[profile].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q (defined: %s)", profile, strings.Join(profileNames(profiles), ", "))
+		}
+		mergeTree(tree, overrides)
+	}
+	return tree, nil
+}
+
+// cut removes the mapping at the given key path from tree and returns it.
+func cut(tree map[string]any, path ...string) map[string]any {
+	node := tree
+	for _, key := range path[:len(path)-1] {
+		next, ok := node[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = next
+	}
+	last := path[len(path)-1]
+	out, _ := node[last].(map[string]any)
+	delete(node, last)
+	return out
+}
+
+// layer builds one config per named entry, in name order: the shared tree
+// with the entry's overrides merged over it, then force applied, if set.
+// kind names the entries in errors ("account" or "entrant"). State files
+// an entry does not set get a copy of their own.
+func layer(tree map[string]any, profile, kind string, entries map[string]any, force func(shared, t map[string]any)) ([]*Config, error) {
+	shared, err := build(tree, profile, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]*Config, 0, len(names))
+	for _, name := range names {
+		if !validAccountName(name) {
+			return nil, fmt.Errorf("%s name %q must be lower-case letters, digits, - or _", kind, name)
+		}
+		overrides, ok := entries[name].(map[string]any)
+		if !ok && entries[name] != nil {
+			return nil, fmt.Errorf("%s %q must be a mapping of overrides", kind, name)
+		}
+		t, err := cloneTree(tree)
+		if err != nil {
+			return nil, err
+		}
+		mergeTree(t, overrides)
+		if force != nil {
+			force(tree, t)
+		}
+		c, err := build(t, profile, name, force != nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.Lifecycle.StateFile == shared.Lifecycle.StateFile {
+			c.Lifecycle.StateFile = AccountPath(c.Lifecycle.StateFile, name)
+		}
+		if c.Universe.AuditFile == shared.Universe.AuditFile {
+			c.Universe.AuditFile = AccountPath(c.Universe.AuditFile, name)
+		}
+		if c.Risk.OrderCountFile == shared.Risk.OrderCountFile {
+			c.Risk.OrderCountFile = AccountPath(c.Risk.OrderCountFile, name)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// build decodes a merged tree and applies environment overrides, defaults
+// and validation.
+func build(tree map[string]any, profile, account string, competing bool) (*Config, error) {
+	b, err := yaml.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
 	var c Config
 	if err := yaml.Unmarshal(b, &c); err != nil {
 		return nil, err
 	}
+	c.Profile = profile
+	c.Account = account
+	c.Competing = competing
+	if err := c.applyEnv(os.Environ()); err != nil {
+		return nil, fmt.Errorf("environment override: %w", err)
+	}
+	if competing {
+		// BOT_MODE and BOT_BROKER apply to every entrant; keep them on paper
+		c.Mode, c.Broker = "DRY_RUN", "paper"
+	}
 
+	c.applyDefaults()
+
+	// Symbols may be exchange-qualified ("BSE:500325"); keep one spelling
+	// so every module keys state the same way
+	for _, list := range [][]string{c.UniverseStatic, c.Universe.Include, c.Universe.Exclude, c.Lifecycle.Blocked} {
+		for i, sym := range list {
+			list[i] = types.NormalizeSymbol(sym)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return &c, nil
+}
+
+// cloneTree deep-copies a decoded YAML tree, so one account's overrides
+// do not leak into the next.
+func cloneTree(tree map[string]any) (map[string]any, error) {
+	b, err := yaml.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	return out, yaml.Unmarshal(b, &out)
+}
+
+func validAccountName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDefaults fills settings left empty in config.yaml. Zero means
+// "use the default" for every key listed here.
+func (c *Config) applyDefaults() {
 	if c.PollSeconds == 0 {
 		c.PollSeconds = 15
 	}
+	if c.PollWorkers == 0 {
+		c.PollWorkers = 1
+	}
+	if c.Broker == "" {
+		c.Broker = "zerodha"
+	}
+	if c.Paper.Feed == "" {
+		c.Paper.Feed = "zerodha"
+	}
 	if c.DataSource == "" {
 		c.DataSource = "STATIC"
 	}
+	if len(c.Universe.Sources) == 0 {
+		c.Universe.Sources = []string{"static"}
+	}
+	if c.Symbols.CacheDir == "" {
+		c.Symbols.CacheDir = ".cache/symbols"
+	}
+	if c.Symbols.RefreshHours == 0 {
+		c.Symbols.RefreshHours = 24
+	}
+	if c.CorporateActions.RefreshHours == 0 {
+		c.CorporateActions.RefreshHours = 24
+	}
+	if c.Options.CacheSeconds == 0 {
+		c.Options.CacheSeconds = 300
+	}
+	if c.EOD.WindowDays == 0 {
+		c.EOD.WindowDays = 60
+	}
+	if c.EOD.Benchmark == "" {
+		c.EOD.Benchmark = c.Benchmark.Symbol
+	}
+	if c.Brief.Dir == "" {
+		c.Brief.Dir = "logs/brief"
+	}
+	if c.Competition.LeaderboardFile == "" {
+		c.Competition.LeaderboardFile = "logs/leaderboard.json"
+	}
+	if c.Brief.LookaheadDays == 0 {
+		c.Brief.LookaheadDays = 7
+	}
+	if c.Surveillance.RefreshHours == 0 {
+		c.Surveillance.RefreshHours = 12
+	}
+	if c.Deals.LookbackDays == 0 {
+		c.Deals.LookbackDays = 30
+	}
+	if c.Deals.DeliveryDays == 0 {
+		c.Deals.DeliveryDays = 5
+	}
+	if c.Deals.RefreshHours == 0 {
+		c.Deals.RefreshHours = 12
+	}
+	if c.Earnings.RefreshHours == 0 {
+		c.Earnings.RefreshHours = 12
+	}
+	if c.Universe.Index.RefreshDays == 0 {
+		c.Universe.Index.RefreshDays = 7
+	}
+	if c.LLM.Queue.Concurrency == 0 {
+		c.LLM.Queue.Concurrency = 4
+	}
+	if c.Chaos.BrokerTimeoutSeconds == 0 {
+		c.Chaos.BrokerTimeoutSeconds = 10
+	}
+	if c.Chaos.StreamDropSeconds == 0 {
+		c.Chaos.StreamDropSeconds = 30
+	}
+	if c.Health.TimeoutSeconds == 0 {
+		c.Health.TimeoutSeconds = 10
+	}
+	if c.Health.Critical == nil {
+		c.Health.Critical = []string{"broker", "llm"}
+	}
+	if c.Candles.IntervalMinutes == 0 {
+		c.Candles.IntervalMinutes = 1
+	}
+	if c.Candles.Backfill == 0 {
+		c.Candles.Backfill = 250
+	}
+	if c.Event.StopProximityPct == 0 {
+		c.Event.StopProximityPct = 0.5
+	}
+	if c.Event.MinIntervalSeconds == 0 {
+		c.Event.MinIntervalSeconds = 5
+	}
+	if c.CircuitBreaker.SymbolFailures == 0 {
+		c.CircuitBreaker.SymbolFailures = 3
+	}
+	if c.CircuitBreaker.GlobalFailures == 0 {
+		c.CircuitBreaker.GlobalFailures = 10
+	}
+	if c.CircuitBreaker.CooldownSeconds == 0 {
+		c.CircuitBreaker.CooldownSeconds = 300
+	}
+	if c.Risk.DryRunFunds == 0 {
+		c.Risk.DryRunFunds = 100000
+	}
+	if c.Execution.Style == "" {
+		c.Execution.Style = "MARKET"
+	}
+	if c.Execution.MaxCrossSpreadBps == 0 {
+		c.Execution.MaxCrossSpreadBps = 10
+	}
+	if c.Stop.ServerLimitPct == 0 {
+		c.Stop.ServerLimitPct = 0.5
+	}
+	if c.Paper.InitialCash == 0 {
+		c.Paper.InitialCash = c.Risk.DryRunFunds
+	}
+	if c.Paper.MarketOrderTTL == 0 {
+		c.Paper.MarketOrderTTL = 3
+	}
+	if c.Indicators.SupertrendPeriod == 0 {
+		c.Indicators.SupertrendPeriod = 10
+	}
+	if c.Indicators.SupertrendMult == 0 {
+		c.Indicators.SupertrendMult = 3
+	}
+	if c.Indicators.IchimokuTenkan == 0 {
+		c.Indicators.IchimokuTenkan = 9
+	}
+	if c.Indicators.IchimokuKijun == 0 {
+		c.Indicators.IchimokuKijun = 26
+	}
+	if c.Indicators.IchimokuSenkou == 0 {
+		c.Indicators.IchimokuSenkou = 52
+	}
+	if c.Indicators.IchimokuDisplacement == 0 {
+		c.Indicators.IchimokuDisplacement = c.Indicators.IchimokuKijun
+	}
+	if c.Indicators.KeltnerPeriod == 0 {
+		c.Indicators.KeltnerPeriod = 20
+	}
+	if c.Indicators.KeltnerATRPeriod == 0 {
+		c.Indicators.KeltnerATRPeriod = 10
+	}
+	if c.Indicators.KeltnerMult == 0 {
+		c.Indicators.KeltnerMult = 2
+	}
+	if len(c.Secrets.Providers) == 0 {
+		c.Secrets.Providers = []string{"env"}
+	}
+	if c.Tracing.Exporter == "" {
+		c.Tracing.Exporter = "stdout"
+	}
+	if c.Tracing.SampleRatio == 0 {
+		c.Tracing.SampleRatio = 1
+	}
+	if c.Metrics.Addr == "" {
+		c.Metrics.Addr = ":9090"
+	}
+	if c.Shutdown.Positions == "" {
+		c.Shutdown.Positions = "keep"
+	}
+	if c.Shutdown.TimeoutSeconds == 0 {
+		c.Shutdown.TimeoutSeconds = 60
+	}
+	if c.Control.Addr == "" {
+		c.Control.Addr = "127.0.0.1:8090"
+	}
+	if c.API.TimeoutSeconds == 0 {
+		c.API.TimeoutSeconds = 30
+	}
+	if c.API.Burst == 0 {
+		c.API.Burst = 1
+	}
+	if c.API.FailureThreshold == 0 {
+		c.API.FailureThreshold = 5
+	}
+	if c.API.CooldownSeconds == 0 {
+		c.API.CooldownSeconds = 60
+	}
+	if c.API.CacheMaxMB == 0 {
+		c.API.CacheMaxMB = 256
+	}
+	if c.API.RetryAttempts == 0 {
+		c.API.RetryAttempts = 3
+	}
+	if c.API.RetryBudgetSeconds == 0 {
+		c.API.RetryBudgetSeconds = 10
+	}
+	if c.Benchmark.Symbol != "" && len(c.Benchmark.RSWindows) == 0 {
+		c.Benchmark.RSWindows = []int{20}
+	}
+	if c.Indicators.PatternLookback == 0 {
+		c.Indicators.PatternLookback = 3
+	}
+	// FIXED was accepted by earlier validation; it is the percent stop
+	if strings.EqualFold(c.Stop.Mode, "FIXED") {
+		c.Stop.Mode = "PCT"
+	}
+}
 
-	if err := c.Validate(); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+func mergeTree(dst, src map[string]any) {
+	for k, v := range src {
+		sub, ok := v.(map[string]any)
+		if cur, isMap := dst[k].(map[string]any); ok && isMap {
+			mergeTree(cur, sub)
+			continue
+		}
+		dst[k] = v
 	}
+}
 
-	return &c, nil
+func profileNames(profiles map[string]any) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
 }
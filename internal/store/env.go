@@ -0,0 +1,94 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix marks environment variables that override config keys. Nested
+// keys are joined with a double underscore: BOT_LLM__PROVIDER=CLAUDE sets
+// llm.provider and BOT_QTY__PER_SYMBOL__TCS=5 sets qty.per_symbol.TCS.
+// List values take YAML ("[A, B]") or a plain comma-separated string.
+const envPrefix = "BOT_"
+
+// applyEnv overrides c from BOT_* variables in environ ("KEY=value" pairs),
+// rejecting names that match no config key.
+func (c *Config) applyEnv(environ []string) error {
+	sort.Strings(environ) // Deterministic order for map keys set twice
+	for _, kv := range environ {
+		name, val, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, envPrefix) {
+			continue
+		}
+		path := strings.Split(strings.TrimPrefix(name, envPrefix), "__")
+		if err := setField(reflect.ValueOf(c).Elem(), path, val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(v reflect.Value, path []string, val string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		if len(path) == 0 {
+			return fmt.Errorf("%s is a section, not a value", v.Type())
+		}
+		f, ok := fieldByYAML(v, path[0])
+		if !ok {
+			return fmt.Errorf("unknown config key %q", strings.ToLower(path[0]))
+		}
+		return setField(f, path[1:], val)
+
+	case reflect.Map:
+		if len(path) == 0 {
+			return decodeValue(v, val)
+		}
+		if len(path) > 1 {
+			return fmt.Errorf("map values under %q cannot be nested", path[0])
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(elem, val); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(path[0]).Convert(v.Type().Key()), elem)
+		return nil
+	}
+
+	if len(path) > 0 {
+		return fmt.Errorf("unknown config key %q", strings.ToLower(path[0]))
+	}
+	return decodeValue(v, val)
+}
+
+// fieldByYAML finds the struct field whose yaml tag matches name, ignoring
+// case since environment names are upper case.
+func fieldByYAML(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if strings.EqualFold(tag, name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func decodeValue(v reflect.Value, val string) error {
+	if v.Kind() == reflect.Slice && !strings.HasPrefix(strings.TrimSpace(val), "[") {
+		val = "[" + val + "]"
+	}
+	ptr := reflect.New(v.Type())
+	if err := yaml.Unmarshal([]byte(val), ptr.Interface()); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", val, v.Type(), err)
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
@@ -0,0 +1,447 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"llm-trading-bot/internal/scheduler"
+	"llm-trading-bot/internal/universe"
+)
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the loaded settings and reports every problem found as a
+// *ValidationError, naming the config key and what it accepts.
+func (c *Config) Validate() error {
+	var v validator
+
+	v.oneOf("mode", c.Mode, "DRY_RUN", "LIVE")
+	v.oneOf("broker", c.Broker, "zerodha", "upstox", "alpaca", "paper")
+	if c.Competing && (c.Mode != "DRY_RUN" || c.Broker != "paper") {
+		v.addf("competition entrants must trade DRY_RUN on the paper broker, got mode %s, broker %s", c.Mode, c.Broker)
+	}
+	if c.Broker == "paper" {
+		v.oneOf("paper.feed", c.Paper.Feed, "zerodha", "upstox", "alpaca")
+	}
+	v.oneOf("data_source", c.DataSource, "STATIC", "LIVE", "FILE")
+	if c.DataSource == "FILE" && c.Candles.FileDir == "" {
+		v.add("candles.file_dir is required when data_source is FILE")
+	}
+	v.positive("poll_seconds", float64(c.PollSeconds))
+	v.positive("poll_workers", float64(c.PollWorkers))
+	v.positive("candles.interval_minutes", float64(c.Candles.IntervalMinutes))
+	if c.Candles.Backfill < 50 {
+		v.addf("candles.backfill must be at least 50 (the engine needs 50 candles), got %d", c.Candles.Backfill)
+	}
+
+	for _, src := range c.Universe.Sources {
+		v.oneOf("universe.sources", src, "static", "index")
+	}
+	if contains(c.Universe.Sources, "index") {
+		if len(c.Universe.Index.Names) == 0 {
+			v.add("universe.index.names cannot be empty when universe.sources lists index")
+		}
+		for _, name := range c.Universe.Index.Names {
+			v.oneOf("universe.index.names", name, universe.Indices()...)
+		}
+		v.positive("universe.index.refresh_days", float64(c.Universe.Index.RefreshDays))
+	}
+	if contains(c.Universe.Sources, "static") && len(c.UniverseStatic) == 0 {
+		v.add("universe_static cannot be empty: list at least one symbol, e.g. [RELIANCE, TCS]")
+	}
+	v.symbols("universe_static", c.UniverseStatic)
+	v.symbols("universe.include", c.Universe.Include)
+	v.symbols("universe.exclude", c.Universe.Exclude)
+	for _, sym := range c.Universe.Include {
+		if contains(c.Universe.Exclude, sym) {
+			v.addf("universe lists %s in both include and exclude", sym)
+		}
+	}
+	if c.Universe.MaxSize < 0 {
+		v.addf("universe.max_size cannot be negative, got %d", c.Universe.MaxSize)
+	}
+	if c.Universe.RefreshMinutes < 0 {
+		v.addf("universe.refresh_minutes cannot be negative, got %d", c.Universe.RefreshMinutes)
+	}
+
+	if c.Symbols.RefreshHours < 0 {
+		v.addf("symbols.refresh_hours cannot be negative, got %d", c.Symbols.RefreshHours)
+	}
+
+	if c.Qty.DefaultBuy < 0 || c.Qty.DefaultSell < 0 {
+		v.addf("qty.default_buy and qty.default_sell cannot be negative, got %d and %d", c.Qty.DefaultBuy, c.Qty.DefaultSell)
+	}
+	for sym, q := range c.Qty.PerSymbol {
+		if q < 0 {
+			v.addf("qty.per_symbol.%s cannot be negative, got %d", sym, q)
+		}
+	}
+
+	v.pct("risk.per_trade_risk_pct", c.Risk.PerTradeRiskPct)
+	v.pct("risk.max_daily_drawdown_pct", c.Risk.MaxDailyDrawdownPct)
+	v.positive("risk.dry_run_funds", c.Risk.DryRunFunds)
+	if c.Risk.MaxOrdersPerSymbolPerDay < 0 || c.Risk.MaxTotalOrdersPerDay < 0 {
+		v.addf("risk.max_orders_per_symbol_per_day and risk.max_total_orders_per_day cannot be negative, got %d and %d",
+			c.Risk.MaxOrdersPerSymbolPerDay, c.Risk.MaxTotalOrdersPerDay)
+	}
+
+	if c.Halt.FrozenBars < 0 || c.Halt.FrozenBars == 1 {
+		v.addf("halt.frozen_bars must be 0 (off) or at least 2, got %d", c.Halt.FrozenBars)
+	}
+	if c.Halt.StaleMinutes < 0 {
+		v.addf("halt.stale_minutes cannot be negative, got %d", c.Halt.StaleMinutes)
+	}
+	if c.DataFilter.MaxJumpPct < 0 {
+		v.addf("data_filter.max_jump_pct cannot be negative, got %g", c.DataFilter.MaxJumpPct)
+	}
+
+	v.oneOf("execution.style", c.Execution.Style, "MARKET", "SPREAD")
+
+	v.oneOf("stop.mode", strings.ToUpper(c.Stop.Mode), "PCT", "ATR", "VOLATILITY")
+	switch strings.ToUpper(c.Stop.Mode) {
+	case "PCT":
+		v.pct("stop.pct", c.Stop.Pct)
+	case "ATR", "VOLATILITY":
+		v.positive("stop.atr_mult", c.Stop.ATRMult)
+	}
+	if c.Stop.MinTick < 0 {
+		v.addf("stop.min_tick cannot be negative, got %g", c.Stop.MinTick)
+	}
+
+	v.positive("event.stop_proximity_pct", c.Event.StopProximityPct)
+
+	if c.Lifecycle.CooldownMinutes < 0 {
+		v.addf("lifecycle.cooldown_minutes cannot be negative, got %d", c.Lifecycle.CooldownMinutes)
+	}
+
+	for _, src := range c.CorporateActions.Sources {
+		v.oneOf("corporate_actions.sources", src, "file", "nse")
+	}
+	if contains(c.CorporateActions.Sources, "file") && c.CorporateActions.File == "" {
+		v.add("corporate_actions.file is required when corporate_actions.sources lists file")
+	}
+	v.positive("corporate_actions.refresh_hours", float64(c.CorporateActions.RefreshHours))
+
+	for _, src := range c.Earnings.Sources {
+		v.oneOf("earnings.sources", src, "file", "nse")
+	}
+	if contains(c.Earnings.Sources, "file") && c.Earnings.File == "" {
+		v.add("earnings.file is required when earnings.sources lists file")
+	}
+	if c.Earnings.BlackoutDays < 0 {
+		v.addf("earnings.blackout_days cannot be negative, got %d", c.Earnings.BlackoutDays)
+	}
+	if c.Earnings.TightenStopPct < 0 || c.Earnings.TightenStopPct >= 100 {
+		v.addf("earnings.tighten_stop_pct must be between 0 and 100, got %g", c.Earnings.TightenStopPct)
+	}
+	v.positive("earnings.refresh_hours", float64(c.Earnings.RefreshHours))
+
+	for _, src := range c.Surveillance.Sources {
+		v.oneOf("surveillance.sources", src, "file", "nse")
+	}
+	if contains(c.Surveillance.Sources, "file") && c.Surveillance.File == "" {
+		v.add("surveillance.file is required when surveillance.sources lists file")
+	}
+	for _, m := range c.Surveillance.Block {
+		v.oneOf("surveillance.block", m, "ASM", "GSM", "T2T")
+	}
+	if contains(c.Surveillance.Block, "T2T") && !c.Symbols.Enabled {
+		v.add("surveillance.block lists T2T, which needs symbols.enabled: true")
+	}
+	v.positive("surveillance.refresh_hours", float64(c.Surveillance.RefreshHours))
+	for _, p := range c.Deals.Block {
+		v.oneOf("deals.block", p, "PROMOTER_EXIT", "LOW_DELIVERY")
+	}
+	if len(c.Deals.Block) > 0 && !c.Deals.Enabled {
+		v.add("deals.block needs deals.enabled: true")
+	}
+	if contains(c.Deals.Block, "LOW_DELIVERY") && c.Deals.MinDeliveryPct <= 0 {
+		v.add("deals.block lists LOW_DELIVERY, which needs deals.min_delivery_pct")
+	}
+	if c.Deals.MinDeliveryPct < 0 || c.Deals.MinDeliveryPct > 100 {
+		v.addf("deals.min_delivery_pct must be between 0 and 100, got %g", c.Deals.MinDeliveryPct)
+	}
+	v.positive("deals.lookback_days", float64(c.Deals.LookbackDays))
+	v.positive("deals.delivery_days", float64(c.Deals.DeliveryDays))
+	v.positive("deals.refresh_hours", float64(c.Deals.RefreshHours))
+
+	if c.Options.Enabled {
+		v.positive("options.cache_seconds", float64(c.Options.CacheSeconds))
+		if c.Options.MinDaysToExpiry < 0 {
+			v.addf("options.min_days_to_expiry cannot be negative, got %d", c.Options.MinDaysToExpiry)
+		}
+		if c.Options.MaxEntryIV < 0 {
+			v.addf("options.max_entry_iv cannot be negative, got %g", c.Options.MaxEntryIV)
+		}
+		if c.Options.Hedge.MinNotional < 0 || c.Options.Hedge.OTMPct < 0 || c.Options.Hedge.MaxPremiumPct < 0 {
+			v.add("options.hedge settings cannot be negative")
+		}
+		for sym, lot := range c.Options.LotSizes {
+			if lot <= 0 {
+				v.addf("options.lot_sizes.%s must be greater than 0, got %d", sym, lot)
+			}
+		}
+	} else if c.Options.MaxEntryIV > 0 || c.Options.Hedge.MinNotional > 0 {
+		v.add("options.max_entry_iv and options.hedge need options.enabled: true")
+	}
+
+	for _, w := range c.Indicators.SMAWindows {
+		v.positive("indicators.sma_windows", float64(w))
+	}
+	v.positive("indicators.rsi_period", float64(c.Indicators.RSIPeriod))
+	v.positive("indicators.bb_window", float64(c.Indicators.BBWindow))
+	v.positive("indicators.bb_stddev", c.Indicators.BBStdDev)
+	v.positive("indicators.atr_period", float64(c.Indicators.ATRPeriod))
+	if c.Indicators.IchimokuTenkan >= c.Indicators.IchimokuKijun || c.Indicators.IchimokuKijun >= c.Indicators.IchimokuSenkou {
+		v.addf("indicators.ichimoku periods must satisfy tenkan < kijun < senkou, got %d/%d/%d",
+			c.Indicators.IchimokuTenkan, c.Indicators.IchimokuKijun, c.Indicators.IchimokuSenkou)
+	}
+
+	for _, w := range c.Benchmark.RSWindows {
+		v.positive("benchmark.rs_windows", float64(w))
+	}
+
+	if c.LLM.Provider != "" {
+		v.oneOf("llm.provider", c.LLM.Provider, "OPENAI", "CLAUDE", "RULES", "NOOP")
+	}
+	if c.LLM.Temperature < 0 || c.LLM.Temperature > 2 {
+		v.addf("llm.temperature must be between 0 and 2, got %g", c.LLM.Temperature)
+	}
+	for _, mc := range []struct {
+		key string
+		got float64
+	}{{"llm.min_confidence_buy", c.LLM.MinConfidenceBuy}, {"llm.min_confidence_sell", c.LLM.MinConfidenceSell}} {
+		if mc.got < 0 || mc.got > 1 {
+			v.addf("%s must be between 0 and 1, got %g", mc.key, mc.got)
+		}
+	}
+	if c.LLM.MaxCallsPerTick < 0 {
+		v.addf("llm.max_calls_per_tick must be 0 (no budget) or more, got %d", c.LLM.MaxCallsPerTick)
+	}
+	q := c.LLM.Queue
+	// Research calls need at least one slot the reserve leaves them
+	reserve := func(key string, n int) {
+		v.positive(key, float64(n))
+		if n > 0 && q.ReservedForDecisions >= n {
+			v.addf("llm.queue.reserved_for_decisions must be below %s (%d), got %d", key, n, q.ReservedForDecisions)
+		}
+	}
+	reserve("llm.queue.concurrency", q.Concurrency)
+	for provider, n := range q.Providers {
+		v.oneOf("llm.queue.providers", provider, "OPENAI", "CLAUDE")
+		reserve("llm.queue.providers."+provider, n)
+	}
+	if q.ReservedForDecisions < 0 {
+		v.addf("llm.queue.reserved_for_decisions cannot be negative, got %d", q.ReservedForDecisions)
+	}
+	if q.MaxWaiting < 0 {
+		v.addf("llm.queue.max_waiting must be 0 (no limit) or more, got %d", q.MaxWaiting)
+	}
+
+	for _, ch := range []struct {
+		name   string
+		events []string
+	}{{"telegram", c.Notify.Telegram.Events}, {"slack", c.Notify.Slack.Events}} {
+		for _, e := range ch.events {
+			v.oneOf("notify."+ch.name+".events", e, "trade", "stop", "eod", "job", "auth", "health", "brief", "crash", "halt", "circuit")
+		}
+	}
+
+	if c.Health.IntervalMinutes < 0 {
+		v.addf("health.interval_minutes must be 0 (startup only) or more, got %d", c.Health.IntervalMinutes)
+	}
+	v.positive("health.timeout_seconds", float64(c.Health.TimeoutSeconds))
+	for _, dep := range c.Health.Critical {
+		v.oneOf("health.critical", dep, "broker", "llm", "nse")
+	}
+
+	if c.EOD.FeeBps < 0 || c.EOD.LLMCostPerCall < 0 {
+		v.add("eod.fee_bps and eod.llm_cost_per_call cannot be negative")
+	}
+	if c.EOD.Capital < 0 || c.EOD.RiskFreePct < 0 || c.EOD.WindowDays < 0 {
+		v.add("eod.capital, eod.risk_free_pct and eod.window_days cannot be negative")
+	}
+
+	if c.Brief.LookaheadDays < 0 {
+		v.addf("brief.lookahead_days cannot be negative, got %d", c.Brief.LookaheadDays)
+	}
+
+	v.oneOf("shutdown.positions", c.Shutdown.Positions, "keep", "flatten", "protect")
+	v.positive("shutdown.timeout_seconds", float64(c.Shutdown.TimeoutSeconds))
+
+	jobs := make(map[string]bool)
+	for i, j := range c.Scheduler.Jobs {
+		key := fmt.Sprintf("scheduler.jobs[%d]", i)
+		if j.Name == "" {
+			v.addf("%s.name cannot be empty", key)
+		} else if jobs[j.Name] {
+			v.addf("scheduler.jobs lists %s more than once", j.Name)
+		}
+		jobs[j.Name] = true
+		v.oneOf(key+".task", j.Task, "eod", "compress_logs", "reindex_logs", "brief")
+		if _, err := scheduler.Parse(j.Schedule); err != nil {
+			v.addf("%s.schedule: %v", key, err)
+		}
+		if j.TimeoutSeconds < 0 {
+			v.addf("%s.timeout_seconds cannot be negative, got %d", key, j.TimeoutSeconds)
+		}
+	}
+
+	for _, p := range c.Secrets.Providers {
+		v.oneOf("secrets.providers", p, "env", "file", "aws", "vault")
+		switch {
+		case p == "file" && c.Secrets.FileDir == "":
+			v.add("secrets.file_dir is required when secrets.providers includes file")
+		case p == "aws" && c.Secrets.AWSSecretID == "":
+			v.add("secrets.aws_secret_id is required when secrets.providers includes aws")
+		case p == "vault" && c.Secrets.VaultPath == "":
+			v.add("secrets.vault_path is required when secrets.providers includes vault")
+		}
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "stdout", "otlp", "none")
+	if c.Tracing.Exporter == "otlp" && c.Tracing.OTLPEndpoint == "" {
+		v.add("tracing.otlp_endpoint is required when tracing.exporter is otlp")
+	}
+	if c.Tracing.SampleRatio <= 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio must be in (0, 1], got %g", c.Tracing.SampleRatio)
+	}
+
+	levels := []string{"debug", "info", "warn", "error"}
+	if c.Logging.Level != "" {
+		v.oneOf("logging.level", strings.ToLower(c.Logging.Level), levels...)
+	}
+	for module, level := range c.Logging.Modules {
+		v.oneOf("logging.modules."+module, strings.ToLower(level), levels...)
+	}
+	for i, sink := range c.Logging.Sinks {
+		key := fmt.Sprintf("logging.sinks[%d]", i)
+		v.oneOf(key+".type", sink.Type, "console", "file", "json")
+		if sink.Type == "file" && sink.Path == "" {
+			v.addf("%s.path is required for a file sink", key)
+		}
+		if sink.Level != "" {
+			v.oneOf(key+".level", strings.ToLower(sink.Level), levels...)
+		}
+		if sink.MaxSizeMB < 0 || sink.MaxFiles < 0 {
+			v.addf("%s.max_size_mb and max_files cannot be negative", key)
+		}
+	}
+
+	if c.API.RatePerSecond < 0 {
+		v.addf("api.rate_per_second cannot be negative, got %g (0 = unlimited)", c.API.RatePerSecond)
+	}
+	if c.API.RetryAttempts < 1 {
+		v.addf("api.retry_attempts must be at least 1, got %d", c.API.RetryAttempts)
+	}
+	if c.API.FixtureMode != "" {
+		v.oneOf("api.fixture_mode", c.API.FixtureMode, "record", "replay")
+		if c.API.FixtureDir == "" {
+			v.add("api.fixture_dir must be set when api.fixture_mode is")
+		}
+	}
+
+	if c.Chaos.Enabled && c.Mode == "LIVE" {
+		v.add("chaos.enabled is not allowed in LIVE mode")
+	}
+	if c.API.FixtureMode != "" && c.Mode == "LIVE" {
+		v.add("api.fixture_mode is not allowed in LIVE mode")
+	}
+	for _, r := range []struct {
+		key string
+		got float64
+	}{
+		{"chaos.broker_timeout_rate", c.Chaos.BrokerTimeoutRate},
+		{"chaos.llm_malformed_rate", c.Chaos.LLMMalformedRate},
+		{"chaos.stream_drop_rate", c.Chaos.StreamDropRate},
+	} {
+		if r.got < 0 || r.got > 1 {
+			v.addf("%s must be between 0 and 1, got %g", r.key, r.got)
+		}
+	}
+	if c.Chaos.BrokerTimeoutSeconds < 0 || c.Chaos.BrokerLatencyMs < 0 || c.Chaos.StreamDropSeconds < 0 {
+		v.add("chaos.broker_timeout_seconds, broker_latency_ms and stream_drop_seconds cannot be negative")
+	}
+
+	// An account's settings are the shared ones with its overrides, so
+	// name the account a problem was found in
+	if c.Account != "" {
+		section := "accounts."
+		if c.Competing {
+			section = "competition.entrants."
+		}
+		for i, p := range v.problems {
+			v.problems[i] = section + c.Account + ": " + p
+		}
+	}
+	return v.err()
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(msg string) {
+	v.problems = append(v.problems, msg)
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.add(fmt.Sprintf(format, args...))
+}
+
+func (v *validator) oneOf(key, got string, allowed ...string) {
+	for _, a := range allowed {
+		if got == a {
+			return
+		}
+	}
+	v.addf("invalid %s '%s': must be one of %s", key, got, strings.Join(allowed, ", "))
+}
+
+func (v *validator) positive(key string, got float64) {
+	if got <= 0 {
+		v.addf("%s must be greater than 0, got %g", key, got)
+	}
+}
+
+func (v *validator) pct(key string, got float64) {
+	if got <= 0 || got > 100 {
+		v.addf("%s must be between 0-100, got %.2f", key, got)
+	}
+}
+
+// symbols rejects empty and repeated entries in a symbol list.
+func (v *validator) symbols(key string, list []string) {
+	seen := make(map[string]bool)
+	for _, sym := range list {
+		if sym == "" {
+			v.addf("%s contains an empty symbol", key)
+		} else if seen[sym] {
+			v.addf("%s lists %s more than once", key, sym)
+		}
+		seen[sym] = true
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
@@ -0,0 +1,309 @@
+// Package surveillance tracks the exchange surveillance measures on
+// stocks: the ASM (additional) and GSM (graded) surveillance lists and the
+// trade-to-trade segment. Stocks under these measures carry higher
+// margins, tighter circuits or delivery-only trading, so the engine flags
+// them to the decider and can refuse new positions in them.
+package surveillance
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/symbols"
+	"llm-trading-bot/internal/types"
+)
+
+// Measures a stock can be listed under.
+const (
+	ASM = "ASM"
+	GSM = "GSM"
+	T2T = "T2T" // Trade-to-trade: delivery only, series BE/BZ
+)
+
+// Listing is one measure on a stock. Stage is as published, e.g.
+// "Stage I" or "LTASM Stage 2"; it is the series for T2T.
+type Listing struct {
+	Measure string `json:"measure"`
+	Stage   string `json:"stage,omitempty"`
+}
+
+func (l Listing) String() string {
+	if l.Stage == "" {
+		return l.Measure
+	}
+	return l.Measure + " " + l.Stage
+}
+
+// Source returns the listings by symbol. Name identifies it in quality
+// scores.
+type Source interface {
+	Name() string
+	Listings(ctx context.Context) (map[string][]Listing, error)
+}
+
+// Monitor merges its sources and caches the result for ttl. Trade-to-trade
+// stocks come from the series in the symbol registry. It is safe for
+// concurrent use.
+type Monitor struct {
+	sources []Source
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	listings  map[string][]Listing
+	fetchedAt time.Time
+}
+
+func NewMonitor(ttl time.Duration, sources ...Source) *Monitor {
+	return &Monitor{sources: sources, ttl: ttl}
+}
+
+// Get returns the measures symbol is under, nil when none.
+func (m *Monitor) Get(ctx context.Context, symbol string) []Listing {
+	out := append([]Listing(nil), m.load(ctx)[symbol]...)
+	if info, ok := symbols.Get(symbol); ok && (info.Series == "BE" || info.Series == "BZ") {
+		out = append(out, Listing{Measure: T2T, Stage: info.Series})
+	}
+	return out
+}
+
+// load refreshes the listings once they are older than ttl. When every
+// source fails the previous listings are kept.
+func (m *Monitor) load(ctx context.Context) map[string][]Listing {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listings != nil && time.Since(m.fetchedAt) < m.ttl {
+		return m.listings
+	}
+
+	merged := make(map[string][]Listing)
+	failed := 0
+	for _, src := range quality.Rank(quality.Default(), m.sources, Source.Name) {
+		listings, err := src.Listings(ctx)
+		if err != nil {
+			logger.Warn(ctx, "Surveillance source failed", "source", src.Name(), "error", err)
+			failed++
+			continue
+		}
+		for sym, ls := range listings {
+			merged[sym] = merge(merged[sym], ls)
+		}
+	}
+	m.fetchedAt = time.Now()
+	if failed == len(m.sources) && m.listings != nil {
+		return m.listings
+	}
+
+	if m.listings != nil {
+		logChanges(ctx, m.listings, merged)
+	}
+	m.listings = merged
+	logger.Debug(ctx, "Surveillance lists loaded", "symbols", len(merged))
+	return merged
+}
+
+// merge adds the listings in add that list does not already have.
+func merge(list, add []Listing) []Listing {
+	for _, l := range add {
+		dup := false
+		for _, x := range list {
+			if x == l {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, l)
+		}
+	}
+	return list
+}
+
+// logChanges logs stocks entering, leaving or changing stage between two
+// loads.
+func logChanges(ctx context.Context, prev, next map[string][]Listing) {
+	syms := make(map[string]bool, len(prev)+len(next))
+	for sym := range prev {
+		syms[sym] = true
+	}
+	for sym := range next {
+		syms[sym] = true
+	}
+	keys := make([]string, 0, len(syms))
+	for sym := range syms {
+		keys = append(keys, sym)
+	}
+	sort.Strings(keys)
+	for _, sym := range keys {
+		before, after := describe(prev[sym]), describe(next[sym])
+		if before != after {
+			logger.Info(ctx, "Surveillance listing changed", "symbol", sym, "from", before, "to", after)
+		}
+	}
+}
+
+func describe(ls []Listing) string {
+	parts := make([]string, len(ls))
+	for i, l := range ls {
+		parts[i] = l.String()
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// File is a source read from a CSV with columns symbol,measure,stage, for
+// BSE listings and manual additions.
+type File struct {
+	Path string
+}
+
+func (File) Name() string { return "surveillance.file" }
+
+func (f File) Listings(ctx context.Context) (out map[string][]Listing, err error) {
+	rows := 0
+	defer func(start time.Time) { quality.Record(f.Name(), start, err, rows, rows) }(time.Now())
+
+	fh, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	r := csv.NewReader(fh)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	out = make(map[string][]Listing)
+	for i, row := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "symbol") {
+			continue // Header
+		}
+		if len(row) < 2 {
+			return nil, fmt.Errorf("%s line %d: want symbol,measure[,stage]", f.Path, i+1)
+		}
+		measure := strings.ToUpper(strings.TrimSpace(row[1]))
+		if measure != ASM && measure != GSM && measure != T2T {
+			return nil, fmt.Errorf("%s line %d: measure %q must be ASM, GSM or T2T", f.Path, i+1, row[1])
+		}
+		l := Listing{Measure: measure}
+		if len(row) > 2 {
+			l.Stage = strings.TrimSpace(row[2])
+		}
+		sym := types.NormalizeSymbol(row[0])
+		out[sym] = merge(out[sym], []Listing{l})
+		rows++
+	}
+	return out, nil
+}
+
+// NSE endpoints for the current ASM and GSM lists. Like every nseindia.com
+// API they need the session cookies set up by api.warm_up.
+var nseURLs = map[string]string{
+	ASM: "https://www.nseindia.com/api/reportASM",
+	GSM: "https://www.nseindia.com/api/reportGSM",
+}
+
+// NSE reads the ASM and GSM lists NSE publishes.
+type NSE struct{}
+
+func (NSE) Name() string { return "surveillance.nse" }
+
+func (n NSE) Listings(ctx context.Context) (out map[string][]Listing, err error) {
+	var usable, total int
+	defer func(start time.Time) { quality.Record(n.Name(), start, err, usable, total) }(time.Now())
+
+	out = make(map[string][]Listing)
+	for _, measure := range []string{ASM, GSM} {
+		var body any
+		if err := fetchJSON(ctx, nseURLs[measure], &body); err != nil {
+			return nil, fmt.Errorf("nse: %s list: %w", measure, err)
+		}
+		rows := records(body)
+		total += len(rows)
+		for _, row := range rows {
+			sym, _ := row["symbol"].(string)
+			if strings.TrimSpace(sym) == "" {
+				continue
+			}
+			usable++
+			sym = types.NormalizeSymbol(sym)
+			out[sym] = merge(out[sym], []Listing{{Measure: measure, Stage: stage(row)}})
+		}
+	}
+	return out, nil
+}
+
+func fetchJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", api.ErrParse, err)
+	}
+	return nil
+}
+
+// records collects every object with a symbol field. The ASM report nests
+// its rows under longterm/shortterm "data" arrays and the GSM report is a
+// flat array, so the rows are found by shape rather than by path.
+func records(v any) []map[string]any {
+	var out []map[string]any
+	switch t := v.(type) {
+	case map[string]any:
+		if _, ok := t["symbol"]; ok {
+			return append(out, t)
+		}
+		for _, child := range t {
+			out = append(out, records(child)...)
+		}
+	case []any:
+		for _, child := range t {
+			out = append(out, records(child)...)
+		}
+	}
+	return out
+}
+
+// stage is the first field naming a stage, e.g. asmSurvIndicator
+// "LTASM Stage I" or gsmStage "Stage 2".
+func stage(row map[string]any) string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lk := strings.ToLower(k)
+		if !strings.Contains(lk, "stage") && !strings.Contains(lk, "indicator") {
+			continue
+		}
+		if s, ok := row[k].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,336 @@
+// Package symbols is the registry of NSE listed securities: company name,
+// ISIN, industry, series, market lot and F&O lot size per trading symbol.
+// It is built from the master files NSE publishes and cached on disk.
+package symbols
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+	"llm-trading-bot/internal/types"
+)
+
+// The NSE master files: every listed equity, the NIFTY 500 constituents
+// (the only list carrying industry) and the F&O market lots.
+const (
+	equityURL   = "https://nsearchives.nseindia.com/content/equities/EQUITY_L.csv"
+	industryURL = "https://nsearchives.nseindia.com/content/indices/ind_nifty500list.csv"
+	foLotsURL   = "https://nsearchives.nseindia.com/content/fo/fo_mktlots.csv"
+)
+
+var (
+	// ErrUnknown is returned by Check for an NSE symbol the registry does
+	// not list.
+	ErrUnknown = errors.New("unknown symbol")
+	// ErrLot is returned by Check for a quantity that is not a whole
+	// number of market lots.
+	ErrLot = errors.New("quantity is not a multiple of the market lot")
+)
+
+// Info describes one NSE security.
+type Info struct {
+	Symbol    string  `json:"symbol"`
+	Name      string  `json:"name"`
+	ISIN      string  `json:"isin"`
+	Industry  string  `json:"industry,omitempty"` // NIFTY 500 constituents only
+	Series    string  `json:"series"`             // EQ, BE, ...
+	MarketLot int     `json:"market_lot"`         // Cash market lot, 1 for almost every stock
+	LotSize   int     `json:"lot_size,omitempty"` // F&O lot size; 0 when not in F&O
+	FaceValue float64 `json:"face_value,omitempty"`
+}
+
+type Params struct {
+	CacheDir string        // Fetched files are kept here; empty disables the cache
+	MaxAge   time.Duration // A cached file younger than this is used without fetching
+}
+
+// Registry looks up securities by symbol or ISIN. It is read-only once
+// loaded.
+type Registry struct {
+	bySymbol map[string]*Info
+	byISIN   map[string]*Info
+}
+
+// Load builds the registry from the NSE master files. The equity list is
+// required; industry and F&O lots are added when available. Each file is
+// fetched at most once per MaxAge and a stale cached copy is used when NSE
+// is unreachable.
+func Load(ctx context.Context, p Params) (*Registry, error) {
+	body, err := p.file(ctx, equityURL)
+	if err != nil {
+		return nil, fmt.Errorf("equity master: %w", err)
+	}
+	r, err := parseEquities(body)
+	if err != nil {
+		return nil, fmt.Errorf("equity master: %w: %w", api.ErrParse, err)
+	}
+
+	if body, err := p.file(ctx, industryURL); err != nil {
+		logger.Warn(ctx, "Symbol registry loaded without industries", "error", err)
+	} else if err := r.addIndustries(body); err != nil {
+		logger.Warn(ctx, "Symbol registry loaded without industries", "error", err)
+	}
+	if body, err := p.file(ctx, foLotsURL); err != nil {
+		logger.Warn(ctx, "Symbol registry loaded without F&O lot sizes", "error", err)
+	} else if err := r.addLots(body); err != nil {
+		logger.Warn(ctx, "Symbol registry loaded without F&O lot sizes", "error", err)
+	}
+
+	logger.Info(ctx, "Symbol registry loaded", "symbols", len(r.bySymbol))
+	return r, nil
+}
+
+// Get returns the security for symbol. Unqualified and NSE: symbols are
+// looked up; other exchanges are not covered.
+func (r *Registry) Get(symbol string) (Info, bool) {
+	if r == nil {
+		return Info{}, false
+	}
+	exchange, sym := types.SplitSymbol(symbol, types.ExchangeNSE)
+	if exchange != types.ExchangeNSE {
+		return Info{}, false
+	}
+	if info, ok := r.bySymbol[sym]; ok {
+		return *info, true
+	}
+	return Info{}, false
+}
+
+func (r *Registry) ByISIN(isin string) (Info, bool) {
+	if r == nil {
+		return Info{}, false
+	}
+	if info, ok := r.byISIN[strings.ToUpper(strings.TrimSpace(isin))]; ok {
+		return *info, true
+	}
+	return Info{}, false
+}
+
+func (r *Registry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.bySymbol)
+}
+
+// Check validates an order for symbol: an NSE symbol must be listed and
+// qty must be a whole number of market lots. Symbols on other exchanges,
+// and every symbol when no registry is loaded, pass.
+func (r *Registry) Check(symbol string, qty int) error {
+	if r == nil {
+		return nil
+	}
+	if exchange, _ := types.SplitSymbol(symbol, types.ExchangeNSE); exchange != types.ExchangeNSE {
+		return nil
+	}
+	info, ok := r.Get(symbol)
+	if !ok {
+		return fmt.Errorf("%w %s: not in the NSE equity list", ErrUnknown, symbol)
+	}
+	if info.MarketLot > 1 && qty%info.MarketLot != 0 {
+		return fmt.Errorf("%w: %s trades in lots of %d, got %d", ErrLot, symbol, info.MarketLot, qty)
+	}
+	return nil
+}
+
+// parseEquities reads EQUITY_L.csv: SYMBOL, NAME OF COMPANY, SERIES,
+// DATE OF LISTING, PAID UP VALUE, MARKET LOT, ISIN NUMBER, FACE VALUE.
+func parseEquities(body []byte) (*Registry, error) {
+	_, rows, col, err := readCSV(body, "SYMBOL", "NAME OF COMPANY", "SERIES", "MARKET LOT", "ISIN NUMBER", "FACE VALUE")
+	if err != nil {
+		return nil, err
+	}
+	r := &Registry{bySymbol: make(map[string]*Info, len(rows)), byISIN: make(map[string]*Info, len(rows))}
+	for _, row := range rows {
+		info := &Info{
+			Symbol: col(row, "SYMBOL"),
+			Name:   col(row, "NAME OF COMPANY"),
+			Series: col(row, "SERIES"),
+			ISIN:   col(row, "ISIN NUMBER"),
+		}
+		if info.Symbol == "" {
+			continue
+		}
+		info.MarketLot, _ = strconv.Atoi(col(row, "MARKET LOT"))
+		if info.MarketLot < 1 {
+			info.MarketLot = 1
+		}
+		info.FaceValue, _ = strconv.ParseFloat(col(row, "FACE VALUE"), 64)
+		r.bySymbol[info.Symbol] = info
+		if info.ISIN != "" {
+			r.byISIN[info.ISIN] = info
+		}
+	}
+	if len(r.bySymbol) == 0 {
+		return nil, errors.New("no symbols listed")
+	}
+	return r, nil
+}
+
+// addIndustries reads an NSE index list: Company Name, Industry, Symbol,
+// Series, ISIN Code.
+func (r *Registry) addIndustries(body []byte) error {
+	_, rows, col, err := readCSV(body, "Symbol", "Industry")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if info, ok := r.bySymbol[col(row, "Symbol")]; ok {
+			info.Industry = col(row, "Industry")
+		}
+	}
+	return nil
+}
+
+// addLots reads fo_mktlots.csv: UNDERLYING, SYMBOL, then one column per
+// contract month. The nearest month with a lot is used.
+func (r *Registry) addLots(body []byte) error {
+	header, rows, col, err := readCSV(body, "SYMBOL")
+	if err != nil {
+		return err
+	}
+	symCol := -1
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), "SYMBOL") {
+			symCol = i
+		}
+	}
+	for _, row := range rows {
+		info, ok := r.bySymbol[col(row, "SYMBOL")]
+		if !ok || symCol >= len(row) {
+			continue
+		}
+		for _, cell := range row[symCol+1:] {
+			if lot, err := strconv.Atoi(strings.TrimSpace(cell)); err == nil && lot > 0 {
+				info.LotSize = lot
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// readCSV returns the header, the data rows and an accessor by column
+// name. The named columns must be present; names match trimmed and
+// case-insensitively.
+func readCSV(body []byte, required ...string) ([]string, [][]string, func(row []string, name string) string, error) {
+	cr := csv.NewReader(strings.NewReader(string(body)))
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(rows) < 2 {
+		return nil, nil, nil, errors.New("file is empty")
+	}
+	idx := make(map[string]int, len(rows[0]))
+	for i, h := range rows[0] {
+		idx[strings.ToUpper(strings.TrimSpace(h))] = i
+	}
+	for _, name := range required {
+		if _, ok := idx[strings.ToUpper(name)]; !ok {
+			return nil, nil, nil, fmt.Errorf("no %s column", name)
+		}
+	}
+	col := func(row []string, name string) string {
+		i := idx[strings.ToUpper(name)]
+		if i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	return rows[0], rows[1:], col, nil
+}
+
+// file returns the body of url, from the cache when fresh enough.
+func (p Params) file(ctx context.Context, url string) ([]byte, error) {
+	path := ""
+	if p.CacheDir != "" {
+		path = filepath.Join(p.CacheDir, filepath.Base(url))
+		if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) < p.MaxAge {
+			if b, err := os.ReadFile(path); err == nil {
+				return b, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	body, err := fetch(ctx, url)
+	quality.Record("symbols.nse", start, err, 1, 1)
+	if err == nil {
+		if path != "" {
+			if werr := writeFile(path, body); werr != nil {
+				logger.Warn(ctx, "Failed to cache symbol master file", "path", path, "error", werr)
+			}
+		}
+		return body, nil
+	}
+	if path != "" {
+		if b, cerr := os.ReadFile(path); cerr == nil {
+			logger.Warn(ctx, "Symbol master fetch failed - using stale cached copy", "file", filepath.Base(url), "error", err)
+			return b, nil
+		}
+	}
+	return nil, err
+}
+
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func writeFile(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+var defaultRegistry *Registry
+
+// SetDefault installs the registry used by Get and Check; until then
+// lookups find nothing and every order passes.
+func SetDefault(r *Registry) {
+	defaultRegistry = r
+}
+
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Get looks symbol up in the default registry.
+func Get(symbol string) (Info, bool) {
+	return defaultRegistry.Get(symbol)
+}
+
+// Check validates an order against the default registry.
+func Check(symbol string, qty int) error {
+	return defaultRegistry.Check(symbol, qty)
+}
@@ -0,0 +1,45 @@
+package ta
+
+import "math"
+
+// midRange is (highest high + lowest low) / 2 over the n bars ending at i.
+func midRange(highs, lows []float64, i, n int) float64 {
+	if n <= 0 || i+1 < n {
+		return math.NaN()
+	}
+	hh, ll := math.Inf(-1), math.Inf(1)
+	for j := i - n + 1; j <= i; j++ {
+		hh = math.Max(hh, highs[j])
+		ll = math.Min(ll, lows[j])
+	}
+	return (hh + ll) / 2
+}
+
+// IchimokuSeries returns Tenkan-sen, Kijun-sen and the two Senkou spans.
+// The spans are displaced forward: senkouA[i] and senkouB[i] are the cloud
+// edges under bar i, i.e. the values computed displacement bars earlier.
+func IchimokuSeries(highs, lows []float64, tenkanPeriod, kijunPeriod, senkouPeriod, displacement int) (tenkan, kijun, senkouA, senkouB []float64) {
+	n := len(highs)
+	tenkan, kijun, senkouA, senkouB = nanSeries(n), nanSeries(n), nanSeries(n), nanSeries(n)
+	if len(lows) != n || displacement < 0 {
+		return
+	}
+
+	spanA, spanB := nanSeries(n), nanSeries(n)
+	for i := 0; i < n; i++ {
+		tenkan[i] = midRange(highs, lows, i, tenkanPeriod)
+		kijun[i] = midRange(highs, lows, i, kijunPeriod)
+		spanA[i] = (tenkan[i] + kijun[i]) / 2
+		spanB[i] = midRange(highs, lows, i, senkouPeriod)
+	}
+	for i := displacement; i < n; i++ {
+		senkouA[i] = spanA[i-displacement]
+		senkouB[i] = spanB[i-displacement]
+	}
+	return
+}
+
+func Ichimoku(highs, lows []float64, tenkanPeriod, kijunPeriod, senkouPeriod, displacement int) (tenkan, kijun, senkouA, senkouB float64) {
+	t, k, a, b := IchimokuSeries(highs, lows, tenkanPeriod, kijunPeriod, senkouPeriod, displacement)
+	return last(t), last(k), last(a), last(b)
+}
@@ -0,0 +1,19 @@
+package ta
+
+// KeltnerSeries returns an EMA midline with bands mult Wilder-ATRs away.
+func KeltnerSeries(highs, lows, closes []float64, emaPeriod, atrPeriod int, mult float64) (mid, up, low []float64) {
+	mid = EMASeries(closes, emaPeriod)
+	atr := WilderSeries(TrueRangeSeries(highs, lows, closes), atrPeriod)
+
+	up, low = nanSeries(len(closes)), nanSeries(len(closes))
+	for i := range closes {
+		up[i] = mid[i] + mult*atr[i]
+		low[i] = mid[i] - mult*atr[i]
+	}
+	return mid, up, low
+}
+
+func Keltner(highs, lows, closes []float64, emaPeriod, atrPeriod int, mult float64) (mid, up, low float64) {
+	m, u, l := KeltnerSeries(highs, lows, closes, emaPeriod, atrPeriod, mult)
+	return last(m), last(u), last(l)
+}
@@ -0,0 +1,127 @@
+// Package patterns detects classic candlestick patterns on OHLC candles.
+package patterns
+
+import (
+	"math"
+
+	"llm-trading-bot/internal/types"
+)
+
+const (
+	Doji             = "doji"
+	Hammer           = "hammer"
+	BullishEngulfing = "bullish_engulfing"
+	BearishEngulfing = "bearish_engulfing"
+	MorningStar      = "morning_star"
+	EveningStar      = "evening_star"
+	InsideBar        = "inside_bar"
+)
+
+// Pattern is one detection. Index is the position in the input slice of
+// the candle that completes the pattern.
+type Pattern struct {
+	Name  string
+	Bias  int // +1 bullish, -1 bearish, 0 indecision
+	Index int
+}
+
+var bias = map[string]int{
+	Doji:             0,
+	Hammer:           1,
+	BullishEngulfing: 1,
+	BearishEngulfing: -1,
+	MorningStar:      1,
+	EveningStar:      -1,
+	InsideBar:        0,
+}
+
+// Bias returns the directional bias of a pattern name, 0 if unknown.
+func Bias(name string) int {
+	return bias[name]
+}
+
+// Detect returns the patterns completed by any of the last lookback
+// candles, oldest first.
+func Detect(candles []types.Candle, lookback int) []Pattern {
+	var out []Pattern
+	start := len(candles) - lookback
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(candles); i++ {
+		for _, name := range at(candles, i) {
+			out = append(out, Pattern{Name: name, Bias: bias[name], Index: i})
+		}
+	}
+	return out
+}
+
+// Names lists the distinct pattern names in detection order.
+func Names(pats []Pattern) []string {
+	seen := make(map[string]bool, len(pats))
+	var out []string
+	for _, p := range pats {
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			out = append(out, p.Name)
+		}
+	}
+	return out
+}
+
+func at(candles []types.Candle, i int) []string {
+	var out []string
+	c := candles[i]
+
+	if isDoji(c) {
+		out = append(out, Doji)
+	} else if isHammer(c) {
+		out = append(out, Hammer)
+	}
+
+	if i >= 1 {
+		p := candles[i-1]
+		switch {
+		case bearish(p) && bullish(c) && c.Open <= p.Close && c.Close >= p.Open && body(c) > body(p):
+			out = append(out, BullishEngulfing)
+		case bullish(p) && bearish(c) && c.Open >= p.Close && c.Close <= p.Open && body(c) > body(p):
+			out = append(out, BearishEngulfing)
+		}
+		if c.High <= p.High && c.Low >= p.Low {
+			out = append(out, InsideBar)
+		}
+	}
+
+	if i >= 2 {
+		a, b := candles[i-2], candles[i-1]
+		long := body(a) >= 0.5*rng(a)
+		small := body(b) <= 0.3*body(a)
+		switch {
+		case long && small && bearish(a) && bullish(c) && c.Close > (a.Open+a.Close)/2:
+			out = append(out, MorningStar)
+		case long && small && bullish(a) && bearish(c) && c.Close < (a.Open+a.Close)/2:
+			out = append(out, EveningStar)
+		}
+	}
+
+	return out
+}
+
+func body(c types.Candle) float64 { return math.Abs(c.Close - c.Open) }
+func rng(c types.Candle) float64  { return c.High - c.Low }
+func bullish(c types.Candle) bool { return c.Close > c.Open }
+func bearish(c types.Candle) bool { return c.Close < c.Open }
+
+func isDoji(c types.Candle) bool {
+	return rng(c) > 0 && body(c) <= 0.1*rng(c)
+}
+
+// isHammer: small body near the top with a lower shadow at least twice
+// the body and little upper shadow.
+func isHammer(c types.Candle) bool {
+	top := math.Max(c.Open, c.Close)
+	bottom := math.Min(c.Open, c.Close)
+	lower := bottom - c.Low
+	upper := c.High - top
+	return rng(c) > 0 && lower >= 2*body(c) && upper <= 0.1*rng(c)
+}
@@ -0,0 +1,21 @@
+package ta
+
+import "math"
+
+// RelativeStrength is the stock's percentage return over window bars minus
+// the benchmark's over the same bars. Both slices must be aligned bar for
+// bar.
+func RelativeStrength(closes, benchmark []float64, window int) float64 {
+	return pctChange(closes, window) - pctChange(benchmark, window)
+}
+
+func pctChange(vals []float64, window int) float64 {
+	if window <= 0 || len(vals) < window+1 {
+		return math.NaN()
+	}
+	from := vals[len(vals)-1-window]
+	if from == 0 {
+		return math.NaN()
+	}
+	return (vals[len(vals)-1] - from) / from * 100
+}
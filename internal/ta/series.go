@@ -0,0 +1,155 @@
+package ta
+
+import "math"
+
+// Series functions return one value per input bar, aligned with the input
+// slice. Bars inside the warm-up window are NaN.
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+func last(vals []float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	return vals[len(vals)-1]
+}
+
+// EMASeries seeds with the SMA of the first period values and skips any
+// leading NaNs, so it can be chained onto another series (e.g. MACD).
+func EMASeries(vals []float64, period int) []float64 {
+	out := nanSeries(len(vals))
+	if period <= 0 {
+		return out
+	}
+
+	start := 0
+	for start < len(vals) && math.IsNaN(vals[start]) {
+		start++
+	}
+	if len(vals)-start < period {
+		return out
+	}
+
+	k := 2.0 / float64(period+1)
+	ema := SMA(vals[start:start+period], period)
+	out[start+period-1] = ema
+	for i := start + period; i < len(vals); i++ {
+		ema = vals[i]*k + ema*(1-k)
+		out[i] = ema
+	}
+	return out
+}
+
+// WilderSeries is Wilder's moving average (RMA): an SMA seed followed by
+// avg = (prev*(period-1) + v) / period. Leading NaNs are skipped.
+func WilderSeries(vals []float64, period int) []float64 {
+	out := nanSeries(len(vals))
+	if period <= 0 {
+		return out
+	}
+
+	start := 0
+	for start < len(vals) && math.IsNaN(vals[start]) {
+		start++
+	}
+	if len(vals)-start < period {
+		return out
+	}
+
+	avg := SMA(vals[start:start+period], period)
+	out[start+period-1] = avg
+	for i := start + period; i < len(vals); i++ {
+		avg = (avg*float64(period-1) + vals[i]) / float64(period)
+		out[i] = avg
+	}
+	return out
+}
+
+// MACDSeries returns the MACD line (fast EMA - slow EMA), its signal line
+// (EMA of the MACD line) and the histogram.
+func MACDSeries(closes []float64, fastPeriod, slowPeriod, signalPeriod int) (macd, signal, histogram []float64) {
+	fast := EMASeries(closes, fastPeriod)
+	slow := EMASeries(closes, slowPeriod)
+
+	macd = nanSeries(len(closes))
+	for i := range closes {
+		macd[i] = fast[i] - slow[i]
+	}
+	signal = EMASeries(macd, signalPeriod)
+
+	histogram = nanSeries(len(closes))
+	for i := range closes {
+		histogram[i] = macd[i] - signal[i]
+	}
+	return macd, signal, histogram
+}
+
+// TrueRangeSeries starts at the second bar since true range needs the
+// previous close.
+func TrueRangeSeries(highs, lows, closes []float64) []float64 {
+	out := nanSeries(len(closes))
+	if len(highs) != len(lows) || len(lows) != len(closes) {
+		return out
+	}
+	for i := 1; i < len(closes); i++ {
+		tr1 := highs[i] - lows[i]
+		tr2 := math.Abs(highs[i] - closes[i-1])
+		tr3 := math.Abs(lows[i] - closes[i-1])
+		out[i] = math.Max(tr1, math.Max(tr2, tr3))
+	}
+	return out
+}
+
+// ADXSeries computes Wilder's +DI, -DI and ADX. ADX needs roughly
+// 2*period bars before its first value.
+func ADXSeries(highs, lows, closes []float64, period int) (adx, plusDI, minusDI []float64) {
+	n := len(closes)
+	adx, plusDI, minusDI = nanSeries(n), nanSeries(n), nanSeries(n)
+	if len(highs) != len(lows) || len(lows) != n || period <= 0 || n < period+1 {
+		return
+	}
+
+	plusDM, minusDM := nanSeries(n), nanSeries(n)
+	for i := 1; i < n; i++ {
+		up := highs[i] - highs[i-1]
+		down := lows[i-1] - lows[i]
+		plusDM[i], minusDM[i] = 0, 0
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+	}
+
+	tr := WilderSeries(TrueRangeSeries(highs, lows, closes), period)
+	sPlus := WilderSeries(plusDM, period)
+	sMinus := WilderSeries(minusDM, period)
+
+	dx := nanSeries(n)
+	for i := range closes {
+		if math.IsNaN(tr[i]) {
+			continue
+		}
+		if tr[i] == 0 {
+			plusDI[i], minusDI[i], dx[i] = 0, 0, 0
+			continue
+		}
+		plusDI[i] = sPlus[i] / tr[i] * 100
+		minusDI[i] = sMinus[i] / tr[i] * 100
+		if sum := plusDI[i] + minusDI[i]; sum != 0 {
+			dx[i] = math.Abs(plusDI[i]-minusDI[i]) / sum * 100
+		} else {
+			dx[i] = 0
+		}
+	}
+
+	adx = WilderSeries(dx, period)
+	return adx, plusDI, minusDI
+}
@@ -0,0 +1,133 @@
+package ta
+
+import (
+	"math"
+	"testing"
+)
+
+// ramp returns 1, 2, ..., n. An EMA seeded with the SMA of a straight line
+// lags it by exactly (period-1)/2 from its first value on, which gives
+// closed-form references for EMA and MACD.
+func ramp(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = float64(i + 1)
+	}
+	return out
+}
+
+func TestEMASeries(t *testing.T) {
+	got := EMASeries(stockCharts, 10)
+	for i := 0; i < 9; i++ {
+		if !math.IsNaN(got[i]) {
+			t.Errorf("EMA(10)[%d] = %v during warm-up, want NaN", i, got[i])
+		}
+	}
+	for i, want := range emaRef {
+		if !near(got[9+i], want, 0.01) {
+			t.Errorf("EMA(10)[%d] = %.4f, want %.2f", 9+i, got[9+i], want)
+		}
+	}
+
+	for _, period := range []int{3, 12, 26} {
+		closes := ramp(60)
+		ema := EMASeries(closes, period)
+		for i := period - 1; i < len(closes); i++ {
+			if want := closes[i] - float64(period-1)/2; !near(ema[i], want, 1e-9) {
+				t.Fatalf("EMA(%d) of a ramp at %d = %v, want %v", period, i, ema[i], want)
+			}
+		}
+	}
+
+	// Leading NaNs are skipped, as when chained onto MACD
+	chained := EMASeries([]float64{math.NaN(), math.NaN(), 1, 2, 3}, 3)
+	if !math.IsNaN(chained[3]) || !near(chained[4], 2, 1e-12) {
+		t.Errorf("EMA over leading NaNs = %v, want [NaN NaN NaN NaN 2]", chained)
+	}
+}
+
+func TestMACDSeries(t *testing.T) {
+	tests := []struct {
+		name                 string
+		closes               []float64
+		macd, signal, hist   float64
+		firstMACD, firstSign int
+	}{
+		// Lags of (26-1)/2 and (12-1)/2 leave a constant gap of 7
+		{"ramp", ramp(60), 7, 7, 0, 25, 33},
+		{"flat", []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}, 0, 0, 0, 25, 33},
+	}
+	for _, tt := range tests {
+		macd, signal, hist := MACDSeries(tt.closes, 12, 26, 9)
+		if !math.IsNaN(macd[tt.firstMACD-1]) || !math.IsNaN(signal[tt.firstSign-1]) {
+			t.Errorf("%s: values before warm-up ends: macd %v, signal %v", tt.name, macd[tt.firstMACD-1], signal[tt.firstSign-1])
+		}
+		for i := tt.firstMACD; i < len(tt.closes); i++ {
+			if !near(macd[i], tt.macd, 1e-9) {
+				t.Fatalf("%s: macd[%d] = %v, want %v", tt.name, i, macd[i], tt.macd)
+			}
+		}
+		for i := tt.firstSign; i < len(tt.closes); i++ {
+			if !near(signal[i], tt.signal, 1e-9) || !near(hist[i], tt.hist, 1e-9) {
+				t.Fatalf("%s: signal/hist[%d] = %v/%v, want %v/%v", tt.name, i, signal[i], hist[i], tt.signal, tt.hist)
+			}
+		}
+	}
+
+	m, s, h := MACD(ramp(60), 12, 26, 9)
+	if !near(m, 7, 1e-9) || !near(s, 7, 1e-9) || !near(h, 0, 1e-9) {
+		t.Errorf("MACD = %v/%v/%v, want 7/7/0", m, s, h)
+	}
+}
+
+// trend returns bars with a 1-wide range stepping by step each bar, closing
+// mid-range: true range 1.5 and one-sided directional movement of 1.
+func trend(n int, step float64) (highs, lows, closes []float64) {
+	for i := 0; i < n; i++ {
+		low := 100 + step*float64(i)
+		highs = append(highs, low+1)
+		lows = append(lows, low)
+		closes = append(closes, low+0.5)
+	}
+	return
+}
+
+func TestADXSeries(t *testing.T) {
+	const period = 14
+	upH, upL, upC := trend(40, 1)
+	downH, downL, downC := trend(40, -1)
+	flat := make([]float64, 40)
+	for i := range flat {
+		flat[i] = 10
+	}
+
+	tests := []struct {
+		name                 string
+		highs, lows, closes  []float64
+		adx, plusDI, minusDI float64
+	}{
+		{"uptrend", upH, upL, upC, 100, 100 / 1.5, 0},
+		{"downtrend", downH, downL, downC, 100, 0, 100 / 1.5},
+		{"no range", flat, flat, flat, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		adx, plus, minus := ADXSeries(tt.highs, tt.lows, tt.closes, period)
+		// DI starts once true range is smoothed, ADX a period later
+		if !math.IsNaN(plus[period-1]) || !math.IsNaN(adx[2*period-2]) {
+			t.Errorf("%s: values before warm-up ends: +DI %v, ADX %v", tt.name, plus[period-1], adx[2*period-2])
+		}
+		for i := period; i < len(tt.closes); i++ {
+			if !near(plus[i], tt.plusDI, 1e-9) || !near(minus[i], tt.minusDI, 1e-9) {
+				t.Fatalf("%s: DI[%d] = +%v/-%v, want +%v/-%v", tt.name, i, plus[i], minus[i], tt.plusDI, tt.minusDI)
+			}
+		}
+		for i := 2*period - 1; i < len(tt.closes); i++ {
+			if !near(adx[i], tt.adx, 1e-9) {
+				t.Fatalf("%s: ADX[%d] = %v, want %v", tt.name, i, adx[i], tt.adx)
+			}
+		}
+		if got := ADX(tt.highs, tt.lows, tt.closes, period); !near(got, tt.adx, 1e-9) {
+			t.Errorf("%s: ADX = %v, want %v", tt.name, got, tt.adx)
+		}
+	}
+}
@@ -0,0 +1,559 @@
+package ta
+
+import (
+	"math"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// Streaming indicators keep running state so a new bar costs O(1) (or
+// O(window) for the short dispersion windows) instead of a pass over the
+// whole history. Add commits a closed bar; Peek returns the value the
+// indicator would have with one more bar appended, without committing it,
+// which is how a still-forming bar is evaluated. Values match the batch
+// functions over the same bars, except that EMA/Wilder-based ones carry
+// memory from before a batch window.
+
+// ring is a fixed-size window with a running sum that is re-summed each
+// time it wraps so float drift stays bounded.
+type ring struct {
+	vals  []float64
+	next  int
+	count int
+	sum   float64
+}
+
+func newRing(n int) *ring {
+	if n < 0 {
+		n = 0
+	}
+	return &ring{vals: make([]float64, n)}
+}
+
+func (r *ring) full() bool { return r.count == len(r.vals) }
+
+func (r *ring) oldest() float64 {
+	if !r.full() {
+		return 0
+	}
+	return r.vals[r.next]
+}
+
+func (r *ring) push(v float64) {
+	if len(r.vals) == 0 {
+		return
+	}
+	r.sum += v - r.oldest()
+	r.vals[r.next] = v
+	r.next = (r.next + 1) % len(r.vals)
+	if r.count < len(r.vals) {
+		r.count++
+	}
+	if r.next == 0 {
+		r.sum = 0
+		for _, x := range r.vals {
+			r.sum += x
+		}
+	}
+}
+
+// sumWith is the window sum if v were pushed, or NaN if the window would
+// still be short.
+func (r *ring) sumWith(v float64) float64 {
+	if len(r.vals) == 0 || r.count+1 < len(r.vals) {
+		return math.NaN()
+	}
+	return r.sum - r.oldest() + v
+}
+
+// each calls fn on the window contents as if v were pushed, oldest first.
+func (r *ring) each(v float64, fn func(float64)) {
+	n := len(r.vals)
+	skip := 0
+	if r.full() {
+		skip = 1
+	}
+	start := r.next
+	if !r.full() {
+		start = 0
+	}
+	for i := skip; i < r.count; i++ {
+		fn(r.vals[(start+i)%n])
+	}
+	fn(v)
+}
+
+type SMAStream struct {
+	n   int
+	win *ring
+}
+
+func NewSMAStream(n int) *SMAStream {
+	return &SMAStream{n: n, win: newRing(n)}
+}
+
+func (s *SMAStream) Add(v float64) { s.win.push(v) }
+
+func (s *SMAStream) Peek(v float64) float64 {
+	if s.n <= 0 {
+		return math.NaN()
+	}
+	return s.win.sumWith(v) / float64(s.n)
+}
+
+// BollingerStream mirrors Bollinger: SMA mid with population stddev bands.
+type BollingerStream struct {
+	n   int
+	k   float64
+	win *ring
+}
+
+func NewBollingerStream(n int, k float64) *BollingerStream {
+	return &BollingerStream{n: n, k: k, win: newRing(n)}
+}
+
+func (b *BollingerStream) Add(v float64) { b.win.push(v) }
+
+func (b *BollingerStream) Peek(v float64) (mid, up, low float64) {
+	if b.n <= 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	mid = b.win.sumWith(v) / float64(b.n)
+	if math.IsNaN(mid) {
+		return mid, mid, mid
+	}
+	ss := 0.0
+	b.win.each(v, func(x float64) {
+		d := x - mid
+		ss += d * d
+	})
+	sd := math.Sqrt(ss / float64(b.n))
+	return mid, mid + b.k*sd, mid - b.k*sd
+}
+
+// RSIStream mirrors RSI: simple averages of gains and losses over the
+// last period closes.
+type RSIStream struct {
+	period      int
+	gains, loss *ring
+	prev        float64
+	hasPrev     bool
+}
+
+func NewRSIStream(period int) *RSIStream {
+	return &RSIStream{period: period, gains: newRing(period), loss: newRing(period)}
+}
+
+func (s *RSIStream) Add(close float64) {
+	if s.hasPrev {
+		g, l := gainLoss(close - s.prev)
+		s.gains.push(g)
+		s.loss.push(l)
+	}
+	s.prev, s.hasPrev = close, true
+}
+
+func (s *RSIStream) Peek(close float64) float64 {
+	if s.period <= 0 || !s.hasPrev {
+		return math.NaN()
+	}
+	g, l := gainLoss(close - s.prev)
+	gain, loss := s.gains.sumWith(g), s.loss.sumWith(l)
+	if math.IsNaN(gain) {
+		return math.NaN()
+	}
+	if loss == 0 {
+		return 100.0
+	}
+	rs := (gain / float64(s.period)) / (loss / float64(s.period))
+	return 100.0 - (100.0 / (1.0 + rs))
+}
+
+func gainLoss(d float64) (gain, loss float64) {
+	if d > 0 {
+		return d, 0
+	}
+	return 0, -d
+}
+
+func trueRange(high, low, prevClose float64) float64 {
+	return math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+}
+
+// ATRStream mirrors ATR: a simple average of the last period true ranges.
+type ATRStream struct {
+	period  int
+	trs     *ring
+	prev    float64
+	hasPrev bool
+}
+
+func NewATRStream(period int) *ATRStream {
+	return &ATRStream{period: period, trs: newRing(period)}
+}
+
+func (s *ATRStream) Add(high, low, close float64) {
+	if s.hasPrev {
+		s.trs.push(trueRange(high, low, s.prev))
+	}
+	s.prev, s.hasPrev = close, true
+}
+
+func (s *ATRStream) Peek(high, low, close float64) float64 {
+	if s.period <= 0 || !s.hasPrev {
+		return math.NaN()
+	}
+	return s.trs.sumWith(trueRange(high, low, s.prev)) / float64(s.period)
+}
+
+// WilderStream mirrors WilderSeries: an SMA seed over the first period
+// values, then Wilder's recursive average.
+type WilderStream struct {
+	period int
+	seed   *ring
+	avg    float64
+	ready  bool
+}
+
+func NewWilderStream(period int) *WilderStream {
+	return &WilderStream{period: period, seed: newRing(period)}
+}
+
+func (s *WilderStream) Add(v float64) {
+	s.avg = s.Peek(v)
+	if s.ready {
+		return
+	}
+	s.seed.push(v)
+	s.ready = s.seed.full()
+}
+
+func (s *WilderStream) Peek(v float64) float64 {
+	if s.period <= 0 {
+		return math.NaN()
+	}
+	if s.ready {
+		return (s.avg*float64(s.period-1) + v) / float64(s.period)
+	}
+	return s.seed.sumWith(v) / float64(s.period)
+}
+
+// VWAPStream mirrors VWAPSeries, resetting at each IST day boundary.
+// VWAPSeries only sums the bars it is given, so Trim drops the bars that
+// have left the caller's window, as for OBVStream.
+type VWAPStream struct {
+	day     time.Time
+	bars    []vwapBar // The day's bars, oldest first
+	pv, vol float64   // Sums over bars
+}
+
+type vwapBar struct {
+	ts      int64
+	pv, vol float64
+}
+
+func NewVWAPStream() *VWAPStream { return &VWAPStream{} }
+
+func (s *VWAPStream) Add(ts int64, high, low, close, vol float64) {
+	day, _, _ := s.next(ts, high, low, close, vol)
+	if !day.Equal(s.day) {
+		s.day, s.bars, s.pv, s.vol = day, nil, 0, 0
+	}
+	pv := (high + low + close) / 3 * vol
+	s.bars = append(s.bars, vwapBar{ts: ts, pv: pv, vol: vol})
+	s.pv += pv
+	s.vol += vol
+}
+
+// Trim drops the bars before first, the first bar of the window.
+func (s *VWAPStream) Trim(first int64) {
+	n := 0
+	for n < len(s.bars) && s.bars[n].ts < first {
+		s.pv -= s.bars[n].pv
+		s.vol -= s.bars[n].vol
+		n++
+	}
+	s.bars = s.bars[n:]
+	if len(s.bars) == 0 {
+		s.pv, s.vol = 0, 0 // Drop any float residue
+	}
+}
+
+func (s *VWAPStream) Peek(ts int64, high, low, close, vol float64) float64 {
+	_, pv, v := s.next(ts, high, low, close, vol)
+	if v <= 0 {
+		return math.NaN()
+	}
+	return pv / v
+}
+
+func (s *VWAPStream) next(ts int64, high, low, close, vol float64) (time.Time, float64, float64) {
+	t := time.Unix(ts, 0).In(timeutil.IST)
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, timeutil.IST)
+	pv, v := s.pv, s.vol
+	if !d.Equal(s.day) {
+		pv, v = 0, 0
+	}
+	return d, pv + (high+low+close)/3*vol, v + vol
+}
+
+// OBVStream mirrors OBVSeries over a moving window. OBVSeries starts from
+// zero at the first bar it is given, so Trim drops the bars that have left
+// the caller's window and the value restarts where the batch one does.
+type OBVStream struct {
+	steps   []obvStep // Signed volume of each bar after the first, oldest first
+	obv     float64   // Sum of steps
+	prev    float64
+	hasPrev bool
+}
+
+type obvStep struct {
+	ts  int64
+	vol float64
+}
+
+func NewOBVStream() *OBVStream { return &OBVStream{} }
+
+func (s *OBVStream) Add(ts int64, close, vol float64) {
+	if s.hasPrev {
+		v := s.signed(close, vol)
+		s.steps = append(s.steps, obvStep{ts: ts, vol: v})
+		s.obv += v
+	}
+	s.prev, s.hasPrev = close, true
+}
+
+// Trim drops the bars up to and including first, the first bar of the
+// window: it has no previous bar inside the window, so adds nothing.
+func (s *OBVStream) Trim(first int64) {
+	n := 0
+	for n < len(s.steps) && s.steps[n].ts <= first {
+		s.obv -= s.steps[n].vol
+		n++
+	}
+	s.steps = s.steps[n:]
+	if len(s.steps) == 0 {
+		s.obv = 0 // Drop any float residue
+	}
+}
+
+func (s *OBVStream) Peek(close, vol float64) float64 {
+	if !s.hasPrev {
+		return 0
+	}
+	return s.obv + s.signed(close, vol)
+}
+
+func (s *OBVStream) signed(close, vol float64) float64 {
+	switch {
+	case close > s.prev:
+		return vol
+	case close < s.prev:
+		return -vol
+	}
+	return 0
+}
+
+// SupertrendStream mirrors SupertrendSeries.
+type SupertrendStream struct {
+	mult         float64
+	atr          *WilderStream
+	prev         float64
+	hasPrev      bool
+	upper, lower float64
+	dir          int
+}
+
+func NewSupertrendStream(period int, mult float64) *SupertrendStream {
+	return &SupertrendStream{mult: mult, atr: NewWilderStream(period)}
+}
+
+func (s *SupertrendStream) Add(high, low, close float64) {
+	if s.hasPrev {
+		tr := trueRange(high, low, s.prev)
+		s.upper, s.lower, s.dir = s.next(high, low, close, tr)
+		s.atr.Add(tr)
+	}
+	s.prev, s.hasPrev = close, true
+}
+
+func (s *SupertrendStream) Peek(high, low, close float64) (value float64, direction int) {
+	if !s.hasPrev {
+		return math.NaN(), 0
+	}
+	upper, lower, dir := s.next(high, low, close, trueRange(high, low, s.prev))
+	switch {
+	case dir > 0:
+		return lower, dir
+	case dir < 0:
+		return upper, dir
+	}
+	return math.NaN(), 0
+}
+
+func (s *SupertrendStream) next(high, low, close, tr float64) (upper, lower float64, dir int) {
+	atr := s.atr.Peek(tr)
+	if math.IsNaN(atr) {
+		return s.upper, s.lower, 0
+	}
+	hl2 := (high + low) / 2
+	basicUpper := hl2 + s.mult*atr
+	basicLower := hl2 - s.mult*atr
+
+	if s.dir == 0 {
+		dir = 1
+		if close < hl2 {
+			dir = -1
+		}
+		return basicUpper, basicLower, dir
+	}
+
+	upper, lower, dir = s.upper, s.lower, s.dir
+	if basicUpper < upper || s.prev > upper {
+		upper = basicUpper
+	}
+	if basicLower > lower || s.prev < lower {
+		lower = basicLower
+	}
+	if dir < 0 && close > upper {
+		dir = 1
+	} else if dir > 0 && close < lower {
+		dir = -1
+	}
+	return upper, lower, dir
+}
+
+// EMAStream mirrors EMASeries.
+type EMAStream struct {
+	period int
+	seed   *ring
+	ema    float64
+	ready  bool
+}
+
+func NewEMAStream(period int) *EMAStream {
+	return &EMAStream{period: period, seed: newRing(period)}
+}
+
+func (s *EMAStream) Add(v float64) {
+	s.ema = s.Peek(v)
+	if s.ready {
+		return
+	}
+	s.seed.push(v)
+	s.ready = s.seed.full()
+}
+
+func (s *EMAStream) Peek(v float64) float64 {
+	if s.period <= 0 {
+		return math.NaN()
+	}
+	if s.ready {
+		k := 2.0 / float64(s.period+1)
+		return v*k + s.ema*(1-k)
+	}
+	return s.seed.sumWith(v) / float64(s.period)
+}
+
+// KeltnerStream mirrors KeltnerSeries.
+type KeltnerStream struct {
+	mult    float64
+	ema     *EMAStream
+	atr     *WilderStream
+	prev    float64
+	hasPrev bool
+}
+
+func NewKeltnerStream(emaPeriod, atrPeriod int, mult float64) *KeltnerStream {
+	return &KeltnerStream{mult: mult, ema: NewEMAStream(emaPeriod), atr: NewWilderStream(atrPeriod)}
+}
+
+func (s *KeltnerStream) Add(high, low, close float64) {
+	s.ema.Add(close)
+	if s.hasPrev {
+		s.atr.Add(trueRange(high, low, s.prev))
+	}
+	s.prev, s.hasPrev = close, true
+}
+
+func (s *KeltnerStream) Peek(high, low, close float64) (mid, up, lower float64) {
+	mid = s.ema.Peek(close)
+	atr := math.NaN()
+	if s.hasPrev {
+		atr = s.atr.Peek(trueRange(high, low, s.prev))
+	}
+	return mid, mid + s.mult*atr, mid - s.mult*atr
+}
+
+// midRangeStream tracks (highest high + lowest low) / 2 over n bars.
+type midRangeStream struct {
+	n           int
+	highs, lows *ring
+}
+
+func newMidRangeStream(n int) *midRangeStream {
+	return &midRangeStream{n: n, highs: newRing(n), lows: newRing(n)}
+}
+
+func (s *midRangeStream) add(high, low float64) {
+	s.highs.push(high)
+	s.lows.push(low)
+}
+
+func (s *midRangeStream) peek(high, low float64) float64 {
+	if s.n <= 0 || s.highs.count+1 < s.n {
+		return math.NaN()
+	}
+	hh, ll := math.Inf(-1), math.Inf(1)
+	s.highs.each(high, func(v float64) { hh = math.Max(hh, v) })
+	s.lows.each(low, func(v float64) { ll = math.Min(ll, v) })
+	return (hh + ll) / 2
+}
+
+// IchimokuStream mirrors IchimokuSeries. Committed span values are kept
+// for displacement bars so the cloud under the next bar is known.
+type IchimokuStream struct {
+	tenkan, kijun, senkou *midRangeStream
+	spanA, spanB          []float64 // Ring of the last displacement committed spans
+	next, count           int
+}
+
+func NewIchimokuStream(tenkanPeriod, kijunPeriod, senkouPeriod, displacement int) *IchimokuStream {
+	if displacement < 0 {
+		displacement = 0
+	}
+	return &IchimokuStream{
+		tenkan: newMidRangeStream(tenkanPeriod),
+		kijun:  newMidRangeStream(kijunPeriod),
+		senkou: newMidRangeStream(senkouPeriod),
+		spanA:  make([]float64, displacement),
+		spanB:  make([]float64, displacement),
+	}
+}
+
+func (s *IchimokuStream) Add(high, low float64) {
+	if d := len(s.spanA); d > 0 {
+		t, k := s.tenkan.peek(high, low), s.kijun.peek(high, low)
+		s.spanA[s.next] = (t + k) / 2
+		s.spanB[s.next] = s.senkou.peek(high, low)
+		s.next = (s.next + 1) % d
+		if s.count < d {
+			s.count++
+		}
+	}
+	s.tenkan.add(high, low)
+	s.kijun.add(high, low)
+	s.senkou.add(high, low)
+}
+
+func (s *IchimokuStream) Peek(high, low float64) (tenkan, kijun, senkouA, senkouB float64) {
+	tenkan, kijun = s.tenkan.peek(high, low), s.kijun.peek(high, low)
+	switch d := len(s.spanA); {
+	case d == 0:
+		return tenkan, kijun, (tenkan + kijun) / 2, s.senkou.peek(high, low)
+	case s.count < d:
+		return tenkan, kijun, math.NaN(), math.NaN()
+	}
+	return tenkan, kijun, s.spanA[s.next], s.spanB[s.next]
+}
@@ -0,0 +1,67 @@
+package ta
+
+import "math"
+
+// SupertrendSeries returns the Supertrend line and its direction (+1 while
+// price trades above the line, -1 below, 0 during warm-up). Bands are
+// hl2 ± mult*ATR with Wilder-smoothed ATR, and only ratchet in the trend
+// direction until price closes through them.
+func SupertrendSeries(highs, lows, closes []float64, period int, mult float64) (line []float64, dir []int) {
+	n := len(closes)
+	line = nanSeries(n)
+	dir = make([]int, n)
+	if len(highs) != n || len(lows) != n {
+		return line, dir
+	}
+
+	atr := WilderSeries(TrueRangeSeries(highs, lows, closes), period)
+
+	var upper, lower float64
+	started := false
+	for i := range closes {
+		if math.IsNaN(atr[i]) {
+			continue
+		}
+		hl2 := (highs[i] + lows[i]) / 2
+		basicUpper := hl2 + mult*atr[i]
+		basicLower := hl2 - mult*atr[i]
+
+		if !started {
+			upper, lower = basicUpper, basicLower
+			dir[i] = 1
+			if closes[i] < hl2 {
+				dir[i] = -1
+			}
+			started = true
+		} else {
+			if basicUpper < upper || closes[i-1] > upper {
+				upper = basicUpper
+			}
+			if basicLower > lower || closes[i-1] < lower {
+				lower = basicLower
+			}
+
+			dir[i] = dir[i-1]
+			if dir[i] < 0 && closes[i] > upper {
+				dir[i] = 1
+			} else if dir[i] > 0 && closes[i] < lower {
+				dir[i] = -1
+			}
+		}
+
+		if dir[i] > 0 {
+			line[i] = lower
+		} else {
+			line[i] = upper
+		}
+	}
+	return line, dir
+}
+
+func Supertrend(highs, lows, closes []float64, period int, mult float64) (value float64, direction int) {
+	line, dir := SupertrendSeries(highs, lows, closes, period, mult)
+	if len(dir) == 0 {
+		return math.NaN(), 0
+	}
+	return last(line), dir[len(dir)-1]
+}
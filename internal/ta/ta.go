@@ -74,33 +74,12 @@ func ATR(highs, lows, closes []float64, period int) float64 {
 }
 
 func EMA(closes []float64, period int) float64 {
-	if len(closes) < period || period <= 0 {
-		return math.NaN()
-	}
-
-	k := 2.0 / float64(period+1)
-	ema := SMA(closes[:period], period)
-
-	for i := period; i < len(closes); i++ {
-		ema = closes[i]*k + ema*(1-k)
-	}
-
-	return ema
+	return last(EMASeries(closes, period))
 }
 
 func MACD(closes []float64, fastPeriod, slowPeriod, signalPeriod int) (macd, signal, histogram float64) {
-	if len(closes) < slowPeriod {
-		return math.NaN(), math.NaN(), math.NaN()
-	}
-
-	fastEMA := EMA(closes, fastPeriod)
-	slowEMA := EMA(closes, slowPeriod)
-	macd = fastEMA - slowEMA
-
-	signal = macd // Simplified for now - TODO: proper signal line calculation
-	histogram = macd - signal
-
-	return macd, signal, histogram
+	m, sig, hist := MACDSeries(closes, fastPeriod, slowPeriod, signalPeriod)
+	return last(m), last(sig), last(hist)
 }
 
 func StochasticRSI(closes []float64, rsiPeriod, stochPeriod int) float64 {
@@ -136,60 +115,6 @@ func StochasticRSI(closes []float64, rsiPeriod, stochPeriod int) float64 {
 }
 
 func ADX(highs, lows, closes []float64, period int) float64 {
-	if len(highs) != len(lows) || len(lows) != len(closes) {
-		return math.NaN()
-	}
-	if len(closes) < period+1 {
-		return math.NaN()
-	}
-
-	plusDM := make([]float64, 0, period)
-	minusDM := make([]float64, 0, period)
-
-	for i := len(closes) - period; i < len(closes); i++ {
-		if i == 0 {
-			continue
-		}
-
-		highDiff := highs[i] - highs[i-1]
-		lowDiff := lows[i-1] - lows[i]
-
-		plusDMVal := 0.0
-		minusDMVal := 0.0
-
-		if highDiff > lowDiff && highDiff > 0 {
-			plusDMVal = highDiff
-		}
-		if lowDiff > highDiff && lowDiff > 0 {
-			minusDMVal = lowDiff
-		}
-
-		plusDM = append(plusDM, plusDMVal)
-		minusDM = append(minusDM, minusDMVal)
-	}
-
-	smoothPlusDM := 0.0
-	smoothMinusDM := 0.0
-	for i := range plusDM {
-		smoothPlusDM += plusDM[i]
-		smoothMinusDM += minusDM[i]
-	}
-	smoothPlusDM /= float64(len(plusDM))
-	smoothMinusDM /= float64(len(minusDM))
-
-	atr := ATR(highs, lows, closes, period)
-	if atr == 0 {
-		return 0
-	}
-
-	plusDI := (smoothPlusDM / atr) * 100
-	minusDI := (smoothMinusDM / atr) * 100
-
-	diSum := plusDI + minusDI
-	if diSum == 0 {
-		return 0
-	}
-	dx := math.Abs(plusDI-minusDI) / diSum * 100
-
-	return dx
+	adx, _, _ := ADXSeries(highs, lows, closes, period)
+	return last(adx)
 }
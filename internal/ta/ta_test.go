@@ -0,0 +1,133 @@
+package ta
+
+import (
+	"math"
+	"testing"
+)
+
+// stockCharts is the closing series of the StockCharts ChartSchool moving
+// average worksheet; smaRef and emaRef are its published 10-day SMA and
+// EMA columns, rounded to cents, from the tenth bar on.
+var (
+	stockCharts = []float64{
+		22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+		22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+		23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+	}
+	smaRef = []float64{
+		22.22, 22.21, 22.23, 22.26, 22.31, 22.42, 22.61, 22.77, 22.91, 23.08, 23.21,
+		23.38, 23.53, 23.65, 23.71, 23.69, 23.61, 23.51, 23.43, 23.28, 23.13,
+	}
+	emaRef = []float64{
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+		23.43, 23.51, 23.54, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92,
+	}
+)
+
+func near(a, b, tol float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return math.Abs(a-b) <= tol
+}
+
+func TestSMA(t *testing.T) {
+	for i, want := range smaRef {
+		end := 10 + i
+		if got := SMA(stockCharts[:end], 10); !near(got, want, 0.01) {
+			t.Errorf("SMA(10) at bar %d = %.4f, want %.2f", end, got, want)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		closes []float64
+		n      int
+		want   float64
+	}{
+		{"exact window", []float64{1, 2, 3, 4}, 4, 2.5},
+		{"last n only", []float64{100, 1, 2, 3}, 3, 2},
+		{"too short", []float64{1, 2}, 3, math.NaN()},
+		{"zero window", []float64{1, 2}, 0, math.NaN()},
+	}
+	for _, tt := range tests {
+		if got := SMA(tt.closes, tt.n); !near(got, tt.want, 1e-12) {
+			t.Errorf("%s: SMA = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// RSI is the simple-average (Cutler) form: mean gain over mean loss of the
+// last period changes. Its first value equals Wilder's, whose later values
+// are smoothed and differ.
+func TestRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		period int
+		want   float64
+	}{
+		// ChartSchool RSI series, first 14 changes: gains 3.34, losses 1.40
+		{"14-day", []float64{
+			44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42,
+			45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28,
+		}, 14, 100 - 100/(1+3.34/1.40)},
+		{"gains 2 losses 1", []float64{1, 2, 3, 2}, 3, 100 - 100/(1+2.0)},
+		{"last period only", []float64{50, 1, 2, 3, 2}, 3, 100 - 100/(1+2.0)},
+		{"no losses", []float64{1, 2, 3, 4}, 3, 100},
+		{"no gains", []float64{4, 3, 2, 1}, 3, 0},
+		{"too short", []float64{1, 2, 3}, 3, math.NaN()},
+	}
+	for _, tt := range tests {
+		if got := RSI(tt.closes, tt.period); !near(got, tt.want, 1e-9) {
+			t.Errorf("%s: RSI = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// ATR is the simple average of the last period true ranges, each taking
+// the previous close into account.
+func TestATR(t *testing.T) {
+	tests := []struct {
+		name               string
+		highs, lows, close []float64
+		period             int
+		want               float64
+	}{
+		// TR: max(1.1, |11.5-10|, |10.4-10|) = 1.5, then max(1, |11-11|, |10-11|) = 1
+		{"gap then range", []float64{10.5, 11.5, 11}, []float64{9.5, 10.4, 10}, []float64{10, 11, 10.5}, 2, 1.25},
+		// Gap down: |9-12| beats the bar's own range
+		{"gap down", []float64{12, 9}, []float64{11, 8.5}, []float64{12, 8.8}, 1, 3.5},
+		{"last period only", []float64{20, 10.5, 11.5, 11}, []float64{0, 9.5, 10.4, 10}, []float64{10, 10, 11, 10.5}, 2, 1.25},
+		{"too short", []float64{1, 2}, []float64{0, 1}, []float64{1, 2}, 2, math.NaN()},
+		{"mismatched", []float64{1, 2, 3}, []float64{0, 1}, []float64{1, 2, 3}, 1, math.NaN()},
+	}
+	for _, tt := range tests {
+		if got := ATR(tt.highs, tt.lows, tt.close, tt.period); !near(got, tt.want, 1e-9) {
+			t.Errorf("%s: ATR = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Bollinger bands use the population standard deviation.
+func TestBollinger(t *testing.T) {
+	tests := []struct {
+		name          string
+		closes        []float64
+		n             int
+		k             float64
+		mid, up, down float64
+	}{
+		// Textbook set: mean 5, population standard deviation 2
+		{"textbook", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 8, 2, 5, 9, 1},
+		{"flat", []float64{3, 3, 3}, 3, 2, 3, 3, 3},
+		{"last n only", []float64{100, 2, 4, 4, 4, 5, 5, 7, 9}, 8, 1, 5, 7, 3},
+		{"too short", []float64{1}, 2, 2, math.NaN(), math.NaN(), math.NaN()},
+	}
+	for _, tt := range tests {
+		mid, up, down := Bollinger(tt.closes, tt.n, tt.k)
+		if !near(mid, tt.mid, 1e-12) || !near(up, tt.up, 1e-12) || !near(down, tt.down, 1e-12) {
+			t.Errorf("%s: Bollinger = %v/%v/%v, want %v/%v/%v", tt.name, mid, up, down, tt.mid, tt.up, tt.down)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ta
+
+import (
+	"math"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// VWAPSeries is the intraday volume-weighted average of the typical price
+// (H+L+C)/3, reset at the start of each IST trading day. ts holds unix
+// seconds per bar.
+func VWAPSeries(ts []int64, highs, lows, closes, vols []float64) []float64 {
+	n := len(closes)
+	out := nanSeries(n)
+	if len(ts) != n || len(highs) != n || len(lows) != n || len(vols) != n {
+		return out
+	}
+
+	var day time.Time
+	pv, vol := 0.0, 0.0
+	for i := range closes {
+		t := time.Unix(ts[i], 0).In(timeutil.IST)
+		d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, timeutil.IST)
+		if !d.Equal(day) {
+			day = d
+			pv, vol = 0, 0
+		}
+		typical := (highs[i] + lows[i] + closes[i]) / 3
+		pv += typical * vols[i]
+		vol += vols[i]
+		if vol > 0 {
+			out[i] = pv / vol
+		}
+	}
+	return out
+}
+
+func VWAP(ts []int64, highs, lows, closes, vols []float64) float64 {
+	return last(VWAPSeries(ts, highs, lows, closes, vols))
+}
+
+// OBVSeries accumulates volume on up closes and subtracts it on down
+// closes, starting from zero at the first bar.
+func OBVSeries(closes, vols []float64) []float64 {
+	out := nanSeries(len(closes))
+	if len(vols) != len(closes) || len(closes) == 0 {
+		return out
+	}
+
+	obv := 0.0
+	out[0] = obv
+	for i := 1; i < len(closes); i++ {
+		switch {
+		case closes[i] > closes[i-1]:
+			obv += vols[i]
+		case closes[i] < closes[i-1]:
+			obv -= vols[i]
+		}
+		out[i] = obv
+	}
+	return out
+}
+
+func OBV(closes, vols []float64) float64 {
+	if len(closes) == 0 {
+		return math.NaN()
+	}
+	return last(OBVSeries(closes, vols))
+}
@@ -0,0 +1,156 @@
+package tax
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var lotHeaders = []string{"symbol", "qty", "buy_date", "buy_price", "buy_value", "sell_date", "sell_price", "sell_value", "holding_days", "class", "gain"}
+
+var totalHeaders = []string{"class", "lots", "buy_value", "sell_value", "gain", "turnover"}
+
+func lotRow(l Lot) []any {
+	return []any{l.Symbol, l.Qty, l.BuyTime.Format(timeLayout), l.BuyPrice, l.BuyValue(),
+		l.SellTime.Format(timeLayout), l.SellPrice, l.SellValue(), l.HoldingDays, l.Class, l.Gain()}
+}
+
+func totalRow(t ClassTotals) []any {
+	return []any{t.Class, t.Lots, t.BuyValue, t.SellValue, t.Gain, t.Turnover}
+}
+
+// WriteCSV writes one row per lot followed by a blank line and the totals
+// per classification.
+func WriteCSV(w io.Writer, lots []Lot) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(lotHeaders); err != nil {
+		return err
+	}
+	for _, l := range lots {
+		if err := cw.Write(csvRecord(lotRow(l))); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write(nil); err != nil {
+		return err
+	}
+	if err := cw.Write(totalHeaders); err != nil {
+		return err
+	}
+	for _, t := range Totals(lots) {
+		if err := cw.Write(csvRecord(totalRow(t))); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvRecord(row []any) []string {
+	out := make([]string, len(row))
+	for i, v := range row {
+		switch v := v.(type) {
+		case float64:
+			out[i] = strconv.FormatFloat(v, 'f', 2, 64)
+		default:
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// WriteXLSX writes a workbook with a Lots sheet and a Summary sheet of
+// totals per classification.
+func WriteXLSX(w io.Writer, lots []Lot) error {
+	lotRows := [][]any{toAny(lotHeaders)}
+	for _, l := range lots {
+		lotRows = append(lotRows, lotRow(l))
+	}
+	totalRows := [][]any{toAny(totalHeaders)}
+	for _, t := range Totals(lots) {
+		totalRows = append(totalRows, totalRow(t))
+	}
+
+	files := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/worksheets/sheet1.xml", sheetXML(lotRows)},
+		{"xl/worksheets/sheet2.xml", sheetXML(totalRows)},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func toAny(ss []string) []any {
+	out := make([]any, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
+
+// sheetXML renders rows with inline strings, so the workbook needs no
+// shared string table.
+func sheetXML(rows [][]any) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for _, row := range rows {
+		sb.WriteString("<row>")
+		for _, v := range row {
+			switch v := v.(type) {
+			case int:
+				fmt.Fprintf(&sb, `<c><v>%d</v></c>`, v)
+			case float64:
+				fmt.Fprintf(&sb, `<c><v>%s</v></c>`, strconv.FormatFloat(v, 'f', 2, 64))
+			default:
+				sb.WriteString(`<c t="inlineStr"><is><t>`)
+				_ = xml.EscapeText(&sb, []byte(fmt.Sprint(v)))
+				sb.WriteString(`</t></is></c>`)
+			}
+		}
+		sb.WriteString("</row>")
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet2.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Lots" sheetId="1" r:id="rId1"/><sheet name="Summary" sheetId="2" r:id="rId2"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,224 @@
+// Package tax turns the trade log into lot-level capital gains for Indian
+// income tax filing. Same-day buys and sells of a symbol are netted as
+// intraday first; the remaining sells are matched to buys first-in
+// first-out and each matched lot is classified by holding period.
+package tax
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/tradelog"
+)
+
+const (
+	// Speculative is intraday equity: bought and sold on the same day,
+	// taxed as speculative business income.
+	Speculative = "SPECULATIVE"
+	// STCG is delivery equity held for at most 12 months (section 111A).
+	STCG = "STCG"
+	// LTCG is delivery equity held for more than 12 months (section 112A).
+	LTCG = "LTCG"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Lot is one buy fill matched against (part of) one sell fill.
+type Lot struct {
+	Symbol      string
+	Qty         int
+	BuyTime     time.Time
+	BuyPrice    float64
+	SellTime    time.Time
+	SellPrice   float64
+	HoldingDays int
+	Class       string
+}
+
+func (l Lot) BuyValue() float64  { return float64(l.Qty) * l.BuyPrice }
+func (l Lot) SellValue() float64 { return float64(l.Qty) * l.SellPrice }
+func (l Lot) Gain() float64      { return l.SellValue() - l.BuyValue() }
+
+// FY is the financial year label of t, e.g. "2025-26" for 2025-04-01
+// through 2026-03-31.
+func FY(t time.Time) string {
+	t = t.In(timeutil.IST)
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// FYRange returns the first and last day of a financial year label.
+func FYRange(label string) (time.Time, time.Time, error) {
+	var start, end int
+	if _, err := fmt.Sscanf(label, "%d-%d", &start, &end); err != nil || (start+1)%100 != end {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q (want e.g. 2025-26)", label)
+	}
+	from := time.Date(start, time.April, 1, 0, 0, 0, 0, timeutil.IST)
+	return from, from.AddDate(1, 0, -1), nil
+}
+
+// Match pairs SELL entries with BUYs of the same symbol. Each day, the
+// quantity bought and sold on that day is netted first: the smaller of the
+// two sides is speculative (intraday), matched within the day. Only the
+// rest is delivery: remaining buys join the holdings and remaining sells
+// are matched to the oldest holdings first. It also returns the sell
+// quantity that had no open buy in the log (positions opened before the
+// log starts).
+func Match(entries []tradelog.Entry) ([]Lot, int) {
+	type fill struct {
+		qty   int
+		price float64
+		at    time.Time
+	}
+	type day struct {
+		symbol      string
+		buys, sells []*fill
+	}
+
+	sorted := make([]tradelog.Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+
+	var lots []Lot
+	unmatched := 0
+	holdings := make(map[string][]*fill)
+
+	// match consumes sells against buys in order and returns what is left
+	// of both.
+	match := func(symbol string, buys, sells []*fill) ([]*fill, []*fill) {
+		for len(buys) > 0 && len(sells) > 0 {
+			b, s := buys[0], sells[0]
+			qty := b.qty
+			if qty > s.qty {
+				qty = s.qty
+			}
+			lots = append(lots, newLot(symbol, qty, b.at, b.price, s.at, s.price))
+			b.qty -= qty
+			s.qty -= qty
+			if b.qty == 0 {
+				buys = buys[1:]
+			}
+			if s.qty == 0 {
+				sells = sells[1:]
+			}
+		}
+		return buys, sells
+	}
+
+	var keys []string
+	days := make(map[string]*day)
+	settle := func() {
+		for _, key := range keys {
+			d := days[key]
+			buys, sells := match(d.symbol, d.buys, d.sells)
+			if len(sells) > 0 {
+				holdings[key], sells = match(d.symbol, holdings[key], sells)
+				for _, s := range sells {
+					unmatched += s.qty
+				}
+			}
+			holdings[key] = append(holdings[key], buys...)
+		}
+		keys, days = nil, make(map[string]*day)
+	}
+
+	current := ""
+	for _, e := range sorted {
+		if !e.Executed() {
+			continue
+		}
+		at, err := time.ParseInLocation(timeLayout, e.Time, timeutil.IST)
+		if err != nil {
+			continue
+		}
+		if date := timeutil.DateKey(at); date != current {
+			settle()
+			current = date
+		}
+		key := e.PositionKey()
+		d := days[key]
+		if d == nil {
+			d = &day{symbol: e.Symbol}
+			days[key] = d
+			keys = append(keys, key)
+		}
+		f := &fill{qty: e.Qty, price: e.Price.Float(), at: at}
+		switch e.Side {
+		case "BUY":
+			d.buys = append(d.buys, f)
+		case "SELL":
+			d.sells = append(d.sells, f)
+		}
+	}
+	settle()
+	return lots, unmatched
+}
+
+func newLot(symbol string, qty int, buyAt time.Time, buyPrice float64, sellAt time.Time, sellPrice float64) Lot {
+	buyDay := time.Date(buyAt.Year(), buyAt.Month(), buyAt.Day(), 0, 0, 0, 0, timeutil.IST)
+	sellDay := time.Date(sellAt.Year(), sellAt.Month(), sellAt.Day(), 0, 0, 0, 0, timeutil.IST)
+
+	class := STCG
+	switch {
+	case sellDay.Equal(buyDay):
+		class = Speculative
+	case sellDay.After(buyDay.AddDate(1, 0, 0)):
+		class = LTCG
+	}
+
+	return Lot{
+		Symbol:      symbol,
+		Qty:         qty,
+		BuyTime:     buyAt,
+		BuyPrice:    buyPrice,
+		SellTime:    sellAt,
+		SellPrice:   sellPrice,
+		HoldingDays: int(sellDay.Sub(buyDay).Hours() / 24),
+		Class:       class,
+	}
+}
+
+// InFY keeps the lots whose sell falls in the financial year; gains are
+// taxed in the year of transfer.
+func InFY(lots []Lot, label string) []Lot {
+	var out []Lot
+	for _, l := range lots {
+		if FY(l.SellTime) == label {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// ClassTotals sums one classification.
+type ClassTotals struct {
+	Class     string
+	Lots      int
+	BuyValue  float64
+	SellValue float64
+	Gain      float64
+	Turnover  float64 // Sum of absolute gains, the business turnover for speculative income
+}
+
+// Totals returns totals per classification in filing order.
+func Totals(lots []Lot) []ClassTotals {
+	out := []ClassTotals{{Class: Speculative}, {Class: STCG}, {Class: LTCG}}
+	for _, l := range lots {
+		for i := range out {
+			if out[i].Class == l.Class {
+				out[i].Lots++
+				out[i].BuyValue += l.BuyValue()
+				out[i].SellValue += l.SellValue()
+				out[i].Gain += l.Gain()
+				out[i].Turnover += math.Abs(l.Gain())
+			}
+		}
+	}
+	return out
+}
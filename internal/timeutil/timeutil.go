@@ -0,0 +1,80 @@
+// Package timeutil is the market's clock face: the IST location, the NSE
+// session boundaries and the IST date keys that logs, reports and the
+// candle store are filed under. Times are converted to IST here rather
+// than by each caller, so a host in another timezone files the same bar
+// under the same day.
+package timeutil
+
+import (
+	"time"
+)
+
+// IST is India Standard Time, UTC+5:30 with no daylight saving.
+var IST = time.FixedZone("IST", 19800)
+
+// DateLayout is the layout of IST date keys, e.g. "2025-11-04".
+const DateLayout = "2006-01-02"
+
+// NSE cash session, IST wall clock.
+const (
+	OpenHour, OpenMinute   = 9, 15
+	CloseHour, CloseMinute = 15, 30
+)
+
+// Now is the wall clock in IST.
+func Now() time.Time {
+	return time.Now().In(IST)
+}
+
+// FromUnix converts a candle or tick timestamp in Unix seconds to IST.
+func FromUnix(ts int64) time.Time {
+	return time.Unix(ts, 0).In(IST)
+}
+
+// Midnight is the start of t's IST day.
+func Midnight(t time.Time) time.Time {
+	return At(t, 0, 0)
+}
+
+// At is hour:minute IST on t's IST day.
+func At(t time.Time, hour, minute int) time.Time {
+	t = t.In(IST)
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, IST)
+}
+
+// SessionOpen is the NSE open on t's IST day.
+func SessionOpen(t time.Time) time.Time {
+	return At(t, OpenHour, OpenMinute)
+}
+
+// SessionClose is the NSE close on t's IST day.
+func SessionClose(t time.Time) time.Time {
+	return At(t, CloseHour, CloseMinute)
+}
+
+// BarStart is the start of the bar holding ts on a grid of interval
+// seconds anchored at the open of its IST day. Pre-open times fall into
+// bars aligned backwards from the open.
+func BarStart(ts, interval int64) int64 {
+	anchor := SessionOpen(FromUnix(ts)).Unix()
+	offset := ts - anchor
+	if offset < 0 {
+		return anchor - ((-offset+interval-1)/interval)*interval
+	}
+	return anchor + (offset/interval)*interval
+}
+
+// DateKey is t's IST date, e.g. "2025-11-04".
+func DateKey(t time.Time) string {
+	return t.In(IST).Format(DateLayout)
+}
+
+// SameDay reports whether two Unix timestamps fall on the same IST day.
+func SameDay(a, b int64) bool {
+	return DateKey(time.Unix(a, 0)) == DateKey(time.Unix(b, 0))
+}
+
+// ParseDate reads an IST date key as midnight IST.
+func ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, s, IST)
+}
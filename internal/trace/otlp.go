@@ -0,0 +1,225 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpExporter posts spans to an OTLP/HTTP collector using the JSON
+// encoding, which needs no protobuf or gRPC dependencies.
+type otlpExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+var _ sdktrace.SpanExporter = (*otlpExporter)(nil)
+
+func newOTLPExporter(endpoint string, headers map[string]string) *otlpExporter {
+	return &otlpExporter{
+		url:     strings.TrimRight(endpoint, "/") + "/v1/traces",
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(encodeSpans(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("otlp export: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// OTLP/JSON payload; field names follow the protobuf JSON mapping.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         int            `json:"kind"`
+	Start        string         `json:"startTimeUnixNano"`
+	End          string         `json:"endTimeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	Events       []otlpEvent    `json:"events,omitempty"`
+	Status       otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	Time       string         `json:"timeUnixNano"`
+	Name       string         `json:"name"`
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// encodeSpans groups spans by instrumentation scope; the provider has a
+// single resource.
+func encodeSpans(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	var scopes []otlpScopeSpans
+	index := make(map[instrumentation.Scope]int)
+	for _, s := range spans {
+		i, ok := index[s.InstrumentationScope()]
+		if !ok {
+			i = len(scopes)
+			index[s.InstrumentationScope()] = i
+			scopes = append(scopes, otlpScopeSpans{Scope: otlpScope{
+				Name:    s.InstrumentationScope().Name,
+				Version: s.InstrumentationScope().Version,
+			}})
+		}
+		scopes[i].Spans = append(scopes[i].Spans, encodeSpan(s))
+	}
+
+	var res otlpResource
+	if r := spans[0].Resource(); r != nil {
+		res.Attributes = encodeAttributes(r.Attributes())
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{Resource: res, ScopeSpans: scopes}}}
+}
+
+func encodeSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	span := otlpSpan{
+		TraceID:    s.SpanContext().TraceID().String(),
+		SpanID:     s.SpanContext().SpanID().String(),
+		Name:       s.Name(),
+		Kind:       int(s.SpanKind()), // SDK and OTLP kinds share values
+		Start:      nanos(s.StartTime()),
+		End:        nanos(s.EndTime()),
+		Attributes: encodeAttributes(s.Attributes()),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, ev := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			Time:       nanos(ev.Time),
+			Name:       ev.Name,
+			Attributes: encodeAttributes(ev.Attributes),
+		})
+	}
+
+	// OTLP numbers OK and ERROR the other way round from the SDK
+	switch s.Status().Code {
+	case codes.Ok:
+		span.Status.Code = 1
+	case codes.Error:
+		span.Status = otlpStatus{Code: 2, Message: s.Status().Description}
+	}
+	return span
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func encodeAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, otlpKeyValue{Key: string(kv.Key), Value: encodeValue(kv.Value)})
+	}
+	return out
+}
+
+func encodeValue(v attribute.Value) map[string]any {
+	switch v.Type() {
+	case attribute.BOOL:
+		return map[string]any{"boolValue": v.AsBool()}
+	case attribute.INT64:
+		return map[string]any{"intValue": strconv.FormatInt(v.AsInt64(), 10)}
+	case attribute.FLOAT64:
+		return map[string]any{"doubleValue": v.AsFloat64()}
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE:
+		var values []map[string]any
+		switch v.Type() {
+		case attribute.BOOLSLICE:
+			for _, x := range v.AsBoolSlice() {
+				values = append(values, encodeValue(attribute.BoolValue(x)))
+			}
+		case attribute.INT64SLICE:
+			for _, x := range v.AsInt64Slice() {
+				values = append(values, encodeValue(attribute.Int64Value(x)))
+			}
+		case attribute.FLOAT64SLICE:
+			for _, x := range v.AsFloat64Slice() {
+				values = append(values, encodeValue(attribute.Float64Value(x)))
+			}
+		default:
+			for _, x := range v.AsStringSlice() {
+				values = append(values, encodeValue(attribute.StringValue(x)))
+			}
+		}
+		return map[string]any{"arrayValue": map[string]any{"values": values}}
+	}
+	return map[string]any{"stringValue": v.Emit()}
+}
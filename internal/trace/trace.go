@@ -2,9 +2,11 @@ package trace
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -18,34 +20,73 @@ var (
 	enabled        bool
 )
 
-func Init() error {
-	enabled = getEnv("LOG_TRACING_ENABLED", "true") == "true"
-	if !enabled {
+type Options struct {
+	Exporter     string            // stdout | otlp | none
+	OTLPEndpoint string            // Collector base URL, e.g. http://localhost:4318
+	OTLPProtocol string            // http (OTLP/HTTP JSON); grpc is not built in
+	OTLPHeaders  map[string]string // e.g. auth headers for a hosted collector
+	SampleRatio  float64           // Share of new traces kept; children follow their parent
+	Version      string
+	Attributes   map[string]string // Extra resource attributes (e.g. bot.mode)
+}
+
+// Init installs the tracer provider. LOG_TRACING_ENABLED=false turns
+// tracing off regardless of opts.
+func Init(opts Options) error {
+	enabled = false
+	if getEnv("LOG_TRACING_ENABLED", "true") != "true" || opts.Exporter == "none" {
 		return nil
 	}
 
-	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
-	if err != nil {
-		return err
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return err
+		}
+		exporter = exp
+	case "otlp":
+		if opts.OTLPProtocol != "" && opts.OTLPProtocol != "http" {
+			return fmt.Errorf("unsupported OTLP protocol %q: only http is built in; point it at the collector's HTTP receiver", opts.OTLPProtocol)
+		}
+		if opts.OTLPEndpoint == "" {
+			return fmt.Errorf("OTLP exporter needs an endpoint")
+		}
+		exporter = newOTLPExporter(opts.OTLPEndpoint, opts.OTLPHeaders)
+	default:
+		return fmt.Errorf("unknown trace exporter %q: must be stdout, otlp or none", opts.Exporter)
 	}
 
-	res, err := resource.New(
-		context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceName("llm-trading-bot"),
-			semconv.ServiceVersion("1.0.0"),
-		),
-	)
+	version := opts.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName("llm-trading-bot"),
+		semconv.ServiceVersion(version),
+	}
+	for k, v := range opts.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res, err := resource.New(context.Background(), resource.WithAttributes(attrs...))
 	if err != nil {
 		return err
 	}
 
+	ratio := opts.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+
 	tracerProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
 	)
 	otel.SetTracerProvider(tracerProvider)
 	tracer = otel.Tracer("llm-trading-bot")
+	enabled = true
 	return nil
 }
 
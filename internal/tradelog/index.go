@@ -0,0 +1,154 @@
+package tradelog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// dayIndex maps stream -> IST date -> symbol -> entry count.
+type dayIndex map[string]map[string]map[string]int
+
+// idx is reloaded whenever index.json changes on disk, so readers in
+// other processes (dashboard, journal) see the bot's writes. Guarded by mu.
+var (
+	idx    dayIndex
+	idxMod time.Time
+)
+
+func indexPath() string {
+	return filepath.Join(logDir(), "index.json")
+}
+
+func loadIndex() dayIndex {
+	info, err := os.Stat(indexPath())
+	if err != nil {
+		if idx == nil {
+			idx = dayIndex{}
+		}
+		return idx
+	}
+	if idx != nil && info.ModTime().Equal(idxMod) {
+		return idx
+	}
+
+	ix := dayIndex{}
+	if b, err := os.ReadFile(indexPath()); err == nil {
+		_ = json.Unmarshal(b, &ix)
+	}
+	idx, idxMod = ix, info.ModTime()
+	return idx
+}
+
+// recordIndex counts one entry for symbol on the day of t. A day missing
+// from the index (first write of the day, or a deleted index) is seeded
+// from its files so the counts stay complete.
+func recordIndex(stream string, t time.Time, symbol string) error {
+	ix := loadIndex()
+	day := timeutil.DateKey(t)
+
+	if ix[stream] == nil {
+		ix[stream] = make(map[string]map[string]int)
+	}
+	if counts, ok := ix[stream][day]; ok {
+		counts[symbol]++
+	} else {
+		// Includes the line just appended
+		counts, err := scanSymbols(stream, t)
+		if err != nil {
+			return err
+		}
+		ix[stream][day] = counts
+	}
+
+	return saveIndex(ix)
+}
+
+func scanSymbols(stream string, t time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := readDay(stream, t, func(b []byte) {
+		var e struct{ Symbol string }
+		if json.Unmarshal(b, &e) == nil {
+			counts[e.Symbol]++
+		}
+	})
+	return counts, err
+}
+
+func saveIndex(ix dayIndex) error {
+	b, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	tmp := indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, indexPath()); err != nil {
+		return err
+	}
+	if info, err := os.Stat(indexPath()); err == nil {
+		idx, idxMod = ix, info.ModTime()
+	}
+	return nil
+}
+
+// mayContain reports whether the day can hold entries for any of symbols.
+// Days the index has not seen are assumed to.
+func mayContain(stream string, t time.Time, symbols []string) bool {
+	if len(symbols) == 0 {
+		return true
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	counts, ok := loadIndex()[stream][timeutil.DateKey(t)]
+	if !ok {
+		return true
+	}
+	for _, s := range symbols {
+		if counts[s] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Reindex rebuilds index.json from every day file under the log
+// directory.
+func Reindex() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ix := dayIndex{}
+	for _, stream := range []string{streamOrders, streamDecisions} {
+		dir := filepath.Dir(dayBase(stream, time.Now()))
+		files, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		ix[stream] = make(map[string]map[string]int)
+		for _, f := range files {
+			if f.IsDir() || len(f.Name()) < 10 {
+				continue
+			}
+			t, err := timeutil.ParseDate(f.Name()[:10])
+			if err != nil {
+				continue
+			}
+			day := timeutil.DateKey(t)
+			if _, done := ix[stream][day]; done {
+				continue
+			}
+			if ix[stream][day], err = scanSymbols(stream, t); err != nil {
+				return err
+			}
+		}
+	}
+
+	return saveIndex(ix)
+}
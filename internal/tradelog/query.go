@@ -0,0 +1,74 @@
+package tradelog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Query selects entries between two IST dates (inclusive). Empty filters
+// match everything.
+type Query struct {
+	From, To time.Time
+	Symbols  []string
+	Account  string // Entries of one account; empty = every account
+	Side     string // Orders only: BUY | SELL
+	Action   string // Decisions only: BUY | SELL | HOLD
+}
+
+func (q Query) wantSymbol(s string) bool {
+	if len(q.Symbols) == 0 {
+		return true
+	}
+	for _, x := range q.Symbols {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (q Query) wantAccount(a string) bool {
+	return q.Account == "" || q.Account == a
+}
+
+// Orders returns the matching order entries, oldest first.
+func Orders(q Query) ([]Entry, error) {
+	var out []Entry
+	err := scan(streamOrders, q, func(b []byte) {
+		var e Entry
+		if json.Unmarshal(b, &e) != nil || !q.wantSymbol(e.Symbol) || !q.wantAccount(e.Account) {
+			return
+		}
+		if q.Side == "" || e.Side == q.Side {
+			out = append(out, e)
+		}
+	})
+	return out, err
+}
+
+// Decisions returns the matching decision entries, oldest first.
+func Decisions(q Query) ([]DecisionEntry, error) {
+	var out []DecisionEntry
+	err := scan(streamDecisions, q, func(b []byte) {
+		var e DecisionEntry
+		if json.Unmarshal(b, &e) != nil || !q.wantSymbol(e.Symbol) || !q.wantAccount(e.Account) {
+			return
+		}
+		if q.Action == "" || e.Action == q.Action {
+			out = append(out, e)
+		}
+	})
+	return out, err
+}
+
+func scan(stream string, q Query, fn func([]byte)) error {
+	for d := q.From; !d.After(q.To); d = d.AddDate(0, 0, 1) {
+		if !mayContain(stream, d, q.Symbols) {
+			continue
+		}
+		if err := readDay(stream, d, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,53 +1,136 @@
+// Package tradelog is the append-only record of orders and decisions. Each
+// IST day is one JSON Lines file per stream (logs/<date>.jsonl and
+// logs/decisions/<date>.jsonl), compressed once older than the retention
+// window; index.json lists the symbols seen on each day so queries skip
+// files that cannot match.
 package tradelog
 
 import (
+	"bufio"
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"llm-trading-bot/internal/interfaces"
+	"llm-trading-bot/internal/money"
+	"llm-trading-bot/internal/timeutil"
 )
 
 var mu sync.Mutex
 
+// Entry records what an order executed: Qty is the filled quantity and
+// Price its average fill price. An order that fills over time gets one
+// entry when placed and one per later fill; the entry that ends it records
+// any quantity left unfilled. Entries written before fills were tracked
+// have no Status and record the requested quantity.
 type Entry struct {
 	Time, Symbol, Side, OrderID, Reason string
 	Qty                                 int
-	Price                               float64
+	Price                               money.Amount
+	Status                              string `json:",omitempty"` // Broker order status when written
+	Unfilled                            int    `json:",omitempty"` // Cancelled, rejected or expired rest of the order
 	Confidence                          float64
-	Extra                               map[string]any `json:"extra,omitempty"`
+	Account                             string             `json:",omitempty"` // Set when several accounts trade in one process
+	Tag                                 string             `json:",omitempty"`
+	OrderType                           string             `json:",omitempty"`
+	LimitPrice                          money.Amount       `json:",omitempty"`
+	SpreadCost                          money.Amount       `json:",omitempty"` // Fill price vs quote mid, times qty
+	Indicators                          map[string]float64 `json:",omitempty"`
+	Signals                             map[string]any     `json:",omitempty"`
+	Extra                               map[string]any     `json:"extra,omitempty"`
+}
+
+// Executed reports whether the entry traded any quantity. Placements that
+// have not filled yet and cancelled remainders did not.
+func (e Entry) Executed() bool {
+	return e.Qty > 0
+}
+
+// Executions keeps the entries that traded quantity.
+func Executions(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.Executed() {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// PositionKey identifies the position an order belongs to: its symbol
+// within its account.
+func (e Entry) PositionKey() string {
+	if e.Account == "" {
+		return e.Symbol
+	}
+	return e.Account + "/" + e.Symbol
 }
+
 type DecisionEntry struct {
 	Time, Symbol, Action, Reason string
 	Confidence                   float64
 	Price                        float64
+	Account                      string `json:",omitempty"`
 	Indicators                   map[string]float64
 	Extra                        map[string]any
 }
 
+const (
+	streamOrders    = "orders"
+	streamDecisions = "decisions"
+)
+
 func logDir() string {
 	if v := os.Getenv("TRADER_LOG_DIR"); v != "" {
 		return v
 	}
 	return "logs"
 }
-func dailyFilepath(t time.Time) string {
-	d := t.In(time.FixedZone("IST", 19800)).Format("2006-01-02")
-	return filepath.Join(logDir(), d+".txt")
+
+// dayBase is the path of a stream's file for the IST date of t, without
+// extension.
+func dayBase(stream string, t time.Time) string {
+	d := timeutil.DateKey(t)
+	if stream == streamDecisions {
+		return filepath.Join(logDir(), "decisions", d)
+	}
+	return filepath.Join(logDir(), d)
+}
+
+// Writer appends to the trade log, stamping entries with its clock's time;
+// a backtest passes its simulated clock so entries land on the replayed day.
+type Writer struct {
+	clock interfaces.Clock
 }
-func decisionsFilepath(t time.Time) string {
-	d := t.In(time.FixedZone("IST", 19800)).Format("2006-01-02")
-	return filepath.Join(logDir(), "decisions", d+".txt")
+
+func NewWriter(c interfaces.Clock) *Writer {
+	return &Writer{clock: c}
+}
+
+func (w *Writer) Append(e Entry) error {
+	now := w.clock.Now().In(timeutil.IST)
+	e.Time = now.Format("2006-01-02 15:04:05")
+	return appendLine(streamOrders, now, e.Symbol, e)
+}
+
+func (w *Writer) AppendDecision(e DecisionEntry) error {
+	now := w.clock.Now().In(timeutil.IST)
+	e.Time = now.Format("2006-01-02 15:04:05")
+	return appendLine(streamDecisions, now, e.Symbol, e)
 }
-func Append(e Entry) error {
+
+func appendLine(stream string, now time.Time, symbol string, v any) error {
 	mu.Lock()
 	defer mu.Unlock()
-	now := time.Now().In(time.FixedZone("IST", 19800))
-	e.Time = now.Format("2006-01-02 15:04:05")
-	p := dailyFilepath(now)
+
+	p := dayBase(stream, now) + ".jsonl"
 	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
 		return err
 	}
@@ -56,75 +139,152 @@ func Append(e Entry) error {
 		return err
 	}
 	defer f.Close()
-	b, _ := json.Marshal(e)
-	_, err = fmt.Fprintln(f, string(b))
-	return err
-}
-func AppendDecision(e DecisionEntry) error {
-	mu.Lock()
-	defer mu.Unlock()
-	now := time.Now().In(time.FixedZone("IST", 19800))
-	e.Time = now.Format("2006-01-02 15:04:05")
-	p := decisionsFilepath(now)
-	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+	b, _ := json.Marshal(v)
+	if _, err := fmt.Fprintln(f, string(b)); err != nil {
 		return err
 	}
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
+	return recordIndex(stream, now, symbol)
+}
+
+// ReadDay returns the order entries logged on the IST date of t.
+func ReadDay(t time.Time) ([]Entry, error) {
+	var out []Entry
+	err := readDay(streamOrders, t, func(b []byte) {
+		var e Entry
+		if json.Unmarshal(b, &e) == nil {
+			out = append(out, e)
+		}
+	})
+	return out, err
+}
+
+// ReadDecisions returns the decision entries logged on the IST date of t.
+func ReadDecisions(t time.Time) ([]DecisionEntry, error) {
+	var out []DecisionEntry
+	err := readDay(streamDecisions, t, func(b []byte) {
+		var e DecisionEntry
+		if json.Unmarshal(b, &e) == nil {
+			out = append(out, e)
+		}
+	})
+	return out, err
+}
+
+// readDay calls fn for each line logged on the day, oldest first. Days
+// written before the switch to .jsonl are still read from their .txt
+// files; either may have been gzipped.
+func readDay(stream string, t time.Time, fn func([]byte)) error {
+	base := dayBase(stream, t)
+	for _, ext := range []string{".txt", ".jsonl"} {
+		if err := readLines(base+ext, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLines calls fn for each line of p, or of p.gz when p is missing. A
+// missing file has no lines.
+func readLines(p string, fn func([]byte)) error {
+	var r io.Reader
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(p + ".gz")
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	} else if err != nil {
 		return err
+	} else {
+		defer f.Close()
+		r = f
 	}
-	defer f.Close()
-	b, _ := json.Marshal(e)
-	_, err = fmt.Fprintln(f, string(b))
-	return err
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		fn(sc.Bytes())
+	}
+	return sc.Err()
 }
+
+// CompressOlder gzips the order and decision day files last written more
+// than retentionDays ago. Only files named after an IST date are touched;
+// other logs under logs/ (equity curve, audits) are left alone.
 func CompressOlder(retentionDays int) error {
 	if retentionDays <= 0 {
 		return nil
 	}
-	root := logDir()
-	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	for _, dir := range []string{logDir(), filepath.Join(logDir(), "decisions")} {
+		entries, err := os.ReadDir(dir)
 		if err != nil {
-			return nil
-		}
-		if d.IsDir() {
-			return nil
+			continue
 		}
-		if filepath.Ext(p) != ".txt" {
-			return nil
-		}
-		info, er := os.Stat(p)
-		if er != nil {
-			return nil
-		}
-		cutoff := time.Now().AddDate(0, 0, -retentionDays)
-		if info.ModTime().Before(cutoff) {
-			gz := p + ".gz"
-			if _, e2 := os.Stat(gz); e2 == nil {
-				_ = os.Remove(p)
-				return nil
-			}
-
-			in, e3 := os.Open(p)
-			if e3 != nil {
-				return nil
-			}
-			defer in.Close()
-
-			out, e4 := os.OpenFile(gz, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
-			if e4 != nil {
-				return nil
+		for _, d := range entries {
+			if d.IsDir() || !isDayFile(d.Name()) {
+				continue
 			}
-			gw := gzip.NewWriter(out)
-			if _, e5 := io.Copy(gw, in); e5 == nil {
-				_ = gw.Close()
-				_ = out.Close()
-				_ = os.Remove(p)
-			} else {
-				_ = gw.Close()
-				_ = out.Close()
+			info, err := d.Info()
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
 			}
+			_ = compressFile(filepath.Join(dir, d.Name()))
 		}
-		return nil
-	})
+	}
+	return nil
+}
+
+// isDayFile reports whether name is a stream's day file, <date>.jsonl or
+// the older <date>.txt.
+func isDayFile(name string) bool {
+	ext := filepath.Ext(name)
+	if ext != ".jsonl" && ext != ".txt" {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", strings.TrimSuffix(name, ext))
+	return err == nil
+}
+
+// compressFile writes p.gz and removes p. If p.gz already exists, p is a
+// leftover of an interrupted run and is just removed.
+func compressFile(p string) error {
+	gz := p + ".gz"
+	if _, err := os.Stat(gz); err == nil {
+		return os.Remove(p)
+	}
+
+	in, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(gz, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(out)
+	_, err = io.Copy(gw, in)
+	if cerr := gw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(gz)
+		return err
+	}
+	return os.Remove(p)
 }
@@ -0,0 +1,34 @@
+package types
+
+import "strings"
+
+// Exchanges a symbol may be qualified with.
+const (
+	ExchangeNSE = "NSE"
+	ExchangeBSE = "BSE"
+)
+
+// NormalizeSymbol returns the canonical form of a symbol: upper-case,
+// trimmed, with Yahoo-style suffixes (.NS/.BO) turned into an exchange
+// prefix. Unqualified symbols stay unqualified so they follow the default
+// exchange.
+func NormalizeSymbol(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case strings.HasSuffix(s, ".NS"):
+		return ExchangeNSE + ":" + strings.TrimSuffix(s, ".NS")
+	case strings.HasSuffix(s, ".BO"):
+		return ExchangeBSE + ":" + strings.TrimSuffix(s, ".BO")
+	}
+	return s
+}
+
+// SplitSymbol splits an exchange-qualified symbol ("BSE:500325") into its
+// exchange and trading symbol, using defaultExchange when unqualified.
+func SplitSymbol(s, defaultExchange string) (exchange, symbol string) {
+	s = NormalizeSymbol(s)
+	if i := strings.IndexByte(s, ':'); i > 0 {
+		return s[:i], s[i+1:]
+	}
+	return strings.ToUpper(defaultExchange), s
+}
@@ -5,15 +5,28 @@ type Candle struct {
 	Open, High, Low, Close, Vol float64
 }
 type Indicators struct {
-	SMA map[int]float64
-	RSI float64
-	BB  struct{ Middle, Upper, Lower float64 }
-	ATR float64
+	SMA        map[int]float64
+	RSI        float64
+	BB         struct{ Middle, Upper, Lower float64 }
+	ATR        float64
+	VWAP       float64 // Intraday, resets each IST session
+	Supertrend struct {
+		Value float64
+		Dir   int // +1 uptrend, -1 downtrend, 0 not enough data
+	}
+	OBV      float64
+	Ichimoku struct {
+		Tenkan, Kijun    float64
+		SenkouA, SenkouB float64 // Cloud under the current bar (already displaced)
+	}
+	Keltner struct{ Middle, Upper, Lower float64 }
+	RS      map[int]float64 `json:",omitempty"` // Return vs benchmark index in % points, by window
 }
 type Decision struct {
-	Action, Reason string  `json:"action"`
-	Confidence     float64 `json:"confidence"`
-	Qty            int     `json:"qty,omitempty"`
+	Action     string  `json:"action"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+	Qty        int     `json:"qty,omitempty"`
 }
 
 type StepResult struct {
@@ -24,11 +37,120 @@ type StepResult struct {
 	Orders   []OrderResp `json:"orders"`
 	Reason   string      `json:"reason"`
 }
+
+// Position is an open long position as tracked by the engine.
+type Position struct {
+	Account string  `json:"account,omitempty"`
+	Symbol  string  `json:"symbol"`
+	Qty     int     `json:"qty"`
+	Avg     float64 `json:"avg"`
+	Stop    float64 `json:"stop"`
+	Halted  string  `json:"halted,omitempty"` // Why the position cannot be managed: circuit limit or trading halt
+}
+
 type OrderReq struct {
 	Symbol, Side string
 	Qty          int
 	Tag          string
+	Type         string  // MARKET (default) | LIMIT | SL | SL-M
+	Price        float64 // Limit price; ignored for MARKET and SL-M
+	TriggerPrice float64 // Stop trigger for SL and SL-M
+}
+
+// StopReq describes a server-side SELL stop protecting a long position.
+type StopReq struct {
+	Symbol       string
+	Qty          int
+	TriggerPrice float64
+	LimitPrice   float64 // Price of the order placed when the trigger fires
+	LastPrice    float64 // Current price; brokers validate the trigger against it
+}
+
+// OrderModify carries the fields to change on an open order; zero values
+// leave the field as is.
+type OrderModify struct {
+	Qty          int
+	Type         string
+	Price        float64
+	TriggerPrice float64
+}
+
+// Normalized order states reported by Broker.GetOrderStatus.
+const (
+	OrderOpen           = "OPEN"
+	OrderTriggerPending = "TRIGGER_PENDING"
+	OrderComplete       = "COMPLETE"
+	OrderCancelled      = "CANCELLED"
+	OrderRejected       = "REJECTED"
+)
+
+type OrderStatus struct {
+	OrderID    string  `json:"order_id"`
+	Status     string  `json:"status"`
+	FilledQty  int     `json:"filled_qty"`
+	PendingQty int     `json:"pending_qty"`
+	AvgPrice   float64 `json:"avg_price"`
+	Message    string  `json:"message,omitempty"`
+}
+
+// Terminal reports whether the order can no longer fill or be modified.
+func (s OrderStatus) Terminal() bool {
+	return s.Status == OrderComplete || s.Status == OrderCancelled || s.Status == OrderRejected
+}
+
+type Tick struct {
+	Symbol   string
+	Price    float64
+	Ts       int64
+	BarClose bool // Set when the tick reports a completed bar rather than a trade
+}
+type DepthLevel struct {
+	Price  float64 `json:"price"`
+	Qty    int     `json:"qty"`
+	Orders int     `json:"orders"`
+}
+
+// Quote is the top of book plus market depth, best levels first.
+type Quote struct {
+	Symbol string       `json:"symbol"`
+	LTP    float64      `json:"ltp"`
+	Bid    float64      `json:"bid"`
+	Ask    float64      `json:"ask"`
+	Bids   []DepthLevel `json:"bids,omitempty"`
+	Asks   []DepthLevel `json:"asks,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+// Mid returns the bid/ask midpoint, or LTP when either side is empty.
+func (q Quote) Mid() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return q.LTP
+	}
+	return (q.Bid + q.Ask) / 2
+}
+
+// SpreadBps returns the bid/ask spread in basis points of the mid, or 0
+// when either side is empty.
+func (q Quote) SpreadBps() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return 0
+	}
+	return (q.Ask - q.Bid) / q.Mid() * 10000.0
+}
+
+type Funds struct {
+	Available, Utilised, Net float64
 }
 type OrderResp struct {
-	OrderID, Status, Message string `json:"order_id"`
+	OrderID string `json:"order_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Notification is an operator alert (trade fill, stop hit, EOD summary).
+type Notification struct {
+	Event   string // trade | stop | eod
+	Account string `json:",omitempty"` // Set when several accounts trade in one process
+	Symbol  string `json:",omitempty"`
+	Text    string
 }
@@ -0,0 +1,190 @@
+package universe
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-trading-bot/internal/api"
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/quality"
+)
+
+// indexURLs maps the supported index names to the constituent lists NSE
+// publishes (CSV: Company Name, Industry, Symbol, Series, ISIN Code).
+var indexURLs = map[string]string{
+	"NIFTY 50":         "https://nsearchives.nseindia.com/content/indices/ind_nifty50list.csv",
+	"NIFTY NEXT 50":    "https://nsearchives.nseindia.com/content/indices/ind_niftynext50list.csv",
+	"NIFTY 100":        "https://nsearchives.nseindia.com/content/indices/ind_nifty100list.csv",
+	"NIFTY MIDCAP 100": "https://nsearchives.nseindia.com/content/indices/ind_niftymidcap100list.csv",
+}
+
+// Indices returns the index names Index accepts.
+func Indices() []string {
+	names := make([]string, 0, len(indexURLs))
+	for name := range indexURLs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nifty50 is the fallback when NIFTY 50 can be neither fetched nor read
+// from the cache. Constituents as of late 2025.
+var nifty50 = []string{
+	"ADANIENT", "ADANIPORTS", "APOLLOHOSP", "ASIANPAINT", "AXISBANK",
+	"BAJAJ-AUTO", "BAJAJFINSV", "BAJFINANCE", "BEL", "BHARTIARTL",
+	"CIPLA", "COALINDIA", "DRREDDY", "EICHERMOT", "ETERNAL",
+	"GRASIM", "HCLTECH", "HDFCBANK", "HDFCLIFE", "HINDALCO",
+	"HINDUNILVR", "ICICIBANK", "INDIGO", "INFY", "ITC",
+	"JIOFIN", "JSWSTEEL", "KOTAKBANK", "LT", "M&M",
+	"MARUTI", "MAXHEALTH", "NESTLEIND", "NTPC", "ONGC",
+	"POWERGRID", "RELIANCE", "SBILIFE", "SBIN", "SHRIRAMFIN",
+	"SUNPHARMA", "TATACONSUM", "TATAMOTORS", "TATASTEEL", "TCS",
+	"TECHM", "TITAN", "TRENT", "ULTRACEMCO", "WIPRO",
+}
+
+type IndexParams struct {
+	Names    []string      // e.g. "NIFTY 50", "NIFTY MIDCAP 100"
+	CacheDir string        // Fetched lists are kept here; empty disables the cache
+	MaxAge   time.Duration // A cached list younger than this is used without fetching
+}
+
+// Index is a source listing the constituents of the named NSE indices, in
+// the order given. Each list is fetched at most once per MaxAge; when NSE
+// is unreachable a stale cached list is used, then the embedded NIFTY 50.
+func Index(p IndexParams) Source {
+	return Source{Name: "index", Symbols: func(ctx context.Context) ([]string, error) {
+		var out []string
+		for _, name := range p.Names {
+			syms, err := p.constituents(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, syms...)
+		}
+		return out, nil
+	}}
+}
+
+func (p IndexParams) constituents(ctx context.Context, name string) ([]string, error) {
+	url, ok := indexURLs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown index %q", name)
+	}
+	path := ""
+	if p.CacheDir != "" {
+		path = filepath.Join(p.CacheDir, strings.ReplaceAll(strings.ToLower(name), " ", "_")+".csv")
+	}
+
+	if path != "" {
+		if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) < p.MaxAge {
+			if syms, err := readIndexFile(path); err == nil {
+				return syms, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	body, err := fetchIndex(ctx, url)
+	if err == nil {
+		syms, perr := parseIndexCSV(bytes.NewReader(body))
+		if perr == nil {
+			quality.Record("universe.index", start, nil, len(syms), len(syms))
+			if path != "" {
+				if werr := writeIndexFile(path, body); werr != nil {
+					logger.Warn(ctx, "Failed to cache index constituents", "index", name, "path", path, "error", werr)
+				}
+			}
+			logger.Info(ctx, "Index constituents fetched", "index", name, "count", len(syms))
+			return syms, nil
+		}
+		err = fmt.Errorf("%w: %w", api.ErrParse, perr)
+	}
+	quality.Record("universe.index", start, err, 0, 0)
+
+	if path != "" {
+		if syms, cerr := readIndexFile(path); cerr == nil {
+			logger.Warn(ctx, "Index fetch failed - using stale cached constituents", "index", name, "error", err)
+			return syms, nil
+		}
+	}
+	if name == "NIFTY 50" {
+		logger.Warn(ctx, "Index fetch failed - using embedded NIFTY 50 list", "error", err)
+		return nifty50, nil
+	}
+	return nil, fmt.Errorf("%s constituents: %w", name, err)
+}
+
+func fetchIndex(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.Default().DoWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := api.CheckStatus(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseIndexCSV returns the Symbol column of an NSE index list.
+func parseIndexCSV(r io.Reader) ([]string, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("index list is empty")
+	}
+	col := -1
+	for i, h := range rows[0] {
+		if strings.EqualFold(strings.TrimSpace(h), "Symbol") {
+			col = i
+		}
+	}
+	if col < 0 {
+		return nil, errors.New("index list has no Symbol column")
+	}
+
+	syms := make([]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if col < len(row) && strings.TrimSpace(row[col]) != "" {
+			syms = append(syms, strings.TrimSpace(row[col]))
+		}
+	}
+	return syms, nil
+}
+
+func readIndexFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseIndexCSV(f)
+}
+
+func writeIndexFile(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
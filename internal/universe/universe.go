@@ -0,0 +1,234 @@
+// Package universe decides which symbols the bot trades. A Manager merges
+// symbol sources (the static list, index constituents, ...) with manual
+// includes and excludes, caps the result at a maximum size and records
+// every addition and removal in an audit trail.
+//
+// Precedence, highest first:
+//
+//	held     symbols with an open position are never dropped
+//	exclude  never traded, whatever lists them
+//	include  always traded
+//	sources  in the order given; earlier sources keep their symbols when
+//	         the size limit cuts the list
+package universe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/logger"
+	"llm-trading-bot/internal/types"
+)
+
+// Source supplies candidate symbols.
+type Source struct {
+	Name    string
+	Symbols func(ctx context.Context) ([]string, error)
+}
+
+// Static is a source listing a fixed set of symbols.
+func Static(name string, symbols []string) Source {
+	return Source{Name: name, Symbols: func(context.Context) ([]string, error) { return symbols, nil }}
+}
+
+type Rules struct {
+	Include []string
+	Exclude []string
+	MaxSize int // 0: no limit; held symbols count toward it but are always kept
+}
+
+// Member is a symbol in the universe and the source that put it there:
+// a source name, "include" or "held".
+type Member struct {
+	Symbol string `json:"symbol"`
+	Source string `json:"source"`
+}
+
+// Change is one entry of the audit trail.
+type Change struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Action string    `json:"action"` // added | removed
+	Source string    `json:"source"`
+	Reason string    `json:"reason"`
+}
+
+// Manager holds the current universe. It is safe for concurrent use.
+type Manager struct {
+	sources   []Source
+	rules     Rules
+	auditPath string // Empty: changes are only logged
+
+	mu      sync.Mutex
+	members []Member
+	last    map[string][]string // Last good result per source
+}
+
+// New returns a manager with an empty universe; call Refresh to fill it.
+func New(rules Rules, auditPath string, sources ...Source) *Manager {
+	return &Manager{sources: sources, rules: rules, auditPath: auditPath, last: make(map[string][]string)}
+}
+
+// Symbols returns the current universe in precedence order.
+func (m *Manager) Symbols() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]string, len(m.members))
+	for i, mb := range m.members {
+		out[i] = mb.Symbol
+	}
+	return out
+}
+
+// Members returns the current universe with the source of each symbol.
+func (m *Manager) Members() []Member {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Member(nil), m.members...)
+}
+
+// Refresh re-reads every source and rebuilds the universe, keeping the
+// held symbols. A source that fails keeps its previous symbols. It returns
+// the changes, which are also logged and appended to the audit trail.
+func (m *Manager) Refresh(ctx context.Context, held []string) ([]Change, error) {
+	listed := make(map[string][]string, len(m.sources))
+	var errs []error
+	for _, src := range m.sources {
+		syms, err := src.Symbols(ctx)
+		if err != nil {
+			logger.Warn(ctx, "Universe source failed - keeping its previous symbols", "source", src.Name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+			m.mu.Lock()
+			listed[src.Name] = m.last[src.Name]
+			m.mu.Unlock()
+			continue
+		}
+		listed[src.Name] = syms
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, syms := range listed {
+		m.last[name] = syms
+	}
+
+	next, dropped := m.build(held, listed)
+	changes := diff(m.members, next, dropped, time.Now())
+	m.members = next
+
+	for _, c := range changes {
+		logger.Info(ctx, "Universe changed", "symbol", c.Symbol, "action", c.Action, "source", c.Source, "reason", c.Reason)
+	}
+	if err := m.audit(changes); err != nil {
+		logger.Warn(ctx, "Failed to write universe audit trail", "path", m.auditPath, "error", err)
+	}
+	if len(errs) > 0 {
+		return changes, fmt.Errorf("universe sources failed: %v", errs)
+	}
+	return changes, nil
+}
+
+// build merges the candidates by precedence. dropped gives the reason for
+// symbols a source listed but the rules left out.
+func (m *Manager) build(held []string, listed map[string][]string) ([]Member, map[string]string) {
+	excluded := make(map[string]bool, len(m.rules.Exclude))
+	for _, s := range m.rules.Exclude {
+		excluded[types.NormalizeSymbol(s)] = true
+	}
+
+	var members []Member
+	seen := make(map[string]bool)
+	dropped := make(map[string]string)
+	add := func(sym, source string, capped bool) {
+		sym = types.NormalizeSymbol(sym)
+		switch {
+		case sym == "" || seen[sym]:
+		case source != "held" && excluded[sym]:
+			dropped[sym] = "excluded"
+		case capped && m.rules.MaxSize > 0 && len(members) >= m.rules.MaxSize:
+			dropped[sym] = fmt.Sprintf("over max_size %d", m.rules.MaxSize)
+		default:
+			seen[sym] = true
+			delete(dropped, sym)
+			members = append(members, Member{Symbol: sym, Source: source})
+		}
+	}
+
+	for _, s := range held {
+		add(s, "held", false)
+	}
+	for _, s := range m.rules.Include {
+		add(s, "include", true)
+	}
+	for _, src := range m.sources {
+		for _, s := range listed[src.Name] {
+			add(s, src.Name, true)
+		}
+	}
+	return members, dropped
+}
+
+func diff(prev, next []Member, dropped map[string]string, now time.Time) []Change {
+	was := make(map[string]Member, len(prev))
+	for _, mb := range prev {
+		was[mb.Symbol] = mb
+	}
+	is := make(map[string]bool, len(next))
+
+	var changes []Change
+	for _, mb := range next {
+		is[mb.Symbol] = true
+		if _, ok := was[mb.Symbol]; !ok {
+			changes = append(changes, Change{Time: now, Symbol: mb.Symbol, Action: "added", Source: mb.Source, Reason: addReason(mb.Source)})
+		}
+	}
+	for _, mb := range prev {
+		if is[mb.Symbol] {
+			continue
+		}
+		reason, ok := dropped[mb.Symbol]
+		if !ok {
+			reason = "no longer listed"
+		}
+		changes = append(changes, Change{Time: now, Symbol: mb.Symbol, Action: "removed", Source: mb.Source, Reason: reason})
+	}
+	return changes
+}
+
+func addReason(source string) string {
+	switch source {
+	case "held":
+		return "position open"
+	case "include":
+		return "manual include"
+	}
+	return "listed by " + source
+}
+
+// audit appends changes to the audit file, one JSON object per line.
+func (m *Manager) audit(changes []Change) error {
+	if m.auditPath == "" || len(changes) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.auditPath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(m.auditPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, c := range changes {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package warehouse
+
+import (
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+)
+
+// Gap is a run of consecutive bars missing from a symbol's session.
+// From is the first missing bar's start, To the last one's.
+type Gap struct {
+	Symbol   string
+	Day      string // IST date
+	From, To time.Time
+	Bars     int
+}
+
+// DayCoverage is how much of a regular session (09:15-15:30 IST) a
+// symbol has stored for one day.
+type DayCoverage struct {
+	Symbol   string
+	Day      string
+	Expected int
+	Stored   int
+	Gaps     []Gap
+}
+
+// Complete reports whether every bar of the session is stored.
+func (d DayCoverage) Complete() bool {
+	return d.Stored >= d.Expected
+}
+
+// Gaps checks each symbol's sessions between two IST dates against the
+// bar grid for intervalMinutes, anchored at the 09:15 open like the
+// broker's own bars. A weekday no symbol has any bars for is taken as a
+// holiday and skipped; a symbol with no bars on a day others traded is
+// reported as one gap spanning the session.
+func (s *Store) Gaps(symbols []string, from, to time.Time, intervalMinutes int) ([]DayCoverage, error) {
+	if intervalMinutes <= 0 {
+		intervalMinutes = 1
+	}
+	step := time.Duration(intervalMinutes) * time.Minute
+
+	type key struct{ symbol, day string }
+	stored := make(map[key]map[int64]bool)
+	traded := make(map[string]bool)
+	for _, sym := range symbols {
+		cs, err := s.Load(sym, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			d := timeutil.DateKey(time.Unix(c.Ts, 0))
+			k := key{sym, d}
+			if stored[k] == nil {
+				stored[k] = make(map[int64]bool)
+			}
+			stored[k][c.Ts] = true
+			traded[d] = true
+		}
+	}
+
+	var out []DayCoverage
+	for d := from.In(timeutil.IST); !d.After(to.In(timeutil.IST)); d = d.AddDate(0, 0, 1) {
+		date := timeutil.DateKey(d)
+		if !traded[date] {
+			continue
+		}
+		open, end := timeutil.SessionOpen(d), timeutil.SessionClose(d)
+
+		for _, sym := range symbols {
+			have := stored[key{sym, date}]
+			cov := DayCoverage{Symbol: sym, Day: date}
+			var gap *Gap
+			for t := open; t.Before(end); t = t.Add(step) {
+				cov.Expected++
+				if have[t.Unix()] {
+					cov.Stored++
+					gap = nil
+					continue
+				}
+				if gap == nil {
+					cov.Gaps = append(cov.Gaps, Gap{Symbol: sym, Day: date, From: t})
+					gap = &cov.Gaps[len(cov.Gaps)-1]
+				}
+				gap.To = t
+				gap.Bars++
+			}
+			out = append(out, cov)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,322 @@
+// Package warehouse keeps every candle the bot receives, live bars and
+// broker backfills alike, one CSV file per symbol and IST day:
+//
+//	<dir>/RELIANCE/2025-11-04.csv
+//
+// with the columns ts,open,high,low,close,volume, the format backtest
+// reads. A symbol holds one bar per timestamp: a bar received again
+// replaces the stored one, so a bar still forming when first seen ends up
+// with its final values.
+package warehouse
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-trading-bot/internal/timeutil"
+	"llm-trading-bot/internal/types"
+)
+
+// Store writes and reads the warehouse under dir.
+type Store struct {
+	dir string
+
+	mu   sync.Mutex
+	days map[string]*day // By file path; only days touched by the latest Put of each symbol
+	last map[string][]string
+}
+
+// day is one symbol-day file held in memory while it is being written.
+type day struct {
+	path string
+	bars map[int64]types.Candle
+}
+
+func New(dir string) *Store {
+	return &Store{dir: dir, days: make(map[string]*day), last: make(map[string][]string)}
+}
+
+// Put stores candles for symbol. New bars are appended to their day's
+// file; a changed bar rewrites the file.
+func (s *Store) Put(symbol string, candles []types.Candle) error {
+	if s == nil || len(candles) == 0 {
+		return nil
+	}
+	symbol = types.NormalizeSymbol(symbol)
+
+	byPath := make(map[string][]types.Candle)
+	for _, c := range candles {
+		if c.Ts <= 0 {
+			continue
+		}
+		p := s.path(symbol, time.Unix(c.Ts, 0))
+		byPath[p] = append(byPath[p], c)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make([]string, 0, len(byPath))
+	var errs []error
+	for p, cs := range byPath {
+		touched = append(touched, p)
+		if err := s.put(p, cs); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+		}
+	}
+
+	// Forget days this symbol has moved past so memory stays bounded
+	keep := make(map[string]bool, len(touched))
+	for _, p := range touched {
+		keep[p] = true
+	}
+	for _, p := range s.last[symbol] {
+		if !keep[p] {
+			delete(s.days, p)
+		}
+	}
+	s.last[symbol] = touched
+	return errors.Join(errs...)
+}
+
+func (s *Store) put(path string, candles []types.Candle) error {
+	d := s.days[path]
+	if d == nil {
+		bars, err := readFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		d = &day{path: path, bars: make(map[int64]types.Candle, len(bars))}
+		for _, c := range bars {
+			d.bars[c.Ts] = c
+		}
+		s.days[path] = d
+	}
+
+	var added []types.Candle
+	changed := false
+	for _, c := range candles {
+		old, ok := d.bars[c.Ts]
+		switch {
+		case !ok:
+			added = append(added, c)
+		case old != c:
+			changed = true
+		default:
+			continue
+		}
+		d.bars[c.Ts] = c
+	}
+
+	switch {
+	case changed:
+		return d.rewrite()
+	case len(added) > 0:
+		return d.append(added)
+	}
+	return nil
+}
+
+func (d *day) append(candles []types.Candle) error {
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(d.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if fi, err := f.Stat(); err == nil && fi.Size() == 0 {
+		_ = w.Write(header)
+	}
+	for _, c := range candles {
+		_ = w.Write(record(c))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rewrite replaces the file with the day's bars in time order.
+func (d *day) rewrite() error {
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return err
+	}
+	tmp := d.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(header)
+	for _, c := range sorted(d.bars) {
+		_ = w.Write(record(c))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.path)
+}
+
+// Load returns symbol's bars between two IST dates (inclusive), oldest
+// first.
+func (s *Store) Load(symbol string, from, to time.Time) ([]types.Candle, error) {
+	symbol = types.NormalizeSymbol(symbol)
+	var out []types.Candle
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		cs, err := readFile(s.path(symbol, d))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cs...)
+	}
+	return out, nil
+}
+
+// All returns every stored bar of symbol, oldest first.
+func (s *Store) All(symbol string) ([]types.Candle, error) {
+	symbol = types.NormalizeSymbol(symbol)
+	files, err := filepath.Glob(filepath.Join(s.dir, safeName(symbol), "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var out []types.Candle
+	for _, f := range files {
+		cs, err := readFile(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cs...)
+	}
+	return out, nil
+}
+
+// Symbols lists the symbols with stored bars.
+func (s *Store) Symbols() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			out = append(out, unsafeName(e.Name()))
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (s *Store) path(symbol string, t time.Time) string {
+	return filepath.Join(s.dir, safeName(symbol), timeutil.DateKey(t)+".csv")
+}
+
+// safeName keeps exchange-qualified symbols (BSE:500325) usable as
+// directory names on every OS.
+func safeName(symbol string) string {
+	return strings.ReplaceAll(symbol, ":", "_")
+}
+
+func unsafeName(name string) string {
+	for _, ex := range []string{types.ExchangeNSE, types.ExchangeBSE} {
+		if strings.HasPrefix(name, ex+"_") {
+			return ex + ":" + name[len(ex)+1:]
+		}
+	}
+	return name
+}
+
+var header = []string{"ts", "open", "high", "low", "close", "volume"}
+
+func record(c types.Candle) []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []string{strconv.FormatInt(c.Ts, 10), f(c.Open), f(c.High), f(c.Low), f(c.Close), f(c.Vol)}
+}
+
+// readFile reads a day file. Rows for a timestamp seen earlier in the
+// file replace it, matching Put.
+func readFile(path string) ([]types.Candle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	bars := make(map[int64]types.Candle)
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 6 || rec[0] == "ts" {
+			continue
+		}
+		var vals [6]float64
+		ok := true
+		for i := range vals {
+			v, err := strconv.ParseFloat(rec[i], 64)
+			if err != nil {
+				ok = false // A torn line from a crash mid-append
+				break
+			}
+			vals[i] = v
+		}
+		if !ok {
+			continue
+		}
+		c := types.Candle{Ts: int64(vals[0]), Open: vals[1], High: vals[2], Low: vals[3], Close: vals[4], Vol: vals[5]}
+		bars[c.Ts] = c
+	}
+	return sorted(bars), nil
+}
+
+func sorted(bars map[int64]types.Candle) []types.Candle {
+	out := make([]types.Candle, 0, len(bars))
+	for _, c := range bars {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Ts < out[j].Ts })
+	return out
+}
+
+var defaultStore *Store
+
+// SetDefault installs the store Put writes to; until then candles are
+// not kept.
+func SetDefault(s *Store) {
+	defaultStore = s
+}
+
+// Put stores candles in the default store.
+func Put(symbol string, candles []types.Candle) error {
+	return defaultStore.Put(symbol, candles)
+}